@@ -0,0 +1,36 @@
+package application
+
+import (
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+type (
+	// BundleConfigPreview is the outcome of a proposed bundle configuration for an existing cart item, the cart itself is not changed
+	BundleConfigPreview struct {
+		ItemID string
+		// Valid is false if the proposed configuration can not be applied, ValidationError contains the reason
+		Valid           bool
+		ValidationError string
+		// Choices are the active choices of the proposed configuration
+		Choices []BundleConfigPreviewChoice
+		// CurrentRowPrice is the gross row price of the item in the cart
+		CurrentRowPrice priceDomain.Price
+		// NewRowPrice is the row price of the item with the proposed configuration, including the surcharges of the item options
+		NewRowPrice priceDomain.Price
+		// Delta is the difference of the new row price to the current row price
+		Delta priceDomain.Price
+		// Available is false if the qty of the item exceeds the qty restrictions (e.g. the stock) of the proposed configuration
+		Available bool
+		// MaxAllowedQty is the max qty of the proposed configuration, only set if it is restricted
+		MaxAllowedQty int
+	}
+
+	// BundleConfigPreviewChoice is an active choice of the proposed bundle configuration
+	BundleConfigPreviewChoice struct {
+		Identifier             string
+		MarketplaceCode        string
+		VariantMarketplaceCode string
+		Qty                    int
+		SinglePrice            priceDomain.Price
+	}
+)
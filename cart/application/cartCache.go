@@ -119,7 +119,12 @@ func (cs *CartSessionCache) BuildIdentifier(ctx context.Context, session *web.Se
 	ctx, span := trace.StartSpan(ctx, "cart/CartSessionCache/BuildIdentifier")
 	defer span.End()
 
-	identity := cs.webIdentityService.Identify(ctx, web.RequestFromContext(ctx))
+	return buildCartCacheIdentifier(ctx, cs.webIdentityService, session)
+}
+
+// buildCartCacheIdentifier identifies the cart of the logged-in customer or the guest cart of the session
+func buildCartCacheIdentifier(ctx context.Context, webIdentityService *auth.WebIdentityService, session *web.Session) (CartCacheIdentifier, error) {
+	identity := webIdentityService.Identify(ctx, web.RequestFromContext(ctx))
 	if identity != nil {
 		return CartCacheIdentifier{
 			CustomerID:     identity.Subject(),
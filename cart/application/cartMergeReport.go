@@ -0,0 +1,101 @@
+package application
+
+import (
+	"encoding/gob"
+
+	"flamingo.me/flamingo/v3/framework/web"
+)
+
+type (
+	// CartMergeReport describes what happened to the guest cart when it was merged into the customer cart after login
+	CartMergeReport struct {
+		GuestCartID    string
+		CustomerCartID string
+		// MergedItems have been added to the customer cart with their full qty
+		MergedItems []CartMergeReportItem
+		// QtyAdjustments have been added to the customer cart with a qty reduced by the qty restrictions
+		QtyAdjustments []CartMergeReportItem
+		// DroppedItems could not be added to the customer cart at all
+		DroppedItems []CartMergeReportItem
+		// DroppedVouchers could not be applied to the customer cart
+		DroppedVouchers []CartMergeReportCode
+		// DroppedGiftCards could not be applied to the customer cart
+		DroppedGiftCards []CartMergeReportCode
+		// PaymentSelection is the outcome of the payment selection of the guest cart, one of CartMergePaymentSelection*
+		PaymentSelection string
+	}
+
+	// CartMergeReportItem is a line of the guest cart and the qty which has been merged into the customer cart
+	CartMergeReportItem struct {
+		DeliveryCode           string
+		MarketplaceCode        string
+		VariantMarketplaceCode string
+		RequestedQty           int
+		MergedQty              int
+		// Reason contains the error message why the line could not or only partially be merged
+		Reason string
+	}
+
+	// CartMergeReportCode is a voucher or gift card code of the guest cart which could not be applied
+	CartMergeReportCode struct {
+		Code   string
+		Reason string
+	}
+)
+
+const (
+	// CartMergeReportFlashKey is the session flash key of the last CartMergeReport
+	CartMergeReportFlashKey = "cart.view.merge.report"
+
+	// CartMergePaymentSelectionNone the guest cart had no payment selection
+	CartMergePaymentSelectionNone = "none"
+	// CartMergePaymentSelectionApplied the payment selection of the guest cart has been applied to the customer cart
+	CartMergePaymentSelectionApplied = "applied"
+	// CartMergePaymentSelectionKept the customer cart already had a payment selection or items, the one of the guest cart has been discarded
+	CartMergePaymentSelectionKept = "kept"
+	// CartMergePaymentSelectionDropped the payment selection of the guest cart could not be applied, e.g. because not all items have been merged
+	CartMergePaymentSelectionDropped = "dropped"
+)
+
+func init() {
+	gob.Register(CartMergeReport{})
+}
+
+// HasConflicts returns true if anything of the guest cart could not be merged as it was
+func (r CartMergeReport) HasConflicts() bool {
+	return len(r.QtyAdjustments) > 0 ||
+		len(r.DroppedItems) > 0 ||
+		len(r.DroppedVouchers) > 0 ||
+		len(r.DroppedGiftCards) > 0 ||
+		r.PaymentSelection == CartMergePaymentSelectionDropped
+}
+
+// LastCartMergeReport returns the report of the last cart merge and removes it from the session, nil if there is none
+func LastCartMergeReport(session *web.Session) *CartMergeReport {
+	if session == nil {
+		return nil
+	}
+
+	flashes := session.Flashes(CartMergeReportFlashKey)
+	if len(flashes) == 0 {
+		return nil
+	}
+
+	report, ok := flashes[len(flashes)-1].(CartMergeReport)
+	if !ok {
+		return nil
+	}
+
+	return &report
+}
+
+func (r *CartMergeReport) addMergedItem(item CartMergeReportItem) {
+	switch {
+	case item.MergedQty == 0:
+		r.DroppedItems = append(r.DroppedItems, item)
+	case item.MergedQty < item.RequestedQty:
+		r.QtyAdjustments = append(r.QtyAdjustments, item)
+	default:
+		r.MergedItems = append(r.MergedItems, item)
+	}
+}
@@ -0,0 +1,116 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.opencensus.io/trace"
+
+	cartDomain "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+)
+
+type (
+	// CartPostProcessor adjusts the cart after each modification, e.g. to add free gifts, apply coupons automatically
+	// or enforce delivery methods. Bind processors via BindMulti.
+	// The behaviour runs the processors before the modification is stored, changes must only be done via the given behaviour,
+	// which stores them together with the modification.
+	CartPostProcessor interface {
+		// Priority defines the order of the processors, lower priorities run first
+		Priority() int
+		// Process may modify the cart via the behaviour and returns the modified cart and the events of the modifications.
+		// If nothing has been changed it returns a nil cart.
+		Process(ctx context.Context, cart *cartDomain.Cart, behaviour cartDomain.ModifyBehaviour) (*cartDomain.Cart, cartDomain.DeferEvents, error)
+	}
+
+	// CartPostProcessorPipeline runs the bound CartPostProcessor in the order of their priority.
+	// As processors can react to the changes of each other, the pipeline is repeated until no processor changes the cart anymore.
+	CartPostProcessorPipeline struct {
+		processors []CartPostProcessor
+		maxPasses  int
+	}
+
+	postProcessingKey struct{}
+)
+
+var (
+	// ErrPostProcessingLoop is returned if the processors still change the cart after the maximum number of passes
+	ErrPostProcessingLoop = errors.New("cart post processors did not settle")
+)
+
+const defaultPostProcessorMaxPasses = 5
+
+// Inject dependencies
+func (p *CartPostProcessorPipeline) Inject(
+	cfg *struct {
+		MaxPasses float64 `inject:"config:commerce.cart.postProcessors.maxPasses,optional"`
+	},
+	optionals *struct {
+		Processors []CartPostProcessor `inject:",optional"`
+	},
+) *CartPostProcessorPipeline {
+	p.maxPasses = defaultPostProcessorMaxPasses
+
+	if cfg != nil && cfg.MaxPasses > 0 {
+		p.maxPasses = int(cfg.MaxPasses)
+	}
+
+	if optionals != nil {
+		p.processors = make([]CartPostProcessor, len(optionals.Processors))
+		copy(p.processors, optionals.Processors)
+		// stable, so that processors with the same priority run in the order they have been bound
+		sort.SliceStable(p.processors, func(i, j int) bool {
+			return p.processors[i].Priority() < p.processors[j].Priority()
+		})
+	}
+
+	return p
+}
+
+// Process runs the processors until they don't change the cart anymore and returns the resulting cart and the events of all modifications.
+// On errors the cart of the last successful modification is returned. Modifications done while processing are not processed again.
+func (p *CartPostProcessorPipeline) Process(ctx context.Context, cart *cartDomain.Cart, behaviour cartDomain.ModifyBehaviour) (*cartDomain.Cart, cartDomain.DeferEvents, error) {
+	if p == nil || len(p.processors) == 0 || cart == nil || behaviour == nil || IsPostProcessing(ctx) {
+		return cart, nil, nil
+	}
+
+	ctx, span := trace.StartSpan(ctx, "cart/CartPostProcessorPipeline/Process")
+	defer span.End()
+
+	ctx = context.WithValue(ctx, postProcessingKey{}, true)
+
+	var defers cartDomain.DeferEvents
+
+	for pass := 0; pass < p.maxPasses; pass++ {
+		changed := false
+
+		for _, processor := range p.processors {
+			processedCart, processorDefers, err := processor.Process(ctx, cart, behaviour)
+			if err != nil {
+				return cart, defers, fmt.Errorf("CartPostProcessorPipeline: processor %T failed: %w", processor, err)
+			}
+
+			if processedCart == nil {
+				continue
+			}
+
+			cart = processedCart
+			defers = append(defers, processorDefers...)
+			changed = true
+		}
+
+		if !changed {
+			return cart, defers, nil
+		}
+	}
+
+	return cart, defers, fmt.Errorf("CartPostProcessorPipeline: %w after %d passes", ErrPostProcessingLoop, p.maxPasses)
+}
+
+// IsPostProcessing returns true if the context belongs to a modification done by a CartPostProcessor
+func IsPostProcessing(ctx context.Context) bool {
+	processing, _ := ctx.Value(postProcessingKey{}).(bool)
+
+	return processing
+}
@@ -0,0 +1,113 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cartApplication "flamingo.me/flamingo-commerce/v3/cart/application"
+	cartDomain "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/cart/mocks"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/events"
+)
+
+type funcPostProcessor struct {
+	priority int
+	process  func(ctx context.Context, cart *cartDomain.Cart, behaviour cartDomain.ModifyBehaviour) (*cartDomain.Cart, cartDomain.DeferEvents, error)
+}
+
+func (p *funcPostProcessor) Priority() int {
+	return p.priority
+}
+
+func (p *funcPostProcessor) Process(ctx context.Context, cart *cartDomain.Cart, behaviour cartDomain.ModifyBehaviour) (*cartDomain.Cart, cartDomain.DeferEvents, error) {
+	return p.process(ctx, cart, behaviour)
+}
+
+func newPostProcessorPipeline(processors ...cartApplication.CartPostProcessor) *cartApplication.CartPostProcessorPipeline {
+	return new(cartApplication.CartPostProcessorPipeline).Inject(
+		&struct {
+			MaxPasses float64 `inject:"config:commerce.cart.postProcessors.maxPasses,optional"`
+		}{MaxPasses: 3},
+		&struct {
+			Processors []cartApplication.CartPostProcessor `inject:",optional"`
+		}{Processors: processors},
+	)
+}
+
+func TestCartPostProcessorPipeline_Process(t *testing.T) {
+	t.Parallel()
+
+	behaviour := new(mocks.ModifyBehaviour)
+
+	t.Run("processors run by priority until the cart settles", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+
+		// adds a gift once the cart has a revision of at least 2
+		gift := &funcPostProcessor{priority: 20, process: func(_ context.Context, cart *cartDomain.Cart, _ cartDomain.ModifyBehaviour) (*cartDomain.Cart, cartDomain.DeferEvents, error) {
+			calls = append(calls, "gift")
+			if cart.Revision < 2 || cart.AdditionalData.CustomAttributes["gift"] != "" {
+				return nil, nil, nil
+			}
+
+			return &cartDomain.Cart{Revision: cart.Revision + 1, AdditionalData: cartDomain.AdditionalData{CustomAttributes: map[string]string{"gift": "added"}}}, cartDomain.DeferEvents{&events.AddToCartEvent{MarketplaceCode: "gift"}}, nil
+		}}
+
+		// raises the revision to 2 once
+		bump := &funcPostProcessor{priority: 10, process: func(_ context.Context, cart *cartDomain.Cart, _ cartDomain.ModifyBehaviour) (*cartDomain.Cart, cartDomain.DeferEvents, error) {
+			calls = append(calls, "bump")
+			if cart.Revision >= 2 {
+				return nil, nil, nil
+			}
+
+			return &cartDomain.Cart{Revision: 2}, nil, nil
+		}}
+
+		cart, defers, err := newPostProcessorPipeline(gift, bump).Process(context.Background(), &cartDomain.Cart{Revision: 1}, behaviour)
+		require.NoError(t, err)
+		assert.Equal(t, 3, cart.Revision)
+		assert.Equal(t, "added", cart.AdditionalData.CustomAttributes["gift"])
+		assert.Len(t, defers, 1)
+		assert.Equal(t, []string{"bump", "gift", "bump", "gift"}, calls, "lower priorities run first, the second pass verifies the cart settled")
+	})
+
+	t.Run("endless changes are stopped", func(t *testing.T) {
+		t.Parallel()
+
+		toggle := &funcPostProcessor{process: func(_ context.Context, cart *cartDomain.Cart, _ cartDomain.ModifyBehaviour) (*cartDomain.Cart, cartDomain.DeferEvents, error) {
+			return &cartDomain.Cart{Revision: cart.Revision + 1}, nil, nil
+		}}
+
+		cart, _, err := newPostProcessorPipeline(toggle).Process(context.Background(), &cartDomain.Cart{Revision: 1}, behaviour)
+		assert.ErrorIs(t, err, cartApplication.ErrPostProcessingLoop)
+		assert.Equal(t, 4, cart.Revision, "the cart of the last modification is returned")
+	})
+
+	t.Run("nested modifications are not processed again", func(t *testing.T) {
+		t.Parallel()
+
+		nested := false
+		var pipeline *cartApplication.CartPostProcessorPipeline
+		pipeline = newPostProcessorPipeline(&funcPostProcessor{process: func(ctx context.Context, cart *cartDomain.Cart, behaviour cartDomain.ModifyBehaviour) (*cartDomain.Cart, cartDomain.DeferEvents, error) {
+			assert.True(t, cartApplication.IsPostProcessing(ctx))
+			if !nested {
+				nested = true
+				// simulates a processor modifying the cart via the CartService
+				_, defers, err := pipeline.Process(ctx, cart, behaviour)
+				assert.Nil(t, defers)
+				return nil, nil, err
+			}
+
+			t.Fatal("nested pipeline must not run the processors")
+			return nil, nil, nil
+		}})
+
+		_, _, err := pipeline.Process(context.Background(), &cartDomain.Cart{}, behaviour)
+		require.NoError(t, err)
+		assert.True(t, nested)
+	})
+}
@@ -44,6 +44,8 @@ var (
 const (
 	// GuestCartSessionKey is a prefix
 	GuestCartSessionKey = "cart.guestid"
+	// CustomerCartSessionKey stores the id of the selected customer cart if the customer switched to a named cart
+	CustomerCartSessionKey = "cart.customer.activeid"
 )
 
 // Inject the dependencies
@@ -242,7 +244,7 @@ func (cs *BaseCartReceiver) getCustomerCart(ctx context.Context, session *web.Se
 	}
 
 	if !found {
-		cart, err = cs.customerCartService.GetCart(ctx, identitiy, "me")
+		cart, err = cs.getActiveCustomerCart(ctx, session, identitiy)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -257,6 +259,145 @@ func (cs *BaseCartReceiver) getCustomerCart(ctx context.Context, session *web.Se
 	return cart, behaviour, nil
 }
 
+// getActiveCustomerCart returns the customer cart selected in the session or the default cart of the customer
+func (cs *BaseCartReceiver) getActiveCustomerCart(ctx context.Context, session *web.Session, identity auth.Identity) (*cartDomain.Cart, error) {
+	cartID, ok := session.Load(CustomerCartSessionKey)
+	if !ok {
+		return cs.customerCartService.GetCart(ctx, identity, "me")
+	}
+
+	cart, err := cs.customerCartService.GetCart(ctx, identity, fmt.Sprint(cartID))
+	if errors.Is(err, cartDomain.ErrCartNotFound) {
+		// the selected cart is gone or belongs to another customer - fall back to the default cart
+		cs.logger.WithContext(ctx).Info(fmt.Sprintf("Selected customer cart %q not found, using default cart", cartID))
+		session.Delete(CustomerCartSessionKey)
+
+		return cs.customerCartService.GetCart(ctx, identity, "me")
+	}
+
+	return cart, err
+}
+
+// namedCartService returns the NamedCartService and the identity of the logged in customer
+func (cs *BaseCartReceiver) namedCartService(ctx context.Context) (cartDomain.NamedCartService, auth.Identity, error) {
+	identity := cs.webIdentityService.Identify(ctx, web.RequestFromContext(ctx))
+	if identity == nil {
+		return nil, nil, application.ErrNoIdentity
+	}
+
+	namedCartService, ok := cs.customerCartService.(cartDomain.NamedCartService)
+	if !ok {
+		return nil, nil, cartDomain.ErrNamedCartsNotSupported
+	}
+
+	return namedCartService, identity, nil
+}
+
+// ListCustomerCarts returns all carts of the logged in customer
+func (cs *BaseCartReceiver) ListCustomerCarts(ctx context.Context) ([]*cartDomain.Cart, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/BaseCartReceiver/ListCustomerCarts")
+	defer span.End()
+
+	namedCartService, identity, err := cs.namedCartService(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return namedCartService.ListCarts(ctx, identity)
+}
+
+// CreateCustomerCart creates a new named cart for the logged in customer, the active cart is not changed
+func (cs *BaseCartReceiver) CreateCustomerCart(ctx context.Context, name string) (*cartDomain.Cart, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/BaseCartReceiver/CreateCustomerCart")
+	defer span.End()
+
+	namedCartService, identity, err := cs.namedCartService(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return namedCartService.CreateCart(ctx, identity, name)
+}
+
+// RenameCustomerCart changes the name of a cart of the logged in customer
+func (cs *BaseCartReceiver) RenameCustomerCart(ctx context.Context, session *web.Session, cartID string, name string) (*cartDomain.Cart, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/BaseCartReceiver/RenameCustomerCart")
+	defer span.End()
+
+	namedCartService, identity, err := cs.namedCartService(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := namedCartService.RenameCart(ctx, identity, cartID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// the renamed cart might be the active one
+	cs.deleteCartInCacheIfCacheIsEnabled(ctx, session)
+
+	return cart, nil
+}
+
+// SwitchCustomerCart makes the given cart the active cart of the logged in customer, all following cart operations will use it
+func (cs *BaseCartReceiver) SwitchCustomerCart(ctx context.Context, session *web.Session, cartID string) (*cartDomain.Cart, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/BaseCartReceiver/SwitchCustomerCart")
+	defer span.End()
+
+	_, identity, err := cs.namedCartService(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := cs.customerCartService.GetCart(ctx, identity, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	session.Store(CustomerCartSessionKey, cart.ID)
+	cs.deleteCartInCacheIfCacheIsEnabled(ctx, session)
+	_ = cs.storeCartInCacheIfCacheIsEnabled(ctx, session, cart)
+
+	return cart, nil
+}
+
+// GetCustomerCartByID returns a cart of the logged in customer by its id, regardless of the active cart
+func (cs *BaseCartReceiver) GetCustomerCartByID(ctx context.Context, cartID string) (*cartDomain.Cart, cartDomain.ModifyBehaviour, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/BaseCartReceiver/GetCustomerCartByID")
+	defer span.End()
+
+	identity := cs.webIdentityService.Identify(ctx, web.RequestFromContext(ctx))
+	if identity == nil {
+		return nil, nil, application.ErrNoIdentity
+	}
+
+	cart, err := cs.customerCartService.GetCart(ctx, identity, cartID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	behaviour, err := cs.customerCartService.GetModifyBehaviour(ctx, identity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cart, behaviour, nil
+}
+
+func (cs *BaseCartReceiver) deleteCartInCacheIfCacheIsEnabled(ctx context.Context, session *web.Session) {
+	if cs.cartCache == nil {
+		return
+	}
+
+	id, err := cs.cartCache.BuildIdentifier(ctx, session)
+	if err != nil {
+		return
+	}
+
+	_ = cs.cartCache.Delete(ctx, session, id)
+}
+
 func (cs *BaseCartReceiver) getCartFromCacheIfCacheIsEnabled(ctx context.Context, session *web.Session) (*cartDomain.Cart, bool, error) {
 	ctx, span := trace.StartSpan(ctx, "cart/BaseCartReceiver/getCartFromCacheIfCacheIsEnabled")
 	defer span.End()
@@ -300,6 +441,12 @@ func (cs *BaseCartReceiver) getExistingGuestCart(ctx context.Context, session *w
 
 	if err != nil || !found {
 		cart, err = cs.getSessionGuestCart(ctx, session)
+		if errors.Is(err, cartDomain.ErrCartNotFound) {
+			// the guest cart expired or has been removed, getSessionGuestCart already removed its ID from the session
+			cs.logger.WithContext(ctx).Info("GetCart - guest cart in session not found, starting a new one")
+
+			return cs.getNewGuestCart(ctx, session)
+		}
 
 		if err != nil {
 			// TODO - decide on recoverable errors (where we should communicate "try again" / and not recoverable (where we should clean up guest cart in session and try to get a new one)
@@ -371,7 +518,7 @@ func (cs *BaseCartReceiver) GetCartWithoutCache(ctx context.Context, session *we
 
 	identitiy := cs.webIdentityService.Identify(ctx, web.RequestFromContext(ctx))
 	if identitiy != nil {
-		return cs.customerCartService.GetCart(ctx, identitiy, "me")
+		return cs.getActiveCustomerCart(ctx, session, identitiy)
 	}
 
 	if cs.ShouldHaveGuestCart(session) {
@@ -236,6 +236,7 @@ func TestCartReceiverService_ShouldHaveGuestCart(t *testing.T) {
 					result.Inject(
 						&MockProductService{},
 						flamingo.NullLogger{},
+						nil,
 					)
 
 					return result
@@ -257,6 +258,7 @@ func TestCartReceiverService_ShouldHaveGuestCart(t *testing.T) {
 					result.Inject(
 						&MockProductService{},
 						flamingo.NullLogger{},
+						nil,
 					)
 
 					return result
@@ -328,6 +330,7 @@ func TestCartReceiverService_ViewGuestCart(t *testing.T) {
 					result.Inject(
 						&MockProductService{},
 						flamingo.NullLogger{},
+						nil,
 					)
 
 					return result
@@ -352,6 +355,7 @@ func TestCartReceiverService_ViewGuestCart(t *testing.T) {
 					result.Inject(
 						&MockProductService{},
 						flamingo.NullLogger{},
+						nil,
 					)
 
 					return result
@@ -376,6 +380,7 @@ func TestCartReceiverService_ViewGuestCart(t *testing.T) {
 					result.Inject(
 						&MockProductService{},
 						flamingo.NullLogger{},
+						nil,
 					)
 
 					return result
@@ -463,6 +468,7 @@ func TestCartReceiverService_DecorateCart(t *testing.T) {
 					result.Inject(
 						&MockProductService{},
 						flamingo.NullLogger{},
+						nil,
 					)
 
 					return result
@@ -487,6 +493,7 @@ func TestCartReceiverService_DecorateCart(t *testing.T) {
 					result.Inject(
 						&MockProductService{},
 						flamingo.NullLogger{},
+						nil,
 					)
 
 					return result
@@ -587,6 +594,7 @@ func TestCartReceiverService_GetDecoratedCart(t *testing.T) {
 					result.Inject(
 						&MockProductService{},
 						flamingo.NullLogger{},
+						nil,
 					)
 
 					return result
@@ -611,6 +619,7 @@ func TestCartReceiverService_GetDecoratedCart(t *testing.T) {
 					result.Inject(
 						&MockProductService{},
 						flamingo.NullLogger{},
+						nil,
 					)
 
 					return result
@@ -933,3 +942,40 @@ func TestCartReceiverService_ModifyBehaviour(t *testing.T) {
 		assert.Same(t, behaviour, mockBehaviour)
 	})
 }
+
+type (
+	// MockGuestCartServiceAdapterExpired returns an expired error for existing guest carts
+	MockGuestCartServiceAdapterExpired struct {
+		MockGuestCartServiceAdapter
+	}
+)
+
+func (m *MockGuestCartServiceAdapterExpired) GetCart(_ context.Context, cartID string) (*cartDomain.Cart, error) {
+	return nil, fmt.Errorf("%w for cart id %q", cartDomain.ErrCartExpired, cartID)
+}
+
+func TestCartReceiverService_GetCart(t *testing.T) {
+	t.Run("expired guest cart is replaced by a new one", func(t *testing.T) {
+		cs := &cartApplication.CartReceiverService{}
+		cs.Inject(
+			&MockGuestCartServiceAdapterExpired{MockGuestCartServiceAdapter{Behaviour: &cartInfrastructure.DefaultCartBehaviour{}}},
+			&MockCustomerCartService{},
+			&decorator.DecoratedCartFactory{},
+			&auth.WebIdentityService{},
+			flamingo.NullLogger{},
+			nil,
+			nil,
+		)
+
+		session := web.EmptySession().Store(cartApplication.GuestCartSessionKey, "expired_cart")
+
+		cart, behaviour, err := cs.GetCart(context.Background(), session)
+		require.NoError(t, err)
+		assert.NotNil(t, behaviour)
+		assert.Equal(t, "mock_guest_cart", cart.ID)
+
+		guestCartID, found := session.Load(cartApplication.GuestCartSessionKey)
+		assert.True(t, found)
+		assert.Equal(t, "mock_guest_cart", guestCartID)
+	})
+}
@@ -2,9 +2,14 @@ package application
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/gob"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"go.opencensus.io/trace"
 
@@ -18,6 +23,7 @@ import (
 	"flamingo.me/flamingo-commerce/v3/cart/domain/events"
 	"flamingo.me/flamingo-commerce/v3/cart/domain/placeorder"
 	"flamingo.me/flamingo-commerce/v3/cart/domain/validation"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
 	productDomain "flamingo.me/flamingo-commerce/v3/product/domain"
 )
 
@@ -37,9 +43,12 @@ type (
 		deleteEmptyDelivery bool
 		// optionals - these may be nil
 		cartValidator     validation.Validator
+		cartValidators    []validation.Validator
 		itemValidator     validation.ItemValidator
 		cartCache         CartCache
 		placeOrderService placeorder.Service
+		historyStorage    cartDomain.HistoryStorage
+		changeNotifier    cartDomain.ChangeNotifier
 	}
 
 	// RestrictionError error enriched with result of restrictions
@@ -62,6 +71,25 @@ type (
 	// QtyAdjustmentResults slice of QtyAdjustmentResult
 	QtyAdjustmentResults []QtyAdjustmentResult
 
+	// BulkAddStatus describes the outcome of a single line of a bulk add
+	BulkAddStatus string
+
+	// BulkAddResult is the result of a single line of a bulk add
+	BulkAddResult struct {
+		// Line is the 1-based position of the request in the bulk add
+		Line         int
+		DeliveryCode string
+		AddRequest   cartDomain.AddRequest
+		Status       BulkAddStatus
+		// RestrictionResult is set if the line has been rejected by the RestrictionService
+		RestrictionResult *validation.RestrictionResult
+		// Error contains the reason why the line has not been added
+		Error string
+	}
+
+	// BulkAddResults slice of BulkAddResult
+	BulkAddResults []BulkAddResult
+
 	// PromotionFunction type takes ctx, cart, couponCode and applies the promotion
 	promotionFunc func(context.Context, *cartDomain.Cart, string) (*cartDomain.Cart, cartDomain.DeferEvents, error)
 
@@ -69,7 +97,20 @@ type (
 )
 
 const (
-	itemIDKey contextKeyType = "item_id"
+	// BulkAddStatusSuccess the line has been added to the cart
+	BulkAddStatusSuccess BulkAddStatus = "success"
+	// BulkAddStatusRestricted the line exceeds the allowed qty of the RestrictionService
+	BulkAddStatusRestricted BulkAddStatus = "restricted"
+	// BulkAddStatusNotAllowed the line has been rejected by the ItemValidator with validation.AddToCartNotAllowed
+	BulkAddStatusNotAllowed BulkAddStatus = "notAllowed"
+	// BulkAddStatusFailed the line could not be added for another reason (e.g. unknown product)
+	BulkAddStatusFailed BulkAddStatus = "failed"
+)
+
+const (
+	itemIDKey           contextKeyType = "item_id"
+	expectedRevisionKey contextKeyType = "expected_revision"
+	expectedCartIDKey   contextKeyType = "expected_cart_id"
 )
 
 func ItemIDFromContext(ctx context.Context) string {
@@ -82,6 +123,25 @@ func ContextWithItemID(ctx context.Context, itemID string) context.Context {
 	return context.WithValue(ctx, itemIDKey, itemID)
 }
 
+// ContextWithExpectedRevision returns a context which makes the next cart modification fail with a
+// *cartDomain.RevisionConflictError if the current cart does not have the given revision
+func ContextWithExpectedRevision(ctx context.Context, revision int) context.Context {
+	return context.WithValue(ctx, expectedRevisionKey, revision)
+}
+
+// ContextWithExpectedCart returns a context which makes the next cart modification fail with a
+// *cartDomain.RevisionConflictError if the current cart is not the given cart or does not have the given revision
+func ContextWithExpectedCart(ctx context.Context, cartID string, revision int) context.Context {
+	return context.WithValue(ContextWithExpectedRevision(ctx, revision), expectedCartIDKey, cartID)
+}
+
+// ExpectedRevisionFromContext returns the cart revision expected by the caller, if any
+func ExpectedRevisionFromContext(ctx context.Context) (int, bool) {
+	revision, ok := ctx.Value(expectedRevisionKey).(int)
+
+	return revision, ok
+}
+
 func init() {
 	gob.Register(RestrictionError{})
 	gob.Register(QtyAdjustmentResults{})
@@ -113,10 +173,13 @@ func (cs *CartService) Inject(
 		DeleteEmptyDelivery bool   `inject:"config:commerce.cart.deleteEmptyDelivery,optional"`
 	},
 	optionals *struct {
-		CartValidator     validation.Validator     `inject:",optional"`
-		ItemValidator     validation.ItemValidator `inject:",optional"`
-		CartCache         CartCache                `inject:",optional"`
-		PlaceOrderService placeorder.Service       `inject:",optional"`
+		CartValidator     validation.Validator      `inject:",optional"`
+		CartValidators    []validation.Validator    `inject:",optional"`
+		ItemValidator     validation.ItemValidator  `inject:",optional"`
+		CartCache         CartCache                 `inject:",optional"`
+		PlaceOrderService placeorder.Service        `inject:",optional"`
+		HistoryStorage    cartDomain.HistoryStorage `inject:",optional"`
+		ChangeNotifier    cartDomain.ChangeNotifier `inject:",optional"`
 	},
 ) {
 	cs.cartReceiverService = cartReceiverService
@@ -133,9 +196,12 @@ func (cs *CartService) Inject(
 	}
 	if optionals != nil {
 		cs.cartValidator = optionals.CartValidator
+		cs.cartValidators = optionals.CartValidators
 		cs.itemValidator = optionals.ItemValidator
 		cs.cartCache = optionals.CartCache
 		cs.placeOrderService = optionals.PlaceOrderService
+		cs.historyStorage = optionals.HistoryStorage
+		cs.changeNotifier = optionals.ChangeNotifier
 	}
 }
 
@@ -144,18 +210,22 @@ func (cs *CartService) GetCartReceiverService() *CartReceiverService {
 	return cs.cartReceiverService
 }
 
-// ValidateCart validates a carts content
+// ValidateCart validates a carts content with the bound validator and all multibound validators
 func (cs *CartService) ValidateCart(ctx context.Context, session *web.Session, decoratedCart *decorator.DecoratedCart) validation.Result {
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/ValidateCart")
 	defer span.End()
 
+	results := make([]validation.Result, 0, len(cs.cartValidators)+1)
+
 	if cs.cartValidator != nil {
-		result := cs.cartValidator.Validate(ctx, session, decoratedCart)
+		results = append(results, cs.cartValidator.Validate(ctx, session, decoratedCart))
+	}
 
-		return result
+	for _, validator := range cs.cartValidators {
+		results = append(results, validator.Validate(ctx, session, decoratedCart))
 	}
 
-	return validation.Result{}
+	return validation.MergeResults(results...)
 }
 
 // ValidateCurrentCart validates the current active cart
@@ -171,12 +241,26 @@ func (cs *CartService) ValidateCurrentCart(ctx context.Context, session *web.Ses
 	return cs.ValidateCart(ctx, session, decoratedCart), nil
 }
 
+// GetItemChangeNotices compares the stored prices and the saleable state of all items of the current cart
+// with the current products and returns a notice for every changed item, e.g. to warn the customer before placing the order
+func (cs *CartService) GetItemChangeNotices(ctx context.Context, session *web.Session) ([]decorator.ItemChangeNotice, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/CartService/GetItemChangeNotices")
+	defer span.End()
+
+	decoratedCart, err := cs.cartReceiverService.ViewDecoratedCart(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return decoratedCart.ChangeNotices, nil
+}
+
 // UpdatePaymentSelection updates the paymentselection in the cart
 func (cs *CartService) UpdatePaymentSelection(ctx context.Context, session *web.Session, paymentSelection cartDomain.PaymentSelection) error {
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/UpdatePaymentSelection")
 	defer span.End()
 
-	cart, behaviour, err := cs.cartReceiverService.GetCart(ctx, session)
+	ctx, cart, behaviour, err := cs.getCartForModification(ctx, session)
 	if err != nil {
 		return err
 	}
@@ -190,6 +274,7 @@ func (cs *CartService) UpdatePaymentSelection(ctx context.Context, session *web.
 	cart, defers, err = behaviour.UpdatePaymentSelection(ctx, cart, paymentSelection)
 	if err != nil {
 		cs.handleCartNotFound(session, err)
+		cs.handleRevisionConflict(ctx, session, err)
 
 		if !errors.Is(err, context.Canceled) {
 			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "UpdatePaymentSelection").Error(err)
@@ -198,6 +283,8 @@ func (cs *CartService) UpdatePaymentSelection(ctx context.Context, session *web.
 		return err
 	}
 
+	cs.recordModification(ctx, session, cart, "UpdatePaymentSelection", paymentSelectionHistoryParameters(paymentSelection), defers)
+
 	return nil
 }
 
@@ -209,7 +296,7 @@ func (cs *CartService) UpdateBillingAddress(ctx context.Context, session *web.Se
 	if billingAddress == nil {
 		return nil
 	}
-	cart, behaviour, err := cs.cartReceiverService.GetCart(ctx, session)
+	ctx, cart, behaviour, err := cs.getCartForModification(ctx, session)
 	if err != nil {
 		return err
 	}
@@ -223,6 +310,7 @@ func (cs *CartService) UpdateBillingAddress(ctx context.Context, session *web.Se
 	cart, defers, err = behaviour.UpdateBillingAddress(ctx, cart, *billingAddress)
 	if err != nil {
 		cs.handleCartNotFound(session, err)
+		cs.handleRevisionConflict(ctx, session, err)
 
 		if !errors.Is(err, context.Canceled) {
 			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "UpdateBillingAddress").Error(err)
@@ -231,6 +319,8 @@ func (cs *CartService) UpdateBillingAddress(ctx context.Context, session *web.Se
 		return err
 	}
 
+	cs.recordModification(ctx, session, cart, "UpdateBillingAddress", map[string]string{"countryCode": billingAddress.CountryCode}, defers)
+
 	return nil
 }
 
@@ -239,7 +329,7 @@ func (cs *CartService) UpdateDeliveryInfo(ctx context.Context, session *web.Sess
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/UpdateDeliveryInfo")
 	defer span.End()
 
-	cart, behaviour, err := cs.cartReceiverService.GetCart(ctx, session)
+	ctx, cart, behaviour, err := cs.getCartForModification(ctx, session)
 	if err != nil {
 		return err
 	}
@@ -257,6 +347,7 @@ func (cs *CartService) UpdateDeliveryInfo(ctx context.Context, session *web.Sess
 	cart, defers, err = behaviour.UpdateDeliveryInfo(ctx, cart, deliveryCode, deliveryInfo)
 	if err != nil {
 		cs.handleCartNotFound(session, err)
+		cs.handleRevisionConflict(ctx, session, err)
 
 		if !errors.Is(err, context.Canceled) {
 			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "UpdateDeliveryInfo").Error(err)
@@ -265,6 +356,8 @@ func (cs *CartService) UpdateDeliveryInfo(ctx context.Context, session *web.Sess
 		return err
 	}
 
+	cs.recordModification(ctx, session, cart, "UpdateDeliveryInfo", map[string]string{"deliveryCode": deliveryCode, "method": deliveryInfo.DeliveryInfo.Method, "carrier": deliveryInfo.DeliveryInfo.Carrier}, defers)
+
 	return nil
 }
 
@@ -273,7 +366,7 @@ func (cs *CartService) UpdatePurchaser(ctx context.Context, session *web.Session
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/UpdatePurchaser")
 	defer span.End()
 
-	cart, behaviour, err := cs.cartReceiverService.GetCart(ctx, session)
+	ctx, cart, behaviour, err := cs.getCartForModification(ctx, session)
 	if err != nil {
 		return err
 	}
@@ -287,6 +380,7 @@ func (cs *CartService) UpdatePurchaser(ctx context.Context, session *web.Session
 	cart, defers, err = behaviour.UpdatePurchaser(ctx, cart, purchaser, additionalData)
 	if err != nil {
 		cs.handleCartNotFound(session, err)
+		cs.handleRevisionConflict(ctx, session, err)
 
 		if !errors.Is(err, context.Canceled) {
 			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "UpdatePurchaser").Error(err)
@@ -295,6 +389,8 @@ func (cs *CartService) UpdatePurchaser(ctx context.Context, session *web.Session
 		return err
 	}
 
+	cs.recordModification(ctx, session, cart, "UpdatePurchaser", nil, defers)
+
 	return nil
 }
 
@@ -308,7 +404,7 @@ func (cs *CartService) UpdateItemQty(ctx context.Context, session *web.Session,
 		return cs.DeleteItem(ctx, session, itemID, deliveryCode)
 	}
 
-	cart, behaviour, err := cs.cartReceiverService.GetCart(ctx, session)
+	ctx, cart, behaviour, err := cs.getCartForModification(ctx, session)
 	if err != nil {
 		return err
 	}
@@ -364,6 +460,7 @@ func (cs *CartService) UpdateItemQty(ctx context.Context, session *web.Session,
 	cart, defers, err = behaviour.UpdateItem(ctx, cart, itemUpdate)
 	if err != nil {
 		cs.handleCartNotFound(session, err)
+		cs.handleRevisionConflict(ctx, session, err)
 
 		if !errors.Is(err, context.Canceled) {
 			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "UpdateItemQty").Error(err)
@@ -383,6 +480,8 @@ func (cs *CartService) UpdateItemQty(ctx context.Context, session *web.Session,
 	}
 	defers = append(defers, updateEvent)
 
+	cs.recordModification(ctx, session, cart, "UpdateItemQty", map[string]string{"itemID": itemID, "deliveryCode": deliveryCode, "marketplaceCode": item.MarketplaceCode, "qty": strconv.Itoa(qty)}, defers)
+
 	return nil
 }
 
@@ -391,7 +490,7 @@ func (cs *CartService) UpdateItemSourceID(ctx context.Context, session *web.Sess
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/UpdateItemSourceID")
 	defer span.End()
 
-	cart, behaviour, err := cs.cartReceiverService.GetCart(ctx, session)
+	ctx, cart, behaviour, err := cs.getCartForModification(ctx, session)
 	if err != nil {
 		return err
 	}
@@ -418,6 +517,7 @@ func (cs *CartService) UpdateItemSourceID(ctx context.Context, session *web.Sess
 	cart, defers, err = behaviour.UpdateItem(ctx, cart, itemUpdate)
 	if err != nil {
 		cs.handleCartNotFound(session, err)
+		cs.handleRevisionConflict(ctx, session, err)
 
 		if !errors.Is(err, context.Canceled) {
 			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "UpdateItemSourceId").Error(err)
@@ -426,6 +526,8 @@ func (cs *CartService) UpdateItemSourceID(ctx context.Context, session *web.Sess
 		return err
 	}
 
+	cs.recordModification(ctx, session, cart, "UpdateItemSourceID", map[string]string{"itemID": itemID, "sourceID": sourceID}, defers)
+
 	return nil
 }
 
@@ -434,7 +536,7 @@ func (cs *CartService) UpdateItems(ctx context.Context, session *web.Session, up
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/UpdateItems")
 	defer span.End()
 
-	cart, behaviour, err := cs.cartReceiverService.GetCart(ctx, session)
+	ctx, cart, behaviour, err := cs.getCartForModification(ctx, session)
 	if err != nil {
 		return err
 	}
@@ -456,6 +558,7 @@ func (cs *CartService) UpdateItems(ctx context.Context, session *web.Session, up
 	cart, defers, err = behaviour.UpdateItems(ctx, cart, updateCommands)
 	if err != nil {
 		cs.handleCartNotFound(session, err)
+		cs.handleRevisionConflict(ctx, session, err)
 
 		if !errors.Is(err, context.Canceled) {
 			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "UpdateItemSourceId").Error(err)
@@ -463,6 +566,8 @@ func (cs *CartService) UpdateItems(ctx context.Context, session *web.Session, up
 		return err
 	}
 
+	cs.recordModification(ctx, session, cart, "UpdateItems", map[string]string{"itemIDs": itemUpdateIDs(updateCommands)}, defers)
+
 	return nil
 }
 
@@ -471,7 +576,7 @@ func (cs *CartService) UpdateItemBundleConfig(ctx context.Context, session *web.
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/UpdateItemBundleConfig")
 	defer span.End()
 
-	cart, behaviour, err := cs.cartReceiverService.GetCart(ctx, session)
+	ctx, cart, behaviour, err := cs.getCartForModification(ctx, session)
 	if err != nil {
 		return err
 	}
@@ -520,6 +625,7 @@ func (cs *CartService) UpdateItemBundleConfig(ctx context.Context, session *web.
 	cart, defers, err = behaviour.UpdateItem(ctx, cart, updateCommand)
 	if err != nil {
 		cs.handleCartNotFound(session, err)
+		cs.handleRevisionConflict(ctx, session, err)
 
 		if !errors.Is(err, context.Canceled) {
 			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "UpdateItemSourceId").Error(err)
@@ -527,6 +633,8 @@ func (cs *CartService) UpdateItemBundleConfig(ctx context.Context, session *web.
 		return err
 	}
 
+	cs.recordModification(ctx, session, cart, "UpdateItemBundleConfig", map[string]string{"itemID": updateCommand.ItemID}, defers)
+
 	return nil
 }
 
@@ -550,12 +658,113 @@ func (cs *CartService) getBundleWithActiveChoices(ctx context.Context, product p
 	return product, nil
 }
 
+// PreviewItemBundleConfig validates the proposed bundle configuration of an existing item and calculates the resulting row price and availability,
+// the cart is not modified. An invalid configuration is reported in the BundleConfigPreview and not returned as error.
+func (cs *CartService) PreviewItemBundleConfig(ctx context.Context, session *web.Session, updateCommand cartDomain.ItemUpdateCommand) (*BundleConfigPreview, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/CartService/PreviewItemBundleConfig")
+	defer span.End()
+
+	if updateCommand.BundleConfiguration == nil {
+		return nil, ErrBundleConfigNotProvided
+	}
+
+	cart, _, err := cs.cartReceiverService.GetCart(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := cart.GetByItemID(updateCommand.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := cart.GetDeliveryByItemID(updateCommand.ItemID)
+	if err != nil {
+		return nil, fmt.Errorf("delivery code not found by item, while previewing bundle: %w", err)
+	}
+
+	product, err := cs.productService.Get(ctx, item.MarketplaceCode)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "PreviewItemBundleConfig").Error(err)
+		}
+		return nil, err
+	}
+
+	bundleProduct, ok := product.(productDomain.BundleProduct)
+	if !ok {
+		return nil, ErrProductNotTypeBundle
+	}
+
+	currency := item.RowPriceGross.Currency()
+	preview := &BundleConfigPreview{
+		ItemID:          item.ID,
+		CurrentRowPrice: item.RowPriceGross,
+		NewRowPrice:     priceDomain.NewZero(currency),
+		Delta:           priceDomain.NewZero(currency),
+	}
+
+	bundleWithActiveChoices, err := bundleProduct.GetBundleProductWithActiveChoices(updateCommand.BundleConfiguration)
+	if err != nil {
+		preview.ValidationError = err.Error()
+		return preview, nil
+	}
+
+	if cs.itemValidator != nil {
+		decoratedCart, _ := cs.cartReceiverService.DecorateCart(ctx, cart)
+		if err := cs.itemValidator.Validate(ctx, session, decoratedCart, delivery.DeliveryInfo.Code, cartDomain.AddRequest{}, bundleWithActiveChoices); err != nil {
+			preview.ValidationError = err.Error()
+			return preview, nil
+		}
+	}
+
+	for identifier, activeChoice := range bundleWithActiveChoices.ActiveChoices {
+		preview.Choices = append(preview.Choices, BundleConfigPreviewChoice{
+			Identifier:             string(identifier),
+			MarketplaceCode:        updateCommand.BundleConfiguration[identifier].MarketplaceCode,
+			VariantMarketplaceCode: updateCommand.BundleConfiguration[identifier].VariantMarketplaceCode,
+			Qty:                    activeChoice.Qty,
+			SinglePrice:            activeChoice.Product.SaleableData().ActivePrice.GetFinalPrice(),
+		})
+	}
+
+	sort.Slice(preview.Choices, func(i, j int) bool {
+		return preview.Choices[i].Identifier < preview.Choices[j].Identifier
+	})
+
+	// the item is priced like it is added to the cart, the bundle price plus the surcharges of the item options
+	singlePrice := bundleWithActiveChoices.SaleableData().ActivePrice.GetFinalPrice()
+	for _, option := range item.Options {
+		singlePrice, err = singlePrice.Add(option.Surcharge)
+		if err != nil {
+			return nil, fmt.Errorf("error adding surcharge of item option %q: %w", option.Code, err)
+		}
+	}
+
+	preview.NewRowPrice = singlePrice.GetPayable().Multiply(item.Qty)
+
+	preview.Delta, err = preview.NewRowPrice.Sub(preview.CurrentRowPrice)
+	if err != nil {
+		return nil, fmt.Errorf("error calculating the price delta of the bundle: %w", err)
+	}
+
+	restrictionResult := cs.restrictionService.RestrictQty(ctx, session, bundleWithActiveChoices, cart, delivery.DeliveryInfo.Code)
+	preview.Available = !restrictionResult.IsRestricted || item.Qty <= restrictionResult.MaxAllowed
+	if restrictionResult.IsRestricted {
+		preview.MaxAllowedQty = restrictionResult.MaxAllowed
+	}
+
+	preview.Valid = true
+
+	return preview, nil
+}
+
 // DeleteItem in current cart
 func (cs *CartService) DeleteItem(ctx context.Context, session *web.Session, itemID string, deliveryCode string) error {
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/DeleteItem")
 	defer span.End()
 
-	cart, behaviour, err := cs.cartReceiverService.GetCart(ctx, session)
+	ctx, cart, behaviour, err := cs.getCartForModification(ctx, session)
 	if err != nil {
 		return err
 	}
@@ -584,6 +793,7 @@ func (cs *CartService) DeleteItem(ctx context.Context, session *web.Session, ite
 	cart, defers, err = behaviour.DeleteItem(ctx, cart, itemID, deliveryCode)
 	if err != nil {
 		cs.handleCartNotFound(session, err)
+		cs.handleRevisionConflict(ctx, session, err)
 
 		if !errors.Is(err, context.Canceled) {
 			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "DeleteItem").Error(fmt.Errorf("trying to delete SKU %q: %w", item.MarketplaceCode, err))
@@ -603,6 +813,148 @@ func (cs *CartService) DeleteItem(ctx context.Context, session *web.Session, ite
 	}
 	defers = append(defers, updateEvent)
 
+	cs.recordModification(ctx, session, cart, "DeleteItem", map[string]string{"itemID": itemID, "deliveryCode": deliveryCode, "marketplaceCode": item.MarketplaceCode}, defers)
+
+	return nil
+}
+
+// MoveItem moves an item of the current cart to another cart of the logged in customer (e.g. to a "saved for later" cart),
+// the delivery codes default to the default delivery code. The item is added to the target cart first and deleted from the
+// current cart afterwards, if the deletion fails the item is removed from the target cart again.
+func (cs *CartService) MoveItem(ctx context.Context, session *web.Session, itemID string, sourceDeliveryCode string, targetCartID string, targetDeliveryCode string) error {
+	ctx, span := trace.StartSpan(ctx, "cart/CartService/MoveItem")
+	defer span.End()
+
+	if sourceDeliveryCode == "" {
+		sourceDeliveryCode = cs.defaultDeliveryCode
+	}
+
+	if targetDeliveryCode == "" {
+		targetDeliveryCode = cs.defaultDeliveryCode
+	}
+
+	_, cart, _, err := cs.getCartForModification(ctx, session)
+	if err != nil {
+		return err
+	}
+
+	sourceDelivery, found := cart.GetDeliveryByCode(sourceDeliveryCode)
+	if !found {
+		return fmt.Errorf("%w: %q", cartDomain.ErrDeliveryCodeNotFound, sourceDeliveryCode)
+	}
+
+	var item *cartDomain.Item
+	for i := range sourceDelivery.Cartitems {
+		if sourceDelivery.Cartitems[i].ID == itemID {
+			item = &sourceDelivery.Cartitems[i]
+		}
+	}
+
+	if item == nil {
+		return fmt.Errorf("%w: %q in delivery %q", cartDomain.ErrItemNotFound, itemID, sourceDeliveryCode)
+	}
+
+	targetCart, targetBehaviour, err := cs.cartReceiverService.GetCustomerCartByID(ctx, targetCartID)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "MoveItem").Error(err)
+		}
+		return err
+	}
+
+	if targetCart.ID == cart.ID {
+		return errors.New("item can not be moved to the same cart")
+	}
+
+	addRequest := cartDomain.AddRequest{
+		MarketplaceCode:        item.MarketplaceCode,
+		Qty:                    item.Qty,
+		VariantMarketplaceCode: item.VariantMarketPlaceCode,
+		AdditionalData:         item.AdditionalData,
+		BundleConfiguration:    item.BundleConfig,
+		Options:                item.OptionValues(),
+	}
+
+	// the item has to pass the same checks as a newly added item of the target cart
+	product, err := cs.checkProductForAddRequest(ctx, session, targetCart, targetDeliveryCode, addRequest)
+	if err != nil {
+		cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "MoveItem").Info(err)
+		return err
+	}
+
+	err = cs.checkProductQtyRestrictions(ctx, session, product, targetCart, addRequest.Qty, targetDeliveryCode, "")
+	if err != nil {
+		cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "MoveItem").Info(err)
+		return err
+	}
+
+	targetCartBefore := targetCart
+	targetCart, defers, err := targetBehaviour.AddToCart(ctx, targetCart, targetDeliveryCode, addRequest)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "MoveItem").Error(fmt.Errorf("trying to add SKU %q to cart %q: %w", item.MarketplaceCode, targetCartID, err))
+		}
+		return err
+	}
+
+	// the expected revision refers to the current cart, it is still part of the context
+	err = cs.DeleteItem(ctx, session, itemID, sourceDeliveryCode)
+	if err != nil {
+		if rollbackErr := cs.rollbackMovedItem(ctx, targetBehaviour, targetCartBefore, targetCart, targetDeliveryCode); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+
+		return err
+	}
+
+	defers = append(defers, &events.AddToCartEvent{
+		Cart:                   targetCart,
+		MarketplaceCode:        item.MarketplaceCode,
+		VariantMarketplaceCode: item.VariantMarketPlaceCode,
+		ProductName:            item.ProductName,
+		Qty:                    item.Qty,
+	})
+	cs.dispatchAllEvents(ctx, defers)
+	cs.recordModification(ctx, session, targetCart, "MoveItem", map[string]string{"itemID": itemID, "marketplaceCode": item.MarketplaceCode, "sourceCartID": cart.ID}, defers)
+
+	return nil
+}
+
+// rollbackMovedItem removes an item added by MoveItem from the target cart again, the item was either added as
+// new item or merged into an existing item of the delivery (see LineItemStrategy)
+func (cs *CartService) rollbackMovedItem(ctx context.Context, behaviour cartDomain.ModifyBehaviour, before *cartDomain.Cart, after *cartDomain.Cart, deliveryCode string) error {
+	afterDelivery, found := after.GetDeliveryByCode(deliveryCode)
+	if !found {
+		return nil
+	}
+
+	qtyBefore := make(map[string]int)
+	if beforeDelivery, found := before.GetDeliveryByCode(deliveryCode); found {
+		for _, item := range beforeDelivery.Cartitems {
+			qtyBefore[item.ID] = item.Qty
+		}
+	}
+
+	// every modification stores a new revision, the next one has to be based on it
+	cart := after
+	for _, item := range afterDelivery.Cartitems {
+		qty, existed := qtyBefore[item.ID]
+		if existed && item.Qty == qty {
+			continue
+		}
+
+		var err error
+		if existed {
+			cart, _, err = behaviour.UpdateItem(ctx, cart, cartDomain.ItemUpdateCommand{ItemID: item.ID, Qty: &qty})
+		} else {
+			cart, _, err = behaviour.DeleteItem(ctx, cart, item.ID, deliveryCode)
+		}
+
+		if err != nil {
+			return fmt.Errorf("item %q is in both carts, removing it from cart %q failed: %w", item.MarketplaceCode, after.ID, err)
+		}
+	}
+
 	return nil
 }
 
@@ -611,7 +963,7 @@ func (cs *CartService) DeleteAllItems(ctx context.Context, session *web.Session)
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/DeleteAllItems")
 	defer span.End()
 
-	cart, behaviour, err := cs.cartReceiverService.GetCart(ctx, session)
+	ctx, cart, behaviour, err := cs.getCartForModification(ctx, session)
 	if err != nil {
 		return err
 	}
@@ -640,6 +992,7 @@ func (cs *CartService) DeleteAllItems(ctx context.Context, session *web.Session)
 			cart, defers, err = behaviour.DeleteItem(ctx, cart, item.ID, delivery.DeliveryInfo.Code)
 			if err != nil {
 				cs.handleCartNotFound(session, err)
+				cs.handleRevisionConflict(ctx, session, err)
 
 				if !errors.Is(err, context.Canceled) {
 					cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "DeleteAllItems").Error(err)
@@ -651,6 +1004,8 @@ func (cs *CartService) DeleteAllItems(ctx context.Context, session *web.Session)
 	// append deferred events of behaviour with changed qty events
 	defers = append(defers, deleteItemEvents)
 
+	cs.recordModification(ctx, session, cart, "DeleteAllItems", nil, defers)
+
 	return nil
 }
 
@@ -659,7 +1014,7 @@ func (cs *CartService) CompleteCurrentCart(ctx context.Context) (*cartDomain.Car
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/CompleteCurrentCart")
 	defer span.End()
 
-	cart, behaviour, err := cs.cartReceiverService.GetCart(ctx, web.SessionFromContext(ctx))
+	ctx, cart, behaviour, err := cs.getCartForModification(ctx, web.SessionFromContext(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -679,6 +1034,7 @@ func (cs *CartService) CompleteCurrentCart(ctx context.Context) (*cartDomain.Car
 	completedCart, defers, err = completeBehaviour.Complete(ctx, cart)
 	if err != nil {
 		cs.handleCartNotFound(web.SessionFromContext(ctx), err)
+		cs.handleRevisionConflict(ctx, web.SessionFromContext(ctx), err)
 
 		if !errors.Is(err, context.Canceled) {
 			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "CloseCurrentCart").Error(err)
@@ -692,6 +1048,8 @@ func (cs *CartService) CompleteCurrentCart(ctx context.Context) (*cartDomain.Car
 		session.Delete(GuestCartSessionKey)
 	}
 
+	cs.recordModification(ctx, web.SessionFromContext(ctx), completedCart, "CompleteCurrentCart", nil, defers)
+
 	return completedCart, nil
 }
 
@@ -723,6 +1081,7 @@ func (cs *CartService) RestoreCart(ctx context.Context, cart *cartDomain.Cart) (
 
 	if err != nil {
 		cs.handleCartNotFound(web.SessionFromContext(ctx), err)
+		cs.handleRevisionConflict(ctx, web.SessionFromContext(ctx), err)
 
 		if !errors.Is(err, context.Canceled) {
 			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "RestoreCart").Error(err)
@@ -734,6 +1093,8 @@ func (cs *CartService) RestoreCart(ctx context.Context, cart *cartDomain.Cart) (
 		session.Store(GuestCartSessionKey, restoredCart.ID)
 	}
 
+	cs.recordModification(ctx, session, restoredCart, "RestoreCart", nil, defers)
+
 	return restoredCart, nil
 }
 
@@ -742,7 +1103,7 @@ func (cs *CartService) Clean(ctx context.Context, session *web.Session) error {
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/Clean")
 	defer span.End()
 
-	cart, behaviour, err := cs.cartReceiverService.GetCart(ctx, session)
+	ctx, cart, behaviour, err := cs.getCartForModification(ctx, session)
 	if err != nil {
 		return err
 	}
@@ -780,6 +1141,8 @@ func (cs *CartService) Clean(ctx context.Context, session *web.Session) error {
 	// append deferred events of behaviour with changed qty events for every deleted item
 	defers = append(defers, deleteItemEvents)
 
+	cs.recordModification(ctx, session, cart, "Clean", nil, defers)
+
 	return nil
 }
 
@@ -788,7 +1151,7 @@ func (cs *CartService) DeleteDelivery(ctx context.Context, session *web.Session,
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/DeleteDelivery")
 	defer span.End()
 
-	cart, behaviour, err := cs.cartReceiverService.GetCart(ctx, session)
+	ctx, cart, behaviour, err := cs.getCartForModification(ctx, session)
 	if err != nil {
 		return nil, err
 	}
@@ -829,6 +1192,8 @@ func (cs *CartService) DeleteDelivery(ctx context.Context, session *web.Session,
 	// append deferred events of behaviour with changed qty events for every deleted item
 	defers = append(defers, deleteItemEvents)
 
+	cs.recordModification(ctx, session, cart, "DeleteDelivery", map[string]string{"deliveryCode": deliveryCode}, defers)
+
 	return cart, nil
 }
 
@@ -859,7 +1224,7 @@ func (cs *CartService) AddProduct(ctx context.Context, session *web.Session, del
 		deliveryCode = cs.defaultDeliveryCode
 	}
 
-	cart, behaviour, err := cs.cartReceiverService.GetCart(ctx, session)
+	ctx, cart, behaviour, err := cs.getCartForModification(ctx, session)
 	if err != nil {
 		if !errors.Is(err, context.Canceled) {
 			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "AddProduct").Error(err)
@@ -907,6 +1272,7 @@ func (cs *CartService) AddProduct(ctx context.Context, session *web.Session, del
 	cart, defers, err = behaviour.AddToCart(ctx, cart, deliveryCode, addRequest)
 	if err != nil {
 		cs.handleCartNotFound(session, err)
+		cs.handleRevisionConflict(ctx, session, err)
 
 		if !errors.Is(err, context.Canceled) {
 			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "AddProduct").Error(err)
@@ -925,15 +1291,173 @@ func (cs *CartService) AddProduct(ctx context.Context, session *web.Session, del
 	}
 	defers = append(defers, addToCart)
 
+	cs.recordModification(ctx, session, cart, "AddProduct", map[string]string{"deliveryCode": deliveryCode, "marketplaceCode": addRequest.MarketplaceCode, "variantMarketplaceCode": addRequest.VariantMarketplaceCode, "qty": strconv.Itoa(addRequest.Qty)}, defers)
+
 	return product, nil
 }
 
+// AddProductsBulk checks all add requests and adds the valid ones with a single modification of the cart if the
+// behaviour implements cartDomain.BulkAddBehaviour. The result contains the outcome of every request in the given order.
+// Otherwise the items are added one by one, this is not atomic: if an item can't be added, the items added before stay in the cart.
+func (cs *CartService) AddProductsBulk(ctx context.Context, session *web.Session, addRequests []cartDomain.DeliveryAddRequest) (BulkAddResults, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/CartService/AddProductsBulk")
+	defer span.End()
+
+	ctx, cart, behaviour, err := cs.getCartForModification(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	var defers cartDomain.DeferEvents
+	defer func() {
+		cs.updateCartInCacheIfCacheIsEnabled(ctx, session, cart)
+		cs.dispatchAllEvents(ctx, defers)
+	}()
+
+	results := make(BulkAddResults, len(addRequests))
+	products := make(map[int]productDomain.BasicProduct)
+	accepted := make([]cartDomain.DeliveryAddRequest, 0, len(addRequests))
+	acceptedLines := make([]int, 0, len(addRequests))
+	// qty of the already accepted lines, restrictions only know the qty of the current cart
+	acceptedQty := make(map[string]int)
+
+	for i, addRequest := range addRequests {
+		if addRequest.DeliveryCode == "" {
+			addRequest.DeliveryCode = cs.defaultDeliveryCode
+		}
+
+		if addRequest.AddRequest.Qty < 0 {
+			addRequest.AddRequest.Qty = 0
+		}
+
+		results[i] = BulkAddResult{
+			Line:         i + 1,
+			DeliveryCode: addRequest.DeliveryCode,
+			AddRequest:   addRequest.AddRequest,
+		}
+
+		product, err := cs.checkProductForAddRequest(ctx, session, cart, addRequest.DeliveryCode, addRequest.AddRequest)
+		if err != nil {
+			results[i].Status = BulkAddStatusFailed
+			var notAllowedErr *validation.AddToCartNotAllowed
+			if errors.As(err, &notAllowedErr) {
+				results[i].Status = BulkAddStatusNotAllowed
+			}
+
+			results[i].Error = err.Error()
+
+			continue
+		}
+
+		key := addRequest.DeliveryCode + "|" + addRequest.AddRequest.MarketplaceCode + "|" + addRequest.AddRequest.VariantMarketplaceCode
+		err = cs.checkProductQtyRestrictions(ctx, session, product, cart, addRequest.AddRequest.Qty+acceptedQty[key], addRequest.DeliveryCode, "")
+		if err != nil {
+			results[i].Status = BulkAddStatusFailed
+			var restrictionErr *RestrictionError
+			if errors.As(err, &restrictionErr) {
+				results[i].Status = BulkAddStatusRestricted
+				results[i].RestrictionResult = &restrictionErr.RestrictionResult
+			}
+
+			results[i].Error = err.Error()
+
+			continue
+		}
+
+		acceptedQty[key] += addRequest.AddRequest.Qty
+		products[i] = product
+		accepted = append(accepted, addRequest)
+		acceptedLines = append(acceptedLines, i)
+	}
+
+	if len(accepted) == 0 {
+		return results, nil
+	}
+
+	// missing deliveries are created together with the items to keep a single modification of the cart
+	initialDeliveryInfos := make(map[string]*cartDomain.DeliveryInfo)
+	for i, addRequest := range accepted {
+		if cart.HasDeliveryForCode(addRequest.DeliveryCode) {
+			continue
+		}
+
+		if _, ok := initialDeliveryInfos[addRequest.DeliveryCode]; !ok {
+			initialDeliveryInfos[addRequest.DeliveryCode], err = cs.deliveryInfoBuilder.BuildByDeliveryCode(addRequest.DeliveryCode)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		accepted[i].InitialDeliveryInfo = initialDeliveryInfos[addRequest.DeliveryCode]
+	}
+
+	cart, defers, err = cs.addToCartBulk(ctx, cart, behaviour, accepted)
+	if err != nil {
+		cs.handleCartNotFound(session, err)
+		cs.handleRevisionConflict(ctx, session, err)
+
+		if !errors.Is(err, context.Canceled) {
+			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "AddProductsBulk").Error(err)
+		}
+
+		return nil, err
+	}
+
+	for _, line := range acceptedLines {
+		results[line].Status = BulkAddStatusSuccess
+
+		defers = append(defers, &events.AddToCartEvent{
+			Cart:                   cart,
+			MarketplaceCode:        results[line].AddRequest.MarketplaceCode,
+			VariantMarketplaceCode: results[line].AddRequest.VariantMarketplaceCode,
+			ProductName:            products[line].TeaserData().ShortTitle,
+			Qty:                    results[line].AddRequest.Qty,
+		})
+	}
+
+	cs.recordModification(ctx, session, cart, "AddProductsBulk", map[string]string{"lines": strconv.Itoa(len(accepted))}, defers)
+
+	return results, nil
+}
+
+// addToCartBulk uses the BulkAddBehaviour if available and falls back to adding the items one by one, in this case
+// the cart with the items added before a failure is returned together with the error since they are already stored
+func (cs *CartService) addToCartBulk(ctx context.Context, cart *cartDomain.Cart, behaviour cartDomain.ModifyBehaviour, addRequests []cartDomain.DeliveryAddRequest) (*cartDomain.Cart, cartDomain.DeferEvents, error) {
+	if bulkBehaviour, ok := behaviour.(cartDomain.BulkAddBehaviour); ok {
+		return bulkBehaviour.AddToCartBulk(ctx, cart, addRequests)
+	}
+
+	var defers cartDomain.DeferEvents
+
+	for _, addRequest := range addRequests {
+		if !cart.HasDeliveryForCode(addRequest.DeliveryCode) && addRequest.InitialDeliveryInfo != nil {
+			newCart, deliveryDefers, err := behaviour.UpdateDeliveryInfo(ctx, cart, addRequest.DeliveryCode, cartDomain.DeliveryInfoUpdateCommand{DeliveryInfo: *addRequest.InitialDeliveryInfo})
+			if err != nil {
+				return cart, defers, err
+			}
+
+			cart = newCart
+			defers = append(defers, deliveryDefers...)
+		}
+
+		newCart, itemDefers, err := behaviour.AddToCart(ctx, cart, addRequest.DeliveryCode, addRequest.AddRequest)
+		if err != nil {
+			return cart, defers, err
+		}
+
+		cart = newCart
+		defers = append(defers, itemDefers...)
+	}
+
+	return cart, defers, nil
+}
+
 // CreateInitialDeliveryIfNotPresent creates the initial delivery
 func (cs *CartService) CreateInitialDeliveryIfNotPresent(ctx context.Context, session *web.Session, deliveryCode string) (*cartDomain.Cart, error) {
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/CreateInitialDeliveryIfNotPresent")
 	defer span.End()
 
-	cart, behaviour, err := cs.cartReceiverService.GetCart(ctx, session)
+	ctx, cart, behaviour, err := cs.getCartForModification(ctx, session)
 	if err != nil {
 		return nil, err
 	}
@@ -957,6 +1481,10 @@ func (cs *CartService) CreateInitialDeliveryIfNotPresent(ctx context.Context, se
 		cs.dispatchAllEvents(ctx, defers)
 	}()
 
+	if err == nil {
+		cs.recordModification(ctx, session, updatedCart, "CreateInitialDeliveryIfNotPresent", map[string]string{"deliveryCode": deliveryCode}, defers)
+	}
+
 	return updatedCart, err
 }
 
@@ -974,7 +1502,7 @@ func (cs *CartService) ApplyVoucher(ctx context.Context, session *web.Session, c
 	if err != nil {
 		return nil, err
 	}
-	return cs.executeVoucherBehaviour(ctx, session, cart, couponCode, behaviour.ApplyVoucher)
+	return cs.executeVoucherBehaviour(ctx, session, cart, behaviour, couponCode, "ApplyVoucher", behaviour.ApplyVoucher)
 }
 
 // ApplyAny applies a voucher or giftcard to the cart
@@ -987,7 +1515,7 @@ func (cs *CartService) ApplyAny(ctx context.Context, session *web.Session, anyCo
 		return nil, err
 	}
 	if giftCardAndVoucherBehaviour, ok := behaviour.(cartDomain.GiftCardAndVoucherBehaviour); ok {
-		return cs.executeVoucherBehaviour(ctx, session, cart, anyCode, giftCardAndVoucherBehaviour.ApplyAny)
+		return cs.executeVoucherBehaviour(ctx, session, cart, behaviour, anyCode, "ApplyAny", giftCardAndVoucherBehaviour.ApplyAny)
 	}
 	return nil, errors.New("ApplyAny not supported")
 }
@@ -1001,7 +1529,7 @@ func (cs *CartService) RemoveVoucher(ctx context.Context, session *web.Session,
 	if err != nil {
 		return nil, err
 	}
-	return cs.executeVoucherBehaviour(ctx, session, cart, couponCode, behaviour.RemoveVoucher)
+	return cs.executeVoucherBehaviour(ctx, session, cart, behaviour, couponCode, "RemoveVoucher", behaviour.RemoveVoucher)
 }
 
 // ApplyGiftCard adds a giftcard to the cart
@@ -1014,7 +1542,7 @@ func (cs *CartService) ApplyGiftCard(ctx context.Context, session *web.Session,
 		return nil, err
 	}
 	if giftCartBehaviour, ok := behaviour.(cartDomain.GiftCardBehaviour); ok {
-		return cs.executeVoucherBehaviour(ctx, session, cart, couponCode, giftCartBehaviour.ApplyGiftCard)
+		return cs.executeVoucherBehaviour(ctx, session, cart, behaviour, couponCode, "ApplyGiftCard", giftCartBehaviour.ApplyGiftCard)
 	}
 	return nil, errors.New("ApplyGiftCard not supported")
 }
@@ -1029,7 +1557,7 @@ func (cs *CartService) RemoveGiftCard(ctx context.Context, session *web.Session,
 		return nil, err
 	}
 	if giftCartBehaviour, ok := behaviour.(cartDomain.GiftCardBehaviour); ok {
-		return cs.executeVoucherBehaviour(ctx, session, cart, couponCode, giftCartBehaviour.RemoveGiftCard)
+		return cs.executeVoucherBehaviour(ctx, session, cart, behaviour, couponCode, "RemoveGiftCard", giftCartBehaviour.RemoveGiftCard)
 	}
 	return nil, errors.New("RemoveGiftCard not supported")
 }
@@ -1039,7 +1567,7 @@ func (cs *CartService) getCartAndBehaviour(ctx context.Context, session *web.Ses
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/getCartAndBehaviour")
 	defer span.End()
 
-	cart, behaviour, err := cs.cartReceiverService.GetCart(ctx, session)
+	_, cart, behaviour, err := cs.getCartForModification(ctx, session)
 	if err != nil {
 		if !errors.Is(err, context.Canceled) {
 			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, logKey).Error(err)
@@ -1050,9 +1578,41 @@ func (cs *CartService) getCartAndBehaviour(ctx context.Context, session *web.Ses
 	return cart, behaviour, nil
 }
 
+// getCartForModification returns the current cart and behaviour and verifies the revision expected by the caller.
+// The returned context no longer carries the expected revision, so that nested modifications are not rejected.
+func (cs *CartService) getCartForModification(ctx context.Context, session *web.Session) (context.Context, *cartDomain.Cart, cartDomain.ModifyBehaviour, error) {
+	cart, behaviour, err := cs.cartReceiverService.GetCart(ctx, session)
+	if err != nil {
+		return ctx, nil, nil, err
+	}
+
+	expectedRevision, ok := ExpectedRevisionFromContext(ctx)
+	if !ok {
+		return ctx, cart, behaviour, nil
+	}
+
+	// revisions of different carts are not comparable, e.g. after switching the named cart
+	expectedCartID, _ := ctx.Value(expectedCartIDKey).(string)
+
+	if cart.Revision != expectedRevision || (expectedCartID != "" && expectedCartID != cart.ID) {
+		// the cached cart might be outdated, make sure the next request gets the stored one
+		cs.DeleteCartInCache(ctx, session, cart)
+
+		return ctx, nil, nil, &cartDomain.RevisionConflictError{
+			CartID:           cart.ID,
+			ExpectedRevision: expectedRevision,
+			ActualRevision:   cart.Revision,
+		}
+	}
+
+	ctx = context.WithValue(ctx, expectedRevisionKey, nil)
+
+	return context.WithValue(ctx, expectedCartIDKey, nil), cart, behaviour, nil
+}
+
 // Executes provided behaviour regarding vouchers, this function serves to reduce duplicated code
 // for voucher / giftcard behaviour as their internal logic is basically the same
-func (cs *CartService) executeVoucherBehaviour(ctx context.Context, session *web.Session, cart *cartDomain.Cart, couponCode string, fn promotionFunc) (*cartDomain.Cart, error) {
+func (cs *CartService) executeVoucherBehaviour(ctx context.Context, session *web.Session, cart *cartDomain.Cart, behaviour cartDomain.ModifyBehaviour, couponCode string, operation string, fn promotionFunc) (*cartDomain.Cart, error) {
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/executeVoucherBehaviour")
 	defer span.End()
 
@@ -1063,6 +1623,10 @@ func (cs *CartService) executeVoucherBehaviour(ctx context.Context, session *web
 		cs.dispatchAllEvents(ctx, defers)
 	}()
 	cart, defers, err := fn(ctx, cart, couponCode)
+	if err == nil {
+		cs.recordModification(ctx, session, cart, operation, map[string]string{"code": historyCode(operation, couponCode)}, defers)
+	}
+
 	return cart, err
 }
 
@@ -1072,6 +1636,14 @@ func (cs *CartService) handleCartNotFound(session *web.Session, err error) {
 	}
 }
 
+// handleRevisionConflict removes a potentially outdated cart from the cache
+func (cs *CartService) handleRevisionConflict(ctx context.Context, session *web.Session, err error) {
+	var conflictErr *cartDomain.RevisionConflictError
+	if errors.As(err, &conflictErr) {
+		cs.DeleteCartInCache(ctx, session, nil)
+	}
+}
+
 // checkProductForAddRequest existence and validate with productService
 func (cs *CartService) checkProductForAddRequest(ctx context.Context, session *web.Session, cart *cartDomain.Cart, deliveryCode string, addRequest cartDomain.AddRequest) (productDomain.BasicProduct, error) {
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/checkProductForAddRequest")
@@ -1112,6 +1684,10 @@ func (cs *CartService) checkProductForAddRequest(ctx context.Context, session *w
 		}
 	}
 
+	if _, err := cartDomain.CreateItemOptions(product.BaseData(), addRequest.Options); err != nil {
+		return nil, err
+	}
+
 	// Now Validate the Item with the optional registered ItemValidator
 	if cs.itemValidator != nil {
 		decoratedCart, _ := cs.cartReceiverService.DecorateCart(ctx, cart)
@@ -1210,7 +1786,7 @@ func (cs *CartService) reserveOrderIDAndSave(ctx context.Context, session *web.S
 	if cs.placeOrderService == nil {
 		return nil, errors.New("No placeOrderService registered")
 	}
-	cart, behaviour, err := cs.cartReceiverService.GetCart(ctx, session)
+	ctx, cart, behaviour, err := cs.getCartForModification(ctx, session)
 	if err != nil {
 		return nil, err
 	}
@@ -1233,6 +1809,10 @@ func (cs *CartService) reserveOrderIDAndSave(ctx context.Context, session *web.S
 		cs.updateCartInCacheIfCacheIsEnabled(ctx, session, cart)
 		cs.dispatchAllEvents(ctx, defers)
 	}()
+	if err == nil {
+		cs.recordModification(ctx, session, cart, "ReserveOrderID", map[string]string{"reservedOrderID": reservedOrderID}, defers)
+	}
+
 	return cart, err
 }
 
@@ -1336,7 +1916,8 @@ func (cs *CartService) cancelOrder(ctx context.Context, _ *web.Session, orderInf
 	return nil
 }
 
-// CancelOrderWithoutRestore cancels a previously placed order
+// CancelOrderWithoutRestore cancels a previously placed order, what has been reserved when the cart was completed
+// stays reserved. Use CancelOrderAndReleaseCart to release it.
 func (cs *CartService) CancelOrderWithoutRestore(ctx context.Context, session *web.Session, orderInfos placeorder.PlacedOrderInfos) error {
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/CancelOrderWithoutRestore")
 	defer span.End()
@@ -1344,6 +1925,39 @@ func (cs *CartService) CancelOrderWithoutRestore(ctx context.Context, session *w
 	return cs.cancelOrder(ctx, session, orderInfos)
 }
 
+// CancelOrderAndReleaseCart cancels a previously placed order without restoring the cart, but releases what has been
+// reserved when the cart was completed (e.g. gift card amounts) if the behaviour implements cartDomain.ReleaseBehaviour
+func (cs *CartService) CancelOrderAndReleaseCart(ctx context.Context, session *web.Session, orderInfos placeorder.PlacedOrderInfos, cart cartDomain.Cart) error {
+	ctx, span := trace.StartSpan(ctx, "cart/CartService/CancelOrderAndReleaseCart")
+	defer span.End()
+
+	err := cs.cancelOrder(ctx, session, orderInfos)
+	if err != nil {
+		return err
+	}
+
+	behaviour, err := cs.cartReceiverService.ModifyBehaviour(ctx)
+	if err != nil {
+		return err
+	}
+
+	releaseBehaviour, ok := behaviour.(cartDomain.ReleaseBehaviour)
+	if !ok {
+		return nil
+	}
+
+	err = releaseBehaviour.Release(ctx, &cart)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "CancelOrderAndReleaseCart").Error(err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
 // GetDefaultDeliveryCode returns the configured default deliverycode
 func (cs *CartService) GetDefaultDeliveryCode() string {
 	return cs.defaultDeliveryCode
@@ -1389,6 +2003,102 @@ func (cs *CartService) dispatchAllEvents(ctx context.Context, events []flamingo.
 	}
 }
 
+// recordModification records the stored modification of the cart in its history and notifies the subscribed clients
+func (cs *CartService) recordModification(ctx context.Context, session *web.Session, cart *cartDomain.Cart, operation string, parameters map[string]string, defers cartDomain.DeferEvents) {
+	cs.recordHistory(ctx, session, cart, operation, parameters, defers)
+	cs.publishChange(ctx, cart, operation)
+}
+
+// publishChange notifies the clients subscribed to the cart or its customer if a ChangeNotifier is bound
+func (cs *CartService) publishChange(ctx context.Context, cart *cartDomain.Cart, operation string) {
+	if cs.changeNotifier == nil || cart == nil {
+		return
+	}
+
+	err := cs.changeNotifier.Publish(ctx, cartDomain.NewChangeNotification(cart, operation))
+	if err != nil && !errors.Is(err, context.Canceled) {
+		cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "publishChange").Error(err)
+	}
+}
+
+// recordHistory appends the modification of the cart to its history if a HistoryStorage is bound
+func (cs *CartService) recordHistory(ctx context.Context, session *web.Session, cart *cartDomain.Cart, operation string, parameters map[string]string, defers cartDomain.DeferEvents) {
+	if cs.historyStorage == nil || cart == nil {
+		return
+	}
+
+	entry := cartDomain.NewHistoryEntry(cart, operation, parameters, historyActor(session, cart), defers)
+
+	err := cs.historyStorage.AppendEntry(ctx, entry)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		cs.logger.WithContext(ctx).WithField(flamingo.LogKeySubCategory, "recordHistory").Error(err)
+	}
+}
+
+// historyActor returns the customer of the cart or the anonymized session of guest carts
+func historyActor(session *web.Session, cart *cartDomain.Cart) cartDomain.HistoryActor {
+	if cart.BelongsToAuthenticatedUser {
+		return cartDomain.HistoryActor{Type: cartDomain.HistoryActorCustomer, ID: cart.AuthenticatedUserID}
+	}
+
+	actor := cartDomain.HistoryActor{Type: cartDomain.HistoryActorGuest}
+	if session != nil {
+		// the session id must not be exposed, a hash is sufficient to tell sessions apart
+		hash := sha256.Sum256([]byte(session.ID()))
+		actor.ID = hex.EncodeToString(hash[:8])
+	}
+
+	return actor
+}
+
+func paymentSelectionHistoryParameters(paymentSelection cartDomain.PaymentSelection) map[string]string {
+	if paymentSelection == nil {
+		return nil
+	}
+
+	methods := make([]string, 0, 1)
+	for qualifier := range paymentSelection.CartSplit() {
+		methods = append(methods, qualifier.Method)
+	}
+
+	sort.Strings(methods)
+
+	return map[string]string{"gateway": paymentSelection.Gateway(), "methods": strings.Join(methods, ",")}
+}
+
+func itemUpdateIDs(updateCommands []cartDomain.ItemUpdateCommand) string {
+	itemIDs := make([]string, 0, len(updateCommands))
+	for _, command := range updateCommands {
+		itemIDs = append(itemIDs, command.ItemID)
+	}
+
+	return strings.Join(itemIDs, ",")
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// historyCode masks gift card codes which are as good as money, voucher codes are recorded as they are
+func historyCode(operation string, code string) string {
+	if operation == "ApplyVoucher" || operation == "RemoveVoucher" {
+		return code
+	}
+
+	if len(code) <= 4 {
+		return strings.Repeat("*", len(code))
+	}
+
+	return strings.Repeat("*", len(code)-4) + code[len(code)-4:]
+}
+
 // AdjustItemsToRestrictedQty checks the quantity restrictions for each item of the cart and returns what quantities have been adjusted
 func (cs *CartService) AdjustItemsToRestrictedQty(ctx context.Context, session *web.Session) (QtyAdjustmentResults, error) {
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/AdjustItemsToRestrictedQty")
@@ -1399,7 +2109,7 @@ func (cs *CartService) AdjustItemsToRestrictedQty(ctx context.Context, session *
 		return nil, err
 	}
 
-	cart, _, err := cs.cartReceiverService.GetCart(ctx, session)
+	ctx, cart, _, err := cs.getCartForModification(ctx, session)
 	if err != nil {
 		return nil, err
 	}
@@ -1539,6 +2249,10 @@ func (cs *CartService) UpdateAdditionalData(ctx context.Context, session *web.Se
 		cs.updateCartInCacheIfCacheIsEnabled(ctx, session, cart)
 		cs.dispatchAllEvents(ctx, defers)
 	}()
+	if err == nil {
+		cs.recordModification(ctx, session, cart, "UpdateAdditionalData", map[string]string{"keys": strings.Join(sortedKeys(additionalData), ",")}, defers)
+	}
+
 	return cart, err
 }
 
@@ -1547,7 +2261,7 @@ func (cs *CartService) UpdateDeliveryAdditionalData(ctx context.Context, session
 	ctx, span := trace.StartSpan(ctx, "cart/CartService/UpdateDeliveryAdditionalData")
 	defer span.End()
 
-	cart, _, err := cs.cartReceiverService.GetCart(ctx, session)
+	ctx, cart, _, err := cs.getCartForModification(ctx, session)
 	if err != nil {
 		return nil, err
 	}
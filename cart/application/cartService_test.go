@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"flamingo.me/flamingo/v3/core/auth"
+	authMock "flamingo.me/flamingo/v3/core/auth/mock"
 	"github.com/go-test/deep"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -27,6 +28,7 @@ import (
 
 	cartApplication "flamingo.me/flamingo-commerce/v3/cart/application"
 	cartDomain "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
 	productDomain "flamingo.me/flamingo-commerce/v3/product/domain"
 )
 
@@ -69,6 +71,7 @@ func TestCartService_DeleteSavedSessionGuestCartID(t *testing.T) {
 							result.Inject(
 								&MockProductService{},
 								flamingo.NullLogger{},
+								nil,
 							)
 
 							return result
@@ -177,6 +180,7 @@ func TestCartService_AdjustItemsToRestrictedQty(t *testing.T) {
 							result.Inject(
 								&MockProductService{},
 								flamingo.NullLogger{},
+								nil,
 							)
 
 							return result
@@ -234,6 +238,7 @@ func TestCartService_AdjustItemsToRestrictedQty(t *testing.T) {
 							result.Inject(
 								&MockProductService{},
 								flamingo.NullLogger{},
+								nil,
 							)
 
 							return result
@@ -307,6 +312,7 @@ func TestCartService_AdjustItemsToRestrictedQty(t *testing.T) {
 							result.Inject(
 								&MockProductService{},
 								flamingo.NullLogger{},
+								nil,
 							)
 
 							return result
@@ -435,6 +441,7 @@ func TestCartService_ReserveOrderIDAndSave(t *testing.T) {
 							result.Inject(
 								&MockProductService{},
 								flamingo.NullLogger{},
+								nil,
 							)
 
 							return result
@@ -485,6 +492,7 @@ func TestCartService_ReserveOrderIDAndSave(t *testing.T) {
 							result.Inject(
 								&MockProductService{},
 								flamingo.NullLogger{},
+								nil,
 							)
 
 							return result
@@ -539,9 +547,12 @@ func TestCartService_ReserveOrderIDAndSave(t *testing.T) {
 				tt.fields.config,
 				&struct {
 					CartValidator     validation.Validator      `inject:",optional"`
+					CartValidators    []validation.Validator    `inject:",optional"`
 					ItemValidator     validation.ItemValidator  `inject:",optional"`
 					CartCache         cartApplication.CartCache `inject:",optional"`
 					PlaceOrderService placeorder.Service        `inject:",optional"`
+					HistoryStorage    cartDomain.HistoryStorage `inject:",optional"`
+					ChangeNotifier    cartDomain.ChangeNotifier `inject:",optional"`
 				}{
 					PlaceOrderService: tt.fields.PlaceOrderService,
 				},
@@ -598,6 +609,7 @@ func TestCartService_ForceReserveOrderIDAndSave(t *testing.T) {
 							result.Inject(
 								&MockProductService{},
 								flamingo.NullLogger{},
+								nil,
 							)
 
 							return result
@@ -648,6 +660,7 @@ func TestCartService_ForceReserveOrderIDAndSave(t *testing.T) {
 							result.Inject(
 								&MockProductService{},
 								flamingo.NullLogger{},
+								nil,
 							)
 
 							return result
@@ -702,9 +715,12 @@ func TestCartService_ForceReserveOrderIDAndSave(t *testing.T) {
 				tt.fields.config,
 				&struct {
 					CartValidator     validation.Validator      `inject:",optional"`
+					CartValidators    []validation.Validator    `inject:",optional"`
 					ItemValidator     validation.ItemValidator  `inject:",optional"`
 					CartCache         cartApplication.CartCache `inject:",optional"`
 					PlaceOrderService placeorder.Service        `inject:",optional"`
+					HistoryStorage    cartDomain.HistoryStorage `inject:",optional"`
+					ChangeNotifier    cartDomain.ChangeNotifier `inject:",optional"`
 				}{
 					PlaceOrderService: tt.fields.PlaceOrderService,
 				},
@@ -772,6 +788,7 @@ func (m *MockGuestCartServiceWithModifyBehaviour) GetModifyBehaviour(context.Con
 		nil,
 		nil,
 		nil,
+		nil,
 	)
 
 	return cob, nil
@@ -931,6 +948,7 @@ func TestCartService_CartInEvent(t *testing.T) {
 				result.Inject(
 					&MockProductService{},
 					flamingo.NullLogger{},
+					nil,
 				)
 
 				return result
@@ -1001,6 +1019,10 @@ func TestCartService_CartInEvent(t *testing.T) {
 }
 
 func createCartServiceWithDependencies() *cartApplication.CartService {
+	return createCartServiceWithObservers(nil, nil)
+}
+
+func createCartServiceWithObservers(historyStorage cartDomain.HistoryStorage, changeNotifier cartDomain.ChangeNotifier) *cartApplication.CartService {
 	eventRouter := new(MockEventRouter)
 	eventRouter.On("Dispatch", mock.Anything, mock.Anything, mock.Anything).Return()
 	cartCache := new(MockCartCache)
@@ -1013,6 +1035,7 @@ func createCartServiceWithDependencies() *cartApplication.CartService {
 			result.Inject(
 				&MockProductService{},
 				flamingo.NullLogger{},
+				nil,
 			)
 
 			return result
@@ -1053,16 +1076,84 @@ func createCartServiceWithDependencies() *cartApplication.CartService {
 		},
 		&struct {
 			CartValidator     validation.Validator      `inject:",optional"`
+			CartValidators    []validation.Validator    `inject:",optional"`
 			ItemValidator     validation.ItemValidator  `inject:",optional"`
 			CartCache         cartApplication.CartCache `inject:",optional"`
 			PlaceOrderService placeorder.Service        `inject:",optional"`
+			HistoryStorage    cartDomain.HistoryStorage `inject:",optional"`
+			ChangeNotifier    cartDomain.ChangeNotifier `inject:",optional"`
 		}{
-			CartCache: cartCache,
+			CartCache:      cartCache,
+			HistoryStorage: historyStorage,
+			ChangeNotifier: changeNotifier,
 		},
 	)
 	return cs
 }
 
+func TestCartService_History(t *testing.T) {
+	historyStorage := new(infrastructure.InMemoryHistoryStorage).Inject(nil)
+	cs := createCartServiceWithObservers(historyStorage, nil)
+
+	ctx := context.Background()
+	session := web.EmptySession()
+
+	_, err := cs.AddProduct(ctx, session, "default_delivery_code", cartDomain.AddRequest{MarketplaceCode: "code-1", Qty: 2})
+	require.NoError(t, err)
+
+	cart, err := cs.UpdateAdditionalData(ctx, session, map[string]string{"foo": "bar", "test": "data"})
+	require.NoError(t, err)
+
+	err = cs.UpdateItemQty(ctx, session, "unknown_item", "default_delivery_code", 3)
+	require.Error(t, err)
+
+	entries, err := historyStorage.GetEntries(ctx, cart.ID)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(entries), 2)
+
+	addProduct := entries[len(entries)-2]
+	assert.Equal(t, "AddProduct", addProduct.Operation)
+	assert.Equal(t, map[string]string{"deliveryCode": "default_delivery_code", "marketplaceCode": "code-1", "variantMarketplaceCode": "", "qty": "2"}, addProduct.Parameters)
+	assert.Contains(t, addProduct.Events, fmt.Sprintf("%T", new(events.AddToCartEvent)))
+	assert.Equal(t, cartDomain.HistoryActorGuest, addProduct.Actor.Type)
+	assert.NotEmpty(t, addProduct.Actor.ID)
+	assert.NotEqual(t, session.ID(), addProduct.Actor.ID, "the session id must not be exposed")
+
+	// the failed modification is not recorded
+	updateAdditionalData := entries[len(entries)-1]
+	assert.Equal(t, "UpdateAdditionalData", updateAdditionalData.Operation)
+	assert.Equal(t, map[string]string{"keys": "foo,test"}, updateAdditionalData.Parameters)
+	assert.Equal(t, addProduct.Actor, updateAdditionalData.Actor)
+}
+
+func TestCartService_ChangeNotifications(t *testing.T) {
+	changeNotifier := new(infrastructure.InMemoryChangeNotifier).Inject(nil)
+	cs := createCartServiceWithObservers(nil, changeNotifier)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	session := web.EmptySession()
+
+	cart, err := cs.UpdateAdditionalData(ctx, session, map[string]string{"foo": "bar"})
+	require.NoError(t, err)
+
+	notifications, err := changeNotifier.Subscribe(ctx, cartDomain.ChangeSubscription{CartID: cart.ID})
+	require.NoError(t, err)
+
+	_, err = cs.AddProduct(ctx, session, "default_delivery_code", cartDomain.AddRequest{MarketplaceCode: "code-1", Qty: 2})
+	require.NoError(t, err)
+
+	err = cs.UpdateItemQty(ctx, session, "unknown_item", "default_delivery_code", 3)
+	require.Error(t, err)
+
+	require.Len(t, notifications, 1, "only the stored modification is published")
+	notification := <-notifications
+	assert.Equal(t, cart.ID, notification.CartID)
+	assert.Equal(t, "AddProduct", notification.Operation)
+	assert.Empty(t, notification.CustomerID)
+}
+
 func TestCartService_SetAdditionalData(t *testing.T) {
 	cs := createCartServiceWithDependencies()
 
@@ -1143,9 +1234,12 @@ func TestCartService_UpdateItemBundleConfig(t *testing.T) {
 			}{},
 			&struct {
 				CartValidator     validation.Validator      `inject:",optional"`
+				CartValidators    []validation.Validator    `inject:",optional"`
 				ItemValidator     validation.ItemValidator  `inject:",optional"`
 				CartCache         cartApplication.CartCache `inject:",optional"`
 				PlaceOrderService placeorder.Service        `inject:",optional"`
+				HistoryStorage    cartDomain.HistoryStorage `inject:",optional"`
+				ChangeNotifier    cartDomain.ChangeNotifier `inject:",optional"`
 			}{},
 		)
 
@@ -1203,9 +1297,12 @@ func TestCartService_UpdateItemBundleConfig(t *testing.T) {
 			}{},
 			&struct {
 				CartValidator     validation.Validator      `inject:",optional"`
+				CartValidators    []validation.Validator    `inject:",optional"`
 				ItemValidator     validation.ItemValidator  `inject:",optional"`
 				CartCache         cartApplication.CartCache `inject:",optional"`
 				PlaceOrderService placeorder.Service        `inject:",optional"`
+				HistoryStorage    cartDomain.HistoryStorage `inject:",optional"`
+				ChangeNotifier    cartDomain.ChangeNotifier `inject:",optional"`
 			}{},
 		)
 
@@ -1271,9 +1368,12 @@ func TestCartService_UpdateItemBundleConfig(t *testing.T) {
 			}{},
 			&struct {
 				CartValidator     validation.Validator      `inject:",optional"`
+				CartValidators    []validation.Validator    `inject:",optional"`
 				ItemValidator     validation.ItemValidator  `inject:",optional"`
 				CartCache         cartApplication.CartCache `inject:",optional"`
 				PlaceOrderService placeorder.Service        `inject:",optional"`
+				HistoryStorage    cartDomain.HistoryStorage `inject:",optional"`
+				ChangeNotifier    cartDomain.ChangeNotifier `inject:",optional"`
 			}{},
 		)
 
@@ -1345,9 +1445,12 @@ func TestCartService_UpdateItemBundleConfig(t *testing.T) {
 			}{},
 			&struct {
 				CartValidator     validation.Validator      `inject:",optional"`
+				CartValidators    []validation.Validator    `inject:",optional"`
 				ItemValidator     validation.ItemValidator  `inject:",optional"`
 				CartCache         cartApplication.CartCache `inject:",optional"`
 				PlaceOrderService placeorder.Service        `inject:",optional"`
+				HistoryStorage    cartDomain.HistoryStorage `inject:",optional"`
+				ChangeNotifier    cartDomain.ChangeNotifier `inject:",optional"`
 			}{
 				ItemValidator: itemValidator,
 			},
@@ -1423,9 +1526,12 @@ func TestCartService_UpdateItemBundleConfig(t *testing.T) {
 			}{},
 			&struct {
 				CartValidator     validation.Validator      `inject:",optional"`
+				CartValidators    []validation.Validator    `inject:",optional"`
 				ItemValidator     validation.ItemValidator  `inject:",optional"`
 				CartCache         cartApplication.CartCache `inject:",optional"`
 				PlaceOrderService placeorder.Service        `inject:",optional"`
+				HistoryStorage    cartDomain.HistoryStorage `inject:",optional"`
+				ChangeNotifier    cartDomain.ChangeNotifier `inject:",optional"`
 			}{
 				ItemValidator: itemValidator,
 			},
@@ -1449,6 +1555,149 @@ func TestCartService_UpdateItemBundleConfig(t *testing.T) {
 	})
 }
 
+func TestCartService_PreviewItemBundleConfig(t *testing.T) {
+	t.Parallel()
+
+	bundle := productDomain.BundleProduct{
+		Saleable: productDomain.Saleable{
+			ActivePrice: productDomain.PriceInfo{Default: priceDomain.NewFromFloat(30, "EUR")},
+		},
+		Choices: []productDomain.Choice{
+			{
+				Identifier: "choice1",
+				Required:   true,
+				Options: []productDomain.Option{
+					{
+						Product: productDomain.SimpleProduct{
+							BasicProductData: productDomain.BasicProductData{MarketPlaceCode: "test"},
+							Saleable: productDomain.Saleable{
+								ActivePrice: productDomain.PriceInfo{Default: priceDomain.NewFromFloat(5, "EUR")},
+							},
+						},
+						MinQty: 1,
+						MaxQty: 3,
+					},
+				},
+			},
+		},
+	}
+
+	createService := func(restrictor validation.MaxQuantityRestrictor) (*cartApplication.CartService, *mocks2.ModifyBehaviour) {
+		cart := &cartDomain.Cart{
+			Deliveries: []cartDomain.Delivery{
+				{
+					DeliveryInfo: cartDomain.DeliveryInfo{Code: "delivery"},
+					Cartitems: []cartDomain.Item{
+						{
+							ID:              "fakeID",
+							MarketplaceCode: "fake",
+							Qty:             2,
+							RowPriceGross:   priceDomain.NewFromFloat(40, "EUR"),
+						},
+					},
+				},
+			},
+		}
+
+		behaviour := &mocks2.ModifyBehaviour{}
+
+		guestCartService := &mocks2.GuestCartService{}
+		guestCartService.EXPECT().GetModifyBehaviour(mock.Anything).Return(behaviour, nil)
+
+		cartReceiverService, _ := getCartReceiverServiceForBundleUpdateTest(cart, guestCartService)
+
+		productService := &mocks.ProductService{}
+		productService.EXPECT().Get(mock.Anything, "fake").Return(bundle, nil)
+
+		restrictionService := &validation.RestrictionService{}
+		restrictionService.Inject([]validation.MaxQuantityRestrictor{restrictor})
+
+		cartService := &cartApplication.CartService{}
+		cartService.Inject(
+			cartReceiverService,
+			productService,
+			new(MockEventPublisher),
+			new(MockEventRouter),
+			new(MockDeliveryInfoBuilder),
+			restrictionService,
+			nil,
+			flamingo.NullLogger{},
+			&struct {
+				DefaultDeliveryCode string `inject:"config:commerce.cart.defaultDeliveryCode,optional"`
+				DeleteEmptyDelivery bool   `inject:"config:commerce.cart.deleteEmptyDelivery,optional"`
+			}{},
+			&struct {
+				CartValidator     validation.Validator      `inject:",optional"`
+				CartValidators    []validation.Validator    `inject:",optional"`
+				ItemValidator     validation.ItemValidator  `inject:",optional"`
+				CartCache         cartApplication.CartCache `inject:",optional"`
+				PlaceOrderService placeorder.Service        `inject:",optional"`
+				HistoryStorage    cartDomain.HistoryStorage `inject:",optional"`
+				ChangeNotifier    cartDomain.ChangeNotifier `inject:",optional"`
+			}{},
+		)
+
+		return cartService, behaviour
+	}
+
+	t.Run("calculates the new row price and availability without modifying the cart", func(t *testing.T) {
+		t.Parallel()
+
+		cartService, behaviour := createService(&MockRestrictor{IsRestricted: true, MaxQty: 1, DifferenceQty: -1})
+		session := web.EmptySession().Store(cartApplication.GuestCartSessionKey, "fakeCartSession")
+
+		preview, err := cartService.PreviewItemBundleConfig(context.Background(), session, cartDomain.ItemUpdateCommand{
+			ItemID: "fakeID",
+			BundleConfiguration: productDomain.BundleConfiguration{
+				"choice1": {MarketplaceCode: "test", Qty: 2},
+			},
+		})
+		require.NoError(t, err)
+
+		assert.True(t, preview.Valid)
+		assert.Empty(t, preview.ValidationError)
+		assert.Equal(t, []cartApplication.BundleConfigPreviewChoice{
+			{Identifier: "choice1", MarketplaceCode: "test", Qty: 2, SinglePrice: priceDomain.NewFromFloat(5, "EUR")},
+		}, preview.Choices)
+		assert.Equal(t, 40.0, preview.CurrentRowPrice.FloatAmount())
+		assert.Equal(t, 60.0, preview.NewRowPrice.FloatAmount())
+		assert.Equal(t, 20.0, preview.Delta.FloatAmount())
+		assert.False(t, preview.Available)
+		assert.Equal(t, 1, preview.MaxAllowedQty)
+
+		behaviour.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("reports an invalid choice quantity", func(t *testing.T) {
+		t.Parallel()
+
+		cartService, _ := createService(&MockRestrictor{})
+		session := web.EmptySession().Store(cartApplication.GuestCartSessionKey, "fakeCartSession")
+
+		preview, err := cartService.PreviewItemBundleConfig(context.Background(), session, cartDomain.ItemUpdateCommand{
+			ItemID: "fakeID",
+			BundleConfiguration: productDomain.BundleConfiguration{
+				"choice1": {MarketplaceCode: "test", Qty: 5},
+			},
+		})
+		require.NoError(t, err)
+
+		assert.False(t, preview.Valid)
+		assert.Contains(t, preview.ValidationError, productDomain.ErrSelectedQuantityOutOfRange.Error())
+		assert.Equal(t, 40.0, preview.CurrentRowPrice.FloatAmount())
+	})
+
+	t.Run("error when bundle configuration not provided", func(t *testing.T) {
+		t.Parallel()
+
+		cartService, _ := createService(&MockRestrictor{})
+		session := web.EmptySession().Store(cartApplication.GuestCartSessionKey, "fakeCartSession")
+
+		_, err := cartService.PreviewItemBundleConfig(context.Background(), session, cartDomain.ItemUpdateCommand{ItemID: "fakeID"})
+		assert.ErrorIs(t, err, cartApplication.ErrBundleConfigNotProvided)
+	})
+}
+
 func getCartReceiverServiceForBundleUpdateTest(cart *cartDomain.Cart, guestCartService cartDomain.GuestCartService) (*cartApplication.CartReceiverService, *MockCartCache) {
 	cartCache := new(MockCartCache)
 	cartCache.CachedCart = cart
@@ -1463,6 +1712,7 @@ func getCartReceiverServiceForBundleUpdateTest(cart *cartDomain.Cart, guestCartS
 				result.Inject(
 					&MockProductService{},
 					flamingo.NullLogger{},
+					nil,
 				)
 
 				return result
@@ -1479,3 +1729,537 @@ func getCartReceiverServiceForBundleUpdateTest(cart *cartDomain.Cart, guestCartS
 		return result, cartCache
 	}()
 }
+
+func TestCartService_ExpectedRevision(t *testing.T) {
+	t.Run("modification based on outdated revision is rejected", func(t *testing.T) {
+		cs := createCartServiceWithDependencies()
+
+		ctx := cartApplication.ContextWithExpectedRevision(context.Background(), 5)
+		_, err := cs.UpdateAdditionalData(ctx, web.EmptySession(), map[string]string{"test": "data"})
+
+		var conflictErr *cartDomain.RevisionConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, 5, conflictErr.ExpectedRevision)
+		assert.Equal(t, 0, conflictErr.ActualRevision)
+	})
+
+	t.Run("modification based on current revision is accepted", func(t *testing.T) {
+		cs := createCartServiceWithDependencies()
+
+		ctx := cartApplication.ContextWithExpectedRevision(context.Background(), 0)
+		cart, err := cs.UpdateAdditionalData(ctx, web.EmptySession(), map[string]string{"test": "data"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, cart.Revision)
+	})
+
+	t.Run("modification based on another cart is rejected", func(t *testing.T) {
+		cs := createCartServiceWithDependencies()
+
+		ctx := cartApplication.ContextWithExpectedCart(context.Background(), "other_cart", 0)
+		_, err := cs.UpdateAdditionalData(ctx, web.EmptySession(), map[string]string{"test": "data"})
+
+		var conflictErr *cartDomain.RevisionConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, "mock_guest_cart", conflictErr.CartID)
+	})
+
+	t.Run("modification based on the current cart and revision is accepted", func(t *testing.T) {
+		cs := createCartServiceWithDependencies()
+
+		ctx := cartApplication.ContextWithExpectedCart(context.Background(), "mock_guest_cart", 0)
+		cart, err := cs.UpdateAdditionalData(ctx, web.EmptySession(), map[string]string{"test": "data"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, cart.Revision)
+	})
+}
+
+// failingCartStorage fails to store the cart with the given id like a concurrent modification would
+type failingCartStorage struct {
+	*infrastructure.InMemoryCartStorage
+	failCartID string
+}
+
+func (s *failingCartStorage) StoreCart(ctx context.Context, cart *cartDomain.Cart) error {
+	if cart.ID == s.failCartID {
+		return &cartDomain.RevisionConflictError{CartID: cart.ID, ExpectedRevision: cart.Revision - 1, ActualRevision: cart.Revision}
+	}
+
+	return s.InMemoryCartStorage.StoreCart(ctx, cart)
+}
+
+func newMoveItemTestServices(
+	storage infrastructure.CartStorage,
+	lineItemStrategy cartDomain.LineItemStrategy,
+	restrictors []validation.MaxQuantityRestrictor,
+	itemValidator validation.ItemValidator,
+) (*cartApplication.CartService, *cartApplication.CartReceiverService) {
+	eventRouter := new(MockEventRouter)
+	eventRouter.On("Dispatch", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	behaviour := &infrastructure.DefaultCartBehaviour{}
+	behaviour.Inject(storage, &MockProductService{}, flamingo.NullLogger{}, nil, nil, nil, &struct {
+		ShippingRateProvider cartDomain.ShippingRateProvider            `inject:",optional"`
+		CartExpirer          infrastructure.CartExpirer                 `inject:",optional"`
+		LineItemStrategy     cartDomain.LineItemStrategy                `inject:",optional"`
+		TaxCalculator        infrastructure.TaxCalculator               `inject:",optional"`
+		PostProcessors       *cartApplication.CartPostProcessorPipeline `inject:",optional"`
+	}{LineItemStrategy: lineItemStrategy})
+	customerCartService := &infrastructure.DefaultCustomerCartService{}
+	customerCartService.Inject(behaviour, flamingo.NullLogger{})
+
+	mockIdentifier := new(authMock.Identifier).SetIdentifyMethod(
+		func(identifier *authMock.Identifier, ctx context.Context, request *web.Request) (auth.Identity, error) {
+			return &authMock.Identity{Sub: "customer-1"}, nil
+		},
+	)
+	webIdentityService := new(auth.WebIdentityService).Inject([]auth.RequestIdentifier{mockIdentifier}, nil, nil, nil)
+
+	decoratedCartFactory := &decorator.DecoratedCartFactory{}
+	decoratedCartFactory.Inject(&MockProductService{}, flamingo.NullLogger{}, nil)
+
+	crs := &cartApplication.CartReceiverService{}
+	crs.Inject(
+		new(MockGuestCartServiceWithModifyBehaviour),
+		customerCartService,
+		decoratedCartFactory,
+		webIdentityService,
+		flamingo.NullLogger{},
+		eventRouter,
+		nil,
+	)
+
+	cs := &cartApplication.CartService{}
+	cs.Inject(
+		crs,
+		&MockProductService{},
+		new(MockEventPublisher),
+		eventRouter,
+		new(MockDeliveryInfoBuilder),
+		new(validation.RestrictionService).Inject(restrictors),
+		webIdentityService,
+		flamingo.NullLogger{},
+		&struct {
+			DefaultDeliveryCode string `inject:"config:commerce.cart.defaultDeliveryCode,optional"`
+			DeleteEmptyDelivery bool   `inject:"config:commerce.cart.deleteEmptyDelivery,optional"`
+		}{
+			DefaultDeliveryCode: "default_delivery_code",
+		},
+		&struct {
+			CartValidator     validation.Validator      `inject:",optional"`
+			CartValidators    []validation.Validator    `inject:",optional"`
+			ItemValidator     validation.ItemValidator  `inject:",optional"`
+			CartCache         cartApplication.CartCache `inject:",optional"`
+			PlaceOrderService placeorder.Service        `inject:",optional"`
+			HistoryStorage    cartDomain.HistoryStorage `inject:",optional"`
+			ChangeNotifier    cartDomain.ChangeNotifier `inject:",optional"`
+		}{
+			ItemValidator: itemValidator,
+		},
+	)
+
+	return cs, crs
+}
+
+func TestCartService_MoveItem(t *testing.T) {
+	cs, crs := newMoveItemTestServices((&infrastructure.InMemoryCartStorage{}).Inject(), nil, nil, nil)
+
+	ctx := context.Background()
+	session := web.EmptySession()
+
+	savedForLater, err := crs.CreateCustomerCart(ctx, "saved for later")
+	require.NoError(t, err)
+
+	_, err = cs.AddProduct(ctx, session, "default_delivery_code", cartDomain.AddRequest{MarketplaceCode: "code-1", Qty: 2})
+	require.NoError(t, err)
+
+	cart, _, err := crs.GetCart(ctx, session)
+	require.NoError(t, err)
+	item := cart.Deliveries[0].Cartitems[0]
+
+	err = cs.MoveItem(ctx, session, item.ID, "unknown_delivery_code", savedForLater.ID, "")
+	assert.ErrorIs(t, err, cartDomain.ErrDeliveryCodeNotFound)
+
+	err = cs.MoveItem(ctx, session, item.ID, "default_delivery_code", savedForLater.ID, "")
+	require.NoError(t, err)
+
+	cart, _, err = crs.GetCart(ctx, session)
+	require.NoError(t, err)
+	assert.Equal(t, 0, cart.ItemCount())
+
+	targetCart, _, err := crs.GetCustomerCartByID(ctx, savedForLater.ID)
+	require.NoError(t, err)
+	targetItem, err := targetCart.GetByItemID(targetCart.Deliveries[0].Cartitems[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "code-1", targetItem.MarketplaceCode)
+	assert.Equal(t, 2, targetItem.Qty)
+
+	t.Run("switch to the target cart", func(t *testing.T) {
+		switched, err := crs.SwitchCustomerCart(ctx, session, savedForLater.ID)
+		require.NoError(t, err)
+		assert.Equal(t, savedForLater.ID, switched.ID)
+
+		cart, _, err := crs.GetCart(ctx, session)
+		require.NoError(t, err)
+		assert.Equal(t, savedForLater.ID, cart.ID)
+		assert.Equal(t, "saved for later", cart.Name)
+
+		carts, err := crs.ListCustomerCarts(ctx)
+		require.NoError(t, err)
+		assert.Len(t, carts, 2)
+	})
+}
+
+func TestCartService_MoveItemRollback(t *testing.T) {
+	storage := &failingCartStorage{InMemoryCartStorage: (&infrastructure.InMemoryCartStorage{}).Inject()}
+	cs, crs := newMoveItemTestServices(storage, nil, nil, nil)
+
+	ctx := context.Background()
+	session := web.EmptySession()
+
+	savedForLater, err := crs.CreateCustomerCart(ctx, "saved for later")
+	require.NoError(t, err)
+
+	_, err = cs.AddProduct(ctx, session, "default_delivery_code", cartDomain.AddRequest{MarketplaceCode: "code-1", Qty: 2})
+	require.NoError(t, err)
+
+	cart, _, err := crs.GetCart(ctx, session)
+	require.NoError(t, err)
+
+	// deleting the item from the current cart fails
+	storage.failCartID = cart.ID
+
+	err = cs.MoveItem(ctx, session, cart.Deliveries[0].Cartitems[0].ID, "default_delivery_code", savedForLater.ID, "saved_delivery")
+	var conflictErr *cartDomain.RevisionConflictError
+	require.ErrorAs(t, err, &conflictErr)
+
+	cart, _, err = crs.GetCart(ctx, session)
+	require.NoError(t, err)
+	assert.Equal(t, 2, cart.ItemCount(), "the item stays in the current cart")
+
+	targetCart, _, err := crs.GetCustomerCartByID(ctx, savedForLater.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, targetCart.ItemCount(), "the item is removed from the target cart again")
+}
+
+func TestCartService_MoveItemRollbackOfSplitItem(t *testing.T) {
+	storage := &failingCartStorage{InMemoryCartStorage: (&infrastructure.InMemoryCartStorage{}).Inject()}
+	strategy := new(cartDomain.DefaultLineItemStrategy).Inject(&struct {
+		DistinguishingAdditionalDataKeys []string `inject:"config:commerce.cart.lineItems.distinguishingAdditionalDataKeys,optional"`
+		UnmergeableAdditionalDataKeys    []string `inject:"config:commerce.cart.lineItems.unmergeableAdditionalDataKeys,optional"`
+		SingleUnitAdditionalDataKeys     []string `inject:"config:commerce.cart.lineItems.singleUnitAdditionalDataKeys,optional"`
+	}{SingleUnitAdditionalDataKeys: []string{"engraving"}})
+	cs, crs := newMoveItemTestServices(storage, strategy, nil, nil)
+
+	ctx := context.Background()
+	session := web.EmptySession()
+
+	savedForLater, err := crs.CreateCustomerCart(ctx, "saved for later")
+	require.NoError(t, err)
+
+	// stored with qty 2, the target cart splits the item into two lines
+	require.NoError(t, storage.StoreCart(ctx, &cartDomain.Cart{
+		ID:                         "customer-1",
+		Revision:                   1,
+		BelongsToAuthenticatedUser: true,
+		AuthenticatedUserID:        "customer-1",
+		Deliveries: []cartDomain.Delivery{{
+			DeliveryInfo: cartDomain.DeliveryInfo{Code: "default_delivery_code"},
+			Cartitems:    []cartDomain.Item{{ID: "item-1", MarketplaceCode: "code-1", Qty: 2, AdditionalData: map[string]string{"engraving": "A"}}},
+		}},
+	}))
+
+	storage.failCartID = "customer-1"
+
+	err = cs.MoveItem(ctx, session, "item-1", "default_delivery_code", savedForLater.ID, "")
+	var conflictErr *cartDomain.RevisionConflictError
+	require.ErrorAs(t, err, &conflictErr)
+
+	targetCart, _, err := crs.GetCustomerCartByID(ctx, savedForLater.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, targetCart.ItemCount(), "all lines are removed from the target cart again")
+}
+
+func TestCartService_MoveItemChecksTargetCart(t *testing.T) {
+	storeSourceCart := func(t *testing.T, storage infrastructure.CartStorage, marketplaceCode string) {
+		t.Helper()
+
+		require.NoError(t, storage.StoreCart(context.Background(), &cartDomain.Cart{
+			ID:                         "customer-1",
+			Revision:                   1,
+			BelongsToAuthenticatedUser: true,
+			AuthenticatedUserID:        "customer-1",
+			Deliveries: []cartDomain.Delivery{{
+				DeliveryInfo: cartDomain.DeliveryInfo{Code: "default_delivery_code"},
+				Cartitems:    []cartDomain.Item{{ID: "item-1", MarketplaceCode: marketplaceCode, Qty: 2}},
+			}},
+		}))
+	}
+
+	assertNotMoved := func(t *testing.T, crs *cartApplication.CartReceiverService, targetCartID string) {
+		t.Helper()
+
+		cart, _, err := crs.GetCart(context.Background(), web.EmptySession())
+		require.NoError(t, err)
+		assert.Equal(t, 2, cart.ItemCount(), "the item stays in the current cart")
+
+		targetCart, _, err := crs.GetCustomerCartByID(context.Background(), targetCartID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, targetCart.ItemCount())
+	}
+
+	t.Run("item validator", func(t *testing.T) {
+		storage := (&infrastructure.InMemoryCartStorage{}).Inject()
+		cs, crs := newMoveItemTestServices(storage, nil, nil, notAllowedItemValidator{})
+		storeSourceCart(t, storage, "not-allowed")
+
+		savedForLater, err := crs.CreateCustomerCart(context.Background(), "saved for later")
+		require.NoError(t, err)
+
+		err = cs.MoveItem(context.Background(), web.EmptySession(), "item-1", "default_delivery_code", savedForLater.ID, "")
+		var notAllowedErr *validation.AddToCartNotAllowed
+		require.ErrorAs(t, err, &notAllowedErr)
+
+		assertNotMoved(t, crs, savedForLater.ID)
+	})
+
+	t.Run("qty restriction", func(t *testing.T) {
+		storage := (&infrastructure.InMemoryCartStorage{}).Inject()
+		restrictor := &MockRestrictor{IsRestricted: true, MaxQty: 1, DifferenceQty: 1}
+		cs, crs := newMoveItemTestServices(storage, nil, []validation.MaxQuantityRestrictor{restrictor}, nil)
+		storeSourceCart(t, storage, "code-1")
+
+		savedForLater, err := crs.CreateCustomerCart(context.Background(), "saved for later")
+		require.NoError(t, err)
+
+		err = cs.MoveItem(context.Background(), web.EmptySession(), "item-1", "default_delivery_code", savedForLater.ID, "")
+		var restrictionErr *cartApplication.RestrictionError
+		require.ErrorAs(t, err, &restrictionErr)
+
+		assertNotMoved(t, crs, savedForLater.ID)
+	})
+}
+
+type notAllowedItemValidator struct{}
+
+func (notAllowedItemValidator) Validate(_ context.Context, _ *web.Session, _ *decorator.DecoratedCart, _ string, request cartDomain.AddRequest, _ productDomain.BasicProduct) error {
+	if request.MarketplaceCode == "not-allowed" {
+		return &validation.AddToCartNotAllowed{Reason: "not allowed"}
+	}
+
+	return nil
+}
+
+func newBulkAddTestService(guestCartService cartDomain.GuestCartService, cache cartApplication.CartCache) *cartApplication.CartService {
+	eventRouter := new(MockEventRouter)
+	eventRouter.On("Dispatch", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	decoratedCartFactory := &decorator.DecoratedCartFactory{}
+	decoratedCartFactory.Inject(&MockProductService{}, flamingo.NullLogger{}, nil)
+
+	crs := &cartApplication.CartReceiverService{}
+	crs.Inject(
+		guestCartService,
+		nil,
+		decoratedCartFactory,
+		nil,
+		flamingo.NullLogger{},
+		eventRouter,
+		nil,
+	)
+
+	cs := &cartApplication.CartService{}
+	cs.Inject(
+		crs,
+		&MockProductService{},
+		new(MockEventPublisher),
+		eventRouter,
+		new(MockDeliveryInfoBuilder),
+		new(validation.RestrictionService).Inject([]validation.MaxQuantityRestrictor{&MockRestrictor{IsRestricted: true, MaxQty: 3, DifferenceQty: 3}}),
+		nil,
+		flamingo.NullLogger{},
+		&struct {
+			DefaultDeliveryCode string `inject:"config:commerce.cart.defaultDeliveryCode,optional"`
+			DeleteEmptyDelivery bool   `inject:"config:commerce.cart.deleteEmptyDelivery,optional"`
+		}{
+			DefaultDeliveryCode: "default_delivery_code",
+		},
+		&struct {
+			CartValidator     validation.Validator      `inject:",optional"`
+			CartValidators    []validation.Validator    `inject:",optional"`
+			ItemValidator     validation.ItemValidator  `inject:",optional"`
+			CartCache         cartApplication.CartCache `inject:",optional"`
+			PlaceOrderService placeorder.Service        `inject:",optional"`
+			HistoryStorage    cartDomain.HistoryStorage `inject:",optional"`
+			ChangeNotifier    cartDomain.ChangeNotifier `inject:",optional"`
+		}{
+			ItemValidator: notAllowedItemValidator{},
+			CartCache:     cache,
+		},
+	)
+
+	return cs
+}
+
+// singleAddGuestCartService hides the BulkAddBehaviour of the default cart behaviour
+type singleAddGuestCartService struct {
+	MockGuestCartServiceWithModifyBehaviour
+	failingMarketplaceCode string
+}
+
+// singleAddBehaviour fails to add the given marketplace code
+type singleAddBehaviour struct {
+	cartDomain.ModifyBehaviour
+	failingMarketplaceCode string
+}
+
+func (m *singleAddGuestCartService) GetModifyBehaviour(ctx context.Context) (cartDomain.ModifyBehaviour, error) {
+	behaviour, err := m.MockGuestCartServiceWithModifyBehaviour.GetModifyBehaviour(ctx)
+
+	return &singleAddBehaviour{ModifyBehaviour: behaviour, failingMarketplaceCode: m.failingMarketplaceCode}, err
+}
+
+func (b *singleAddBehaviour) AddToCart(ctx context.Context, cart *cartDomain.Cart, deliveryCode string, addRequest cartDomain.AddRequest) (*cartDomain.Cart, cartDomain.DeferEvents, error) {
+	if addRequest.MarketplaceCode == b.failingMarketplaceCode {
+		return nil, nil, errors.New("add to cart failed")
+	}
+
+	return b.ModifyBehaviour.AddToCart(ctx, cart, deliveryCode, addRequest)
+}
+
+func TestCartService_AddProductsBulk(t *testing.T) {
+	cache := new(MockCartCache)
+	cs := newBulkAddTestService(new(MockGuestCartServiceWithModifyBehaviour), cache)
+
+	results, err := cs.AddProductsBulk(context.Background(), web.EmptySession(), []cartDomain.DeliveryAddRequest{
+		{AddRequest: cartDomain.AddRequest{MarketplaceCode: "code-1", Qty: 2}},
+		{AddRequest: cartDomain.AddRequest{MarketplaceCode: "not-allowed", Qty: 1}},
+		{AddRequest: cartDomain.AddRequest{MarketplaceCode: "code-1", Qty: 2}},
+		{DeliveryCode: "other_delivery", AddRequest: cartDomain.AddRequest{MarketplaceCode: "code-2", Qty: 1}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	assert.Equal(t, cartApplication.BulkAddStatusSuccess, results[0].Status)
+	assert.Equal(t, "default_delivery_code", results[0].DeliveryCode)
+	assert.Equal(t, cartApplication.BulkAddStatusNotAllowed, results[1].Status)
+	assert.Equal(t, "Product is not allowed: not allowed", results[1].Error)
+	assert.Equal(t, cartApplication.BulkAddStatusRestricted, results[2].Status, "qty of the first line must be taken into account")
+	require.NotNil(t, results[2].RestrictionResult)
+	assert.Equal(t, 3, results[2].RestrictionResult.MaxAllowed)
+	assert.Equal(t, cartApplication.BulkAddStatusSuccess, results[3].Status)
+	assert.Equal(t, 4, results[3].Line)
+
+	require.NotNil(t, cache.CachedCart)
+	assert.Equal(t, 3, cache.CachedCart.ItemCount())
+	assert.Equal(t, 1, cache.CachedCart.Revision, "cart must be stored only once")
+}
+
+func TestCartService_AddProductsBulkWithoutBulkAddBehaviour(t *testing.T) {
+	t.Run("items are added one by one", func(t *testing.T) {
+		cache := new(MockCartCache)
+		cs := newBulkAddTestService(new(singleAddGuestCartService), cache)
+
+		results, err := cs.AddProductsBulk(context.Background(), web.EmptySession(), []cartDomain.DeliveryAddRequest{
+			{AddRequest: cartDomain.AddRequest{MarketplaceCode: "code-1", Qty: 1}},
+			{AddRequest: cartDomain.AddRequest{MarketplaceCode: "code-2", Qty: 1}},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, cartApplication.BulkAddStatusSuccess, results[0].Status)
+		assert.Equal(t, cartApplication.BulkAddStatusSuccess, results[1].Status)
+
+		require.NotNil(t, cache.CachedCart)
+		assert.Equal(t, 2, cache.CachedCart.ItemCount())
+		assert.Equal(t, 2, cache.CachedCart.Revision, "cart is stored once per item")
+	})
+
+	t.Run("items added before a failure stay in the cart", func(t *testing.T) {
+		cache := new(MockCartCache)
+		cs := newBulkAddTestService(&singleAddGuestCartService{failingMarketplaceCode: "code-2"}, cache)
+
+		results, err := cs.AddProductsBulk(context.Background(), web.EmptySession(), []cartDomain.DeliveryAddRequest{
+			{AddRequest: cartDomain.AddRequest{MarketplaceCode: "code-1", Qty: 1}},
+			{AddRequest: cartDomain.AddRequest{MarketplaceCode: "code-2", Qty: 1}},
+		})
+		assert.EqualError(t, err, "add to cart failed")
+		assert.Nil(t, results)
+
+		require.NotNil(t, cache.CachedCart, "the stored partial cart must be cached")
+		assert.Equal(t, 1, cache.CachedCart.ItemCount())
+	})
+}
+
+// releaseRecordingBehaviour records the carts passed to Release
+type releaseRecordingBehaviour struct {
+	cartDomain.ModifyBehaviour
+	released []string
+}
+
+func (b *releaseRecordingBehaviour) Release(_ context.Context, cart *cartDomain.Cart) error {
+	b.released = append(b.released, cart.ID)
+
+	return nil
+}
+
+type releaseRecordingGuestCartService struct {
+	MockGuestCartServiceWithModifyBehaviour
+	behaviour *releaseRecordingBehaviour
+}
+
+func (m *releaseRecordingGuestCartService) GetModifyBehaviour(ctx context.Context) (cartDomain.ModifyBehaviour, error) {
+	if m.behaviour == nil {
+		behaviour, err := m.MockGuestCartServiceWithModifyBehaviour.GetModifyBehaviour(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		m.behaviour = &releaseRecordingBehaviour{ModifyBehaviour: behaviour}
+	}
+
+	return m.behaviour, nil
+}
+
+func TestCartService_CancelOrderAndReleaseCart(t *testing.T) {
+	guestCartService := new(releaseRecordingGuestCartService)
+
+	decoratedCartFactory := &decorator.DecoratedCartFactory{}
+	decoratedCartFactory.Inject(&MockProductService{}, flamingo.NullLogger{}, nil)
+
+	crs := &cartApplication.CartReceiverService{}
+	crs.Inject(guestCartService, nil, decoratedCartFactory, nil, flamingo.NullLogger{}, new(MockEventRouter), nil)
+
+	cs := &cartApplication.CartService{}
+	cs.Inject(
+		crs,
+		&MockProductService{},
+		new(MockEventPublisher),
+		new(MockEventRouter),
+		new(MockDeliveryInfoBuilder),
+		nil,
+		new(auth.WebIdentityService).Inject(nil, nil, nil, nil),
+		flamingo.NullLogger{},
+		nil,
+		&struct {
+			CartValidator     validation.Validator      `inject:",optional"`
+			CartValidators    []validation.Validator    `inject:",optional"`
+			ItemValidator     validation.ItemValidator  `inject:",optional"`
+			CartCache         cartApplication.CartCache `inject:",optional"`
+			PlaceOrderService placeorder.Service        `inject:",optional"`
+			HistoryStorage    cartDomain.HistoryStorage `inject:",optional"`
+			ChangeNotifier    cartDomain.ChangeNotifier `inject:",optional"`
+		}{
+			PlaceOrderService: &MockPlaceOrderService{},
+		},
+	)
+
+	orderInfos := placeorder.PlacedOrderInfos{{OrderNumber: "order-1", DeliveryCode: "delivery"}}
+
+	err := cs.CancelOrderWithoutRestore(context.Background(), web.EmptySession(), orderInfos)
+	require.NoError(t, err)
+	assert.Nil(t, guestCartService.behaviour, "nothing is released without release")
+
+	err = cs.CancelOrderAndReleaseCart(context.Background(), web.EmptySession(), orderInfos, cartDomain.Cart{ID: "completed-cart"})
+	require.NoError(t, err)
+	require.NotNil(t, guestCartService.behaviour)
+	assert.Equal(t, []string{"completed-cart"}, guestCartService.behaviour.released)
+}
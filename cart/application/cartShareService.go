@@ -0,0 +1,293 @@
+package application
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+	"go.opencensus.io/trace"
+
+	cartDomain "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/validation"
+	productDomain "flamingo.me/flamingo-commerce/v3/product/domain"
+)
+
+type (
+	// CartShareService creates signed and expiring share tokens containing a snapshot of the cart items
+	// and imports such a snapshot into the cart of the current visitor
+	CartShareService struct {
+		cartService         *CartService
+		cartReceiverService *CartReceiverService
+		logger              flamingo.Logger
+		secret              []byte
+		tokenLifetime       time.Duration
+		now                 func() time.Time
+	}
+
+	// SharedCart is the snapshot of the cart encoded in a share token
+	SharedCart struct {
+		ExpiresAt  int64            `json:"exp"`
+		Deliveries []SharedDelivery `json:"deliveries"`
+	}
+
+	// SharedDelivery contains the shared items of one delivery
+	SharedDelivery struct {
+		DeliveryCode string       `json:"code"`
+		Items        []SharedItem `json:"items"`
+	}
+
+	// SharedItem contains everything needed to add the item again
+	SharedItem struct {
+		MarketplaceCode        string                            `json:"marketplaceCode"`
+		VariantMarketplaceCode string                            `json:"variantMarketplaceCode,omitempty"`
+		Qty                    int                               `json:"qty"`
+		BundleConfiguration    productDomain.BundleConfiguration `json:"bundleConfiguration,omitempty"`
+		AdditionalData         map[string]string                 `json:"additionalData,omitempty"`
+		Options                map[string]string                 `json:"options,omitempty"`
+	}
+
+	// CartImportResult reports a shared item which could not or only partially be added to the cart
+	CartImportResult struct {
+		OriginalItem cartDomain.Item
+		DeliveryCode string
+		// WasSkipped is true if the item has not been added at all
+		WasSkipped bool
+		// RestrictionResult is set if the qty of the item is restricted
+		RestrictionResult *validation.RestrictionResult
+		NewQty            int
+		// Reason contains the error message why the item could not be added
+		Reason string
+	}
+
+	// CartImportResults slice of CartImportResult
+	CartImportResults []CartImportResult
+)
+
+var (
+	// ErrInvalidShareToken is returned if the token is malformed or the signature does not match
+	ErrInvalidShareToken = errors.New("invalid cart share token")
+	// ErrShareTokenExpired is returned if the token lifetime is exceeded
+	ErrShareTokenExpired = errors.New("cart share token expired")
+	// ErrCartSharingDisabled is returned if no secret is configured for signing the tokens
+	ErrCartSharingDisabled = errors.New("cart sharing is disabled, commerce.cart.share.secret is not configured")
+)
+
+func init() {
+	gob.Register(CartImportResults{})
+}
+
+// Inject dependencies
+func (s *CartShareService) Inject(
+	cartService *CartService,
+	cartReceiverService *CartReceiverService,
+	logger flamingo.Logger,
+	config *struct {
+		Secret        string `inject:"config:commerce.cart.share.secret,optional"`
+		TokenLifetime string `inject:"config:commerce.cart.share.tokenLifetime,optional"`
+	},
+) *CartShareService {
+	s.cartService = cartService
+	s.cartReceiverService = cartReceiverService
+	s.logger = logger.WithField(flamingo.LogKeyModule, "cart").WithField(flamingo.LogKeyCategory, "application.cartShareService")
+	s.now = time.Now
+
+	if config != nil {
+		s.secret = []byte(config.Secret)
+
+		if config.TokenLifetime != "" {
+			var err error
+			s.tokenLifetime, err = time.ParseDuration(config.TokenLifetime)
+			if err != nil {
+				panic("can't parse commerce.cart.share.tokenLifetime")
+			}
+		}
+	}
+
+	return s
+}
+
+// ShareCart returns a signed token containing the items of the current cart
+func (s *CartShareService) ShareCart(ctx context.Context, session *web.Session) (string, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/CartShareService/ShareCart")
+	defer span.End()
+
+	cart, err := s.cartReceiverService.ViewCart(ctx, session)
+	if err != nil {
+		return "", err
+	}
+
+	return s.CreateToken(cart)
+}
+
+// CreateToken returns a signed token containing the items of the given cart
+func (s *CartShareService) CreateToken(cart *cartDomain.Cart) (string, error) {
+	if len(s.secret) == 0 {
+		return "", ErrCartSharingDisabled
+	}
+
+	sharedCart := SharedCart{
+		ExpiresAt: s.now().Add(s.tokenLifetime).Unix(),
+	}
+
+	for _, delivery := range cart.Deliveries {
+		if !delivery.HasItems() {
+			continue
+		}
+
+		sharedDelivery := SharedDelivery{DeliveryCode: delivery.DeliveryInfo.Code}
+		for _, item := range delivery.Cartitems {
+			sharedDelivery.Items = append(sharedDelivery.Items, SharedItem{
+				MarketplaceCode:        item.MarketplaceCode,
+				VariantMarketplaceCode: item.VariantMarketPlaceCode,
+				Qty:                    item.Qty,
+				BundleConfiguration:    item.BundleConfig,
+				AdditionalData:         item.AdditionalData,
+				Options:                item.OptionValues(),
+			})
+		}
+
+		sharedCart.Deliveries = append(sharedCart.Deliveries, sharedDelivery)
+	}
+
+	payload, err := json.Marshal(sharedCart)
+	if err != nil {
+		return "", fmt.Errorf("CartShareService: error encoding cart: %w", err)
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encodedPayload + "." + base64.RawURLEncoding.EncodeToString(s.sign(encodedPayload)), nil
+}
+
+// DecodeToken verifies the signature and expiry of the token and returns the contained cart snapshot
+func (s *CartShareService) DecodeToken(token string) (*SharedCart, error) {
+	if len(s.secret) == 0 {
+		return nil, ErrCartSharingDisabled
+	}
+
+	encodedPayload, encodedSignature, found := strings.Cut(token, ".")
+	if !found {
+		return nil, ErrInvalidShareToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, s.sign(encodedPayload)) {
+		return nil, ErrInvalidShareToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidShareToken
+	}
+
+	sharedCart := new(SharedCart)
+	if err := json.Unmarshal(payload, sharedCart); err != nil {
+		return nil, ErrInvalidShareToken
+	}
+
+	if s.now().Unix() > sharedCart.ExpiresAt {
+		return nil, ErrShareTokenExpired
+	}
+
+	return sharedCart, nil
+}
+
+// ImportCart adds the items of the shared cart to the current cart via CartService.AddProduct.
+// Items which could not be added completely (e.g. not saleable products or qty restrictions) are returned.
+func (s *CartShareService) ImportCart(ctx context.Context, session *web.Session, token string) (CartImportResults, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/CartShareService/ImportCart")
+	defer span.End()
+
+	sharedCart, err := s.DecodeToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(CartImportResults, 0)
+
+	for _, delivery := range sharedCart.Deliveries {
+		for _, item := range delivery.Items {
+			result, err := s.importItem(ctx, session, delivery.DeliveryCode, item)
+			if err != nil {
+				return results, err
+			}
+
+			if result != nil {
+				results = append(results, *result)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// importItem adds the shared item, if the qty is restricted the remaining qty is added instead
+func (s *CartShareService) importItem(ctx context.Context, session *web.Session, deliveryCode string, item SharedItem) (*CartImportResult, error) {
+	addRequest := s.cartService.BuildAddRequest(ctx, item.MarketplaceCode, item.VariantMarketplaceCode, item.Qty, item.AdditionalData)
+	addRequest.BundleConfiguration = item.BundleConfiguration
+	addRequest.Options = item.Options
+
+	result := &CartImportResult{
+		OriginalItem: cartDomain.Item{
+			MarketplaceCode:        item.MarketplaceCode,
+			VariantMarketPlaceCode: item.VariantMarketplaceCode,
+			Qty:                    item.Qty,
+			BundleConfig:           item.BundleConfiguration,
+			AdditionalData:         item.AdditionalData,
+		},
+		DeliveryCode: deliveryCode,
+		WasSkipped:   true,
+	}
+
+	_, err := s.cartService.AddProduct(ctx, session, deliveryCode, addRequest)
+	if err == nil {
+		return nil, nil
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return nil, err
+	}
+
+	result.Reason = err.Error()
+
+	var restrictionErr *RestrictionError
+	if !errors.As(err, &restrictionErr) {
+		return result, nil
+	}
+
+	restrictionResult := restrictionErr.RestrictionResult
+	result.RestrictionResult = &restrictionResult
+
+	if restrictionResult.RemainingDifference < 1 {
+		return result, nil
+	}
+
+	addRequest.Qty = restrictionResult.RemainingDifference
+
+	_, err = s.cartService.AddProduct(ctx, session, deliveryCode, addRequest)
+	if err != nil {
+		result.Reason = err.Error()
+		return result, nil
+	}
+
+	result.WasSkipped = false
+	result.NewQty = addRequest.Qty
+
+	return result, nil
+}
+
+func (s *CartShareService) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+
+	return mac.Sum(nil)
+}
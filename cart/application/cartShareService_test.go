@@ -0,0 +1,163 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	cartApplication "flamingo.me/flamingo-commerce/v3/cart/application"
+	cartDomain "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/decorator"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/validation"
+)
+
+func newCartShareService(restrictor validation.MaxQuantityRestrictor, tokenLifetime string) *cartApplication.CartShareService {
+	eventRouter := new(MockEventRouter)
+	eventRouter.On("Dispatch", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	decoratedCartFactory := &decorator.DecoratedCartFactory{}
+	decoratedCartFactory.Inject(&MockProductService{}, flamingo.NullLogger{}, nil)
+
+	crs := &cartApplication.CartReceiverService{}
+	crs.Inject(
+		new(MockGuestCartServiceWithModifyBehaviour),
+		nil,
+		decoratedCartFactory,
+		nil,
+		flamingo.NullLogger{},
+		eventRouter,
+		nil,
+	)
+
+	cs := &cartApplication.CartService{}
+	cs.Inject(
+		crs,
+		&MockProductService{},
+		new(MockEventPublisher),
+		eventRouter,
+		new(MockDeliveryInfoBuilder),
+		new(validation.RestrictionService).Inject([]validation.MaxQuantityRestrictor{restrictor}),
+		nil,
+		flamingo.NullLogger{},
+		&struct {
+			DefaultDeliveryCode string `inject:"config:commerce.cart.defaultDeliveryCode,optional"`
+			DeleteEmptyDelivery bool   `inject:"config:commerce.cart.deleteEmptyDelivery,optional"`
+		}{
+			DefaultDeliveryCode: "default_delivery_code",
+		},
+		nil,
+	)
+
+	return new(cartApplication.CartShareService).Inject(cs, crs, flamingo.NullLogger{}, &struct {
+		Secret        string `inject:"config:commerce.cart.share.secret,optional"`
+		TokenLifetime string `inject:"config:commerce.cart.share.tokenLifetime,optional"`
+	}{
+		Secret:        "secret",
+		TokenLifetime: tokenLifetime,
+	})
+}
+
+func TestCartShareService_Token(t *testing.T) {
+	t.Parallel()
+
+	cart := &cartDomain.Cart{
+		ID: "17",
+		Deliveries: []cartDomain.Delivery{
+			{
+				DeliveryInfo: cartDomain.DeliveryInfo{Code: "delivery"},
+				Cartitems: []cartDomain.Item{
+					{ID: "1", MarketplaceCode: "code-1", VariantMarketPlaceCode: "variant-1", Qty: 2},
+				},
+			},
+			{
+				DeliveryInfo: cartDomain.DeliveryInfo{Code: "empty"},
+			},
+		},
+	}
+
+	t.Run("token contains the items", func(t *testing.T) {
+		t.Parallel()
+
+		service := newCartShareService(&MockRestrictor{}, "1h")
+
+		token, err := service.CreateToken(cart)
+		require.NoError(t, err)
+
+		sharedCart, err := service.DecodeToken(token)
+		require.NoError(t, err)
+		require.Len(t, sharedCart.Deliveries, 1)
+		assert.Equal(t, "delivery", sharedCart.Deliveries[0].DeliveryCode)
+		assert.Equal(t, []cartApplication.SharedItem{{MarketplaceCode: "code-1", VariantMarketplaceCode: "variant-1", Qty: 2}}, sharedCart.Deliveries[0].Items)
+	})
+
+	t.Run("manipulated token is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		service := newCartShareService(&MockRestrictor{}, "1h")
+
+		token, err := service.CreateToken(cart)
+		require.NoError(t, err)
+
+		_, err = service.DecodeToken("e30" + token[3:])
+		assert.ErrorIs(t, err, cartApplication.ErrInvalidShareToken)
+
+		_, err = service.DecodeToken("invalid")
+		assert.ErrorIs(t, err, cartApplication.ErrInvalidShareToken)
+	})
+
+	t.Run("expired token is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		service := newCartShareService(&MockRestrictor{}, "-1h")
+
+		token, err := service.CreateToken(cart)
+		require.NoError(t, err)
+
+		_, err = service.DecodeToken(token)
+		assert.ErrorIs(t, err, cartApplication.ErrShareTokenExpired)
+	})
+
+	t.Run("sharing is disabled without secret", func(t *testing.T) {
+		t.Parallel()
+
+		service := new(cartApplication.CartShareService).Inject(nil, nil, flamingo.NullLogger{}, nil)
+
+		_, err := service.CreateToken(cart)
+		assert.ErrorIs(t, err, cartApplication.ErrCartSharingDisabled)
+	})
+}
+
+func TestCartShareService_ImportCart(t *testing.T) {
+	t.Parallel()
+
+	service := newCartShareService(&MockRestrictor{IsRestricted: true, MaxQty: 3, DifferenceQty: 3}, "1h")
+
+	token, err := service.CreateToken(&cartDomain.Cart{
+		Deliveries: []cartDomain.Delivery{
+			{
+				DeliveryInfo: cartDomain.DeliveryInfo{Code: "default_delivery_code"},
+				Cartitems: []cartDomain.Item{
+					{ID: "1", MarketplaceCode: "code-1", Qty: 1},
+					{ID: "2", MarketplaceCode: "code-2", Qty: 5},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	results, err := service.ImportCart(context.Background(), web.EmptySession(), token)
+	require.NoError(t, err)
+
+	require.Len(t, results, 1)
+	assert.Equal(t, "code-2", results[0].OriginalItem.MarketplaceCode)
+	assert.Equal(t, "default_delivery_code", results[0].DeliveryCode)
+	assert.False(t, results[0].WasSkipped)
+	assert.Equal(t, 3, results[0].NewQty)
+	require.NotNil(t, results[0].RestrictionResult)
+	assert.Equal(t, 3, results[0].RestrictionResult.MaxAllowed)
+}
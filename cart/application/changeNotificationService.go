@@ -0,0 +1,66 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"flamingo.me/flamingo/v3/framework/web"
+	"go.opencensus.io/trace"
+
+	cartDomain "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+)
+
+type (
+	// ChangeNotificationService subscribes clients to the change notifications of the current cart which are published by the CartService
+	ChangeNotificationService struct {
+		cartReceiverService *CartReceiverService
+		notifier            cartDomain.ChangeNotifier
+	}
+)
+
+var (
+	// ErrNoChangeNotifier is returned if cart change notifications are requested but no ChangeNotifier is bound
+	ErrNoChangeNotifier = errors.New("no cart change notifier bound")
+)
+
+// Inject dependencies
+func (s *ChangeNotificationService) Inject(
+	cartReceiverService *CartReceiverService,
+	optionals *struct {
+		Notifier cartDomain.ChangeNotifier `inject:",optional"`
+	},
+) *ChangeNotificationService {
+	s.cartReceiverService = cartReceiverService
+
+	if optionals != nil {
+		s.notifier = optionals.Notifier
+	}
+
+	return s
+}
+
+// SubscribeCurrentCart subscribes to the changes of the current cart and all carts of the logged-in customer until ctx is done.
+// The returned notification describes the current state of the cart so that clients can sync before waiting for changes.
+func (s *ChangeNotificationService) SubscribeCurrentCart(ctx context.Context, session *web.Session) (cartDomain.ChangeNotification, <-chan cartDomain.ChangeNotification, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/ChangeNotificationService/SubscribeCurrentCart")
+	defer span.End()
+
+	if s.notifier == nil {
+		return cartDomain.ChangeNotification{}, nil, ErrNoChangeNotifier
+	}
+
+	cart, err := s.cartReceiverService.ViewCart(ctx, session)
+	if err != nil {
+		return cartDomain.ChangeNotification{}, nil, fmt.Errorf("ChangeNotificationService: error getting cart: %w", err)
+	}
+
+	current := cartDomain.NewChangeNotification(cart, "")
+
+	notifications, err := s.notifier.Subscribe(ctx, cartDomain.ChangeSubscription{CartID: current.CartID, CustomerID: current.CustomerID})
+	if err != nil {
+		return cartDomain.ChangeNotification{}, nil, fmt.Errorf("ChangeNotificationService: error subscribing to cart %q: %w", cart.ID, err)
+	}
+
+	return current, notifications, nil
+}
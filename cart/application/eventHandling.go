@@ -26,18 +26,22 @@ type (
 		cartMerger          CartMerger
 	}
 
+	// CartMerger merges the guest cart into the customer cart after login
 	CartMerger interface {
-		Merge(ctx context.Context, session *web.Session, guestCart cartDomain.Cart, customerCart cartDomain.Cart)
+		// Merge returns a report of the merged, adjusted and dropped parts of the guest cart, nil if nothing has been merged
+		Merge(ctx context.Context, session *web.Session, guestCart cartDomain.Cart, customerCart cartDomain.Cart) *CartMergeReport
 	}
 
 	CartMergeStrategyMerge struct {
-		cartService Service
-		logger      flamingo.Logger
+		cartService      Service
+		lineItemStrategy cartDomain.LineItemStrategy
+		logger           flamingo.Logger
 	}
 
 	CartMergeStrategyReplace struct {
-		cartService Service
-		logger      flamingo.Logger
+		cartService      Service
+		lineItemStrategy cartDomain.LineItemStrategy
+		logger           flamingo.Logger
 	}
 
 	CartMergeStrategyNone struct{}
@@ -129,7 +133,11 @@ func (e *EventReceiver) handleLoginEvent(ctx context.Context, loginEvent *auth.W
 	e.eventRouter.Dispatch(ctx, &PreCartMergeEvent{GuestCart: clonedGuestCart, CustomerCart: clonedCustomerCart})
 
 	// merge the cart depending on the set strategy
-	e.cartMerger.Merge(ctx, session, *guestCart, *customerCart)
+	mergeReport := e.cartMerger.Merge(ctx, session, *guestCart, *customerCart)
+	if mergeReport != nil {
+		// the report is shown to the customer after the login redirect, see LastCartMergeReport
+		session.AddFlash(*mergeReport, CartMergeReportFlashKey)
+	}
 
 	if e.cartCache != nil {
 		cacheID, err := e.cartCache.BuildIdentifier(ctx, session)
@@ -178,25 +186,41 @@ func (e *EventReceiver) Notify(ctx context.Context, event flamingo.Event) {
 	}
 }
 
-func (c *CartMergeStrategyNone) Merge(_ context.Context, _ *web.Session, _ cartDomain.Cart, _ cartDomain.Cart) {
+// Merge does nothing and returns no report
+func (c *CartMergeStrategyNone) Merge(_ context.Context, _ *web.Session, _ cartDomain.Cart, _ cartDomain.Cart) *CartMergeReport {
 	// do nothing
+	return nil
 }
 
 // Inject dependencies
 func (c *CartMergeStrategyReplace) Inject(
 	logger flamingo.Logger,
 	cartService Service,
+	lineItemStrategy cartDomain.LineItemStrategy,
 ) *CartMergeStrategyReplace {
 	c.logger = logger
 	c.cartService = cartService
+	c.lineItemStrategy = lineItemStrategy
+
+	if c.lineItemStrategy == nil {
+		c.lineItemStrategy = new(cartDomain.DefaultLineItemStrategy)
+	}
 
 	return c
 }
 
+// Merge replaces the content of the customer cart with the guest cart
+//
 //nolint:cyclop // setting all cart attributes is a complex task
-func (c *CartMergeStrategyReplace) Merge(ctx context.Context, session *web.Session, guestCart cartDomain.Cart, _ cartDomain.Cart) {
+func (c *CartMergeStrategyReplace) Merge(ctx context.Context, session *web.Session, guestCart cartDomain.Cart, customerCart cartDomain.Cart) *CartMergeReport {
 	var err error
 
+	report := &CartMergeReport{
+		GuestCartID:      guestCart.ID,
+		CustomerCartID:   customerCart.ID,
+		PaymentSelection: CartMergePaymentSelectionNone,
+	}
+
 	c.logger.WithContext(ctx).Info("cleaning existing customer cart, to be able to replace the content with the guest one.")
 
 	err = c.cartService.Clean(ctx, session)
@@ -204,7 +228,7 @@ func (c *CartMergeStrategyReplace) Merge(ctx context.Context, session *web.Sessi
 		c.logger.WithContext(ctx).Error(fmt.Errorf("cleaning the customer cart didn't work: %w", err))
 	}
 
-	errAddDelivery := c.addDeliveries(ctx, session, guestCart)
+	errAddDelivery := c.addDeliveries(ctx, session, guestCart, report)
 
 	if guestCart.BillingAddress != nil {
 		err = c.cartService.UpdateBillingAddress(ctx, session, guestCart.BillingAddress)
@@ -225,6 +249,8 @@ func (c *CartMergeStrategyReplace) Merge(ctx context.Context, session *web.Sessi
 			_, err = c.cartService.ApplyVoucher(ctx, session, code.Code)
 			if err != nil {
 				c.logger.WithContext(ctx).Error(fmt.Errorf("couldn't apply voucher %q: %w", code.Code, err))
+
+				report.DroppedVouchers = append(report.DroppedVouchers, CartMergeReportCode{Code: code.Code, Reason: err.Error()})
 			}
 		}
 	}
@@ -234,20 +260,30 @@ func (c *CartMergeStrategyReplace) Merge(ctx context.Context, session *web.Sessi
 			_, err = c.cartService.ApplyGiftCard(ctx, session, code.Code)
 			if err != nil {
 				c.logger.WithContext(ctx).Error(fmt.Errorf("couldn't apply gift card %q: %w", code.Code, err))
+
+				report.DroppedGiftCards = append(report.DroppedGiftCards, CartMergeReportCode{Code: code.Code, Reason: err.Error()})
 			}
 		}
 	}
 
+	if guestCart.PaymentSelection != nil {
+		report.PaymentSelection = CartMergePaymentSelectionDropped
+	}
+
 	if errAddDelivery == nil && guestCart.PaymentSelection != nil {
 		err = c.cartService.UpdatePaymentSelection(ctx, session, guestCart.PaymentSelection)
 
 		if err != nil {
 			c.logger.WithContext(ctx).Error(fmt.Errorf("couldn't payment selection: %w", err))
+		} else {
+			report.PaymentSelection = CartMergePaymentSelectionApplied
 		}
 	}
+
+	return report
 }
 
-func (c *CartMergeStrategyReplace) addDeliveries(ctx context.Context, session *web.Session, guestCart cartDomain.Cart) error {
+func (c *CartMergeStrategyReplace) addDeliveries(ctx context.Context, session *web.Session, guestCart cartDomain.Cart, report *CartMergeReport) error {
 	var errAddDeliveries error
 
 	for _, delivery := range guestCart.Deliveries {
@@ -255,12 +291,14 @@ func (c *CartMergeStrategyReplace) addDeliveries(ctx context.Context, session *w
 		if err != nil {
 			c.logger.WithContext(ctx).Error(fmt.Errorf("error during delivery info update: %w", err))
 
+			dropDeliveryItems(report, delivery, err)
+
 			errAddDeliveries = err
 
 			continue
 		}
 
-		err = c.addItems(ctx, session, delivery)
+		err = c.addItems(ctx, session, delivery, report)
 		if err != nil {
 			errAddDeliveries = err
 		}
@@ -273,7 +311,7 @@ func (c *CartMergeStrategyReplace) addDeliveries(ctx context.Context, session *w
 	return nil
 }
 
-func (c *CartMergeStrategyReplace) addItems(ctx context.Context, session *web.Session, delivery cartDomain.Delivery) error {
+func (c *CartMergeStrategyReplace) addItems(ctx context.Context, session *web.Session, delivery cartDomain.Delivery, report *CartMergeReport) error {
 	var errAddItems error
 
 	for _, item := range delivery.Cartitems {
@@ -284,13 +322,18 @@ func (c *CartMergeStrategyReplace) addItems(ctx context.Context, session *web.Se
 			VariantMarketplaceCode: item.VariantMarketPlaceCode,
 			AdditionalData:         item.AdditionalData,
 			BundleConfiguration:    item.BundleConfig,
+			Options:                item.OptionValues(),
 		}
 
-		_, err := c.cartService.AddProduct(ctx, session, delivery.DeliveryInfo.Code, addRequest)
-		if err != nil {
-			c.logger.WithContext(ctx).Error(fmt.Errorf("add to cart for guest item %v failed: %w", item, err))
+		for _, lineAddRequest := range c.lineItemStrategy.SplitAddRequest(addRequest) {
+			reportItem, err := mergeLine(ctx, c.cartService, session, delivery.DeliveryInfo.Code, lineAddRequest)
+			report.addMergedItem(reportItem)
 
-			errAddItems = err
+			if err != nil {
+				c.logger.WithContext(ctx).Error(fmt.Errorf("add to cart for guest item %v failed: %w", item, err))
+
+				errAddItems = err
+			}
 		}
 	}
 
@@ -305,18 +348,32 @@ func (c *CartMergeStrategyReplace) addItems(ctx context.Context, session *web.Se
 func (c *CartMergeStrategyMerge) Inject(
 	logger flamingo.Logger,
 	cartService Service,
+	lineItemStrategy cartDomain.LineItemStrategy,
 ) *CartMergeStrategyMerge {
 	c.logger = logger
 	c.cartService = cartService
+	c.lineItemStrategy = lineItemStrategy
+
+	if c.lineItemStrategy == nil {
+		c.lineItemStrategy = new(cartDomain.DefaultLineItemStrategy)
+	}
 
 	return c
 }
 
+// Merge adds the content of the guest cart to the customer cart
+//
 //nolint:cyclop,gocognit // setting all cart attributes is a complex task
-func (c *CartMergeStrategyMerge) Merge(ctx context.Context, session *web.Session, guestCart cartDomain.Cart, customerCart cartDomain.Cart) {
+func (c *CartMergeStrategyMerge) Merge(ctx context.Context, session *web.Session, guestCart cartDomain.Cart, customerCart cartDomain.Cart) *CartMergeReport {
 	var err error
 
-	errAddDelivery := c.addDeliveries(ctx, session, guestCart, customerCart)
+	report := &CartMergeReport{
+		GuestCartID:      guestCart.ID,
+		CustomerCartID:   customerCart.ID,
+		PaymentSelection: CartMergePaymentSelectionNone,
+	}
+
+	errAddDelivery := c.addDeliveries(ctx, session, guestCart, customerCart, report)
 
 	if customerCart.BillingAddress == nil && guestCart.BillingAddress != nil {
 		err = c.cartService.UpdateBillingAddress(ctx, session, guestCart.BillingAddress)
@@ -337,6 +394,8 @@ func (c *CartMergeStrategyMerge) Merge(ctx context.Context, session *web.Session
 			_, err = c.cartService.ApplyVoucher(ctx, session, code.Code)
 			if err != nil {
 				c.logger.WithContext(ctx).Error("WebLoginEvent - customerCart ApplyVoucher has error", code.Code, err)
+
+				report.DroppedVouchers = append(report.DroppedVouchers, CartMergeReportCode{Code: code.Code, Reason: err.Error()})
 			}
 		}
 	}
@@ -346,19 +405,34 @@ func (c *CartMergeStrategyMerge) Merge(ctx context.Context, session *web.Session
 			_, err = c.cartService.ApplyGiftCard(ctx, session, code.Code)
 			if err != nil {
 				c.logger.WithContext(ctx).Error("WebLoginEvent - customerCart ApplyGiftCard has error", code.Code, err)
+
+				report.DroppedGiftCards = append(report.DroppedGiftCards, CartMergeReportCode{Code: code.Code, Reason: err.Error()})
 			}
 		}
 	}
 
-	if errAddDelivery == nil && customerCart.PaymentSelection == nil && guestCart.PaymentSelection != nil && customerCart.ItemCount() == 0 {
+	switch {
+	case guestCart.PaymentSelection == nil:
+		report.PaymentSelection = CartMergePaymentSelectionNone
+	case errAddDelivery != nil:
+		report.PaymentSelection = CartMergePaymentSelectionDropped
+	case customerCart.PaymentSelection != nil || customerCart.ItemCount() != 0:
+		report.PaymentSelection = CartMergePaymentSelectionKept
+	default:
+		report.PaymentSelection = CartMergePaymentSelectionApplied
+
 		err = c.cartService.UpdatePaymentSelection(ctx, session, guestCart.PaymentSelection)
 		if err != nil {
 			c.logger.WithContext(ctx).Error("WebLoginEvent - customerCart UpdatePaymentSelection error", err)
+
+			report.PaymentSelection = CartMergePaymentSelectionDropped
 		}
 	}
+
+	return report
 }
 
-func (c *CartMergeStrategyMerge) addDeliveries(ctx context.Context, session *web.Session, guestCart cartDomain.Cart, customerCart cartDomain.Cart) error {
+func (c *CartMergeStrategyMerge) addDeliveries(ctx context.Context, session *web.Session, guestCart cartDomain.Cart, customerCart cartDomain.Cart, report *CartMergeReport) error {
 	var errAddDeliveries error
 
 	for _, delivery := range guestCart.Deliveries {
@@ -368,12 +442,14 @@ func (c *CartMergeStrategyMerge) addDeliveries(ctx context.Context, session *web
 		if err != nil {
 			c.logger.WithContext(ctx).Error("WebLoginEvent customerCart UpdateDeliveryInfo error", err)
 
+			dropDeliveryItems(report, delivery, err)
+
 			errAddDeliveries = err
 
 			continue
 		}
 
-		err = c.addItems(ctx, session, delivery)
+		err = c.addItems(ctx, session, delivery, report)
 		if err != nil {
 			errAddDeliveries = err
 		}
@@ -386,7 +462,7 @@ func (c *CartMergeStrategyMerge) addDeliveries(ctx context.Context, session *web
 	return nil
 }
 
-func (c *CartMergeStrategyMerge) addItems(ctx context.Context, session *web.Session, delivery cartDomain.Delivery) error {
+func (c *CartMergeStrategyMerge) addItems(ctx context.Context, session *web.Session, delivery cartDomain.Delivery, report *CartMergeReport) error {
 	var errAddItems error
 
 	for _, item := range delivery.Cartitems {
@@ -397,13 +473,18 @@ func (c *CartMergeStrategyMerge) addItems(ctx context.Context, session *web.Sess
 			VariantMarketplaceCode: item.VariantMarketPlaceCode,
 			AdditionalData:         item.AdditionalData,
 			BundleConfiguration:    item.BundleConfig,
+			Options:                item.OptionValues(),
 		}
 
-		_, err := c.cartService.AddProduct(ctx, session, delivery.DeliveryInfo.Code, addRequest)
-		if err != nil {
-			c.logger.WithContext(ctx).Error("WebLoginEvent - customerCart product has merge error", addRequest.MarketplaceCode, err)
+		for _, lineAddRequest := range c.lineItemStrategy.SplitAddRequest(addRequest) {
+			reportItem, err := mergeLine(ctx, c.cartService, session, delivery.DeliveryInfo.Code, lineAddRequest)
+			report.addMergedItem(reportItem)
+
+			if err != nil {
+				c.logger.WithContext(ctx).Error("WebLoginEvent - customerCart product has merge error", addRequest.MarketplaceCode, err)
 
-			errAddItems = err
+				errAddItems = err
+			}
 		}
 	}
 
@@ -413,3 +494,54 @@ func (c *CartMergeStrategyMerge) addItems(ctx context.Context, session *web.Sess
 
 	return nil
 }
+
+// mergeLine adds the line of the guest cart, if its qty is restricted the remaining qty is added instead.
+// The error is returned if the line could not be added with its full qty.
+func mergeLine(ctx context.Context, cartService Service, session *web.Session, deliveryCode string, addRequest cartDomain.AddRequest) (CartMergeReportItem, error) {
+	reportItem := CartMergeReportItem{
+		DeliveryCode:           deliveryCode,
+		MarketplaceCode:        addRequest.MarketplaceCode,
+		VariantMarketplaceCode: addRequest.VariantMarketplaceCode,
+		RequestedQty:           addRequest.Qty,
+	}
+
+	_, err := cartService.AddProduct(ctx, session, deliveryCode, addRequest)
+	if err == nil {
+		reportItem.MergedQty = addRequest.Qty
+
+		return reportItem, nil
+	}
+
+	reportItem.Reason = err.Error()
+
+	var restrictionErr *RestrictionError
+	if !errors.As(err, &restrictionErr) || restrictionErr.RestrictionResult.RemainingDifference < 1 {
+		return reportItem, err
+	}
+
+	addRequest.Qty = restrictionErr.RestrictionResult.RemainingDifference
+
+	_, errRemaining := cartService.AddProduct(ctx, session, deliveryCode, addRequest)
+	if errRemaining != nil {
+		reportItem.Reason = errRemaining.Error()
+
+		return reportItem, errRemaining
+	}
+
+	reportItem.MergedQty = addRequest.Qty
+
+	return reportItem, err
+}
+
+// dropDeliveryItems reports all items of the delivery as dropped
+func dropDeliveryItems(report *CartMergeReport, delivery cartDomain.Delivery, err error) {
+	for _, item := range delivery.Cartitems {
+		report.addMergedItem(CartMergeReportItem{
+			DeliveryCode:           delivery.DeliveryInfo.Code,
+			MarketplaceCode:        item.MarketplaceCode,
+			VariantMarketplaceCode: item.VariantMarketPlaceCode,
+			RequestedQty:           item.Qty,
+			Reason:                 err.Error(),
+		})
+	}
+}
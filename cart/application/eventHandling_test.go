@@ -9,11 +9,14 @@ import (
 	authMock "flamingo.me/flamingo/v3/core/auth/mock"
 	"flamingo.me/flamingo/v3/framework/flamingo"
 	"flamingo.me/flamingo/v3/framework/web"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"flamingo.me/flamingo-commerce/v3/cart/application"
 	"flamingo.me/flamingo-commerce/v3/cart/application/mocks"
 	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/validation"
 	"flamingo.me/flamingo-commerce/v3/product/domain"
 )
 
@@ -83,7 +86,11 @@ func TestEventReceiver_Notify(t *testing.T) {
 		cartReceiver.EXPECT().ViewGuestCart(mock.Anything, session).Return(&guestCart, nil)
 		cartReceiver.EXPECT().ViewCart(mock.Anything, session).Return(&customerCart, nil)
 		cartMerger := mocks.NewCartMerger(t)
-		cartMerger.EXPECT().Merge(mock.Anything, session, guestCart, customerCart)
+		cartMerger.EXPECT().Merge(mock.Anything, session, guestCart, customerCart).Return(&application.CartMergeReport{
+			GuestCartID:    "guestCart",
+			CustomerCartID: "customerCart",
+			DroppedItems:   []application.CartMergeReportItem{{MarketplaceCode: "foo", RequestedQty: 1}},
+		})
 
 		cartCache := mocks.NewCartCache(t)
 		cartCache.EXPECT().BuildIdentifier(mock.Anything, session).Return(application.CartCacheIdentifier{}, nil)
@@ -100,6 +107,12 @@ func TestEventReceiver_Notify(t *testing.T) {
 			Broker:   "example",
 			Identity: &authMock.Identity{},
 		})
+
+		report := application.LastCartMergeReport(session)
+		require.NotNil(t, report)
+		assert.Equal(t, "guestCart", report.GuestCartID)
+		assert.True(t, report.HasConflicts())
+		assert.Nil(t, application.LastCartMergeReport(session), "the report is a flash")
 	})
 }
 
@@ -133,8 +146,8 @@ func TestCartMergeStrategyReplace_Merge(t *testing.T) {
 		cartService.EXPECT().ApplyVoucher(mock.Anything, session, "SUMMER_SALE").Return(&cart.Cart{}, nil)
 		cartService.EXPECT().ApplyGiftCard(mock.Anything, session, "GHDJAHJH-DADAD-2113").Return(&cart.Cart{}, nil)
 		cartService.EXPECT().UpdatePaymentSelection(mock.Anything, session, mock.Anything).Return(nil)
-		c.Inject(flamingo.NullLogger{}, cartService)
-		c.Merge(context.Background(), session, cart.Cart{
+		c.Inject(flamingo.NullLogger{}, cartService, nil)
+		report := c.Merge(context.Background(), session, cart.Cart{
 			ID: "guest", BelongsToAuthenticatedUser: false,
 			Deliveries: []cart.Delivery{{
 				DeliveryInfo: cart.DeliveryInfo{Code: "delivery1"},
@@ -158,6 +171,10 @@ func TestCartMergeStrategyReplace_Merge(t *testing.T) {
 			AppliedGiftCards:   []cart.AppliedGiftCard{{Code: "GHDJAHJH-DADAD-2113"}},
 			PaymentSelection:   cart.DefaultPaymentSelection{},
 		}, cart.Cart{ID: "customer", BelongsToAuthenticatedUser: true})
+
+		assert.False(t, report.HasConflicts())
+		assert.Len(t, report.MergedItems, 2)
+		assert.Equal(t, application.CartMergePaymentSelectionApplied, report.PaymentSelection)
 	})
 
 	t.Run("one item addition failed", func(t *testing.T) {
@@ -187,8 +204,8 @@ func TestCartMergeStrategyReplace_Merge(t *testing.T) {
 		cartService.EXPECT().ApplyVoucher(mock.Anything, session, "SUMMER_SALE").Return(&cart.Cart{}, nil)
 		cartService.EXPECT().ApplyGiftCard(mock.Anything, session, "GHDJAHJH-DADAD-2113").Return(&cart.Cart{}, nil)
 
-		c.Inject(flamingo.NullLogger{}, cartService)
-		c.Merge(context.Background(), session, cart.Cart{
+		c.Inject(flamingo.NullLogger{}, cartService, nil)
+		report := c.Merge(context.Background(), session, cart.Cart{
 			ID: "guest", BelongsToAuthenticatedUser: false,
 			Deliveries: []cart.Delivery{{
 				DeliveryInfo: cart.DeliveryInfo{Code: "delivery1"},
@@ -212,6 +229,10 @@ func TestCartMergeStrategyReplace_Merge(t *testing.T) {
 			AppliedGiftCards:   []cart.AppliedGiftCard{{Code: "GHDJAHJH-DADAD-2113"}},
 			PaymentSelection:   cart.DefaultPaymentSelection{},
 		}, cart.Cart{ID: "customer", BelongsToAuthenticatedUser: true})
+
+		assert.True(t, report.HasConflicts())
+		assert.Equal(t, []application.CartMergeReportItem{{DeliveryCode: "delivery1", MarketplaceCode: "bundle", RequestedQty: 1, Reason: "some error"}}, report.DroppedItems)
+		assert.Equal(t, application.CartMergePaymentSelectionDropped, report.PaymentSelection)
 	})
 }
 
@@ -244,8 +265,8 @@ func TestCartMergeStrategyMerge_Merge(t *testing.T) {
 		cartService.EXPECT().ApplyVoucher(mock.Anything, session, "SUMMER_SALE").Return(&cart.Cart{}, nil)
 		cartService.EXPECT().ApplyGiftCard(mock.Anything, session, "GHDJAHJH-DADAD-2113").Return(&cart.Cart{}, nil)
 		cartService.EXPECT().UpdatePaymentSelection(mock.Anything, session, mock.Anything).Return(nil)
-		c.Inject(flamingo.NullLogger{}, cartService)
-		c.Merge(context.Background(), session, cart.Cart{
+		c.Inject(flamingo.NullLogger{}, cartService, nil)
+		report := c.Merge(context.Background(), session, cart.Cart{
 			ID: "guest", BelongsToAuthenticatedUser: false,
 			Deliveries: []cart.Delivery{{
 				DeliveryInfo: cart.DeliveryInfo{Code: "delivery1"},
@@ -269,6 +290,10 @@ func TestCartMergeStrategyMerge_Merge(t *testing.T) {
 			AppliedGiftCards:   []cart.AppliedGiftCard{{Code: "GHDJAHJH-DADAD-2113"}},
 			PaymentSelection:   cart.DefaultPaymentSelection{},
 		}, cart.Cart{ID: "customer", BelongsToAuthenticatedUser: true})
+
+		assert.False(t, report.HasConflicts())
+		assert.Len(t, report.MergedItems, 2)
+		assert.Equal(t, application.CartMergePaymentSelectionApplied, report.PaymentSelection)
 	})
 
 	t.Run("failed to add one item", func(t *testing.T) {
@@ -296,8 +321,8 @@ func TestCartMergeStrategyMerge_Merge(t *testing.T) {
 		cartService.EXPECT().UpdatePurchaser(mock.Anything, session, mock.Anything, mock.Anything).Return(nil)
 		cartService.EXPECT().ApplyVoucher(mock.Anything, session, "SUMMER_SALE").Return(&cart.Cart{}, nil)
 		cartService.EXPECT().ApplyGiftCard(mock.Anything, session, "GHDJAHJH-DADAD-2113").Return(&cart.Cart{}, nil)
-		c.Inject(flamingo.NullLogger{}, cartService)
-		c.Merge(context.Background(), session, cart.Cart{
+		c.Inject(flamingo.NullLogger{}, cartService, nil)
+		report := c.Merge(context.Background(), session, cart.Cart{
 			ID: "guest", BelongsToAuthenticatedUser: false,
 			Deliveries: []cart.Delivery{{
 				DeliveryInfo: cart.DeliveryInfo{Code: "delivery1"},
@@ -321,6 +346,86 @@ func TestCartMergeStrategyMerge_Merge(t *testing.T) {
 			AppliedGiftCards:   []cart.AppliedGiftCard{{Code: "GHDJAHJH-DADAD-2113"}},
 			PaymentSelection:   cart.DefaultPaymentSelection{},
 		}, cart.Cart{ID: "customer", BelongsToAuthenticatedUser: true})
+
+		assert.True(t, report.HasConflicts())
+		assert.Equal(t, []application.CartMergeReportItem{{DeliveryCode: "delivery1", MarketplaceCode: "foo", RequestedQty: 1, Reason: "some error"}}, report.DroppedItems)
+		assert.Equal(t, application.CartMergePaymentSelectionDropped, report.PaymentSelection)
+	})
+
+	t.Run("items are split by the line item strategy", func(t *testing.T) {
+		t.Parallel()
+
+		session := web.EmptySession()
+
+		strategy := new(cart.DefaultLineItemStrategy).Inject(&struct {
+			DistinguishingAdditionalDataKeys []string `inject:"config:commerce.cart.lineItems.distinguishingAdditionalDataKeys,optional"`
+			UnmergeableAdditionalDataKeys    []string `inject:"config:commerce.cart.lineItems.unmergeableAdditionalDataKeys,optional"`
+			SingleUnitAdditionalDataKeys     []string `inject:"config:commerce.cart.lineItems.singleUnitAdditionalDataKeys,optional"`
+		}{
+			SingleUnitAdditionalDataKeys: []string{"personalisation"},
+		})
+
+		c := &application.CartMergeStrategyMerge{}
+		cartService := mocks.NewCartService(t)
+		cartService.EXPECT().UpdateDeliveryInfo(mock.Anything, session, "delivery1", mock.Anything).Return(nil)
+		cartService.EXPECT().AddProduct(mock.Anything, session, "delivery1", cart.AddRequest{
+			MarketplaceCode: "foo",
+			Qty:             1,
+			AdditionalData:  map[string]string{"personalisation": "A"},
+		}).Return(nil, nil).Times(2)
+		c.Inject(flamingo.NullLogger{}, cartService, strategy)
+		report := c.Merge(context.Background(), session, cart.Cart{
+			ID: "guest",
+			Deliveries: []cart.Delivery{{
+				DeliveryInfo: cart.DeliveryInfo{Code: "delivery1"},
+				Cartitems: []cart.Item{
+					{MarketplaceCode: "foo", Qty: 2, AdditionalData: map[string]string{"personalisation": "A"}},
+				},
+			}},
+		}, cart.Cart{ID: "customer", BelongsToAuthenticatedUser: true})
+
+		assert.Len(t, report.MergedItems, 2)
+		assert.Equal(t, application.CartMergePaymentSelectionNone, report.PaymentSelection)
+	})
+
+	t.Run("restricted qty and dropped codes are reported", func(t *testing.T) {
+		t.Parallel()
+
+		session := web.EmptySession()
+
+		c := &application.CartMergeStrategyMerge{}
+		cartService := mocks.NewCartService(t)
+		cartService.EXPECT().UpdateDeliveryInfo(mock.Anything, session, "delivery1", mock.Anything).Return(nil)
+		cartService.EXPECT().AddProduct(mock.Anything, session, "delivery1", cart.AddRequest{
+			MarketplaceCode: "foo",
+			Qty:             5,
+		}).Return(nil, &application.RestrictionError{RestrictionResult: validation.RestrictionResult{IsRestricted: true, MaxAllowed: 3, RemainingDifference: 2}})
+		cartService.EXPECT().AddProduct(mock.Anything, session, "delivery1", cart.AddRequest{
+			MarketplaceCode: "foo",
+			Qty:             2,
+		}).Return(nil, nil)
+		cartService.EXPECT().ApplyVoucher(mock.Anything, session, "SUMMER_SALE").Return(nil, errors.New("voucher expired"))
+		cartService.EXPECT().ApplyGiftCard(mock.Anything, session, "GHDJAHJH-DADAD-2113").Return(nil, errors.New("gift card not found"))
+		c.Inject(flamingo.NullLogger{}, cartService, nil)
+		report := c.Merge(context.Background(), session, cart.Cart{
+			ID: "guest",
+			Deliveries: []cart.Delivery{{
+				DeliveryInfo: cart.DeliveryInfo{Code: "delivery1"},
+				Cartitems:    []cart.Item{{MarketplaceCode: "foo", Qty: 5}},
+			}},
+			AppliedCouponCodes: []cart.CouponCode{{Code: "SUMMER_SALE"}},
+			AppliedGiftCards:   []cart.AppliedGiftCard{{Code: "GHDJAHJH-DADAD-2113"}},
+			PaymentSelection:   cart.DefaultPaymentSelection{},
+		}, cart.Cart{ID: "customer", BelongsToAuthenticatedUser: true})
+
+		require.Len(t, report.QtyAdjustments, 1)
+		assert.Equal(t, 5, report.QtyAdjustments[0].RequestedQty)
+		assert.Equal(t, 2, report.QtyAdjustments[0].MergedQty)
+		assert.Empty(t, report.MergedItems)
+		assert.Empty(t, report.DroppedItems)
+		assert.Equal(t, []application.CartMergeReportCode{{Code: "SUMMER_SALE", Reason: "voucher expired"}}, report.DroppedVouchers)
+		assert.Equal(t, []application.CartMergeReportCode{{Code: "GHDJAHJH-DADAD-2113", Reason: "gift card not found"}}, report.DroppedGiftCards)
+		assert.Equal(t, application.CartMergePaymentSelectionDropped, report.PaymentSelection)
 	})
 }
 
@@ -328,5 +433,5 @@ func TestCartMergeStrategyNone_Merge(t *testing.T) {
 	t.Parallel()
 
 	c := &application.CartMergeStrategyNone{}
-	c.Merge(context.Background(), nil, cart.Cart{}, cart.Cart{})
+	assert.Nil(t, c.Merge(context.Background(), nil, cart.Cart{}, cart.Cart{}))
 }
@@ -0,0 +1,53 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opencensus.io/trace"
+
+	cartDomain "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+)
+
+type (
+	// GiftCardService provides the balance check of gift cards
+	GiftCardService struct {
+		registry cartDomain.GiftCardRegistry
+	}
+)
+
+var (
+	// ErrNoGiftCardRegistry is returned if the balance of a gift card is requested but no GiftCardRegistry is bound
+	ErrNoGiftCardRegistry = errors.New("no gift card registry bound")
+)
+
+// Inject dependencies
+func (s *GiftCardService) Inject(
+	optionals *struct {
+		Registry cartDomain.GiftCardRegistry `inject:",optional"`
+	},
+) *GiftCardService {
+	if optionals != nil {
+		s.registry = optionals.Registry
+	}
+
+	return s
+}
+
+// Balance returns the gift card with its currently available balance
+func (s *GiftCardService) Balance(ctx context.Context, code string) (*cartDomain.GiftCard, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/GiftCardService/Balance")
+	defer span.End()
+
+	if s.registry == nil {
+		return nil, ErrNoGiftCardRegistry
+	}
+
+	giftCard, err := s.registry.GiftCard(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("GiftCardService: error getting gift card: %w", err)
+	}
+
+	return giftCard, nil
+}
@@ -0,0 +1,63 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"flamingo.me/flamingo/v3/framework/web"
+	"go.opencensus.io/trace"
+
+	cartDomain "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+)
+
+type (
+	// HistoryService provides the change history of the current cart which is recorded by the CartService
+	HistoryService struct {
+		cartReceiverService *CartReceiverService
+		storage             cartDomain.HistoryStorage
+	}
+)
+
+var (
+	// ErrNoHistoryStorage is returned if the cart history is requested but no HistoryStorage is bound
+	ErrNoHistoryStorage = errors.New("no cart history storage bound")
+)
+
+// Inject dependencies
+func (s *HistoryService) Inject(
+	cartReceiverService *CartReceiverService,
+	optionals *struct {
+		Storage cartDomain.HistoryStorage `inject:",optional"`
+	},
+) *HistoryService {
+	s.cartReceiverService = cartReceiverService
+
+	if optionals != nil {
+		s.storage = optionals.Storage
+	}
+
+	return s
+}
+
+// CurrentCartHistory returns the change history of the current cart, oldest entry first
+func (s *HistoryService) CurrentCartHistory(ctx context.Context, session *web.Session) ([]cartDomain.HistoryEntry, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/HistoryService/CurrentCartHistory")
+	defer span.End()
+
+	if s.storage == nil {
+		return nil, ErrNoHistoryStorage
+	}
+
+	cart, err := s.cartReceiverService.ViewCart(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("HistoryService: error getting cart: %w", err)
+	}
+
+	entries, err := s.storage.GetEntries(ctx, cart.ID)
+	if err != nil {
+		return nil, fmt.Errorf("HistoryService: error getting history of cart %q: %w", cart.ID, err)
+	}
+
+	return entries, nil
+}
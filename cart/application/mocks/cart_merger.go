@@ -5,6 +5,7 @@ package mocks
 import (
 	context "context"
 
+	application "flamingo.me/flamingo-commerce/v3/cart/application"
 	cart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
 	web "flamingo.me/flamingo/v3/framework/web"
 	mock "github.com/stretchr/testify/mock"
@@ -24,8 +25,23 @@ func (_m *CartMerger) EXPECT() *CartMerger_Expecter {
 }
 
 // Merge provides a mock function with given fields: ctx, session, guestCart, customerCart
-func (_m *CartMerger) Merge(ctx context.Context, session *web.Session, guestCart cart.Cart, customerCart cart.Cart) {
-	_m.Called(ctx, session, guestCart, customerCart)
+func (_m *CartMerger) Merge(ctx context.Context, session *web.Session, guestCart cart.Cart, customerCart cart.Cart) *application.CartMergeReport {
+	ret := _m.Called(ctx, session, guestCart, customerCart)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Merge")
+	}
+
+	var r0 *application.CartMergeReport
+	if rf, ok := ret.Get(0).(func(context.Context, *web.Session, cart.Cart, cart.Cart) *application.CartMergeReport); ok {
+		r0 = rf(ctx, session, guestCart, customerCart)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*application.CartMergeReport)
+		}
+	}
+
+	return r0
 }
 
 // CartMerger_Merge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Merge'
@@ -49,13 +65,13 @@ func (_c *CartMerger_Merge_Call) Run(run func(ctx context.Context, session *web.
 	return _c
 }
 
-func (_c *CartMerger_Merge_Call) Return() *CartMerger_Merge_Call {
-	_c.Call.Return()
+func (_c *CartMerger_Merge_Call) Return(_a0 *application.CartMergeReport) *CartMerger_Merge_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *CartMerger_Merge_Call) RunAndReturn(run func(context.Context, *web.Session, cart.Cart, cart.Cart)) *CartMerger_Merge_Call {
-	_c.Run(run)
+func (_c *CartMerger_Merge_Call) RunAndReturn(run func(context.Context, *web.Session, cart.Cart, cart.Cart) *application.CartMergeReport) *CartMerger_Merge_Call {
+	_c.Call.Return(run)
 	return _c
 }
 
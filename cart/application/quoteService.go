@@ -0,0 +1,428 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"flamingo.me/flamingo/v3/core/auth"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+	"github.com/google/uuid"
+	"go.opencensus.io/trace"
+
+	cartDomain "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/events"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/quote"
+	"flamingo.me/flamingo-commerce/v3/customer/application"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+type (
+	// QuoteService manages the quotes which sales reps offer to customers, the prices of the quoted items are locked
+	// and an accepted quote is converted by placing the order of a cart created from the quote
+	QuoteService struct {
+		cartReceiverService *CartReceiverService
+		webIdentityService  *auth.WebIdentityService
+		logger              flamingo.Logger
+		lifetime            time.Duration
+		now                 func() time.Time
+		storage             quote.Storage
+		cartAdapter         quote.CartAdapter
+		salesRepResolver    quote.SalesRepResolver
+	}
+)
+
+var (
+	// ErrNoQuoteStorage is returned if quotes are requested but no quote storage or cart adapter is bound
+	ErrNoQuoteStorage = errors.New("no quote storage bound")
+	// ErrNotSalesRep is returned if a quote is created or priced by an identity which is no sales rep
+	ErrNotSalesRep = errors.New("only sales reps can create and price quotes")
+
+	_ flamingo.Notifier = &QuoteService{}
+)
+
+// Inject dependencies
+func (s *QuoteService) Inject(
+	cartReceiverService *CartReceiverService,
+	webIdentityService *auth.WebIdentityService,
+	logger flamingo.Logger,
+	config *struct {
+		Lifetime string `inject:"config:commerce.cart.quotes.lifetime,optional"`
+	},
+	optionals *struct {
+		Storage          quote.Storage          `inject:",optional"`
+		CartAdapter      quote.CartAdapter      `inject:",optional"`
+		SalesRepResolver quote.SalesRepResolver `inject:",optional"`
+	},
+) *QuoteService {
+	s.cartReceiverService = cartReceiverService
+	s.webIdentityService = webIdentityService
+	s.logger = logger.WithField(flamingo.LogKeyModule, "cart").WithField(flamingo.LogKeyCategory, "application.quoteService")
+	s.now = time.Now
+
+	if config != nil && config.Lifetime != "" {
+		var err error
+		s.lifetime, err = time.ParseDuration(config.Lifetime)
+		if err != nil {
+			panic("can't parse commerce.cart.quotes.lifetime")
+		}
+	}
+
+	if optionals != nil {
+		s.storage = optionals.Storage
+		s.cartAdapter = optionals.CartAdapter
+		s.salesRepResolver = optionals.SalesRepResolver
+	}
+
+	return s
+}
+
+// CreateQuote creates a draft quote for the customer from the current cart of the sales rep,
+// the quote expires after commerce.cart.quotes.lifetime if no expiry date is given
+func (s *QuoteService) CreateQuote(ctx context.Context, session *web.Session, customerID string, expiresAt *time.Time) (*quote.Quote, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/QuoteService/CreateQuote")
+	defer span.End()
+
+	salesRep, err := s.salesRep(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if customerID == "" {
+		return nil, errors.New("QuoteService: no customer given")
+	}
+
+	cart, err := s.cartReceiverService.ViewCart(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("QuoteService: error getting cart: %w", err)
+	}
+
+	if cart.IsEmpty() {
+		return nil, errors.New("QuoteService: can't create a quote from an empty cart")
+	}
+
+	now := s.now()
+
+	var expiry time.Time
+	if expiresAt != nil {
+		expiry = *expiresAt
+	} else if s.lifetime > 0 {
+		expiry = now.Add(s.lifetime)
+	}
+
+	if !expiry.IsZero() && !expiry.After(now) {
+		return nil, fmt.Errorf("QuoteService: expiry date %s is not in the future", expiry.Format(time.RFC3339))
+	}
+
+	newQuote, err := quote.NewQuote(uuid.New().String(), customerID, salesRep.Subject(), *cart, now, expiry)
+	if err != nil {
+		return nil, fmt.Errorf("QuoteService: %w", err)
+	}
+
+	err = s.storage.StoreQuote(ctx, newQuote)
+	if err != nil {
+		return nil, fmt.Errorf("QuoteService: error storing quote: %w", err)
+	}
+
+	return newQuote, nil
+}
+
+// GetQuote returns the quote if it is offered to the logged in customer or the logged in identity is a sales rep
+func (s *QuoteService) GetQuote(ctx context.Context, quoteID string) (*quote.Quote, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/QuoteService/GetQuote")
+	defer span.End()
+
+	identity, err := s.identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := s.getQuote(ctx, quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.CustomerID != identity.Subject() && !s.isSalesRep(ctx, identity) {
+		return nil, fmt.Errorf("QuoteService: %w for id %q", quote.ErrQuoteNotFound, quoteID)
+	}
+
+	return q, nil
+}
+
+// ListQuotes returns the quotes of the logged in customer, sales reps may request the quotes of any customer, newest quote first
+func (s *QuoteService) ListQuotes(ctx context.Context, customerID string) ([]*quote.Quote, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/QuoteService/ListQuotes")
+	defer span.End()
+
+	identity, err := s.identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if customerID == "" || !s.isSalesRep(ctx, identity) {
+		customerID = identity.Subject()
+	}
+
+	quotes, err := s.storage.ListQuotes(ctx, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("QuoteService: error listing quotes of customer %q: %w", customerID, err)
+	}
+
+	for _, q := range quotes {
+		s.expireIfDue(ctx, q)
+	}
+
+	return quotes, nil
+}
+
+// UpdateItemPrice locks the negotiated single price of an item of the draft quote, only allowed for sales reps,
+// the currency of the item is used if the price has no currency
+func (s *QuoteService) UpdateItemPrice(ctx context.Context, quoteID string, itemID string, singlePrice priceDomain.Price) (*quote.Quote, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/QuoteService/UpdateItemPrice")
+	defer span.End()
+
+	if _, err := s.salesRep(ctx); err != nil {
+		return nil, err
+	}
+
+	q, err := s.getQuote(ctx, quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.State == quote.StateExpired {
+		return nil, quote.ErrQuoteExpired
+	}
+
+	if q.State != quote.StateDraft {
+		return nil, quote.ErrQuoteNotEditable
+	}
+
+	item, err := q.Cart.GetByItemID(itemID)
+	if err != nil {
+		return nil, fmt.Errorf("QuoteService: %w", err)
+	}
+
+	if singlePrice.Currency() == "" {
+		singlePrice = priceDomain.NewFromBigFloat(*singlePrice.Amount(), item.SinglePriceGross.Currency())
+	}
+
+	if singlePrice.IsNegative() || singlePrice.Currency() != item.SinglePriceGross.Currency() {
+		return nil, fmt.Errorf("QuoteService: invalid price %v %s for item %q", singlePrice.FloatAmount(), singlePrice.Currency(), itemID)
+	}
+
+	updatedCart, err := s.cartAdapter.UpdateItemPrice(ctx, q.Cart, itemID, singlePrice)
+	if err != nil {
+		return nil, fmt.Errorf("QuoteService: error updating price of item %q: %w", itemID, err)
+	}
+
+	err = q.UpdateCart(*updatedCart, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	return q, s.storeQuote(ctx, q)
+}
+
+// SendQuote offers the draft quote to the customer, only allowed for sales reps
+func (s *QuoteService) SendQuote(ctx context.Context, quoteID string) (*quote.Quote, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/QuoteService/SendQuote")
+	defer span.End()
+
+	if _, err := s.salesRep(ctx); err != nil {
+		return nil, err
+	}
+
+	q, err := s.getQuote(ctx, quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.transition(ctx, q, q.Send)
+}
+
+// AcceptQuote accepts the sent quote by the customer it is offered to
+func (s *QuoteService) AcceptQuote(ctx context.Context, quoteID string) (*quote.Quote, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/QuoteService/AcceptQuote")
+	defer span.End()
+
+	q, err := s.customerQuote(ctx, quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.transition(ctx, q, q.Accept)
+}
+
+// PrepareConversion creates a cart with the locked prices of the accepted quote and makes it the current cart of the customer,
+// the quote is converted as soon as the order of this cart is placed via the checkout
+func (s *QuoteService) PrepareConversion(ctx context.Context, session *web.Session, quoteID string) (*cartDomain.Cart, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/QuoteService/PrepareConversion")
+	defer span.End()
+
+	identity, err := s.identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := s.customerQuote(ctx, quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.State == quote.StateExpired {
+		return nil, quote.ErrQuoteExpired
+	}
+
+	if !q.CanTransitionTo(quote.StateConverted) {
+		return nil, fmt.Errorf("QuoteService: %w, quote %q is %s", quote.ErrInvalidTransition, q.ID, q.State)
+	}
+
+	orderCart, err := s.cartAdapter.CreateOrderCart(ctx, identity, *q)
+	if err != nil {
+		return nil, fmt.Errorf("QuoteService: error creating cart of quote %q: %w", q.ID, err)
+	}
+
+	q.OrderCartID = orderCart.ID
+	q.UpdatedAt = s.now()
+
+	err = s.storeQuote(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.cartReceiverService.SwitchCustomerCart(ctx, session, orderCart.ID)
+}
+
+// Notify converts the quote of the cart after its order has been placed
+func (s *QuoteService) Notify(ctx context.Context, event flamingo.Event) {
+	orderPlacedEvent, ok := event.(*events.OrderPlacedEvent)
+	if !ok || s.storage == nil || orderPlacedEvent.Cart == nil {
+		return
+	}
+
+	quoteID := quote.IDOfCart(*orderPlacedEvent.Cart)
+	if quoteID == "" {
+		return
+	}
+
+	ctx, span := trace.StartSpan(ctx, "cart/QuoteService/Notify")
+	defer span.End()
+
+	q, err := s.getQuote(ctx, quoteID)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(fmt.Errorf("quote of placed order not found: %w", err))
+		return
+	}
+
+	err = q.Convert(s.now(), orderPlacedEvent.PlacedOrderInfos)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(fmt.Errorf("can't convert quote %q: %w", quoteID, err))
+		return
+	}
+
+	err = s.storeQuote(ctx, q)
+	if err != nil {
+		s.logger.WithContext(ctx).Error(err)
+	}
+}
+
+func (s *QuoteService) identity(ctx context.Context) (auth.Identity, error) {
+	if s.storage == nil || s.cartAdapter == nil {
+		return nil, ErrNoQuoteStorage
+	}
+
+	identity := s.webIdentityService.Identify(ctx, web.RequestFromContext(ctx))
+	if identity == nil {
+		return nil, application.ErrNoIdentity
+	}
+
+	return identity, nil
+}
+
+func (s *QuoteService) salesRep(ctx context.Context) (auth.Identity, error) {
+	identity, err := s.identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.isSalesRep(ctx, identity) {
+		return nil, ErrNotSalesRep
+	}
+
+	return identity, nil
+}
+
+func (s *QuoteService) isSalesRep(ctx context.Context, identity auth.Identity) bool {
+	return s.salesRepResolver != nil && s.salesRepResolver.IsSalesRep(ctx, identity)
+}
+
+// customerQuote returns the quote if it is offered to the logged in customer
+func (s *QuoteService) customerQuote(ctx context.Context, quoteID string) (*quote.Quote, error) {
+	identity, err := s.identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := s.getQuote(ctx, quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.CustomerID != identity.Subject() {
+		return nil, fmt.Errorf("QuoteService: %w for id %q", quote.ErrQuoteNotFound, quoteID)
+	}
+
+	return q, nil
+}
+
+// getQuote loads the quote and stores it as expired if its expiry date is reached
+func (s *QuoteService) getQuote(ctx context.Context, quoteID string) (*quote.Quote, error) {
+	q, err := s.storage.GetQuote(ctx, quoteID)
+	if err != nil {
+		return nil, fmt.Errorf("QuoteService: error getting quote: %w", err)
+	}
+
+	s.expireIfDue(ctx, q)
+
+	return q, nil
+}
+
+func (s *QuoteService) expireIfDue(ctx context.Context, q *quote.Quote) {
+	if !q.ExpireIfDue(s.now()) {
+		return
+	}
+
+	if err := s.storeQuote(ctx, q); err != nil {
+		s.logger.WithContext(ctx).Error(err)
+	}
+}
+
+// transition changes the state of the quote, a quote which expired in the meantime is stored as expired
+func (s *QuoteService) transition(ctx context.Context, q *quote.Quote, transition func(now time.Time) error) (*quote.Quote, error) {
+	if q.State == quote.StateExpired {
+		return nil, quote.ErrQuoteExpired
+	}
+
+	err := transition(s.now())
+	if errors.Is(err, quote.ErrQuoteExpired) {
+		_ = s.storeQuote(ctx, q)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return q, s.storeQuote(ctx, q)
+}
+
+func (s *QuoteService) storeQuote(ctx context.Context, q *quote.Quote) error {
+	err := s.storage.StoreQuote(ctx, q)
+	if err != nil {
+		return fmt.Errorf("QuoteService: error storing quote %q: %w", q.ID, err)
+	}
+
+	return nil
+}
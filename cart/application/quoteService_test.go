@@ -0,0 +1,184 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"flamingo.me/flamingo/v3/core/auth"
+	authMock "flamingo.me/flamingo/v3/core/auth/mock"
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	cartApplication "flamingo.me/flamingo-commerce/v3/cart/application"
+	cartDomain "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/decorator"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/events"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/placeorder"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/quote"
+	"flamingo.me/flamingo-commerce/v3/cart/infrastructure"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+func TestQuoteService(t *testing.T) {
+	eventRouter := new(MockEventRouter)
+	eventRouter.On("Dispatch", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	storage := (&infrastructure.InMemoryCartStorage{}).Inject()
+	behaviour := &infrastructure.DefaultCartBehaviour{}
+	behaviour.Inject(storage, &MockProductService{}, flamingo.NullLogger{}, nil, nil, &struct {
+		DefaultTaxRate  float64 `inject:"config:commerce.cart.defaultCartAdapter.defaultTaxRate,optional"`
+		ProductPricing  string  `inject:"config:commerce.cart.defaultCartAdapter.productPrices"`
+		DefaultCurrency string  `inject:"config:commerce.cart.defaultCartAdapter.defaultCurrency"`
+	}{ProductPricing: "gross", DefaultCurrency: "EUR"}, nil)
+	customerCartService := &infrastructure.DefaultCustomerCartService{}
+	customerCartService.Inject(behaviour, flamingo.NullLogger{})
+
+	subject := "rep-1"
+	mockIdentifier := new(authMock.Identifier).SetIdentifyMethod(
+		func(identifier *authMock.Identifier, ctx context.Context, request *web.Request) (auth.Identity, error) {
+			return &authMock.Identity{Sub: subject}, nil
+		},
+	)
+	webIdentityService := new(auth.WebIdentityService).Inject([]auth.RequestIdentifier{mockIdentifier}, nil, nil, nil)
+
+	decoratedCartFactory := &decorator.DecoratedCartFactory{}
+	decoratedCartFactory.Inject(&MockProductService{}, flamingo.NullLogger{}, nil)
+
+	crs := &cartApplication.CartReceiverService{}
+	crs.Inject(
+		new(MockGuestCartServiceWithModifyBehaviour),
+		customerCartService,
+		decoratedCartFactory,
+		webIdentityService,
+		flamingo.NullLogger{},
+		eventRouter,
+		nil,
+	)
+
+	quoteService := new(cartApplication.QuoteService).Inject(
+		crs,
+		webIdentityService,
+		flamingo.NullLogger{},
+		&struct {
+			Lifetime string `inject:"config:commerce.cart.quotes.lifetime,optional"`
+		}{Lifetime: "24h"},
+		&struct {
+			Storage          quote.Storage          `inject:",optional"`
+			CartAdapter      quote.CartAdapter      `inject:",optional"`
+			SalesRepResolver quote.SalesRepResolver `inject:",optional"`
+		}{
+			Storage:     &infrastructure.InMemoryQuoteStorage{},
+			CartAdapter: new(infrastructure.DefaultQuoteCartAdapter).Inject(behaviour),
+			SalesRepResolver: new(infrastructure.ConfigSalesRepResolver).Inject(flamingo.NullLogger{}, &struct {
+				SalesReps config.Slice `inject:"config:commerce.cart.quotes.salesReps,optional"`
+			}{SalesReps: config.Slice{"rep-1"}}),
+		},
+	)
+
+	ctx := context.Background()
+
+	_, err := behaviour.StoreNewCart(ctx, &cartDomain.Cart{
+		ID:                         "rep-1",
+		BelongsToAuthenticatedUser: true,
+		AuthenticatedUserID:        "rep-1",
+		Deliveries: []cartDomain.Delivery{
+			{
+				DeliveryInfo: cartDomain.DeliveryInfo{Code: "delivery"},
+				Cartitems: []cartDomain.Item{
+					{
+						ID:               "item-1",
+						MarketplaceCode:  "code-1",
+						Qty:              2,
+						SinglePriceGross: priceDomain.NewFromFloat(10, "EUR"),
+						SinglePriceNet:   priceDomain.NewFromFloat(10, "EUR"),
+						RowPriceGross:    priceDomain.NewFromFloat(20, "EUR"),
+						RowPriceNet:      priceDomain.NewFromFloat(20, "EUR"),
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	subject = "customer-1"
+	_, err = quoteService.CreateQuote(ctx, web.EmptySession(), "customer-1", nil)
+	assert.ErrorIs(t, err, cartApplication.ErrNotSalesRep)
+
+	subject = "rep-1"
+	created, err := quoteService.CreateQuote(ctx, web.EmptySession(), "customer-1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, quote.StateDraft, created.State)
+	assert.Equal(t, "rep-1", created.SalesRepID)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), created.ExpiresAt, time.Minute)
+
+	priced, err := quoteService.UpdateItemPrice(ctx, created.ID, "item-1", priceDomain.NewFromFloat(7.5, "EUR"))
+	require.NoError(t, err)
+	assert.Equal(t, priceDomain.NewFromFloat(15, "EUR"), priced.Cart.GrandTotal)
+
+	subject = "customer-1"
+	_, err = quoteService.UpdateItemPrice(ctx, created.ID, "item-1", priceDomain.NewFromFloat(1, "EUR"))
+	assert.ErrorIs(t, err, cartApplication.ErrNotSalesRep)
+
+	_, err = quoteService.AcceptQuote(ctx, created.ID)
+	assert.ErrorIs(t, err, quote.ErrInvalidTransition, "drafts can't be accepted")
+
+	subject = "rep-1"
+	_, err = quoteService.SendQuote(ctx, created.ID)
+	require.NoError(t, err)
+
+	_, err = quoteService.UpdateItemPrice(ctx, created.ID, "item-1", priceDomain.NewFromFloat(1, "EUR"))
+	assert.ErrorIs(t, err, quote.ErrQuoteNotEditable)
+
+	subject = "customer-2"
+	_, err = quoteService.GetQuote(ctx, created.ID)
+	assert.ErrorIs(t, err, quote.ErrQuoteNotFound, "quotes of other customers are not accessible")
+
+	_, err = quoteService.AcceptQuote(ctx, created.ID)
+	assert.ErrorIs(t, err, quote.ErrQuoteNotFound)
+
+	subject = "customer-1"
+	session := web.EmptySession()
+
+	_, err = quoteService.PrepareConversion(ctx, session, created.ID)
+	assert.ErrorIs(t, err, quote.ErrInvalidTransition, "only accepted quotes can be converted")
+
+	accepted, err := quoteService.AcceptQuote(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, quote.StateAccepted, accepted.State)
+
+	quotes, err := quoteService.ListQuotes(ctx, "customer-2")
+	require.NoError(t, err)
+	require.Len(t, quotes, 1, "customers only get their own quotes")
+	assert.Equal(t, created.ID, quotes[0].ID)
+
+	orderCart, err := quoteService.PrepareConversion(ctx, session, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, quote.IDOfCart(*orderCart))
+	assert.Equal(t, priceDomain.NewFromFloat(15, "EUR"), orderCart.GrandTotal, "the prices of the quote are locked")
+
+	currentCartID, _ := session.Load(cartApplication.CustomerCartSessionKey)
+	assert.Equal(t, orderCart.ID, currentCartID)
+
+	quoteService.Notify(ctx, &events.OrderPlacedEvent{
+		Cart:             orderCart,
+		PlacedOrderInfos: placeorder.PlacedOrderInfos{{OrderNumber: "order-1"}},
+	})
+
+	converted, err := quoteService.GetQuote(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, quote.StateConverted, converted.State)
+	assert.Equal(t, orderCart.ID, converted.OrderCartID)
+	assert.Equal(t, "order-1", converted.PlacedOrderInfos[0].OrderNumber)
+}
+
+func TestQuoteService_NoStorage(t *testing.T) {
+	quoteService := new(cartApplication.QuoteService).Inject(nil, nil, flamingo.NullLogger{}, nil, nil)
+
+	_, err := quoteService.ListQuotes(context.Background(), "")
+	assert.ErrorIs(t, err, cartApplication.ErrNoQuoteStorage)
+}
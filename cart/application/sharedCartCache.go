@@ -0,0 +1,230 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"time"
+
+	"flamingo.me/flamingo/v3/core/auth"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+	"go.opencensus.io/trace"
+
+	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+)
+
+type (
+	// CartCacheStore is the secondary port of the SharedCartCache, a key value store shared by all sessions
+	CartCacheStore interface {
+		// Get returns the value of the key or ErrNoCacheEntry
+		Get(ctx context.Context, key string) ([]byte, error)
+		// Set stores the value, it expires after the lifetime
+		Set(ctx context.Context, key string, value []byte, lifetime time.Duration) error
+		// Delete removes the key and returns false if it did not exist
+		Delete(ctx context.Context, key string) (bool, error)
+	}
+
+	// CartCacheInvalidator allows other services to invalidate cached carts, e.g. after the backend changed a cart
+	CartCacheInvalidator interface {
+		// InvalidateCart removes the cached cart with the given ID
+		InvalidateCart(ctx context.Context, cartID string) error
+		// InvalidateCustomer removes the cached cart of the customer
+		InvalidateCustomer(ctx context.Context, customerID string) error
+	}
+
+	// SharedCartCache is a CartCache storing the carts outside the web session in a CartCacheStore,
+	// so that the session stays small and cached carts can be invalidated by cart or customer ID
+	SharedCartCache struct {
+		logger             flamingo.Logger
+		webIdentityService *auth.WebIdentityService
+		store              CartCacheStore
+		lifetime           time.Duration
+	}
+)
+
+const (
+	// SharedCartCacheKeyPrefix is the key prefix of the cached carts in the CartCacheStore
+	SharedCartCacheKeyPrefix = "cart.sharedcache."
+	// SharedCartCacheIndexKeyPrefix is the key prefix of the references from cart IDs to the cache keys
+	SharedCartCacheIndexKeyPrefix = "cart.sharedcache.id."
+)
+
+var (
+	_ CartCache            = (*SharedCartCache)(nil)
+	_ CartCacheInvalidator = (*SharedCartCache)(nil)
+)
+
+// Inject the dependencies
+func (sc *SharedCartCache) Inject(
+	logger flamingo.Logger,
+	webIdentityService *auth.WebIdentityService,
+	store CartCacheStore,
+	config *struct {
+		LifetimeSeconds float64 `inject:"config:commerce.cart.cacheLifetime"` // in seconds
+	},
+) *SharedCartCache {
+	sc.webIdentityService = webIdentityService
+	sc.store = store
+	sc.logger = logger.WithField(flamingo.LogKeyCategory, "SharedCartCache").WithField(flamingo.LogKeyModule, "cart")
+
+	if config != nil {
+		sc.lifetime = time.Duration(config.LifetimeSeconds * float64(time.Second))
+	}
+
+	return sc
+}
+
+// BuildIdentifier creates a CartCacheIdentifier based on the login state
+func (sc *SharedCartCache) BuildIdentifier(ctx context.Context, session *web.Session) (CartCacheIdentifier, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/SharedCartCache/BuildIdentifier")
+	defer span.End()
+
+	return buildCartCacheIdentifier(ctx, sc.webIdentityService, session)
+}
+
+// GetCart fetches a Cart from the Cache
+func (sc *SharedCartCache) GetCart(ctx context.Context, _ *web.Session, id CartCacheIdentifier) (*cart.Cart, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/SharedCartCache/GetCart")
+	defer span.End()
+
+	value, err := sc.store.Get(ctx, SharedCartCacheKeyPrefix+id.CacheKey())
+	if err != nil {
+		return nil, err
+	}
+
+	var entry CachedCartEntry
+	err = gob.NewDecoder(bytes.NewBuffer(value)).Decode(&entry)
+	if err != nil {
+		return nil, fmt.Errorf("SharedCartCache: cached cart %q is not decodable: %w", id.CacheKey(), err)
+	}
+
+	if entry.IsInvalid {
+		return &entry.Entry, ErrCacheIsInvalid
+	}
+
+	return &entry.Entry, nil
+}
+
+// CacheCart adds a Cart to the Cache
+func (sc *SharedCartCache) CacheCart(ctx context.Context, _ *web.Session, id CartCacheIdentifier, cartForCache *cart.Cart) error {
+	ctx, span := trace.StartSpan(ctx, "cart/SharedCartCache/CacheCart")
+	defer span.End()
+
+	if cartForCache == nil {
+		return errors.New("no cart given to cache")
+	}
+
+	entry := CachedCartEntry{
+		Entry:     *cartForCache,
+		ExpiresOn: time.Now().Add(sc.lifetime),
+	}
+
+	buffer := new(bytes.Buffer)
+	err := gob.NewEncoder(buffer).Encode(entry)
+	if err != nil {
+		return fmt.Errorf("SharedCartCache: error encoding cart %q: %w", id.CacheKey(), err)
+	}
+
+	key := SharedCartCacheKeyPrefix + id.CacheKey()
+
+	sc.logger.WithContext(ctx).Debug("Caching cart %v", key)
+	err = sc.store.Set(ctx, key, buffer.Bytes(), sc.lifetime)
+	if err != nil {
+		return fmt.Errorf("SharedCartCache: error caching cart %q: %w", id.CacheKey(), err)
+	}
+
+	// customer carts are cached by customer, the reference allows to invalidate them by cart ID
+	err = sc.store.Set(ctx, SharedCartCacheIndexKeyPrefix+cartForCache.ID, []byte(key), sc.lifetime)
+	if err != nil {
+		return fmt.Errorf("SharedCartCache: error referencing cart %q: %w", cartForCache.ID, err)
+	}
+
+	return nil
+}
+
+// Invalidate removes the cache entry, so that the cart is loaded again on the next request
+func (sc *SharedCartCache) Invalidate(ctx context.Context, session *web.Session, id CartCacheIdentifier) error {
+	ctx, span := trace.StartSpan(ctx, "cart/SharedCartCache/Invalidate")
+	defer span.End()
+
+	return sc.Delete(ctx, session, id)
+}
+
+// Delete a Cache entry
+func (sc *SharedCartCache) Delete(ctx context.Context, _ *web.Session, id CartCacheIdentifier) error {
+	ctx, span := trace.StartSpan(ctx, "cart/SharedCartCache/Delete")
+	defer span.End()
+
+	return sc.delete(ctx, SharedCartCacheKeyPrefix+id.CacheKey())
+}
+
+// DeleteAll removes the cart of the current session from the Cache
+func (sc *SharedCartCache) DeleteAll(ctx context.Context, session *web.Session) error {
+	ctx, span := trace.StartSpan(ctx, "cart/SharedCartCache/DeleteAll")
+	defer span.End()
+
+	id, err := sc.BuildIdentifier(ctx, session)
+	if err != nil {
+		return ErrNoCacheEntry
+	}
+
+	return sc.Delete(ctx, session, id)
+}
+
+// InvalidateCart removes the cached cart with the given ID
+func (sc *SharedCartCache) InvalidateCart(ctx context.Context, cartID string) error {
+	ctx, span := trace.StartSpan(ctx, "cart/SharedCartCache/InvalidateCart")
+	defer span.End()
+
+	key, err := sc.store.Get(ctx, SharedCartCacheIndexKeyPrefix+cartID)
+	if errors.Is(err, ErrNoCacheEntry) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("SharedCartCache: error loading reference of cart %q: %w", cartID, err)
+	}
+
+	_, err = sc.store.Delete(ctx, SharedCartCacheIndexKeyPrefix+cartID)
+	if err != nil {
+		return fmt.Errorf("SharedCartCache: error deleting reference of cart %q: %w", cartID, err)
+	}
+
+	err = sc.delete(ctx, string(key))
+	if errors.Is(err, ErrNoCacheEntry) {
+		return nil
+	}
+
+	return err
+}
+
+// InvalidateCustomer removes the cached cart of the customer
+func (sc *SharedCartCache) InvalidateCustomer(ctx context.Context, customerID string) error {
+	ctx, span := trace.StartSpan(ctx, "cart/SharedCartCache/InvalidateCustomer")
+	defer span.End()
+
+	id := CartCacheIdentifier{CustomerID: customerID, IsCustomerCart: true}
+
+	err := sc.delete(ctx, SharedCartCacheKeyPrefix+id.CacheKey())
+	if errors.Is(err, ErrNoCacheEntry) {
+		return nil
+	}
+
+	return err
+}
+
+func (sc *SharedCartCache) delete(ctx context.Context, key string) error {
+	deleted, err := sc.store.Delete(ctx, key)
+	if err != nil {
+		return fmt.Errorf("SharedCartCache: error deleting %q: %w", key, err)
+	}
+
+	if !deleted {
+		return ErrNoCacheEntry
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo-commerce/v3/cart/application"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/infrastructure"
+)
+
+func TestSharedCartCache(t *testing.T) {
+	t.Parallel()
+
+	newCache := func() *application.SharedCartCache {
+		return new(application.SharedCartCache).Inject(flamingo.NullLogger{}, nil, new(infrastructure.LRUCartCacheStore).Inject(nil), &struct {
+			LifetimeSeconds float64 `inject:"config:commerce.cart.cacheLifetime"`
+		}{LifetimeSeconds: 60})
+	}
+
+	ctx := context.Background()
+	session := web.EmptySession()
+	guestID := application.CartCacheIdentifier{GuestCartID: "guest-cart"}
+	customerID := application.CartCacheIdentifier{CustomerID: "customer-1", IsCustomerCart: true}
+
+	t.Run("cached carts are shared by all sessions", func(t *testing.T) {
+		t.Parallel()
+
+		cache := newCache()
+		require.NoError(t, cache.CacheCart(ctx, session, guestID, &cart.Cart{ID: "guest-cart", Revision: 2}))
+
+		cached, err := cache.GetCart(ctx, web.EmptySession(), guestID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, cached.Revision)
+
+		_, err = cache.GetCart(ctx, session, customerID)
+		assert.ErrorIs(t, err, application.ErrNoCacheEntry)
+
+		require.NoError(t, cache.Invalidate(ctx, session, guestID))
+		_, err = cache.GetCart(ctx, session, guestID)
+		assert.ErrorIs(t, err, application.ErrNoCacheEntry)
+		assert.ErrorIs(t, cache.Delete(ctx, session, guestID), application.ErrNoCacheEntry)
+	})
+
+	t.Run("customer carts are invalidated by cart and customer ID", func(t *testing.T) {
+		t.Parallel()
+
+		cache := newCache()
+		require.NoError(t, cache.CacheCart(ctx, session, customerID, &cart.Cart{ID: "customer-cart"}))
+
+		require.NoError(t, cache.InvalidateCart(ctx, "customer-cart"))
+		_, err := cache.GetCart(ctx, session, customerID)
+		assert.ErrorIs(t, err, application.ErrNoCacheEntry)
+
+		require.NoError(t, cache.CacheCart(ctx, session, customerID, &cart.Cart{ID: "customer-cart"}))
+		require.NoError(t, cache.InvalidateCustomer(ctx, "customer-1"))
+		_, err = cache.GetCart(ctx, session, customerID)
+		assert.ErrorIs(t, err, application.ErrNoCacheEntry)
+
+		assert.NoError(t, cache.InvalidateCart(ctx, "unknown"), "invalidating uncached carts is no error")
+		assert.NoError(t, cache.InvalidateCustomer(ctx, "unknown"))
+	})
+}
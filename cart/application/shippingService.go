@@ -0,0 +1,69 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"flamingo.me/flamingo/v3/framework/web"
+	"go.opencensus.io/trace"
+
+	cartDomain "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+)
+
+type (
+	// ShippingService provides the shipping methods available for the deliveries of the current cart
+	ShippingService struct {
+		cartReceiverService *CartReceiverService
+		provider            cartDomain.ShippingRateProvider
+	}
+)
+
+var (
+	// ErrNoShippingRateProvider is returned if the shipping methods are requested but no ShippingRateProvider is bound
+	ErrNoShippingRateProvider = errors.New("no shipping rate provider bound")
+)
+
+// Inject dependencies
+func (s *ShippingService) Inject(
+	cartReceiverService *CartReceiverService,
+	optionals *struct {
+		Provider cartDomain.ShippingRateProvider `inject:",optional"`
+	},
+) *ShippingService {
+	s.cartReceiverService = cartReceiverService
+
+	if optionals != nil {
+		s.provider = optionals.Provider
+	}
+
+	return s
+}
+
+// AvailableShippingMethods returns the shipping methods available for the delivery of the current cart,
+// the code and carrier of a method can be used to update the delivery info
+func (s *ShippingService) AvailableShippingMethods(ctx context.Context, session *web.Session, deliveryCode string) ([]cartDomain.ShippingMethod, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/ShippingService/AvailableShippingMethods")
+	defer span.End()
+
+	if s.provider == nil {
+		return nil, ErrNoShippingRateProvider
+	}
+
+	cart, err := s.cartReceiverService.ViewCart(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("ShippingService: error getting cart: %w", err)
+	}
+
+	delivery, found := cart.GetDeliveryByCode(deliveryCode)
+	if !found {
+		return nil, cartDomain.ErrDeliveryCodeNotFound
+	}
+
+	methods, err := s.provider.ShippingMethods(ctx, cart, delivery)
+	if err != nil {
+		return nil, fmt.Errorf("ShippingService: error getting shipping methods: %w", err)
+	}
+
+	return methods, nil
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"flamingo.me/flamingo/v3/framework/web"
 
@@ -20,6 +21,17 @@ type (
 		// EntityID is a second identifier that may be used by some backends
 		EntityID string
 
+		// Revision is increased with every modification of the cart, it is used to detect concurrent modifications
+		Revision int
+
+		// Name of the cart, used to distinguish multiple carts of a customer (e.g. "wishlist" or "saved for later")
+		Name string
+
+		// CreatedAt is the time the cart was stored for the first time
+		CreatedAt time.Time
+		// UpdatedAt is the time of the last modification of the cart
+		UpdatedAt time.Time
+
 		// BillingAddress is the main billing address (relevant for all payments/invoices)
 		BillingAddress *Address
 
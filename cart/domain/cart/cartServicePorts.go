@@ -11,6 +11,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	productDomain "flamingo.me/flamingo-commerce/v3/product/domain"
 	"flamingo.me/flamingo/v3/core/auth"
@@ -44,6 +45,17 @@ type (
 		RestoreCart(ctx context.Context, identity auth.Identity, cart Cart) (*Cart, error)
 	}
 
+	// NamedCartService can be implemented by a CustomerCartService to support multiple named carts per customer
+	// (e.g. "wishlist", "saved for later") in addition to the default cart
+	NamedCartService interface {
+		// ListCarts returns all carts of the customer including the default cart
+		ListCarts(ctx context.Context, identity auth.Identity) ([]*Cart, error)
+		// CreateCart creates a new named cart for the customer
+		CreateCart(ctx context.Context, identity auth.Identity, name string) (*Cart, error)
+		// RenameCart changes the name of a cart of the customer
+		RenameCart(ctx context.Context, identity auth.Identity, cartID string, name string) (*Cart, error)
+	}
+
 	// DeferEvents represents events that should be dispatched after a cart modify call
 	DeferEvents []flamingo.Event
 
@@ -75,6 +87,12 @@ type (
 		Restore(context.Context, *Cart) (*Cart, DeferEvents, error)
 	}
 
+	// ReleaseBehaviour - additional interface that can be implemented by a CompleteBehaviour to release what Complete
+	// reserved for the cart (e.g. gift card amounts) if the order is cancelled and the cart is not restored
+	ReleaseBehaviour interface {
+		Release(ctx context.Context, cart *Cart) error
+	}
+
 	// GiftCardBehaviour - additional interface that can be implemented to support GiftCard features
 	GiftCardBehaviour interface {
 		ApplyGiftCard(ctx context.Context, cart *Cart, giftCardCode string) (*Cart, DeferEvents, error)
@@ -86,6 +104,11 @@ type (
 		ApplyAny(ctx context.Context, cart *Cart, anyCode string) (*Cart, DeferEvents, error)
 	}
 
+	// BulkAddBehaviour - additional interface that can be implemented to add many items with a single modification of the cart
+	BulkAddBehaviour interface {
+		AddToCartBulk(ctx context.Context, cart *Cart, addRequests []DeliveryAddRequest) (*Cart, DeferEvents, error)
+	}
+
 	// AddRequest defines add to cart request
 	AddRequest struct {
 		MarketplaceCode        string
@@ -93,6 +116,18 @@ type (
 		VariantMarketplaceCode string
 		AdditionalData         map[string]string
 		BundleConfiguration    productDomain.BundleConfiguration
+		// Options contains the chosen values of the item options of the product by their code
+		Options map[string]string
+		// SourceID is set on a new item, an existing item of the same line keeps its SourceID
+		SourceID string
+	}
+
+	// DeliveryAddRequest defines an add request for a specific delivery, used for bulk operations
+	DeliveryAddRequest struct {
+		DeliveryCode string
+		AddRequest   AddRequest
+		// InitialDeliveryInfo is used if the delivery does not yet exist in the cart
+		InitialDeliveryInfo *DeliveryInfo
 	}
 
 	// ItemUpdateCommand defines the update item command
@@ -114,6 +149,15 @@ type (
 		DeliveryInfo DeliveryInfo
 		additional   map[string]json.RawMessage
 	}
+
+	// RevisionConflictError is returned if a cart modification is based on an outdated revision of the cart
+	RevisionConflictError struct {
+		CartID string
+		// ExpectedRevision is the revision the modification was based on
+		ExpectedRevision int
+		// ActualRevision is the current revision of the cart
+		ActualRevision int
+	}
 )
 
 var (
@@ -123,8 +167,22 @@ var (
 	ErrItemNotFound = errors.New("item not found")
 	// ErrDeliveryCodeNotFound is used if a delivery was not found
 	ErrDeliveryCodeNotFound = errors.New("delivery not found")
+	// ErrNamedCartsNotSupported is used if the CustomerCartService does not implement the NamedCartService
+	ErrNamedCartsNotSupported = errors.New("named carts not supported")
+	// ErrCartExpired is used if a cart exceeded its lifetime, it wraps ErrCartNotFound since the cart is gone afterwards
+	ErrCartExpired = fmt.Errorf("%w: cart expired", ErrCartNotFound)
 )
 
+// Error message
+func (e *RevisionConflictError) Error() string {
+	return fmt.Sprintf("cart %q has been modified concurrently: expected revision %d, actual revision %d", e.CartID, e.ExpectedRevision, e.ActualRevision)
+}
+
+// MessageCode message code
+func (e *RevisionConflictError) MessageCode() string {
+	return "cart_revision_conflict"
+}
+
 // CreateDeliveryInfoUpdateCommand - factory to get the update command based on the given deliveryInfos (which might come from cart)
 func CreateDeliveryInfoUpdateCommand(info DeliveryInfo) DeliveryInfoUpdateCommand {
 	uc := DeliveryInfoUpdateCommand{
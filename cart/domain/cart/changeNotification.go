@@ -0,0 +1,62 @@
+package cart
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	// ChangeNotification informs subscribed clients (e.g. other browser tabs or devices) that a cart has been modified
+	ChangeNotification struct {
+		CartID string
+		// CustomerID is the customer the cart belongs to, empty for guest carts
+		CustomerID string
+		// Revision of the cart after the modification
+		Revision int
+		// Operation is the name of the modifying CartService method, e.g. "AddProduct"
+		Operation string
+		// Teaser contains the counts of the cart after the modification
+		Teaser    Teaser
+		Timestamp time.Time
+	}
+
+	// ChangeSubscription selects the notifications of a cart and / or all carts of a customer
+	ChangeSubscription struct {
+		CartID     string
+		CustomerID string
+	}
+
+	// ChangeNotifier is an optional secondary port which distributes the change notifications of carts to the subscribed clients
+	ChangeNotifier interface {
+		// Publish sends the notification to all matching subscriptions
+		Publish(ctx context.Context, notification ChangeNotification) error
+		// Subscribe returns a channel receiving the matching notifications, the channel is closed once ctx is done
+		Subscribe(ctx context.Context, subscription ChangeSubscription) (<-chan ChangeNotification, error)
+	}
+)
+
+// NewChangeNotification creates a change notification of the modified cart
+func NewChangeNotification(cart *Cart, operation string) ChangeNotification {
+	notification := ChangeNotification{
+		CartID:    cart.ID,
+		Revision:  cart.Revision,
+		Operation: operation,
+		Teaser:    *cart.GetCartTeaser(),
+		Timestamp: time.Now(),
+	}
+
+	if cart.BelongsToAuthenticatedUser {
+		notification.CustomerID = cart.AuthenticatedUserID
+	}
+
+	return notification
+}
+
+// Matches returns true if the notification concerns the subscribed cart or customer
+func (s ChangeSubscription) Matches(notification ChangeNotification) bool {
+	if s.CartID != "" && s.CartID == notification.CartID {
+		return true
+	}
+
+	return s.CustomerID != "" && s.CustomerID == notification.CustomerID
+}
@@ -0,0 +1,47 @@
+package cart_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+)
+
+func TestNewChangeNotification(t *testing.T) {
+	t.Parallel()
+
+	c := &cart.Cart{
+		ID:                         "cart-1",
+		Revision:                   2,
+		BelongsToAuthenticatedUser: true,
+		AuthenticatedUserID:        "customer-1",
+		Deliveries: []cart.Delivery{{
+			DeliveryInfo: cart.DeliveryInfo{Code: "delivery"},
+			Cartitems:    []cart.Item{{ID: "1", Qty: 2}, {ID: "2", Qty: 1}},
+		}},
+	}
+
+	notification := cart.NewChangeNotification(c, "AddProduct")
+
+	assert.Equal(t, "cart-1", notification.CartID)
+	assert.Equal(t, "customer-1", notification.CustomerID)
+	assert.Equal(t, 2, notification.Revision)
+	assert.Equal(t, "AddProduct", notification.Operation)
+	assert.Equal(t, cart.Teaser{ProductCount: 2, ItemCount: 3, DeliveryCodes: []string{"delivery"}}, notification.Teaser)
+	assert.False(t, notification.Timestamp.IsZero())
+
+	c.BelongsToAuthenticatedUser = false
+	assert.Empty(t, cart.NewChangeNotification(c, "AddProduct").CustomerID, "guest carts have no customer")
+}
+
+func TestChangeSubscription_Matches(t *testing.T) {
+	t.Parallel()
+
+	notification := cart.ChangeNotification{CartID: "cart-1", CustomerID: "customer-1"}
+
+	assert.True(t, cart.ChangeSubscription{CartID: "cart-1"}.Matches(notification))
+	assert.True(t, cart.ChangeSubscription{CartID: "cart-2", CustomerID: "customer-1"}.Matches(notification))
+	assert.False(t, cart.ChangeSubscription{CartID: "cart-2", CustomerID: "customer-2"}.Matches(notification))
+	assert.False(t, cart.ChangeSubscription{}.Matches(cart.ChangeNotification{CartID: "cart-1"}), "empty subscriptions match nothing")
+}
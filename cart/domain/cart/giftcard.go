@@ -1,6 +1,10 @@
 package cart
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"flamingo.me/flamingo-commerce/v3/price/domain"
 )
 
@@ -21,6 +25,36 @@ type (
 		HasRemainingGiftCards() bool
 		HasAppliedGiftCards() bool
 	}
+
+	// GiftCard is a gift card with its currently available balance
+	GiftCard struct {
+		Code string
+		// Balance is the available balance, reserved amounts are already subtracted
+		Balance domain.Price
+		// ValidUntil is the expiry date of the gift card, zero if the gift card does not expire
+		ValidUntil time.Time
+	}
+
+	// GiftCardRegistry is an optional secondary port which stores the balances of gift cards
+	GiftCardRegistry interface {
+		// GiftCard returns the gift card with its available balance or ErrGiftCardNotFound
+		GiftCard(ctx context.Context, code string) (*GiftCard, error)
+		// Reserve subtracts the amount from the balance of the gift card, the reservation is identified by the reference, e.g. the cart id
+		Reserve(ctx context.Context, code string, reference string, amount domain.Price) error
+		// Release adds the amount reserved for the reference back to the balance, releasing an unknown reservation does nothing
+		Release(ctx context.Context, code string, reference string) error
+	}
+)
+
+var (
+	// ErrGiftCardNotFound is returned if the gift card does not exist
+	ErrGiftCardNotFound = errors.New("gift card not found")
+	// ErrGiftCardExpired is returned if the gift card is not valid anymore
+	ErrGiftCardExpired = errors.New("gift card expired")
+	// ErrGiftCardCurrencyMismatch is returned if the currency of the gift card differs from the cart currency
+	ErrGiftCardCurrencyMismatch = errors.New("gift card currency does not match")
+	// ErrGiftCardInsufficientBalance is returned if the balance of the gift card is too low
+	ErrGiftCardInsufficientBalance = errors.New("gift card balance insufficient")
 )
 
 var (
@@ -79,3 +113,8 @@ func (cards *AppliedGiftCards) GiftCardByCode(code string) (*AppliedGiftCard, bo
 	}
 	return nil, false
 }
+
+// IsValid checks if the gift card is not expired at the given time
+func (g GiftCard) IsValid(now time.Time) bool {
+	return g.ValidUntil.IsZero() || now.Before(g.ValidUntil)
+}
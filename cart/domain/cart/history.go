@@ -0,0 +1,96 @@
+package cart
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+type (
+	// HistoryEntry records a single modification of a cart, entries are never changed after they have been stored
+	HistoryEntry struct {
+		CartID string
+		// Revision of the cart after the modification
+		Revision int
+		// Operation is the name of the modifying CartService method, e.g. "AddProduct"
+		Operation string
+		// Parameters of the operation, personal data like addresses is not recorded
+		Parameters map[string]string
+		Actor      HistoryActor
+		Timestamp  time.Time
+		Totals     HistoryTotals
+		// Events are the types of the deferred events returned by the behaviour
+		Events []string
+	}
+
+	// HistoryActor is the customer or the guest session which modified the cart
+	HistoryActor struct {
+		// Type is either HistoryActorCustomer or HistoryActorGuest
+		Type string
+		// ID is the customer ID or an anonymized session ID
+		ID string
+	}
+
+	// HistoryTotals are the totals of the cart after the modification
+	HistoryTotals struct {
+		ItemCount           int
+		SubTotalGross       domain.Price
+		SubTotalNet         domain.Price
+		TotalDiscountAmount domain.Price
+		ShippingGross       domain.Price
+		GrandTotal          domain.Price
+	}
+
+	// HistoryStorage is an optional secondary port which stores the append-only change history of carts
+	HistoryStorage interface {
+		// AppendEntry adds the entry to the history of the cart
+		AppendEntry(ctx context.Context, entry HistoryEntry) error
+		// GetEntries returns the history of the cart, oldest entry first
+		GetEntries(ctx context.Context, cartID string) ([]HistoryEntry, error)
+	}
+)
+
+const (
+	// HistoryActorCustomer is the actor type of modifications made by an authenticated customer
+	HistoryActorCustomer = "customer"
+	// HistoryActorGuest is the actor type of modifications made in a guest session
+	HistoryActorGuest = "guest"
+)
+
+// NewHistoryEntry creates a history entry of the modified cart
+func NewHistoryEntry(cart *Cart, operation string, parameters map[string]string, actor HistoryActor, events DeferEvents) HistoryEntry {
+	return HistoryEntry{
+		CartID:     cart.ID,
+		Revision:   cart.Revision,
+		Operation:  operation,
+		Parameters: parameters,
+		Actor:      actor,
+		Timestamp:  time.Now(),
+		Totals: HistoryTotals{
+			ItemCount:           cart.ItemCount(),
+			SubTotalGross:       cart.SubTotalGross,
+			SubTotalNet:         cart.SubTotalNet,
+			TotalDiscountAmount: cart.TotalDiscountAmount,
+			ShippingGross:       cart.ShippingGross,
+			GrandTotal:          cart.GrandTotal,
+		},
+		Events: historyEventTypes(make([]string, 0, len(events)), events),
+	}
+}
+
+// historyEventTypes appends the types of the events, nested DeferEvents are flattened
+func historyEventTypes(eventTypes []string, events DeferEvents) []string {
+	for _, event := range events {
+		if nested, ok := event.(DeferEvents); ok {
+			eventTypes = historyEventTypes(eventTypes, nested)
+
+			continue
+		}
+
+		eventTypes = append(eventTypes, fmt.Sprintf("%T", event))
+	}
+
+	return eventTypes
+}
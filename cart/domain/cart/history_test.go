@@ -0,0 +1,35 @@
+package cart_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/events"
+	"flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+func TestNewHistoryEntry(t *testing.T) {
+	t.Parallel()
+
+	c := &cart.Cart{
+		ID:         "cart-1",
+		Revision:   3,
+		GrandTotal: domain.NewFromFloat(20, "EUR"),
+		Deliveries: []cart.Delivery{{Cartitems: []cart.Item{{ID: "1", Qty: 2}}}},
+	}
+
+	entry := cart.NewHistoryEntry(c, "DeleteAllItems", nil, cart.HistoryActor{Type: cart.HistoryActorGuest, ID: "abc"}, cart.DeferEvents{
+		&events.AddToCartEvent{},
+		cart.DeferEvents{&events.ChangedQtyInCartEvent{}, &events.ChangedQtyInCartEvent{}},
+	})
+
+	assert.Equal(t, "cart-1", entry.CartID)
+	assert.Equal(t, 3, entry.Revision)
+	assert.Equal(t, "DeleteAllItems", entry.Operation)
+	assert.Equal(t, 2, entry.Totals.ItemCount)
+	assert.True(t, entry.Totals.GrandTotal.Equal(domain.NewFromFloat(20, "EUR")))
+	assert.False(t, entry.Timestamp.IsZero())
+	assert.Equal(t, []string{"*events.AddToCartEvent", "*events.ChangedQtyInCartEvent", "*events.ChangedQtyInCartEvent"}, entry.Events, "nested events are flattened")
+}
@@ -30,6 +30,12 @@ type (
 
 		AdditionalData map[string]string
 
+		// Options are the personalisation options chosen for the item, their surcharges are included in the item prices
+		Options []ItemOption
+
+		// TaxClass of the product, see productDomain.PriceInfo
+		TaxClass string
+
 		// SinglePriceGross is the gross price (incl. taxes) for a single product
 		SinglePriceGross priceDomain.Price
 
@@ -138,6 +144,8 @@ func (s *ItemSplitter) SplitInSingleQtyItems(givenItem Item) ([]Item, error) {
 			ID:                           givenItem.ID,
 			SourceID:                     givenItem.SourceID,
 			AdditionalData:               givenItem.AdditionalData,
+			Options:                      givenItem.Options,
+			TaxClass:                     givenItem.TaxClass,
 			Qty:                          1,
 			TotalDiscountAmount:          priceDomain.NewZero(givenItem.SinglePriceGross.Currency()),
 			ItemRelatedDiscountAmount:    priceDomain.NewZero(givenItem.SinglePriceGross.Currency()),
@@ -0,0 +1,102 @@
+package cart
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+	productDomain "flamingo.me/flamingo-commerce/v3/product/domain"
+)
+
+type (
+	// ItemOption is a personalisation option chosen for a cart item, e.g. an engraving text
+	ItemOption struct {
+		// Code identifies the option of the product
+		Code string
+		// Label is the speaking name of the option
+		Label string
+		// Value is the chosen value
+		Value string
+		// Surcharge is the surcharge for a single product, it is already included in the prices of the item
+		Surcharge priceDomain.Price
+	}
+)
+
+var (
+	// ErrUnknownItemOption is returned if an option is chosen that is not defined for the product
+	ErrUnknownItemOption = errors.New("unknown item option")
+	// ErrRequiredItemOptionMissing is returned if a required option of the product is not chosen
+	ErrRequiredItemOptionMissing = errors.New("required item option missing")
+	// ErrInvalidItemOptionValue is returned if the chosen value is not allowed for the option
+	ErrInvalidItemOptionValue = errors.New("invalid item option value")
+)
+
+// CreateItemOptions validates the chosen values against the options of the product and returns the item options
+// sorted by their code
+func CreateItemOptions(product productDomain.BasicProductData, values map[string]string) ([]ItemOption, error) {
+	for code := range values {
+		if _, found := product.ItemOption(code); !found {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownItemOption, code)
+		}
+	}
+
+	var options []ItemOption
+
+	for _, productOption := range product.ItemOptions {
+		value, chosen := values[productOption.Code]
+		if !chosen {
+			if productOption.Required {
+				return nil, fmt.Errorf("%w: %q", ErrRequiredItemOptionMissing, productOption.Code)
+			}
+
+			continue
+		}
+
+		if !productOption.IsValidValue(value) {
+			return nil, fmt.Errorf("%w: %q for option %q", ErrInvalidItemOptionValue, value, productOption.Code)
+		}
+
+		options = append(options, ItemOption{
+			Code:      productOption.Code,
+			Label:     productOption.Label,
+			Value:     value,
+			Surcharge: productOption.Surcharge,
+		})
+	}
+
+	sort.Slice(options, func(i, j int) bool {
+		return options[i].Code < options[j].Code
+	})
+
+	return options, nil
+}
+
+// HasOptions checks if any options are chosen for the item
+func (i Item) HasOptions() bool {
+	return len(i.Options) > 0
+}
+
+// OptionValues returns the chosen values of all options by their code, e.g. to build a new add request for the item
+func (i Item) OptionValues() map[string]string {
+	if len(i.Options) == 0 {
+		return nil
+	}
+
+	values := make(map[string]string, len(i.Options))
+	for _, option := range i.Options {
+		values[option.Code] = option.Value
+	}
+
+	return values
+}
+
+// OptionSurcharge is the sum of the surcharges of all chosen options for a single product
+func (i Item) OptionSurcharge() priceDomain.Price {
+	surcharge := priceDomain.NewZero(i.SinglePriceGross.Currency())
+	for _, option := range i.Options {
+		surcharge = surcharge.ForceAdd(option.Surcharge)
+	}
+
+	return surcharge
+}
@@ -0,0 +1,99 @@
+package cart_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+	productDomain "flamingo.me/flamingo-commerce/v3/product/domain"
+)
+
+func TestCreateItemOptions(t *testing.T) {
+	t.Parallel()
+
+	product := productDomain.BasicProductData{
+		ItemOptions: []productDomain.ItemOption{
+			{Code: "giftWrap", Label: "Gift wrap", Values: []string{"red", "blue"}, Surcharge: priceDomain.NewFromFloat(2.5, "EUR")},
+			{Code: "engraving", Label: "Engraving", Required: true, MaxLength: 5, Surcharge: priceDomain.NewFromFloat(5, "EUR")},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		values  map[string]string
+		want    []cart.ItemOption
+		wantErr error
+	}{
+		{
+			name:   "valid options",
+			values: map[string]string{"giftWrap": "red", "engraving": "Hello"},
+			want: []cart.ItemOption{
+				{Code: "engraving", Label: "Engraving", Value: "Hello", Surcharge: priceDomain.NewFromFloat(5, "EUR")},
+				{Code: "giftWrap", Label: "Gift wrap", Value: "red", Surcharge: priceDomain.NewFromFloat(2.5, "EUR")},
+			},
+		},
+		{
+			name:    "unknown option",
+			values:  map[string]string{"engraving": "Hello", "monogram": "AB"},
+			wantErr: cart.ErrUnknownItemOption,
+		},
+		{
+			name:    "required option missing",
+			values:  map[string]string{"giftWrap": "red"},
+			wantErr: cart.ErrRequiredItemOptionMissing,
+		},
+		{
+			name:    "value not allowed",
+			values:  map[string]string{"giftWrap": "green", "engraving": "Hello"},
+			wantErr: cart.ErrInvalidItemOptionValue,
+		},
+		{
+			name:    "value too long",
+			values:  map[string]string{"engraving": "Hello World"},
+			wantErr: cart.ErrInvalidItemOptionValue,
+		},
+		{
+			name:    "empty value",
+			values:  map[string]string{"engraving": ""},
+			wantErr: cart.ErrInvalidItemOptionValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := cart.CreateItemOptions(product, tt.values)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestItem_OptionSurcharge(t *testing.T) {
+	t.Parallel()
+
+	item := cart.Item{
+		SinglePriceGross: priceDomain.NewFromFloat(20, "EUR"),
+		Options: []cart.ItemOption{
+			{Code: "engraving", Value: "Hello", Surcharge: priceDomain.NewFromFloat(5, "EUR")},
+			{Code: "giftWrap", Value: "red", Surcharge: priceDomain.NewFromFloat(2.5, "EUR")},
+		},
+	}
+
+	assert.True(t, item.HasOptions())
+	assert.Equal(t, 7.5, item.OptionSurcharge().FloatAmount())
+	assert.Equal(t, map[string]string{"engraving": "Hello", "giftWrap": "red"}, item.OptionValues())
+
+	assert.True(t, cart.Item{SinglePriceGross: priceDomain.NewFromFloat(20, "EUR")}.OptionSurcharge().IsZero())
+	assert.Nil(t, cart.Item{}.OptionValues())
+}
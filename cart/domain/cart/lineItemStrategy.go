@@ -0,0 +1,120 @@
+package cart
+
+type (
+	// LineItemStrategy decides how added products are combined into the line items of a delivery
+	LineItemStrategy interface {
+		// IsSameLine checks if the add request belongs to the existing item, so that only its qty is increased
+		IsSameLine(item Item, addRequest AddRequest) bool
+		// SplitAddRequest returns the add requests for all lines that should be created for the given add request,
+		// e.g. one line per unit for personalised products
+		SplitAddRequest(addRequest AddRequest) []AddRequest
+	}
+
+	// DefaultLineItemStrategy merges items with the same marketplace code, variant, bundle configuration and item options.
+	// Additional data keys can be configured to keep lines separate, to never merge lines or to split lines in single units.
+	DefaultLineItemStrategy struct {
+		distinguishingKeys []string
+		unmergeableKeys    []string
+		singleUnitKeys     []string
+	}
+)
+
+var _ LineItemStrategy = new(DefaultLineItemStrategy)
+
+// Inject dependencies
+func (s *DefaultLineItemStrategy) Inject(
+	config *struct {
+		DistinguishingAdditionalDataKeys []string `inject:"config:commerce.cart.lineItems.distinguishingAdditionalDataKeys,optional"`
+		UnmergeableAdditionalDataKeys    []string `inject:"config:commerce.cart.lineItems.unmergeableAdditionalDataKeys,optional"`
+		SingleUnitAdditionalDataKeys     []string `inject:"config:commerce.cart.lineItems.singleUnitAdditionalDataKeys,optional"`
+	},
+) *DefaultLineItemStrategy {
+	if config != nil {
+		s.distinguishingKeys = config.DistinguishingAdditionalDataKeys
+		s.unmergeableKeys = config.UnmergeableAdditionalDataKeys
+		s.singleUnitKeys = config.SingleUnitAdditionalDataKeys
+	}
+
+	return s
+}
+
+// IsSameLine checks if the item has the same product, the same bundle configuration, the same item options and the same values for all
+// distinguishing additional data keys. Items with an unmergeable or single unit additional data key are never the same line.
+func (s *DefaultLineItemStrategy) IsSameLine(item Item, addRequest AddRequest) bool {
+	if item.MarketplaceCode != addRequest.MarketplaceCode {
+		return false
+	}
+
+	if item.VariantMarketPlaceCode != addRequest.VariantMarketplaceCode {
+		return false
+	}
+
+	if !item.BundleConfig.Equals(addRequest.BundleConfiguration) {
+		return false
+	}
+
+	if !sameOptionValues(item.OptionValues(), addRequest.Options) {
+		return false
+	}
+
+	for _, key := range s.distinguishingKeys {
+		if item.AdditionalData[key] != addRequest.AdditionalData[key] {
+			return false
+		}
+	}
+
+	for _, keys := range [][]string{s.unmergeableKeys, s.singleUnitKeys} {
+		if hasAnyKey(item.AdditionalData, keys) || hasAnyKey(addRequest.AdditionalData, keys) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SplitAddRequest splits add requests with a single unit additional data key into add requests with qty 1
+func (s *DefaultLineItemStrategy) SplitAddRequest(addRequest AddRequest) []AddRequest {
+	if addRequest.Qty <= 1 || !hasAnyKey(addRequest.AdditionalData, s.singleUnitKeys) {
+		return []AddRequest{addRequest}
+	}
+
+	addRequests := make([]AddRequest, 0, addRequest.Qty)
+
+	for i := 0; i < addRequest.Qty; i++ {
+		singleUnit := addRequest
+		singleUnit.Qty = 1
+
+		singleUnit.AdditionalData = make(map[string]string, len(addRequest.AdditionalData))
+		for key, val := range addRequest.AdditionalData {
+			singleUnit.AdditionalData[key] = val
+		}
+
+		addRequests = append(addRequests, singleUnit)
+	}
+
+	return addRequests
+}
+
+func hasAnyKey(data map[string]string, keys []string) bool {
+	for _, key := range keys {
+		if _, ok := data[key]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sameOptionValues(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for code, value := range a {
+		if other, ok := b[code]; !ok || other != value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,133 @@
+package cart_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+)
+
+func newLineItemStrategy() *cart.DefaultLineItemStrategy {
+	return new(cart.DefaultLineItemStrategy).Inject(&struct {
+		DistinguishingAdditionalDataKeys []string `inject:"config:commerce.cart.lineItems.distinguishingAdditionalDataKeys,optional"`
+		UnmergeableAdditionalDataKeys    []string `inject:"config:commerce.cart.lineItems.unmergeableAdditionalDataKeys,optional"`
+		SingleUnitAdditionalDataKeys     []string `inject:"config:commerce.cart.lineItems.singleUnitAdditionalDataKeys,optional"`
+	}{
+		DistinguishingAdditionalDataKeys: []string{"engraving"},
+		UnmergeableAdditionalDataKeys:    []string{"gift"},
+		SingleUnitAdditionalDataKeys:     []string{"personalisation"},
+	})
+}
+
+func TestDefaultLineItemStrategy_IsSameLine(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		item       cart.Item
+		addRequest cart.AddRequest
+		want       bool
+	}{
+		{
+			name:       "same product",
+			item:       cart.Item{MarketplaceCode: "foo", VariantMarketPlaceCode: "bar"},
+			addRequest: cart.AddRequest{MarketplaceCode: "foo", VariantMarketplaceCode: "bar"},
+			want:       true,
+		},
+		{
+			name:       "different variant",
+			item:       cart.Item{MarketplaceCode: "foo", VariantMarketPlaceCode: "bar"},
+			addRequest: cart.AddRequest{MarketplaceCode: "foo", VariantMarketplaceCode: "baz"},
+			want:       false,
+		},
+		{
+			name:       "same distinguishing additional data",
+			item:       cart.Item{MarketplaceCode: "foo", AdditionalData: map[string]string{"engraving": "Hello", "other": "1"}},
+			addRequest: cart.AddRequest{MarketplaceCode: "foo", AdditionalData: map[string]string{"engraving": "Hello"}},
+			want:       true,
+		},
+		{
+			name:       "different distinguishing additional data",
+			item:       cart.Item{MarketplaceCode: "foo", AdditionalData: map[string]string{"engraving": "Hello"}},
+			addRequest: cart.AddRequest{MarketplaceCode: "foo", AdditionalData: map[string]string{"engraving": "World"}},
+			want:       false,
+		},
+		{
+			name:       "same item options",
+			item:       cart.Item{MarketplaceCode: "foo", Options: []cart.ItemOption{{Code: "engraving", Value: "Hello"}}},
+			addRequest: cart.AddRequest{MarketplaceCode: "foo", Options: map[string]string{"engraving": "Hello"}},
+			want:       true,
+		},
+		{
+			name:       "different item options",
+			item:       cart.Item{MarketplaceCode: "foo", Options: []cart.ItemOption{{Code: "engraving", Value: "Hello"}}},
+			addRequest: cart.AddRequest{MarketplaceCode: "foo"},
+			want:       false,
+		},
+		{
+			name:       "unmergeable item",
+			item:       cart.Item{MarketplaceCode: "foo", AdditionalData: map[string]string{"gift": "true"}},
+			addRequest: cart.AddRequest{MarketplaceCode: "foo"},
+			want:       false,
+		},
+		{
+			name:       "unmergeable add request",
+			item:       cart.Item{MarketplaceCode: "foo"},
+			addRequest: cart.AddRequest{MarketplaceCode: "foo", AdditionalData: map[string]string{"gift": "true"}},
+			want:       false,
+		},
+		{
+			name:       "single unit item",
+			item:       cart.Item{MarketplaceCode: "foo", AdditionalData: map[string]string{"personalisation": "A"}},
+			addRequest: cart.AddRequest{MarketplaceCode: "foo", AdditionalData: map[string]string{"personalisation": "A"}},
+			want:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, newLineItemStrategy().IsSameLine(tt.item, tt.addRequest))
+		})
+	}
+}
+
+func TestDefaultLineItemStrategy_SplitAddRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("add request without single unit key is not split", func(t *testing.T) {
+		t.Parallel()
+
+		addRequest := cart.AddRequest{MarketplaceCode: "foo", Qty: 3}
+		assert.Equal(t, []cart.AddRequest{addRequest}, newLineItemStrategy().SplitAddRequest(addRequest))
+	})
+
+	t.Run("add request with single unit key is split", func(t *testing.T) {
+		t.Parallel()
+
+		addRequests := newLineItemStrategy().SplitAddRequest(cart.AddRequest{
+			MarketplaceCode: "foo",
+			Qty:             3,
+			AdditionalData:  map[string]string{"personalisation": "A"},
+		})
+
+		assert.Len(t, addRequests, 3)
+
+		for _, addRequest := range addRequests {
+			assert.Equal(t, 1, addRequest.Qty)
+			assert.Equal(t, map[string]string{"personalisation": "A"}, addRequest.AdditionalData)
+		}
+	})
+
+	t.Run("default strategy without config", func(t *testing.T) {
+		t.Parallel()
+
+		strategy := new(cart.DefaultLineItemStrategy)
+		addRequest := cart.AddRequest{MarketplaceCode: "foo", Qty: 3, AdditionalData: map[string]string{"personalisation": "A"}}
+
+		assert.Equal(t, []cart.AddRequest{addRequest}, strategy.SplitAddRequest(addRequest))
+		assert.True(t, strategy.IsSameLine(cart.Item{MarketplaceCode: "foo"}, addRequest))
+	})
+}
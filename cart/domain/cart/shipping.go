@@ -0,0 +1,44 @@
+package cart
+
+import (
+	"context"
+	"errors"
+
+	"flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+type (
+	// ShippingMethod is a shipping method available for a delivery together with its shipping costs
+	ShippingMethod struct {
+		// Code of the shipping method, used as DeliveryInfo.Method
+		Code string
+		// Carrier of the shipping method, used as DeliveryInfo.Carrier
+		Carrier    string
+		Title      string
+		PriceNet   domain.Price
+		PriceGross domain.Price
+		TaxAmount  domain.Price
+	}
+
+	// ShippingRateProvider is an optional secondary port which provides the shipping methods and costs of deliveries
+	ShippingRateProvider interface {
+		// ShippingMethods returns the shipping methods available for the delivery of the cart
+		ShippingMethods(ctx context.Context, cart *Cart, delivery *Delivery) ([]ShippingMethod, error)
+	}
+)
+
+var (
+	// ErrShippingMethodNotAvailable is returned if the shipping method can't be used for the delivery
+	ErrShippingMethodNotAvailable = errors.New("shipping method not available")
+)
+
+// ShippingMethodByCode returns the shipping method with the code and carrier, an empty carrier matches every carrier
+func ShippingMethodByCode(methods []ShippingMethod, code string, carrier string) (ShippingMethod, bool) {
+	for _, method := range methods {
+		if method.Code == code && (carrier == "" || method.Carrier == carrier) {
+			return method, true
+		}
+	}
+
+	return ShippingMethod{}, false
+}
@@ -7,6 +7,7 @@ import (
 	"go.opencensus.io/trace"
 
 	cartDomain "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/quote"
 
 	"flamingo.me/flamingo/v3/framework/flamingo"
 
@@ -17,16 +18,19 @@ import (
 type (
 	// DecoratedCartFactory - Factory to be injected: If you need to create a new Decorator then get the factory injected and use the factory
 	DecoratedCartFactory struct {
-		productService domain.ProductService
-		logger         flamingo.Logger
+		productService  domain.ProductService
+		logger          flamingo.Logger
+		compareNetPrice bool
 	}
 
 	// DecoratedCart Decorates Access To a Cart
 	DecoratedCart struct {
 		Cart                cartDomain.Cart
 		DecoratedDeliveries []DecoratedDelivery
-		Ctx                 context.Context `json:"-"`
-		Logger              flamingo.Logger `json:"-"`
+		// ChangeNotices contains the items whose price or saleable state changed since they have been added
+		ChangeNotices []ItemChangeNotice
+		Ctx           context.Context `json:"-"`
+		Logger        flamingo.Logger `json:"-"`
 	}
 
 	// DecoratedDelivery Decorates a CartItem with its Product
@@ -40,7 +44,9 @@ type (
 	DecoratedCartItem struct {
 		Item    cartDomain.Item
 		Product domain.BasicProduct
-		logger  flamingo.Logger
+		// ChangeNotice is set if the price or the saleable state of the product changed
+		ChangeNotice *ItemChangeNotice
+		logger       flamingo.Logger
 	}
 
 	// GroupedDecoratedCartItem - value object used for grouping (generated on the fly)
@@ -57,9 +63,16 @@ type (
 func (df *DecoratedCartFactory) Inject(
 	productService domain.ProductService,
 	logger flamingo.Logger,
+	config *struct {
+		CompareNetPrice bool `inject:"config:commerce.cart.itemChangeNotices.compareNetPrice,optional"`
+	},
 ) {
 	df.productService = productService
 	df.logger = logger
+
+	if config != nil {
+		df.compareNetPrice = config.CompareNetPrice
+	}
 }
 
 // Create Factory method to get Decorated Cart
@@ -71,12 +84,27 @@ func (df *DecoratedCartFactory) Create(ctx context.Context, cart cartDomain.Cart
 
 	contextWithCart := context.WithValue(ctx, cartCtxKey{}, cart)
 
+	// the prices of carts created for a quote are negotiated and locked, they don't follow the product prices
+	lockedPrices := quote.IDOfCart(cart) != ""
+
 	for _, d := range cart.Deliveries {
 		contextWithDeliveryCode := ContextWithDeliveryCode(contextWithCart, d.DeliveryInfo.Code)
 
+		decoratedItems := df.CreateDecorateCartItems(contextWithDeliveryCode, d.Cartitems)
+		for i := range decoratedItems {
+			decoratedItems[i].ChangeNotice = detectItemChange(d.DeliveryInfo.Code, decoratedItems[i], df.compareNetPrice)
+			if lockedPrices && decoratedItems[i].ChangeNotice != nil && decoratedItems[i].ChangeNotice.Type != ItemChangeNoticeNotSaleable {
+				decoratedItems[i].ChangeNotice = nil
+			}
+
+			if decoratedItems[i].ChangeNotice != nil {
+				decoratedCart.ChangeNotices = append(decoratedCart.ChangeNotices, *decoratedItems[i].ChangeNotice)
+			}
+		}
+
 		decoratedCart.DecoratedDeliveries = append(decoratedCart.DecoratedDeliveries, DecoratedDelivery{
 			Delivery:       d,
-			DecoratedItems: df.CreateDecorateCartItems(contextWithDeliveryCode, d.Cartitems),
+			DecoratedItems: decoratedItems,
 			logger:         df.logger,
 		})
 	}
@@ -0,0 +1,82 @@
+package decorator
+
+import (
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+type (
+	// ItemChangeNoticeType describes how a cart item changed since it has been added to the cart
+	ItemChangeNoticeType string
+
+	// ItemChangeNotice reports that the price or the saleable state of the product of a cart item has changed
+	ItemChangeNotice struct {
+		ItemID                 string
+		DeliveryCode           string
+		MarketplaceCode        string
+		VariantMarketplaceCode string
+		Type                   ItemChangeNoticeType
+		// StoredPrice is the single price stored in the cart item
+		StoredPrice priceDomain.Price
+		// CurrentPrice is the current active price of the product incl. item option surcharges, zero if the product is not saleable anymore
+		CurrentPrice priceDomain.Price
+	}
+)
+
+const (
+	// ItemChangeNoticePriceIncreased the current price of the product is higher than the price stored in the cart
+	ItemChangeNoticePriceIncreased ItemChangeNoticeType = "priceIncreased"
+	// ItemChangeNoticePriceDecreased the current price of the product is lower than the price stored in the cart
+	ItemChangeNoticePriceDecreased ItemChangeNoticeType = "priceDecreased"
+	// ItemChangeNoticeNotSaleable the product does not exist anymore or is not saleable
+	ItemChangeNoticeNotSaleable ItemChangeNoticeType = "notSaleable"
+)
+
+// detectItemChange compares the stored single price and the saleable state of the item with its current product
+func detectItemChange(deliveryCode string, dci DecoratedCartItem, compareNetPrice bool) *ItemChangeNotice {
+	if dci.Product == nil {
+		return nil
+	}
+
+	notice := &ItemChangeNotice{
+		ItemID:                 dci.Item.ID,
+		DeliveryCode:           deliveryCode,
+		MarketplaceCode:        dci.Item.MarketplaceCode,
+		VariantMarketplaceCode: dci.Item.VariantMarketPlaceCode,
+		StoredPrice:            dci.Item.SinglePriceGross,
+	}
+
+	if compareNetPrice {
+		notice.StoredPrice = dci.Item.SinglePriceNet
+	}
+
+	if !dci.Product.IsSaleable() || !dci.Product.SaleableData().IsSaleableNow() {
+		notice.Type = ItemChangeNoticeNotSaleable
+
+		return notice
+	}
+
+	// the stored price includes the surcharges of the chosen item options
+	notice.CurrentPrice = dci.Product.SaleableData().ActivePrice.GetFinalPrice().ForceAdd(dci.Item.OptionSurcharge()).GetPayable()
+
+	// items without price (e.g. free gifts) and prices in a different currency can't be compared
+	if notice.StoredPrice.IsZero() || notice.CurrentPrice.IsZero() || notice.StoredPrice.Currency() != notice.CurrentPrice.Currency() {
+		return nil
+	}
+
+	storedPrice := notice.StoredPrice.GetPayable()
+	switch {
+	case notice.CurrentPrice.IsGreaterThen(storedPrice):
+		notice.Type = ItemChangeNoticePriceIncreased
+	case notice.CurrentPrice.IsLessThen(storedPrice):
+		notice.Type = ItemChangeNoticePriceDecreased
+	default:
+		return nil
+	}
+
+	return notice
+}
+
+// HasChangeNotices checks if any item of the cart changed
+func (dc DecoratedCart) HasChangeNotices() bool {
+	return len(dc.ChangeNotices) > 0
+}
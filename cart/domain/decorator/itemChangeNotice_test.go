@@ -0,0 +1,82 @@
+package decorator_test
+
+import (
+	"context"
+	"testing"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/decorator"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/quote"
+	"flamingo.me/flamingo-commerce/v3/price/domain"
+	"flamingo.me/flamingo-commerce/v3/product/infrastructure/fake"
+)
+
+func TestDecoratedCartFactory_ChangeNotices(t *testing.T) {
+	t.Parallel()
+
+	factory := &decorator.DecoratedCartFactory{}
+	factory.Inject(&fake.ProductService{}, flamingo.NullLogger{}, nil)
+
+	decoratedCart := factory.Create(context.Background(), cart.Cart{
+		Deliveries: []cart.Delivery{
+			{
+				DeliveryInfo: cart.DeliveryInfo{Code: "delivery"},
+				Cartitems: []cart.Item{
+					{ID: "unchanged", MarketplaceCode: "fake_fixed_simple_without_discounts", Qty: 1, SinglePriceGross: domain.NewFromFloat(20.99, "EUR")},
+					{ID: "unchangedWithOptions", MarketplaceCode: "fake_fixed_simple_without_discounts", Qty: 1, SinglePriceGross: domain.NewFromFloat(25.99, "EUR"), Options: []cart.ItemOption{{Code: "engraving", Value: "Hello", Surcharge: domain.NewFromFloat(5, "EUR")}}},
+					{ID: "increased", MarketplaceCode: "fake_fixed_simple_without_discounts", Qty: 1, SinglePriceGross: domain.NewFromFloat(19.99, "EUR")},
+					{ID: "decreased", MarketplaceCode: "fake_fixed_simple_without_discounts", Qty: 1, SinglePriceGross: domain.NewFromFloat(25, "EUR")},
+					{ID: "free", MarketplaceCode: "fake_fixed_simple_without_discounts", Qty: 1, SinglePriceGross: domain.NewZero("EUR")},
+					{ID: "removed", MarketplaceCode: "unknown", Qty: 1, SinglePriceGross: domain.NewFromFloat(5, "EUR")},
+				},
+			},
+		},
+	})
+
+	require.True(t, decoratedCart.HasChangeNotices())
+	require.Len(t, decoratedCart.ChangeNotices, 3)
+
+	assert.Equal(t, "increased", decoratedCart.ChangeNotices[0].ItemID)
+	assert.Equal(t, "delivery", decoratedCart.ChangeNotices[0].DeliveryCode)
+	assert.Equal(t, decorator.ItemChangeNoticePriceIncreased, decoratedCart.ChangeNotices[0].Type)
+	assert.Equal(t, 20.99, decoratedCart.ChangeNotices[0].CurrentPrice.FloatAmount())
+
+	assert.Equal(t, "decreased", decoratedCart.ChangeNotices[1].ItemID)
+	assert.Equal(t, decorator.ItemChangeNoticePriceDecreased, decoratedCart.ChangeNotices[1].Type)
+
+	assert.Equal(t, "removed", decoratedCart.ChangeNotices[2].ItemID)
+	assert.Equal(t, decorator.ItemChangeNoticeNotSaleable, decoratedCart.ChangeNotices[2].Type)
+
+	assert.Nil(t, decoratedCart.DecoratedDeliveries[0].DecoratedItems[0].ChangeNotice)
+	assert.Nil(t, decoratedCart.DecoratedDeliveries[0].DecoratedItems[1].ChangeNotice)
+	require.NotNil(t, decoratedCart.DecoratedDeliveries[0].DecoratedItems[2].ChangeNotice)
+	assert.Equal(t, decorator.ItemChangeNoticePriceIncreased, decoratedCart.DecoratedDeliveries[0].DecoratedItems[2].ChangeNotice.Type)
+}
+
+func TestDecoratedCartFactory_ChangeNoticesOfQuoteCart(t *testing.T) {
+	t.Parallel()
+
+	factory := &decorator.DecoratedCartFactory{}
+	factory.Inject(&fake.ProductService{}, flamingo.NullLogger{}, nil)
+
+	decoratedCart := factory.Create(context.Background(), cart.Cart{
+		AdditionalData: cart.AdditionalData{CustomAttributes: map[string]string{quote.CartAttributeQuoteID: "quote-1"}},
+		Deliveries: []cart.Delivery{
+			{
+				DeliveryInfo: cart.DeliveryInfo{Code: "delivery"},
+				Cartitems: []cart.Item{
+					{ID: "negotiated", MarketplaceCode: "fake_fixed_simple_without_discounts", Qty: 1, SinglePriceGross: domain.NewFromFloat(15, "EUR")},
+					{ID: "removed", MarketplaceCode: "unknown", Qty: 1, SinglePriceGross: domain.NewFromFloat(5, "EUR")},
+				},
+			},
+		},
+	})
+
+	require.Len(t, decoratedCart.ChangeNotices, 1, "the locked prices of quotes are not compared")
+	assert.Equal(t, "removed", decoratedCart.ChangeNotices[0].ItemID)
+	assert.Equal(t, decorator.ItemChangeNoticeNotSaleable, decoratedCart.ChangeNotices[0].Type)
+}
@@ -38,4 +38,16 @@ type (
 		Cart                     *cartDomain.Cart
 		ResettedPaymentSelection *cartDomain.PaymentSelection
 	}
+
+	// AbandonedCartEvent is dispatched for carts with items which have not been modified for the configured time
+	AbandonedCartEvent struct {
+		Cart *cartDomain.Cart
+		// Email of the purchaser, taken from the main shipping address, may be empty
+		Email string
+	}
+
+	// CartExpiredEvent is dispatched after a cart exceeded its lifetime and has been removed from the storage
+	CartExpiredEvent struct {
+		Cart *cartDomain.Cart
+	}
 )
@@ -0,0 +1,214 @@
+package quote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"flamingo.me/flamingo/v3/core/auth"
+
+	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/placeorder"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+type (
+	// State of a quote in its lifecycle
+	State string
+
+	// Quote is a cart with negotiated prices which a sales rep offers to a customer, once accepted by the customer it can be converted into an order
+	Quote struct {
+		ID    string
+		State State
+		// CustomerID is the subject of the customer the quote is offered to
+		CustomerID string
+		// SalesRepID is the subject of the sales rep who created the quote
+		SalesRepID string
+		// Cart contains the quoted items, the prices of the items are locked and not recomputed from the ProductService
+		Cart      cart.Cart
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		ExpiresAt time.Time
+		// OrderCartID is the id of the cart created by the last conversion of the quote
+		OrderCartID string
+		// PlacedOrderInfos are the orders placed for the quote
+		PlacedOrderInfos placeorder.PlacedOrderInfos
+	}
+
+	// Storage persists the quotes - secondary port
+	Storage interface {
+		// StoreQuote creates or replaces the quote
+		StoreQuote(ctx context.Context, quote *Quote) error
+		// GetQuote returns the quote or ErrQuoteNotFound
+		GetQuote(ctx context.Context, id string) (*Quote, error)
+		// ListQuotes returns all quotes offered to the customer, newest quote first
+		ListQuotes(ctx context.Context, customerID string) ([]*Quote, error)
+	}
+
+	// CartAdapter prices the carts of the quotes and creates the carts to place the orders, it is provided by the cart adapter - secondary port
+	CartAdapter interface {
+		// UpdateItemPrice returns the cart with the negotiated single price of the item and recalculated totals, the given cart is not changed
+		UpdateItemPrice(ctx context.Context, quoteCart cart.Cart, itemID string, singlePrice priceDomain.Price) (*cart.Cart, error)
+		// CreateOrderCart stores a copy of the quote cart with the locked prices as new cart of the customer, it is marked with the quote id
+		CreateOrderCart(ctx context.Context, identity auth.Identity, quote Quote) (*cart.Cart, error)
+	}
+
+	// SalesRepResolver decides which identities are sales reps, only sales reps may create, price and send quotes - secondary port
+	SalesRepResolver interface {
+		IsSalesRep(ctx context.Context, identity auth.Identity) bool
+	}
+)
+
+const (
+	// StateDraft the quote is prepared by the sales rep, only drafts can be changed
+	StateDraft State = "draft"
+	// StateSent the quote has been offered to the customer
+	StateSent State = "sent"
+	// StateAccepted the customer accepted the quote, it can be converted into an order
+	StateAccepted State = "accepted"
+	// StateExpired the quote has not been converted before its expiry date
+	StateExpired State = "expired"
+	// StateConverted an order has been placed for the quote
+	StateConverted State = "converted"
+
+	// CartAttributeQuoteID is the key of the custom attribute of the cart additional data which marks carts created for a quote
+	CartAttributeQuoteID = "quoteID"
+)
+
+var (
+	// ErrQuoteNotFound is returned if the quote does not exist
+	ErrQuoteNotFound = errors.New("quote not found")
+	// ErrInvalidTransition is returned if the state of the quote does not allow the requested transition
+	ErrInvalidTransition = errors.New("invalid quote state transition")
+	// ErrQuoteExpired is returned if the quote is changed after its expiry date
+	ErrQuoteExpired = errors.New("quote expired")
+	// ErrQuoteNotEditable is returned if the cart of a quote is changed which is not a draft anymore
+	ErrQuoteNotEditable = errors.New("quote is not a draft")
+
+	// transitions contains the allowed target states of every state
+	transitions = map[State][]State{
+		StateDraft:    {StateSent, StateExpired},
+		StateSent:     {StateAccepted, StateExpired},
+		StateAccepted: {StateConverted, StateExpired},
+	}
+)
+
+// NewQuote creates a draft quote for the customer with a copy of the cart
+func NewQuote(id string, customerID string, salesRepID string, quoteCart cart.Cart, now time.Time, expiresAt time.Time) (*Quote, error) {
+	clonedCart, err := quoteCart.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("quote: error cloning cart: %w", err)
+	}
+
+	return &Quote{
+		ID:         id,
+		State:      StateDraft,
+		CustomerID: customerID,
+		SalesRepID: salesRepID,
+		Cart:       clonedCart,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// IDOfCart returns the id of the quote the cart has been created for, empty if it is a regular cart
+func IDOfCart(c cart.Cart) string {
+	return c.AdditionalData.CustomAttributes[CartAttributeQuoteID]
+}
+
+// IsFinal checks if the quote reached a state without further transitions
+func (q *Quote) IsFinal() bool {
+	return len(transitions[q.State]) == 0
+}
+
+// IsExpired checks if the expiry date of the quote is reached, converted quotes never expire
+func (q *Quote) IsExpired(now time.Time) bool {
+	if q.State == StateExpired {
+		return true
+	}
+
+	return !q.IsFinal() && !q.ExpiresAt.IsZero() && !now.Before(q.ExpiresAt)
+}
+
+// CanTransitionTo checks if the quote can change to the given state
+func (q *Quote) CanTransitionTo(state State) bool {
+	for _, allowed := range transitions[q.State] {
+		if allowed == state {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExpireIfDue changes the quote to expired if its expiry date is reached and reports if the state changed
+func (q *Quote) ExpireIfDue(now time.Time) bool {
+	if q.State == StateExpired || !q.IsExpired(now) {
+		return false
+	}
+
+	q.State = StateExpired
+	q.UpdatedAt = now
+
+	return true
+}
+
+// UpdateCart replaces the cart of a draft quote
+func (q *Quote) UpdateCart(quoteCart cart.Cart, now time.Time) error {
+	if q.ExpireIfDue(now) {
+		return ErrQuoteExpired
+	}
+
+	if q.State != StateDraft {
+		return ErrQuoteNotEditable
+	}
+
+	q.Cart = quoteCart
+	q.UpdatedAt = now
+
+	return nil
+}
+
+// Send offers the draft quote to the customer
+func (q *Quote) Send(now time.Time) error {
+	if q.ExpireIfDue(now) {
+		return ErrQuoteExpired
+	}
+
+	return q.transition(StateSent, now)
+}
+
+// Accept marks the sent quote as accepted by the customer
+func (q *Quote) Accept(now time.Time) error {
+	if q.ExpireIfDue(now) {
+		return ErrQuoteExpired
+	}
+
+	return q.transition(StateAccepted, now)
+}
+
+// Convert marks the accepted quote as converted into the placed orders, the expiry date is not checked
+// because the order has been started before
+func (q *Quote) Convert(now time.Time, placedOrderInfos placeorder.PlacedOrderInfos) error {
+	err := q.transition(StateConverted, now)
+	if err != nil {
+		return err
+	}
+
+	q.PlacedOrderInfos = placedOrderInfos
+
+	return nil
+}
+
+func (q *Quote) transition(state State, now time.Time) error {
+	if !q.CanTransitionTo(state) {
+		return fmt.Errorf("%w from %q to %q", ErrInvalidTransition, q.State, state)
+	}
+
+	q.State = state
+	q.UpdatedAt = now
+
+	return nil
+}
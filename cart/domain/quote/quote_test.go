@@ -0,0 +1,100 @@
+package quote_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/placeorder"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/quote"
+)
+
+func newQuote(t *testing.T, now time.Time) *quote.Quote {
+	t.Helper()
+
+	q, err := quote.NewQuote("quote-1", "customer-1", "rep-1", cart.Cart{ID: "cart-1"}, now, now.Add(24*time.Hour))
+	require.NoError(t, err)
+
+	return q
+}
+
+func TestQuote_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	q := newQuote(t, now)
+	assert.Equal(t, quote.StateDraft, q.State)
+	assert.Equal(t, "cart-1", q.Cart.ID)
+
+	require.NoError(t, q.UpdateCart(cart.Cart{ID: "cart-2"}, now))
+	assert.Equal(t, "cart-2", q.Cart.ID)
+
+	assert.ErrorIs(t, q.Accept(now), quote.ErrInvalidTransition, "drafts can't be accepted")
+
+	require.NoError(t, q.Send(now.Add(time.Hour)))
+	assert.Equal(t, quote.StateSent, q.State)
+	assert.Equal(t, now.Add(time.Hour), q.UpdatedAt)
+	assert.ErrorIs(t, q.UpdateCart(cart.Cart{}, now), quote.ErrQuoteNotEditable)
+
+	require.NoError(t, q.Accept(now.Add(2*time.Hour)))
+	assert.Equal(t, quote.StateAccepted, q.State)
+
+	orderInfos := placeorder.PlacedOrderInfos{{OrderNumber: "order-1"}}
+	require.NoError(t, q.Convert(now.Add(48*time.Hour), orderInfos), "the expiry is not checked on conversion")
+	assert.Equal(t, quote.StateConverted, q.State)
+	assert.Equal(t, orderInfos, q.PlacedOrderInfos)
+	assert.True(t, q.IsFinal())
+	assert.False(t, q.IsExpired(now.Add(48*time.Hour)), "converted quotes don't expire")
+	assert.ErrorIs(t, q.Send(now), quote.ErrInvalidTransition)
+}
+
+func TestQuote_Expiry(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("expired quotes can't be sent", func(t *testing.T) {
+		t.Parallel()
+
+		q := newQuote(t, now)
+
+		assert.ErrorIs(t, q.Send(now.Add(24*time.Hour)), quote.ErrQuoteExpired)
+		assert.Equal(t, quote.StateExpired, q.State)
+		assert.True(t, q.IsFinal())
+	})
+
+	t.Run("expired quotes can't be accepted", func(t *testing.T) {
+		t.Parallel()
+
+		q := newQuote(t, now)
+		require.NoError(t, q.Send(now))
+
+		assert.ErrorIs(t, q.Accept(now.Add(25*time.Hour)), quote.ErrQuoteExpired)
+		assert.Equal(t, quote.StateExpired, q.State)
+	})
+
+	t.Run("expire if due", func(t *testing.T) {
+		t.Parallel()
+
+		q := newQuote(t, now)
+
+		assert.False(t, q.ExpireIfDue(now.Add(time.Hour)))
+		assert.Equal(t, quote.StateDraft, q.State)
+		assert.True(t, q.ExpireIfDue(now.Add(24*time.Hour)))
+		assert.Equal(t, quote.StateExpired, q.State)
+		assert.False(t, q.ExpireIfDue(now.Add(48*time.Hour)), "already expired")
+	})
+}
+
+func TestIDOfCart(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, quote.IDOfCart(cart.Cart{}))
+	assert.Equal(t, "quote-1", quote.IDOfCart(cart.Cart{
+		AdditionalData: cart.AdditionalData{CustomAttributes: map[string]string{quote.CartAttributeQuoteID: "quote-1"}},
+	}))
+}
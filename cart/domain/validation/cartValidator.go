@@ -39,6 +39,22 @@ func (c Result) IsValid() bool {
 	return true
 }
 
+// MergeResults combines the results of multiple validators, the common error message key of the first result with a common error is kept
+func MergeResults(results ...Result) Result {
+	merged := Result{}
+
+	for _, result := range results {
+		if result.HasCommonError && !merged.HasCommonError {
+			merged.HasCommonError = true
+			merged.CommonErrorMessageKey = result.CommonErrorMessageKey
+		}
+
+		merged.ItemResults = append(merged.ItemResults, result.ItemResults...)
+	}
+
+	return merged
+}
+
 // HasErrorForItem checks if a specified item has an error
 func (c Result) HasErrorForItem(id string) bool {
 	for _, itemMessage := range c.ItemResults {
@@ -0,0 +1,31 @@
+package validation_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"flamingo.me/flamingo-commerce/v3/cart/domain/validation"
+)
+
+func TestMergeResults(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, validation.MergeResults().IsValid())
+	assert.True(t, validation.MergeResults(validation.Result{}, validation.Result{}).IsValid())
+
+	merged := validation.MergeResults(
+		validation.Result{ItemResults: []validation.ItemValidationError{{ItemID: "1", ErrorMessageKey: "item.first"}}},
+		validation.Result{HasCommonError: true, CommonErrorMessageKey: "common.first"},
+		validation.Result{
+			HasCommonError:        true,
+			CommonErrorMessageKey: "common.second",
+			ItemResults:           []validation.ItemValidationError{{ItemID: "2", ErrorMessageKey: "item.second"}},
+		},
+	)
+
+	assert.False(t, merged.IsValid())
+	assert.Equal(t, "common.first", merged.CommonErrorMessageKey)
+	assert.Equal(t, "item.first", merged.GetErrorMessageKeyForItem("1"))
+	assert.Equal(t, "item.second", merged.GetErrorMessageKeyForItem("2"))
+}
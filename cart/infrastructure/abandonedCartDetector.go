@@ -0,0 +1,129 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"go.opencensus.io/trace"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/events"
+	"flamingo.me/flamingo-commerce/v3/internal/periodic"
+)
+
+type (
+	// AbandonedCartDetector periodically scans the cart storage and dispatches an events.AbandonedCartEvent for every
+	// cart with items that has not been modified for the configured time.
+	// Every revision of a cart is reported only once per running instance, the detector has to be a singleton
+	// to remember the notified revisions.
+	AbandonedCartDetector struct {
+		cartStorage    CartStorage
+		eventRouter    flamingo.EventRouter
+		logger         flamingo.Logger
+		abandonedAfter time.Duration
+		now            func() time.Time
+		notified       map[string]int
+		mutex          sync.Mutex
+		job            *periodic.Job
+	}
+)
+
+var _ flamingo.Subscriber = &AbandonedCartDetector{}
+
+// Inject dependencies
+func (d *AbandonedCartDetector) Inject(
+	cartStorage CartStorage,
+	eventRouter flamingo.EventRouter,
+	logger flamingo.Logger,
+	cfg *struct {
+		Interval       string `inject:"config:commerce.cart.defaultCartAdapter.abandonedCarts.interval"`
+		AbandonedAfter string `inject:"config:commerce.cart.defaultCartAdapter.abandonedCarts.abandonedAfter"`
+	},
+) *AbandonedCartDetector {
+	d.cartStorage = cartStorage
+	d.eventRouter = eventRouter
+	d.logger = logger.WithField(flamingo.LogKeyModule, "cart").WithField(flamingo.LogKeyCategory, "AbandonedCartDetector")
+	d.now = time.Now
+	d.notified = make(map[string]int)
+
+	var interval time.Duration
+	if cfg != nil {
+		var err error
+		interval, err = time.ParseDuration(cfg.Interval)
+		if err != nil {
+			panic("can't parse commerce.cart.defaultCartAdapter.abandonedCarts.interval")
+		}
+
+		d.abandonedAfter, err = time.ParseDuration(cfg.AbandonedAfter)
+		if err != nil {
+			panic("can't parse commerce.cart.defaultCartAdapter.abandonedCarts.abandonedAfter")
+		}
+	}
+
+	d.job = periodic.NewJob(interval, d.Detect, d.logger)
+
+	return d
+}
+
+// Notify starts the detection on server start and stops it on shutdown
+func (d *AbandonedCartDetector) Notify(ctx context.Context, event flamingo.Event) {
+	d.job.Notify(ctx, event)
+}
+
+// Detect scans the cart storage once and dispatches an events.AbandonedCartEvent for every newly abandoned cart
+func (d *AbandonedCartDetector) Detect(ctx context.Context) error {
+	ctx, span := trace.StartSpan(ctx, "cart/AbandonedCartDetector/Detect")
+	defer span.End()
+
+	storage, ok := d.cartStorage.(IterableCartStorage)
+	if !ok {
+		return fmt.Errorf("AbandonedCartDetector: cart storage %T does not support iterating carts", d.cartStorage)
+	}
+
+	deadline := d.now().Add(-d.abandonedAfter)
+	seen := make(map[string]bool)
+
+	var abandoned []*domaincart.Cart
+
+	err := storage.ForEachCart(ctx, func(cart *domaincart.Cart) error {
+		seen[cart.ID] = true
+
+		// a cart without UpdatedAt was last stored by a version without cart timestamps, it is unknown how long it has been idle
+		if cart.ItemCount() == 0 || cart.UpdatedAt.IsZero() || cart.UpdatedAt.After(deadline) {
+			return nil
+		}
+
+		d.mutex.Lock()
+		revision, notified := d.notified[cart.ID]
+		if !notified || revision != cart.Revision {
+			d.notified[cart.ID] = cart.Revision
+			abandoned = append(abandoned, cart)
+		}
+		d.mutex.Unlock()
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("AbandonedCartDetector: %w", err)
+	}
+
+	d.mutex.Lock()
+	for id := range d.notified {
+		if !seen[id] {
+			delete(d.notified, id)
+		}
+	}
+	d.mutex.Unlock()
+
+	for _, cart := range abandoned {
+		d.eventRouter.Dispatch(ctx, &events.AbandonedCartEvent{
+			Cart:  cart,
+			Email: cart.GetMainShippingEMail(),
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/events"
+)
+
+type recordingEventRouter struct {
+	events []flamingo.Event
+}
+
+func (r *recordingEventRouter) Dispatch(_ context.Context, event flamingo.Event) {
+	r.events = append(r.events, event)
+}
+
+func TestAbandonedCartDetector_Detect(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	cartWithItem := func(id string, updatedAt time.Time) *domaincart.Cart {
+		return &domaincart.Cart{
+			ID:        id,
+			Revision:  1,
+			UpdatedAt: updatedAt,
+			Deliveries: []domaincart.Delivery{
+				{
+					DeliveryInfo: domaincart.DeliveryInfo{
+						Code: "delivery",
+						DeliveryLocation: domaincart.DeliveryLocation{
+							Address: &domaincart.Address{Email: "customer@example.com"},
+						},
+					},
+					Cartitems: []domaincart.Item{{ID: "1", Qty: 1}},
+				},
+			},
+		}
+	}
+
+	newDetector := func(storage CartStorage, router flamingo.EventRouter) *AbandonedCartDetector {
+		detector := new(AbandonedCartDetector).Inject(storage, router, flamingo.NullLogger{}, &struct {
+			Interval       string `inject:"config:commerce.cart.defaultCartAdapter.abandonedCarts.interval"`
+			AbandonedAfter string `inject:"config:commerce.cart.defaultCartAdapter.abandonedCarts.abandonedAfter"`
+		}{
+			Interval:       "10m",
+			AbandonedAfter: "24h",
+		})
+		detector.now = func() time.Time { return now }
+
+		return detector
+	}
+
+	t.Run("only old carts with items are reported", func(t *testing.T) {
+		t.Parallel()
+
+		storage := newInMemoryStorage()
+		require.NoError(t, storage.StoreCart(context.Background(), cartWithItem("abandoned", now.Add(-25*time.Hour))))
+		require.NoError(t, storage.StoreCart(context.Background(), cartWithItem("active", now.Add(-time.Hour))))
+		require.NoError(t, storage.StoreCart(context.Background(), &domaincart.Cart{ID: "empty", Revision: 1, UpdatedAt: now.Add(-48 * time.Hour)}))
+
+		router := new(recordingEventRouter)
+		require.NoError(t, newDetector(storage, router).Detect(context.Background()))
+
+		require.Len(t, router.events, 1)
+		event, ok := router.events[0].(*events.AbandonedCartEvent)
+		require.True(t, ok)
+		assert.Equal(t, "abandoned", event.Cart.ID)
+		assert.Equal(t, "customer@example.com", event.Email)
+	})
+
+	t.Run("cart revision is reported only once", func(t *testing.T) {
+		t.Parallel()
+
+		storage := newInMemoryStorage()
+		cart := cartWithItem("abandoned", now.Add(-25*time.Hour))
+		require.NoError(t, storage.StoreCart(context.Background(), cart))
+
+		router := new(recordingEventRouter)
+		detector := newDetector(storage, router)
+
+		require.NoError(t, detector.Detect(context.Background()))
+		require.NoError(t, detector.Detect(context.Background()))
+		assert.Len(t, router.events, 1)
+
+		modified := cartWithItem("abandoned", now.Add(-25*time.Hour))
+		modified.Revision = 2
+		require.NoError(t, storage.StoreCart(context.Background(), modified))
+
+		require.NoError(t, detector.Detect(context.Background()))
+		assert.Len(t, router.events, 2)
+	})
+
+	t.Run("storage without iteration support", func(t *testing.T) {
+		t.Parallel()
+
+		router := new(recordingEventRouter)
+		err := newDetector(struct{ CartStorage }{newInMemoryStorage()}, router).Detect(context.Background())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,289 @@
+package infrastructure
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"flamingo.me/flamingo/v3/core/healthcheck/domain/healthcheck"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/gomodule/redigo/redis"
+	"go.opencensus.io/trace"
+
+	"flamingo.me/flamingo-commerce/v3/cart/application"
+)
+
+type (
+	// LRUCartCacheStore keeps the cached carts in memory of the running instance and drops the least recently used entries
+	// once the configured size is exceeded
+	LRUCartCacheStore struct {
+		mutex   sync.Mutex
+		size    int
+		entries map[string]*list.Element
+		order   *list.List
+		now     func() time.Time
+	}
+
+	// RedisCartCacheStore keeps the cached carts in redis, so that they are shared by all instances and can be invalidated from anywhere.
+	// Entries read from redis are kept in a local LRUCartCacheStore for the configured local lifetime.
+	RedisCartCacheStore struct {
+		pool          *redis.Pool
+		logger        flamingo.Logger
+		keyPrefix     string
+		local         *LRUCartCacheStore
+		localLifetime time.Duration
+	}
+
+	lruCartCacheEntry struct {
+		key       string
+		value     []byte
+		expiresAt time.Time
+	}
+)
+
+var (
+	_ application.CartCacheStore = &LRUCartCacheStore{}
+	_ application.CartCacheStore = &RedisCartCacheStore{}
+	_ healthcheck.Status         = &RedisCartCacheStore{}
+)
+
+const defaultLRUCartCacheStoreSize = 10000
+
+// Inject dependencies
+func (s *LRUCartCacheStore) Inject(
+	cfg *struct {
+		Size float64 `inject:"config:commerce.cart.sharedCache.size,optional"`
+	},
+) *LRUCartCacheStore {
+	s.entries = make(map[string]*list.Element)
+	s.order = list.New()
+	s.now = time.Now
+	s.size = defaultLRUCartCacheStoreSize
+
+	if cfg != nil && cfg.Size > 0 {
+		s.size = int(cfg.Size)
+	}
+
+	return s
+}
+
+// Get returns the value of the key or application.ErrNoCacheEntry
+func (s *LRUCartCacheStore) Get(ctx context.Context, key string) ([]byte, error) {
+	_, span := trace.StartSpan(ctx, "cart/LRUCartCacheStore/Get")
+	defer span.End()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, application.ErrNoCacheEntry
+	}
+
+	entry := element.Value.(*lruCartCacheEntry)
+	if !s.now().Before(entry.expiresAt) {
+		s.remove(element)
+
+		return nil, application.ErrNoCacheEntry
+	}
+
+	s.order.MoveToFront(element)
+
+	return entry.value, nil
+}
+
+// Set stores the value and drops the least recently used entry if the store is full
+func (s *LRUCartCacheStore) Set(ctx context.Context, key string, value []byte, lifetime time.Duration) error {
+	_, span := trace.StartSpan(ctx, "cart/LRUCartCacheStore/Set")
+	defer span.End()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry := &lruCartCacheEntry{key: key, value: value, expiresAt: s.now().Add(lifetime)}
+
+	if element, ok := s.entries[key]; ok {
+		element.Value = entry
+		s.order.MoveToFront(element)
+
+		return nil
+	}
+
+	s.entries[key] = s.order.PushFront(entry)
+
+	for s.order.Len() > s.size {
+		s.remove(s.order.Back())
+	}
+
+	return nil
+}
+
+// Delete removes the key and returns false if it did not exist
+func (s *LRUCartCacheStore) Delete(ctx context.Context, key string) (bool, error) {
+	_, span := trace.StartSpan(ctx, "cart/LRUCartCacheStore/Delete")
+	defer span.End()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return false, nil
+	}
+
+	s.remove(element)
+
+	return true, nil
+}
+
+func (s *LRUCartCacheStore) remove(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.entries, element.Value.(*lruCartCacheEntry).key)
+}
+
+// Inject dependencies
+func (r *RedisCartCacheStore) Inject(
+	logger flamingo.Logger,
+	cfg *struct {
+		Size                    float64 `inject:"config:commerce.cart.sharedCache.size,optional"`
+		LocalLifetime           string  `inject:"config:commerce.cart.sharedCache.redis.localLifetime"`
+		MaxIdle                 int     `inject:"config:commerce.cart.sharedCache.redis.maxIdle"`
+		IdleTimeoutMilliseconds int     `inject:"config:commerce.cart.sharedCache.redis.idleTimeoutMilliseconds"`
+		Network                 string  `inject:"config:commerce.cart.sharedCache.redis.network"`
+		Address                 string  `inject:"config:commerce.cart.sharedCache.redis.address"`
+		Database                int     `inject:"config:commerce.cart.sharedCache.redis.database"`
+		Username                string  `inject:"config:commerce.cart.sharedCache.redis.username,optional"`
+		Password                string  `inject:"config:commerce.cart.sharedCache.redis.password,optional"`
+		UseTLS                  bool    `inject:"config:commerce.cart.sharedCache.redis.useTLS,optional"`
+		KeyPrefix               string  `inject:"config:commerce.cart.sharedCache.redis.keyPrefix"`
+	},
+) *RedisCartCacheStore {
+	r.logger = logger.WithField(flamingo.LogKeyModule, "cart").WithField(flamingo.LogKeyCategory, "RedisCartCacheStore")
+
+	if cfg == nil {
+		return r
+	}
+
+	var err error
+	r.localLifetime, err = time.ParseDuration(cfg.LocalLifetime)
+	if err != nil {
+		panic("can't parse commerce.cart.sharedCache.redis.localLifetime")
+	}
+
+	r.keyPrefix = cfg.KeyPrefix
+	r.local = new(LRUCartCacheStore).Inject(&struct {
+		Size float64 `inject:"config:commerce.cart.sharedCache.size,optional"`
+	}{Size: cfg.Size})
+
+	r.pool = newRedisPool(redisPoolConfig{
+		MaxIdle:                 cfg.MaxIdle,
+		IdleTimeoutMilliseconds: cfg.IdleTimeoutMilliseconds,
+		Network:                 cfg.Network,
+		Address:                 cfg.Address,
+		Database:                cfg.Database,
+		Username:                cfg.Username,
+		Password:                cfg.Password,
+		UseTLS:                  cfg.UseTLS,
+	})
+	runtime.SetFinalizer(r, func(r *RedisCartCacheStore) { r.pool.Close() }) // close all connections on destruction
+
+	return r
+}
+
+// Get returns the value from the local store or from redis, application.ErrNoCacheEntry if it does not exist
+func (r *RedisCartCacheStore) Get(ctx context.Context, key string) ([]byte, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/RedisCartCacheStore/Get")
+	defer span.End()
+
+	if r.localLifetime > 0 {
+		if value, err := r.local.Get(ctx, key); err == nil {
+			return value, nil
+		}
+	}
+
+	conn := r.pool.Get()
+	defer conn.Close()
+	if conn.Err() != nil {
+		r.logger.WithContext(ctx).Error("Get:", conn.Err())
+		return nil, ErrNoRedisConnection
+	}
+
+	value, err := redis.Bytes(conn.Do("GET", r.keyPrefix+key))
+	if err == redis.ErrNil {
+		return nil, application.ErrNoCacheEntry
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("RedisCartCacheStore: error getting %q: %w", key, err)
+	}
+
+	if r.localLifetime > 0 {
+		_ = r.local.Set(ctx, key, value, r.localLifetime)
+	}
+
+	return value, nil
+}
+
+// Set stores the value in redis with the lifetime as expiry
+func (r *RedisCartCacheStore) Set(ctx context.Context, key string, value []byte, lifetime time.Duration) error {
+	ctx, span := trace.StartSpan(ctx, "cart/RedisCartCacheStore/Set")
+	defer span.End()
+
+	conn := r.pool.Get()
+	defer conn.Close()
+	if conn.Err() != nil {
+		r.logger.WithContext(ctx).Error("Set:", conn.Err())
+		return ErrNoRedisConnection
+	}
+
+	_, err := conn.Do("SET", r.keyPrefix+key, value, "PX", lifetime.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("RedisCartCacheStore: error setting %q: %w", key, err)
+	}
+
+	if r.localLifetime > 0 {
+		_ = r.local.Set(ctx, key, value, min(lifetime, r.localLifetime))
+	}
+
+	return nil
+}
+
+// Delete removes the key from redis and the local store and returns false if it did not exist in redis
+func (r *RedisCartCacheStore) Delete(ctx context.Context, key string) (bool, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/RedisCartCacheStore/Delete")
+	defer span.End()
+
+	if r.localLifetime > 0 {
+		_, _ = r.local.Delete(ctx, key)
+	}
+
+	conn := r.pool.Get()
+	defer conn.Close()
+	if conn.Err() != nil {
+		r.logger.WithContext(ctx).Error("Delete:", conn.Err())
+		return false, ErrNoRedisConnection
+	}
+
+	deleted, err := redis.Int(conn.Do("DEL", r.keyPrefix+key))
+	if err != nil {
+		return false, fmt.Errorf("RedisCartCacheStore: error deleting %q: %w", key, err)
+	}
+
+	return deleted > 0, nil
+}
+
+// Status handles the health check of redis
+func (r *RedisCartCacheStore) Status() (alive bool, details string) {
+	conn := r.pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("PING")
+	if err != nil {
+		return false, err.Error()
+	}
+
+	return true, "redis for cart cache replies to PING"
+}
@@ -0,0 +1,99 @@
+package infrastructure_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo-commerce/v3/cart/application"
+	"flamingo.me/flamingo-commerce/v3/cart/infrastructure"
+)
+
+func TestLRUCartCacheStore(t *testing.T) {
+	t.Parallel()
+
+	store := new(infrastructure.LRUCartCacheStore).Inject(&struct {
+		Size float64 `inject:"config:commerce.cart.sharedCache.size,optional"`
+	}{Size: 2})
+
+	ctx := context.Background()
+	require.NoError(t, store.Set(ctx, "a", []byte("1"), time.Hour))
+	require.NoError(t, store.Set(ctx, "b", []byte("2"), time.Hour))
+
+	value, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), value)
+
+	require.NoError(t, store.Set(ctx, "c", []byte("3"), time.Hour))
+
+	_, err = store.Get(ctx, "b")
+	assert.ErrorIs(t, err, application.ErrNoCacheEntry, "the least recently used entry is dropped")
+
+	_, err = store.Get(ctx, "a")
+	assert.NoError(t, err)
+
+	require.NoError(t, store.Set(ctx, "expired", []byte("4"), -time.Second))
+	_, err = store.Get(ctx, "expired")
+	assert.ErrorIs(t, err, application.ErrNoCacheEntry, "expired entries are not returned")
+
+	deleted, err := store.Delete(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = store.Delete(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
+
+func TestRedisCartCacheStore(t *testing.T) {
+	server, _ := startUpLocalCartRedis(t)
+
+	newStore := func(localLifetime string) *infrastructure.RedisCartCacheStore {
+		return new(infrastructure.RedisCartCacheStore).Inject(new(flamingo.NullLogger), &struct {
+			Size                    float64 `inject:"config:commerce.cart.sharedCache.size,optional"`
+			LocalLifetime           string  `inject:"config:commerce.cart.sharedCache.redis.localLifetime"`
+			MaxIdle                 int     `inject:"config:commerce.cart.sharedCache.redis.maxIdle"`
+			IdleTimeoutMilliseconds int     `inject:"config:commerce.cart.sharedCache.redis.idleTimeoutMilliseconds"`
+			Network                 string  `inject:"config:commerce.cart.sharedCache.redis.network"`
+			Address                 string  `inject:"config:commerce.cart.sharedCache.redis.address"`
+			Database                int     `inject:"config:commerce.cart.sharedCache.redis.database"`
+			Username                string  `inject:"config:commerce.cart.sharedCache.redis.username,optional"`
+			Password                string  `inject:"config:commerce.cart.sharedCache.redis.password,optional"`
+			UseTLS                  bool    `inject:"config:commerce.cart.sharedCache.redis.useTLS,optional"`
+			KeyPrefix               string  `inject:"config:commerce.cart.sharedCache.redis.keyPrefix"`
+		}{Size: 100, LocalLifetime: localLifetime, MaxIdle: 3, IdleTimeoutMilliseconds: 240000, Network: "unix", Address: server.Socket(), KeyPrefix: "cartCache:"})
+	}
+
+	// two instances sharing the redis, the reader has no local store to see deletions immediately
+	writer := newStore("1m")
+	reader := newStore("0s")
+
+	ctx := context.Background()
+	require.NoError(t, writer.Set(ctx, "key", []byte("value"), time.Hour))
+
+	value, err := reader.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+
+	deleted, err := reader.Delete(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	_, err = reader.Get(ctx, "key")
+	assert.ErrorIs(t, err, application.ErrNoCacheEntry)
+
+	value, err = writer.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value, "the local store keeps entries for the local lifetime")
+
+	deleted, err = writer.Delete(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+
+	alive, _ := writer.Status()
+	assert.True(t, alive)
+}
@@ -0,0 +1,173 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"go.opencensus.io/trace"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/events"
+	"flamingo.me/flamingo-commerce/v3/internal/periodic"
+)
+
+type (
+	// CartExpirer decides if a cart of the default cart adapter exceeded its lifetime and removes it
+	CartExpirer interface {
+		// IsExpired returns true if the cart has not been modified for longer than its lifetime
+		IsExpired(cart *domaincart.Cart) bool
+		// Expire removes the cart from the storage and dispatches an events.CartExpiredEvent
+		Expire(ctx context.Context, cart *domaincart.Cart) error
+	}
+
+	// CartExpiry expires guest and customer carts which have not been modified for the configured lifetime.
+	// Expired carts are removed when they are loaded by the DefaultCartBehaviour, carts nobody loads anymore are
+	// removed by the sweep every sweepInterval. Bind it as singleton so that only one sweep runs per instance.
+	CartExpiry struct {
+		cartStorage          CartStorage
+		eventRouter          flamingo.EventRouter
+		logger               flamingo.Logger
+		guestCartLifetime    time.Duration
+		customerCartLifetime time.Duration
+		now                  func() time.Time
+		job                  *periodic.Job
+	}
+)
+
+var (
+	_ CartExpirer         = &CartExpiry{}
+	_ flamingo.Subscriber = &CartExpiry{}
+)
+
+// Inject dependencies
+func (e *CartExpiry) Inject(
+	cartStorage CartStorage,
+	eventRouter flamingo.EventRouter,
+	logger flamingo.Logger,
+	cfg *struct {
+		GuestCartLifetime    string `inject:"config:commerce.cart.defaultCartAdapter.expiry.guestCartLifetime"`
+		CustomerCartLifetime string `inject:"config:commerce.cart.defaultCartAdapter.expiry.customerCartLifetime"`
+		SweepInterval        string `inject:"config:commerce.cart.defaultCartAdapter.expiry.sweepInterval"`
+	},
+) *CartExpiry {
+	e.cartStorage = cartStorage
+	e.eventRouter = eventRouter
+	e.logger = logger.WithField(flamingo.LogKeyModule, "cart").WithField(flamingo.LogKeyCategory, "CartExpiry")
+	e.now = time.Now
+
+	var sweepInterval time.Duration
+	if cfg != nil {
+		var err error
+		e.guestCartLifetime, err = time.ParseDuration(cfg.GuestCartLifetime)
+		if err != nil {
+			panic("can't parse commerce.cart.defaultCartAdapter.expiry.guestCartLifetime")
+		}
+
+		e.customerCartLifetime, err = time.ParseDuration(cfg.CustomerCartLifetime)
+		if err != nil {
+			panic("can't parse commerce.cart.defaultCartAdapter.expiry.customerCartLifetime")
+		}
+
+		sweepInterval, err = time.ParseDuration(cfg.SweepInterval)
+		if err != nil {
+			panic("can't parse commerce.cart.defaultCartAdapter.expiry.sweepInterval")
+		}
+	}
+
+	e.job = periodic.NewJob(sweepInterval, func(ctx context.Context) error {
+		_, err := e.Sweep(ctx)
+
+		return err
+	}, e.logger)
+
+	return e
+}
+
+// ExpiresAt returns the time the cart expires, false if the cart never expires
+func (e *CartExpiry) ExpiresAt(cart *domaincart.Cart) (time.Time, bool) {
+	lifetime := e.guestCartLifetime
+	if cart.BelongsToAuthenticatedUser {
+		lifetime = e.customerCartLifetime
+	}
+
+	// UpdatedAt is missing on carts last stored by a version without cart timestamps, they are kept rather than expired immediately
+	if lifetime <= 0 || cart.UpdatedAt.IsZero() {
+		return time.Time{}, false
+	}
+
+	return cart.UpdatedAt.Add(lifetime), true
+}
+
+// IsExpired returns true if the cart has not been modified for longer than its lifetime
+func (e *CartExpiry) IsExpired(cart *domaincart.Cart) bool {
+	expiresAt, expires := e.ExpiresAt(cart)
+
+	return expires && !e.now().Before(expiresAt)
+}
+
+// Expire removes the cart from the storage and dispatches an events.CartExpiredEvent
+func (e *CartExpiry) Expire(ctx context.Context, cart *domaincart.Cart) error {
+	ctx, span := trace.StartSpan(ctx, "cart/CartExpiry/Expire")
+	defer span.End()
+
+	err := e.cartStorage.RemoveCart(ctx, cart)
+	if err != nil {
+		return fmt.Errorf("CartExpiry: error removing expired cart %q: %w", cart.ID, err)
+	}
+
+	e.logger.WithContext(ctx).Info(fmt.Sprintf("removed expired cart %q", cart.ID))
+	e.eventRouter.Dispatch(ctx, &events.CartExpiredEvent{Cart: cart})
+
+	return nil
+}
+
+// Notify starts the background sweep on server start and stops it on shutdown
+func (e *CartExpiry) Notify(ctx context.Context, event flamingo.Event) {
+	e.job.Notify(ctx, event)
+}
+
+// Sweep scans the cart storage once, expires all carts which exceeded their lifetime and returns their number
+func (e *CartExpiry) Sweep(ctx context.Context) (int, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/CartExpiry/Sweep")
+	defer span.End()
+
+	storage, ok := e.cartStorage.(IterableCartStorage)
+	if !ok {
+		return 0, fmt.Errorf("CartExpiry: cart storage %T does not support iterating carts", e.cartStorage)
+	}
+
+	var expired []*domaincart.Cart
+
+	err := storage.ForEachCart(ctx, func(cart *domaincart.Cart) error {
+		if e.IsExpired(cart) {
+			expired = append(expired, cart)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("CartExpiry: %w", err)
+	}
+
+	removed := 0
+
+	for _, cart := range expired {
+		// the cart may have been modified in the meantime
+		current, err := e.cartStorage.GetCart(ctx, cart.ID)
+		if err != nil || !e.IsExpired(current) {
+			continue
+		}
+
+		if err := e.Expire(ctx, current); err != nil {
+			e.logger.WithContext(ctx).Error(err)
+
+			continue
+		}
+
+		removed++
+	}
+
+	return removed, nil
+}
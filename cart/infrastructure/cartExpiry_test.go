@@ -0,0 +1,97 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo-commerce/v3/cart/application"
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/events"
+)
+
+func TestCartExpiry(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	newExpiry := func(storage CartStorage, router flamingo.EventRouter) *CartExpiry {
+		expiry := new(CartExpiry).Inject(storage, router, flamingo.NullLogger{}, &struct {
+			GuestCartLifetime    string `inject:"config:commerce.cart.defaultCartAdapter.expiry.guestCartLifetime"`
+			CustomerCartLifetime string `inject:"config:commerce.cart.defaultCartAdapter.expiry.customerCartLifetime"`
+			SweepInterval        string `inject:"config:commerce.cart.defaultCartAdapter.expiry.sweepInterval"`
+		}{
+			GuestCartLifetime:    "24h",
+			CustomerCartLifetime: "0s",
+			SweepInterval:        "1h",
+		})
+		expiry.now = func() time.Time { return now }
+
+		return expiry
+	}
+
+	t.Run("lifetime depends on the cart type", func(t *testing.T) {
+		t.Parallel()
+
+		expiry := newExpiry(newInMemoryStorage(), new(recordingEventRouter))
+
+		expiresAt, expires := expiry.ExpiresAt(&domaincart.Cart{UpdatedAt: now})
+		assert.True(t, expires)
+		assert.Equal(t, now.Add(24*time.Hour), expiresAt)
+
+		assert.True(t, expiry.IsExpired(&domaincart.Cart{UpdatedAt: now.Add(-24 * time.Hour)}))
+		assert.False(t, expiry.IsExpired(&domaincart.Cart{UpdatedAt: now.Add(-23 * time.Hour)}))
+		assert.False(t, expiry.IsExpired(&domaincart.Cart{UpdatedAt: now.Add(-48 * time.Hour), BelongsToAuthenticatedUser: true}), "customer carts don't expire with a lifetime of 0")
+		assert.False(t, expiry.IsExpired(&domaincart.Cart{}), "carts without timestamp don't expire")
+	})
+
+	t.Run("sweep removes expired carts and dispatches events", func(t *testing.T) {
+		t.Parallel()
+
+		storage := newInMemoryStorage()
+		require.NoError(t, storage.StoreCart(context.Background(), &domaincart.Cart{ID: "expired", Revision: 1, UpdatedAt: now.Add(-25 * time.Hour)}))
+		require.NoError(t, storage.StoreCart(context.Background(), &domaincart.Cart{ID: "active", Revision: 1, UpdatedAt: now.Add(-time.Hour)}))
+		require.NoError(t, storage.StoreCart(context.Background(), &domaincart.Cart{ID: "customer", Revision: 1, UpdatedAt: now.Add(-48 * time.Hour), BelongsToAuthenticatedUser: true}))
+
+		router := new(recordingEventRouter)
+		removed, err := newExpiry(storage, router).Sweep(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, removed)
+
+		assert.False(t, storage.HasCart(context.Background(), "expired"))
+		assert.True(t, storage.HasCart(context.Background(), "active"))
+		assert.True(t, storage.HasCart(context.Background(), "customer"))
+
+		require.Len(t, router.events, 1)
+		event, ok := router.events[0].(*events.CartExpiredEvent)
+		require.True(t, ok)
+		assert.Equal(t, "expired", event.Cart.ID)
+	})
+
+	t.Run("expired carts are removed when loaded by the default cart behaviour", func(t *testing.T) {
+		t.Parallel()
+
+		storage := newInMemoryStorage()
+		require.NoError(t, storage.StoreCart(context.Background(), &domaincart.Cart{ID: "expired", Revision: 1, UpdatedAt: now.Add(-25 * time.Hour)}))
+
+		router := new(recordingEventRouter)
+		cob := &DefaultCartBehaviour{}
+		cob.Inject(storage, nil, flamingo.NullLogger{}, nil, nil, nil, &struct {
+			ShippingRateProvider domaincart.ShippingRateProvider        `inject:",optional"`
+			CartExpirer          CartExpirer                            `inject:",optional"`
+			LineItemStrategy     domaincart.LineItemStrategy            `inject:",optional"`
+			TaxCalculator        TaxCalculator                          `inject:",optional"`
+			PostProcessors       *application.CartPostProcessorPipeline `inject:",optional"`
+		}{CartExpirer: newExpiry(storage, router)})
+
+		_, err := cob.GetCart(context.Background(), "expired")
+		assert.ErrorIs(t, err, domaincart.ErrCartExpired)
+		assert.ErrorIs(t, err, domaincart.ErrCartNotFound)
+		assert.False(t, storage.HasCart(context.Background(), "expired"))
+		assert.Len(t, router.events, 1)
+	})
+}
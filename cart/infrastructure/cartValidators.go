@@ -0,0 +1,237 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+
+	"flamingo.me/flamingo-commerce/v3/cart/domain/decorator"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/validation"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+type (
+	// OrderValueValidator checks the minimum and maximum order value per currency configured via commerce.cart.validation.orderValue,
+	// the order value is the sub total incl. taxes and discounts but without shipping costs
+	OrderValueValidator struct {
+		limits map[string]OrderValueLimit
+	}
+
+	// OrderValueLimit of a currency, a zero value means no limit
+	OrderValueLimit struct {
+		Min float64 `json:"min"`
+		Max float64 `json:"max"`
+	}
+
+	// MaxDistinctItemsValidator limits the number of distinct products in the cart via commerce.cart.validation.maxDistinctItems
+	MaxDistinctItemsValidator struct {
+		maxDistinctItems int
+	}
+
+	// ProductCombinationValidator rejects products which must not be shipped together in one delivery,
+	// configured as lists of marketplace codes via commerce.cart.validation.disallowedCombinations
+	ProductCombinationValidator struct {
+		combinations [][]string
+	}
+
+	// BillingAddressValidator requires a billing address if one of the payment methods configured via
+	// commerce.cart.validation.billingAddressRequiredForPaymentMethods is selected
+	BillingAddressValidator struct {
+		paymentMethods []string
+	}
+)
+
+const (
+	// ValidationKeyOrderValueBelowMinimum is the message key if the order value is below the minimum
+	ValidationKeyOrderValueBelowMinimum = "cart.validation.orderValue.belowMinimum"
+	// ValidationKeyOrderValueAboveMaximum is the message key if the order value exceeds the maximum
+	ValidationKeyOrderValueAboveMaximum = "cart.validation.orderValue.aboveMaximum"
+	// ValidationKeyMaxDistinctItemsExceeded is the message key of the cart and of the items exceeding the distinct items limit
+	ValidationKeyMaxDistinctItemsExceeded = "cart.validation.maxDistinctItems.exceeded"
+	// ValidationKeyProductCombinationNotAllowed is the message key of the cart and of the items which must not be shipped together
+	ValidationKeyProductCombinationNotAllowed = "cart.validation.productCombination.notAllowed"
+	// ValidationKeyBillingAddressRequired is the message key if the selected payment method requires a billing address
+	ValidationKeyBillingAddressRequired = "cart.validation.billingAddress.required"
+)
+
+var (
+	_ validation.Validator = (*OrderValueValidator)(nil)
+	_ validation.Validator = (*MaxDistinctItemsValidator)(nil)
+	_ validation.Validator = (*ProductCombinationValidator)(nil)
+	_ validation.Validator = (*BillingAddressValidator)(nil)
+)
+
+// Inject dependencies
+func (v *OrderValueValidator) Inject(
+	logger flamingo.Logger,
+	cfg *struct {
+		Limits config.Map `inject:"config:commerce.cart.validation.orderValue,optional"`
+	},
+) *OrderValueValidator {
+	if cfg == nil {
+		return v
+	}
+
+	if err := cfg.Limits.MapInto(&v.limits); err != nil {
+		logger.WithField(flamingo.LogKeyCategory, "OrderValueValidator").Error(fmt.Errorf("failed to map order value limits: %w", err))
+	}
+
+	return v
+}
+
+// Validate checks the order value of non empty carts against the limits of the cart currency
+func (v *OrderValueValidator) Validate(_ context.Context, _ *web.Session, cart *decorator.DecoratedCart) validation.Result {
+	orderValue := cart.Cart.SubTotalGrossWithDiscounts
+
+	limit, found := v.limits[orderValue.Currency()]
+	if !found || cart.Cart.IsEmpty() {
+		return validation.Result{}
+	}
+
+	if limit.Min > 0 && orderValue.IsLessThen(priceDomain.NewFromFloat(limit.Min, orderValue.Currency())) {
+		return validation.Result{HasCommonError: true, CommonErrorMessageKey: ValidationKeyOrderValueBelowMinimum}
+	}
+
+	if limit.Max > 0 && orderValue.IsGreaterThen(priceDomain.NewFromFloat(limit.Max, orderValue.Currency())) {
+		return validation.Result{HasCommonError: true, CommonErrorMessageKey: ValidationKeyOrderValueAboveMaximum}
+	}
+
+	return validation.Result{}
+}
+
+// Inject dependencies
+func (v *MaxDistinctItemsValidator) Inject(
+	cfg *struct {
+		MaxDistinctItems float64 `inject:"config:commerce.cart.validation.maxDistinctItems,optional"`
+	},
+) *MaxDistinctItemsValidator {
+	if cfg != nil {
+		v.maxDistinctItems = int(cfg.MaxDistinctItems)
+	}
+
+	return v
+}
+
+// Validate marks the items of all products exceeding the limit, the products are counted in the order of the cart
+func (v *MaxDistinctItemsValidator) Validate(_ context.Context, _ *web.Session, cart *decorator.DecoratedCart) validation.Result {
+	result := validation.Result{}
+
+	if v.maxDistinctItems <= 0 || cart.Cart.ProductCountUnique() <= v.maxDistinctItems {
+		return result
+	}
+
+	allowed := make(map[string]bool)
+
+	for _, delivery := range cart.Cart.Deliveries {
+		for _, item := range delivery.Cartitems {
+			if _, counted := allowed[item.MarketplaceCode]; !counted {
+				allowed[item.MarketplaceCode] = len(allowed) < v.maxDistinctItems
+			}
+
+			if !allowed[item.MarketplaceCode] {
+				result.ItemResults = append(result.ItemResults, validation.ItemValidationError{ItemID: item.ID, ErrorMessageKey: ValidationKeyMaxDistinctItemsExceeded})
+			}
+		}
+	}
+
+	result.HasCommonError = true
+	result.CommonErrorMessageKey = ValidationKeyMaxDistinctItemsExceeded
+
+	return result
+}
+
+// Inject dependencies
+func (v *ProductCombinationValidator) Inject(
+	logger flamingo.Logger,
+	cfg *struct {
+		Combinations config.Slice `inject:"config:commerce.cart.validation.disallowedCombinations,optional"`
+	},
+) *ProductCombinationValidator {
+	if cfg == nil {
+		return v
+	}
+
+	if err := cfg.Combinations.MapInto(&v.combinations); err != nil {
+		logger.WithField(flamingo.LogKeyCategory, "ProductCombinationValidator").Error(fmt.Errorf("failed to map disallowed product combinations: %w", err))
+	}
+
+	return v
+}
+
+// Validate marks all items of a delivery which belong to a disallowed combination with at least two products present,
+// an item matches by its marketplace code or variant marketplace code
+func (v *ProductCombinationValidator) Validate(_ context.Context, _ *web.Session, cart *decorator.DecoratedCart) validation.Result {
+	result := validation.Result{}
+
+	for _, delivery := range cart.Cart.Deliveries {
+		for _, combination := range v.combinations {
+			var itemIDs []string
+
+			products := make(map[string]struct{})
+
+			for _, item := range delivery.Cartitems {
+				for _, code := range combination {
+					if code == item.MarketplaceCode || (item.VariantMarketPlaceCode != "" && code == item.VariantMarketPlaceCode) {
+						products[code] = struct{}{}
+						itemIDs = append(itemIDs, item.ID)
+
+						break
+					}
+				}
+			}
+
+			if len(products) < 2 {
+				continue
+			}
+
+			for _, itemID := range itemIDs {
+				if !result.HasErrorForItem(itemID) {
+					result.ItemResults = append(result.ItemResults, validation.ItemValidationError{ItemID: itemID, ErrorMessageKey: ValidationKeyProductCombinationNotAllowed})
+				}
+			}
+
+			result.HasCommonError = true
+			result.CommonErrorMessageKey = ValidationKeyProductCombinationNotAllowed
+		}
+	}
+
+	return result
+}
+
+// Inject dependencies
+func (v *BillingAddressValidator) Inject(
+	cfg *struct {
+		PaymentMethods []string `inject:"config:commerce.cart.validation.billingAddressRequiredForPaymentMethods,optional"`
+	},
+) *BillingAddressValidator {
+	if cfg != nil {
+		v.paymentMethods = cfg.PaymentMethods
+	}
+
+	return v
+}
+
+// Validate checks the billing address if a payment method requiring it is selected
+func (v *BillingAddressValidator) Validate(_ context.Context, _ *web.Session, cart *decorator.DecoratedCart) validation.Result {
+	if len(v.paymentMethods) == 0 || cart.Cart.PaymentSelection == nil {
+		return validation.Result{}
+	}
+
+	if cart.Cart.BillingAddress != nil && !cart.Cart.BillingAddress.IsEmpty() {
+		return validation.Result{}
+	}
+
+	for qualifier := range cart.Cart.PaymentSelection.CartSplit() {
+		for _, method := range v.paymentMethods {
+			if strings.EqualFold(method, qualifier.Method) {
+				return validation.Result{HasCommonError: true, CommonErrorMessageKey: ValidationKeyBillingAddressRequired}
+			}
+		}
+	}
+
+	return validation.Result{}
+}
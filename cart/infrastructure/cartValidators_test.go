@@ -0,0 +1,156 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/decorator"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/validation"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+func validatorTestCart(items ...domaincart.Item) *decorator.DecoratedCart {
+	cart := promotionTestCart(items...)
+
+	subTotal := priceDomain.NewZero("EUR")
+	for _, item := range items {
+		subTotal, _ = subTotal.Add(item.RowPriceGross)
+	}
+
+	cart.SubTotalGrossWithDiscounts = subTotal
+
+	return &decorator.DecoratedCart{Cart: *cart}
+}
+
+func TestOrderValueValidator_Validate(t *testing.T) {
+	t.Parallel()
+
+	validator := new(OrderValueValidator).Inject(flamingo.NullLogger{}, &struct {
+		Limits config.Map `inject:"config:commerce.cart.validation.orderValue,optional"`
+	}{
+		Limits: config.Map{"EUR": config.Map{"min": 20.0, "max": 100.0}},
+	})
+
+	tests := []struct {
+		name string
+		cart *decorator.DecoratedCart
+		want string
+	}{
+		{name: "empty cart", cart: validatorTestCart(), want: ""},
+		{name: "below minimum", cart: validatorTestCart(promotionTestItem("1", "a", 1, 19.99)), want: ValidationKeyOrderValueBelowMinimum},
+		{name: "minimum", cart: validatorTestCart(promotionTestItem("1", "a", 2, 10)), want: ""},
+		{name: "above maximum", cart: validatorTestCart(promotionTestItem("1", "a", 1, 100.01)), want: ValidationKeyOrderValueAboveMaximum},
+	}
+
+	for _, tt := range tests {
+		result := validator.Validate(context.Background(), nil, tt.cart)
+		assert.Equal(t, tt.want != "", result.HasCommonError, tt.name)
+		assert.Equal(t, tt.want, result.CommonErrorMessageKey, tt.name)
+	}
+
+	usdCart := validatorTestCart(promotionTestItem("1", "a", 1, 1))
+	usdCart.Cart.SubTotalGrossWithDiscounts = priceDomain.NewFromFloat(1, "USD")
+	assert.True(t, validator.Validate(context.Background(), nil, usdCart).IsValid(), "currencies without limits are not validated")
+}
+
+func TestMaxDistinctItemsValidator_Validate(t *testing.T) {
+	t.Parallel()
+
+	validator := new(MaxDistinctItemsValidator).Inject(&struct {
+		MaxDistinctItems float64 `inject:"config:commerce.cart.validation.maxDistinctItems,optional"`
+	}{MaxDistinctItems: 2})
+
+	result := validator.Validate(context.Background(), nil, validatorTestCart(
+		promotionTestItem("1", "a", 5, 10),
+		promotionTestItem("2", "b", 1, 10),
+		promotionTestItem("3", "a", 1, 10),
+	))
+	assert.True(t, result.IsValid())
+
+	result = validator.Validate(context.Background(), nil, validatorTestCart(
+		promotionTestItem("1", "a", 1, 10),
+		promotionTestItem("2", "b", 1, 10),
+		promotionTestItem("3", "c", 1, 10),
+		promotionTestItem("4", "a", 1, 10),
+	))
+	assert.Equal(t, ValidationKeyMaxDistinctItemsExceeded, result.CommonErrorMessageKey)
+	assert.Equal(t, []validation.ItemValidationError{{ItemID: "3", ErrorMessageKey: ValidationKeyMaxDistinctItemsExceeded}}, result.ItemResults)
+
+	unlimited := new(MaxDistinctItemsValidator).Inject(nil)
+	assert.True(t, unlimited.Validate(context.Background(), nil, validatorTestCart(promotionTestItem("1", "a", 1, 10))).IsValid())
+}
+
+func TestProductCombinationValidator_Validate(t *testing.T) {
+	t.Parallel()
+
+	validator := new(ProductCombinationValidator).Inject(flamingo.NullLogger{}, &struct {
+		Combinations config.Slice `inject:"config:commerce.cart.validation.disallowedCombinations,optional"`
+	}{
+		Combinations: config.Slice{config.Slice{"fireworks", "lighter", "matches"}},
+	})
+
+	result := validator.Validate(context.Background(), nil, validatorTestCart(
+		promotionTestItem("1", "fireworks", 1, 10),
+		promotionTestItem("2", "fireworks", 1, 10),
+		promotionTestItem("3", "shirt", 1, 10),
+	))
+	assert.True(t, result.IsValid(), "the same product of a combination is allowed")
+
+	variant := promotionTestItem("3", "matches-configurable", 1, 10)
+	variant.VariantMarketPlaceCode = "matches"
+
+	result = validator.Validate(context.Background(), nil, validatorTestCart(
+		promotionTestItem("1", "fireworks", 1, 10),
+		promotionTestItem("2", "shirt", 1, 10),
+		variant,
+	))
+	require.False(t, result.IsValid())
+	assert.Equal(t, ValidationKeyProductCombinationNotAllowed, result.CommonErrorMessageKey)
+	assert.True(t, result.HasErrorForItem("1"))
+	assert.False(t, result.HasErrorForItem("2"))
+	assert.True(t, result.HasErrorForItem("3"))
+
+	cart := validatorTestCart(promotionTestItem("1", "fireworks", 1, 10))
+	cart.Cart.Deliveries = append(cart.Cart.Deliveries, domaincart.Delivery{
+		DeliveryInfo: domaincart.DeliveryInfo{Code: "other"},
+		Cartitems:    []domaincart.Item{promotionTestItem("2", "lighter", 1, 10)},
+	})
+	assert.True(t, validator.Validate(context.Background(), nil, cart).IsValid(), "combinations are only checked per delivery")
+}
+
+func TestBillingAddressValidator_Validate(t *testing.T) {
+	t.Parallel()
+
+	validator := new(BillingAddressValidator).Inject(&struct {
+		PaymentMethods []string `inject:"config:commerce.cart.validation.billingAddressRequiredForPaymentMethods,optional"`
+	}{PaymentMethods: []string{"invoice"}})
+
+	withPayment := func(method string) *decorator.DecoratedCart {
+		item := promotionTestItem("1", "a", 1, 10)
+		item.RowPriceGrossWithDiscount = item.RowPriceGross
+
+		cart := validatorTestCart(item)
+		selection, err := domaincart.NewDefaultPaymentSelection("gateway", map[string]string{priceDomain.ChargeTypeMain: method}, cart.Cart)
+		require.NoError(t, err)
+
+		cart.Cart.PaymentSelection = selection
+
+		return cart
+	}
+
+	assert.True(t, validator.Validate(context.Background(), nil, validatorTestCart()).IsValid(), "no payment selected")
+	assert.True(t, validator.Validate(context.Background(), nil, withPayment("creditcard")).IsValid())
+
+	cart := withPayment("invoice")
+	result := validator.Validate(context.Background(), nil, cart)
+	assert.Equal(t, ValidationKeyBillingAddressRequired, result.CommonErrorMessageKey)
+
+	cart.Cart.BillingAddress = &domaincart.Address{Firstname: "Jane", Lastname: "Doe", Street: "Main Street"}
+	assert.True(t, validator.Validate(context.Background(), nil, cart).IsValid())
+}
@@ -0,0 +1,296 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"flamingo.me/flamingo/v3/core/healthcheck/domain/healthcheck"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/gomodule/redigo/redis"
+	"go.opencensus.io/trace"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+)
+
+type (
+	// InMemoryChangeNotifier distributes cart change notifications to the subscribers of the same instance,
+	// for development and single instance setups. Subscribers which don't keep up miss notifications
+	// instead of blocking the cart modification.
+	InMemoryChangeNotifier struct {
+		mutex       sync.RWMutex
+		subscribers map[int]*changeSubscriber
+		nextID      int
+		bufferSize  int
+	}
+
+	// RedisChangeNotifier publishes cart change notifications to a redis channel, so that the subscribers of all instances receive them
+	RedisChangeNotifier struct {
+		pool    *redis.Pool
+		logger  flamingo.Logger
+		channel string
+		local   *InMemoryChangeNotifier
+		mutex   sync.Mutex
+		stop    chan struct{}
+	}
+
+	changeSubscriber struct {
+		subscription  domaincart.ChangeSubscription
+		notifications chan domaincart.ChangeNotification
+	}
+)
+
+var (
+	_ domaincart.ChangeNotifier = &InMemoryChangeNotifier{}
+	_ domaincart.ChangeNotifier = &RedisChangeNotifier{}
+	_ healthcheck.Status        = &RedisChangeNotifier{}
+	_ flamingo.Subscriber       = &RedisChangeNotifier{}
+)
+
+const defaultChangeNotifierBufferSize = 10
+
+// Inject dependencies
+func (n *InMemoryChangeNotifier) Inject(
+	cfg *struct {
+		BufferSize float64 `inject:"config:commerce.cart.notifications.bufferSize,optional"`
+	},
+) *InMemoryChangeNotifier {
+	n.subscribers = make(map[int]*changeSubscriber)
+	n.bufferSize = defaultChangeNotifierBufferSize
+
+	if cfg != nil && cfg.BufferSize > 0 {
+		n.bufferSize = int(cfg.BufferSize)
+	}
+
+	return n
+}
+
+// Publish sends the notification to all matching subscribers, full subscriber buffers are skipped
+func (n *InMemoryChangeNotifier) Publish(ctx context.Context, notification domaincart.ChangeNotification) error {
+	_, span := trace.StartSpan(ctx, "cart/InMemoryChangeNotifier/Publish")
+	defer span.End()
+
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+
+	for _, subscriber := range n.subscribers {
+		if !subscriber.subscription.Matches(notification) {
+			continue
+		}
+
+		select {
+		case subscriber.notifications <- notification:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Subscribe registers a subscriber until ctx is done
+func (n *InMemoryChangeNotifier) Subscribe(ctx context.Context, subscription domaincart.ChangeSubscription) (<-chan domaincart.ChangeNotification, error) {
+	if subscription.CartID == "" && subscription.CustomerID == "" {
+		return nil, fmt.Errorf("InMemoryChangeNotifier: subscription without cart and customer")
+	}
+
+	subscriber := &changeSubscriber{
+		subscription:  subscription,
+		notifications: make(chan domaincart.ChangeNotification, n.bufferSize),
+	}
+
+	n.mutex.Lock()
+	id := n.nextID
+	n.nextID++
+	n.subscribers[id] = subscriber
+	n.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		n.mutex.Lock()
+		defer n.mutex.Unlock()
+
+		delete(n.subscribers, id)
+		close(subscriber.notifications)
+	}()
+
+	return subscriber.notifications, nil
+}
+
+// Inject dependencies
+func (r *RedisChangeNotifier) Inject(
+	logger flamingo.Logger,
+	cfg *struct {
+		BufferSize              float64 `inject:"config:commerce.cart.notifications.bufferSize,optional"`
+		MaxIdle                 int     `inject:"config:commerce.cart.notifications.redis.maxIdle"`
+		IdleTimeoutMilliseconds int     `inject:"config:commerce.cart.notifications.redis.idleTimeoutMilliseconds"`
+		Network                 string  `inject:"config:commerce.cart.notifications.redis.network"`
+		Address                 string  `inject:"config:commerce.cart.notifications.redis.address"`
+		Database                int     `inject:"config:commerce.cart.notifications.redis.database"`
+		Username                string  `inject:"config:commerce.cart.notifications.redis.username,optional"`
+		Password                string  `inject:"config:commerce.cart.notifications.redis.password,optional"`
+		UseTLS                  bool    `inject:"config:commerce.cart.notifications.redis.useTLS,optional"`
+		Channel                 string  `inject:"config:commerce.cart.notifications.redis.channel"`
+	},
+) *RedisChangeNotifier {
+	r.logger = logger.WithField(flamingo.LogKeyModule, "cart").WithField(flamingo.LogKeyCategory, "RedisChangeNotifier")
+
+	if cfg == nil {
+		return r
+	}
+
+	r.channel = cfg.Channel
+	r.local = new(InMemoryChangeNotifier).Inject(&struct {
+		BufferSize float64 `inject:"config:commerce.cart.notifications.bufferSize,optional"`
+	}{BufferSize: cfg.BufferSize})
+
+	r.pool = newRedisPool(redisPoolConfig{
+		MaxIdle:                 cfg.MaxIdle,
+		IdleTimeoutMilliseconds: cfg.IdleTimeoutMilliseconds,
+		Network:                 cfg.Network,
+		Address:                 cfg.Address,
+		Database:                cfg.Database,
+		Username:                cfg.Username,
+		Password:                cfg.Password,
+		UseTLS:                  cfg.UseTLS,
+	})
+	runtime.SetFinalizer(r, func(r *RedisChangeNotifier) { r.pool.Close() }) // close all connections on destruction
+
+	return r
+}
+
+// Publish sends the json encoded notification to the configured redis channel
+func (r *RedisChangeNotifier) Publish(ctx context.Context, notification domaincart.ChangeNotification) error {
+	_, span := trace.StartSpan(ctx, "cart/RedisChangeNotifier/Publish")
+	defer span.End()
+
+	conn := r.pool.Get()
+	defer conn.Close()
+	if conn.Err() != nil {
+		r.logger.WithContext(ctx).Error("Publish:", conn.Err())
+		return ErrNoRedisConnection
+	}
+
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("RedisChangeNotifier: error encoding change notification of cart %q: %w", notification.CartID, err)
+	}
+
+	_, err = conn.Do("PUBLISH", r.channel, payload)
+	if err != nil {
+		return fmt.Errorf("RedisChangeNotifier: error publishing change notification of cart %q: %w", notification.CartID, err)
+	}
+
+	return nil
+}
+
+// Subscribe registers a subscriber until ctx is done, the redis channel is listened to from the first subscription on
+func (r *RedisChangeNotifier) Subscribe(ctx context.Context, subscription domaincart.ChangeSubscription) (<-chan domaincart.ChangeNotification, error) {
+	r.listen()
+
+	return r.local.Subscribe(ctx, subscription)
+}
+
+// Notify stops listening to the redis channel on shutdown
+func (r *RedisChangeNotifier) Notify(_ context.Context, event flamingo.Event) {
+	if _, ok := event.(*flamingo.ServerShutdownEvent); ok {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+
+		if r.stop != nil {
+			close(r.stop)
+			r.stop = nil
+		}
+	}
+}
+
+// Status handles the health check of redis
+func (r *RedisChangeNotifier) Status() (alive bool, details string) {
+	conn := r.pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("PING")
+	if err != nil {
+		return false, err.Error()
+	}
+
+	return true, "redis for cart notifications replies to PING"
+}
+
+func (r *RedisChangeNotifier) listen() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.stop != nil {
+		return
+	}
+
+	r.stop = make(chan struct{})
+
+	go func(stop <-chan struct{}) {
+		for {
+			err := r.receive(stop)
+			if err == nil {
+				return
+			}
+
+			r.logger.Error("listening to redis channel failed, retrying: ", err)
+
+			select {
+			case <-stop:
+				return
+			case <-time.After(time.Second):
+			}
+		}
+	}(r.stop)
+}
+
+// receive forwards the notifications of the redis channel to the local subscribers until stop is closed or the connection fails
+func (r *RedisChangeNotifier) receive(stop <-chan struct{}) error {
+	conn := r.pool.Get()
+	if conn.Err() != nil {
+		conn.Close()
+		return conn.Err()
+	}
+
+	pubSub := redis.PubSubConn{Conn: conn}
+	defer pubSub.Close()
+
+	err := pubSub.Subscribe(r.channel)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-stop:
+			_ = pubSub.Unsubscribe()
+		case <-done:
+		}
+	}()
+
+	for {
+		switch message := pubSub.Receive().(type) {
+		case redis.Message:
+			var notification domaincart.ChangeNotification
+			if err := json.Unmarshal(message.Data, &notification); err != nil {
+				r.logger.Error("change notification is not decodable: ", err)
+				continue
+			}
+
+			_ = r.local.Publish(context.Background(), notification)
+		case redis.Subscription:
+			if message.Count == 0 {
+				return nil
+			}
+		case error:
+			return message
+		}
+	}
+}
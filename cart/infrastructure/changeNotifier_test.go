@@ -0,0 +1,95 @@
+package infrastructure_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/infrastructure"
+)
+
+func TestInMemoryChangeNotifier(t *testing.T) {
+	t.Parallel()
+
+	notifier := new(infrastructure.InMemoryChangeNotifier).Inject(&struct {
+		BufferSize float64 `inject:"config:commerce.cart.notifications.bufferSize,optional"`
+	}{BufferSize: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cartNotifications, err := notifier.Subscribe(ctx, domaincart.ChangeSubscription{CartID: "cart-1"})
+	require.NoError(t, err)
+
+	customerNotifications, err := notifier.Subscribe(ctx, domaincart.ChangeSubscription{CustomerID: "customer-1"})
+	require.NoError(t, err)
+
+	_, err = notifier.Subscribe(ctx, domaincart.ChangeSubscription{})
+	assert.Error(t, err, "subscriptions need a cart or customer")
+
+	require.NoError(t, notifier.Publish(ctx, domaincart.ChangeNotification{CartID: "cart-1", Revision: 1}))
+	require.NoError(t, notifier.Publish(ctx, domaincart.ChangeNotification{CartID: "cart-2", CustomerID: "customer-1", Revision: 1}))
+	require.NoError(t, notifier.Publish(ctx, domaincart.ChangeNotification{CartID: "cart-1", Revision: 2}), "full buffers don't block")
+
+	require.Len(t, cartNotifications, 1)
+	assert.Equal(t, 1, (<-cartNotifications).Revision)
+
+	require.Len(t, customerNotifications, 1)
+	assert.Equal(t, "cart-2", (<-customerNotifications).CartID)
+
+	cancel()
+
+	_, open := <-cartNotifications
+	assert.False(t, open, "the channel is closed once the subscription context is done")
+}
+
+func TestRedisChangeNotifier(t *testing.T) {
+	server, _ := startUpLocalCartRedis(t)
+
+	newNotifier := func() *infrastructure.RedisChangeNotifier {
+		return new(infrastructure.RedisChangeNotifier).Inject(new(flamingo.NullLogger), &struct {
+			BufferSize              float64 `inject:"config:commerce.cart.notifications.bufferSize,optional"`
+			MaxIdle                 int     `inject:"config:commerce.cart.notifications.redis.maxIdle"`
+			IdleTimeoutMilliseconds int     `inject:"config:commerce.cart.notifications.redis.idleTimeoutMilliseconds"`
+			Network                 string  `inject:"config:commerce.cart.notifications.redis.network"`
+			Address                 string  `inject:"config:commerce.cart.notifications.redis.address"`
+			Database                int     `inject:"config:commerce.cart.notifications.redis.database"`
+			Username                string  `inject:"config:commerce.cart.notifications.redis.username,optional"`
+			Password                string  `inject:"config:commerce.cart.notifications.redis.password,optional"`
+			UseTLS                  bool    `inject:"config:commerce.cart.notifications.redis.useTLS,optional"`
+			Channel                 string  `inject:"config:commerce.cart.notifications.redis.channel"`
+		}{MaxIdle: 3, IdleTimeoutMilliseconds: 240000, Network: "unix", Address: server.Socket(), Channel: "cartChanges"})
+	}
+
+	// subscriber and publisher simulate two instances
+	subscriber := newNotifier()
+	publisher := newNotifier()
+	defer subscriber.Notify(context.Background(), &flamingo.ServerShutdownEvent{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	notifications, err := subscriber.Subscribe(ctx, domaincart.ChangeSubscription{CartID: "cart-1"})
+	require.NoError(t, err)
+
+	teaser := domaincart.Teaser{ProductCount: 1, ItemCount: 2, DeliveryCodes: []string{"delivery"}}
+
+	// the redis subscription is established asynchronously, publish until the notification arrives
+	assert.Eventually(t, func() bool {
+		require.NoError(t, publisher.Publish(ctx, domaincart.ChangeNotification{CartID: "cart-1", Revision: 3, Teaser: teaser}))
+
+		select {
+		case notification := <-notifications:
+			return notification.Revision == 3 && assert.Equal(t, teaser, notification.Teaser)
+		case <-time.After(50 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+
+	alive, _ := subscriber.Status()
+	assert.True(t, alive)
+}
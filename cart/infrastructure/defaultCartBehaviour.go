@@ -7,14 +7,17 @@ import (
 	"math/big"
 	"math/rand"
 	"strconv"
+	"time"
 
 	"go.opencensus.io/trace"
 
 	"flamingo.me/flamingo/v3/framework/flamingo"
 
+	"flamingo.me/flamingo-commerce/v3/cart/application"
 	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
 	"flamingo.me/flamingo-commerce/v3/cart/domain/decorator"
 	"flamingo.me/flamingo-commerce/v3/cart/domain/events"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/quote"
 	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
 	"flamingo.me/flamingo-commerce/v3/product/domain"
 )
@@ -25,11 +28,19 @@ import (
 type (
 	// DefaultCartBehaviour defines the default cart order behaviour
 	DefaultCartBehaviour struct {
-		cartStorage     CartStorage
-		productService  domain.ProductService
-		logger          flamingo.Logger
-		giftCardHandler GiftCardHandler
-		voucherHandler  VoucherHandler
+		cartStorage      CartStorage
+		productService   domain.ProductService
+		logger           flamingo.Logger
+		giftCardHandler  GiftCardHandler
+		voucherHandler   VoucherHandler
+		lineItemStrategy domaincart.LineItemStrategy
+		taxCalculator    TaxCalculator
+		// shippingRateProvider is optional, without it the shipping items are left untouched
+		shippingRateProvider domaincart.ShippingRateProvider
+		// cartExpirer is optional, without it carts never expire
+		cartExpirer CartExpirer
+		// postProcessors are optional, they adjust every modification before it is stored
+		postProcessors  *application.CartPostProcessorPipeline
 		defaultTaxRate  float64
 		grossPricing    bool
 		defaultCurrency string
@@ -39,10 +50,24 @@ type (
 	CartStorage interface {
 		GetCart(ctx context.Context, id string) (*domaincart.Cart, error)
 		HasCart(ctx context.Context, id string) bool
+		// StoreCart stores the cart, if a cart with the same id is already stored its revision must be exactly
+		// one lower than the revision of the given cart, otherwise a *domaincart.RevisionConflictError is returned
 		StoreCart(ctx context.Context, cart *domaincart.Cart) error
 		RemoveCart(ctx context.Context, cart *domaincart.Cart) error
 	}
 
+	// IterableCartStorage can optionally be implemented by a CartStorage to allow background jobs to scan all stored carts
+	IterableCartStorage interface {
+		// ForEachCart calls fn for every stored cart, iteration stops on the first error returned by fn
+		ForEachCart(ctx context.Context, fn func(cart *domaincart.Cart) error) error
+	}
+
+	// CustomerCartIndex can optionally be implemented by a CartStorage to look up the carts of a customer without reading every stored cart
+	CustomerCartIndex interface {
+		// CartIDsOfCustomer returns the ids of the stored carts with the given AuthenticatedUserID, ids of expired carts may be included
+		CartIDsOfCustomer(ctx context.Context, customerID string) ([]string, error)
+	}
+
 	// GiftCardHandler enables the projects to have specific GiftCard handling
 	GiftCardHandler interface {
 		ApplyGiftCard(ctx context.Context, cart *domaincart.Cart, giftCardCode string) (*domaincart.Cart, error)
@@ -55,17 +80,51 @@ type (
 		RemoveVoucher(ctx context.Context, cart *domaincart.Cart, couponCode string) (*domaincart.Cart, error)
 	}
 
+	// DiscountCalculator can optionally be implemented by a VoucherHandler to recalculate the discounts of the cart
+	// every time the default cart adapter collects the cart totals, e.g. after the qty of an item changed
+	DiscountCalculator interface {
+		CalculateDiscounts(cart *domaincart.Cart) error
+	}
+
+	// TaxCalculator calculates the prices and taxes of a delivery for the default cart adapter
+	TaxCalculator interface {
+		// CalculateDeliveryTaxes calculates the single and row prices and the row taxes of all items and the prices and
+		// the tax amount of the shipping item for the address the delivery is shipped to, the address might be nil
+		CalculateDeliveryTaxes(delivery *domaincart.Delivery, address *domaincart.Address) error
+	}
+
+	// GiftCardCalculator can optionally be implemented by a GiftCardHandler to recalculate the applied amounts of the
+	// gift cards every time the default cart adapter collects the cart totals
+	GiftCardCalculator interface {
+		CalculateGiftCards(cart *domaincart.Cart) error
+	}
+
+	// GiftCardReserver can optionally be implemented by a GiftCardHandler to reserve the applied gift card amounts when
+	// the cart is completed and to release them when the cart is restored, e.g. after the order has been cancelled
+	GiftCardReserver interface {
+		ReserveGiftCards(ctx context.Context, cart *domaincart.Cart) error
+		ReleaseGiftCards(ctx context.Context, cart *domaincart.Cart) error
+	}
+
 	// DefaultGiftCardHandler implements a basic gift card handler
 	DefaultGiftCardHandler struct{}
 
 	// DefaultVoucherHandler implements a basic voucher handler
 	DefaultVoucherHandler struct{}
+
+	// stagedCartStorage keeps the carts stored by the post processors in memory, the result is stored with the modification
+	stagedCartStorage struct {
+		CartStorage
+		carts map[string]*domaincart.Cart
+	}
 )
 
 var (
 	_ domaincart.ModifyBehaviour             = (*DefaultCartBehaviour)(nil)
 	_ domaincart.GiftCardAndVoucherBehaviour = (*DefaultCartBehaviour)(nil)
 	_ domaincart.CompleteBehaviour           = (*DefaultCartBehaviour)(nil)
+	_ domaincart.ReleaseBehaviour            = (*DefaultCartBehaviour)(nil)
+	_ domaincart.BulkAddBehaviour            = (*DefaultCartBehaviour)(nil)
 	_ GiftCardHandler                        = (*DefaultGiftCardHandler)(nil)
 	_ VoucherHandler                         = (*DefaultVoucherHandler)(nil)
 )
@@ -86,6 +145,13 @@ func (cob *DefaultCartBehaviour) Inject(
 		ProductPricing  string  `inject:"config:commerce.cart.defaultCartAdapter.productPrices"`
 		DefaultCurrency string  `inject:"config:commerce.cart.defaultCartAdapter.defaultCurrency"`
 	},
+	optionals *struct {
+		ShippingRateProvider domaincart.ShippingRateProvider        `inject:",optional"`
+		CartExpirer          CartExpirer                            `inject:",optional"`
+		LineItemStrategy     domaincart.LineItemStrategy            `inject:",optional"`
+		TaxCalculator        TaxCalculator                          `inject:",optional"`
+		PostProcessors       *application.CartPostProcessorPipeline `inject:",optional"`
+	},
 ) {
 	cob.cartStorage = cartStorage
 	cob.productService = productService
@@ -93,6 +159,18 @@ func (cob *DefaultCartBehaviour) Inject(
 	cob.voucherHandler = voucherHandler
 	cob.giftCardHandler = giftCardHandler
 
+	if optionals != nil {
+		cob.shippingRateProvider = optionals.ShippingRateProvider
+		cob.cartExpirer = optionals.CartExpirer
+		cob.lineItemStrategy = optionals.LineItemStrategy
+		cob.taxCalculator = optionals.TaxCalculator
+		cob.postProcessors = optionals.PostProcessors
+	}
+
+	if cob.lineItemStrategy == nil {
+		cob.lineItemStrategy = new(domaincart.DefaultLineItemStrategy)
+	}
+
 	if config != nil {
 		cob.defaultTaxRate = config.DefaultTaxRate
 		cob.defaultCurrency = config.DefaultCurrency
@@ -101,6 +179,14 @@ func (cob *DefaultCartBehaviour) Inject(
 			cob.grossPricing = true
 		}
 	}
+
+	if cob.taxCalculator == nil {
+		cob.taxCalculator = &DefaultTaxCalculator{
+			defaultTaxRate: cob.defaultTaxRate,
+			grossPricing:   cob.grossPricing,
+			rounding:       TaxRoundingRow,
+		}
+	}
 }
 
 // Complete a cart and remove from storage
@@ -108,8 +194,20 @@ func (cob *DefaultCartBehaviour) Complete(ctx context.Context, cart *domaincart.
 	ctx, span := trace.StartSpan(ctx, "cart/DefaultCartBehaviour/Complete")
 	defer span.End()
 
+	reserver, reserveGiftCards := cob.giftCardHandler.(GiftCardReserver)
+	if reserveGiftCards {
+		err := reserver.ReserveGiftCards(ctx, cart)
+		if err != nil {
+			return nil, nil, fmt.Errorf("DefaultCartBehaviour: error reserving gift cards: %w", err)
+		}
+	}
+
 	err := cob.cartStorage.RemoveCart(ctx, cart)
 	if err != nil {
+		if reserveGiftCards {
+			_ = reserver.ReleaseGiftCards(ctx, cart)
+		}
+
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error removing cart: %w", err)
 	}
 
@@ -131,12 +229,37 @@ func (cob *DefaultCartBehaviour) Restore(ctx context.Context, cart *domaincart.C
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error collecting totals: %w", err)
 	}
 
-	err = cob.cartStorage.StoreCart(ctx, &newCart)
+	if reserver, ok := cob.giftCardHandler.(GiftCardReserver); ok {
+		err = reserver.ReleaseGiftCards(ctx, &newCart)
+		if err != nil {
+			return nil, nil, fmt.Errorf("DefaultCartBehaviour: error releasing gift cards: %w", err)
+		}
+	}
+
+	defers, err := cob.storeModifiedCart(ctx, &newCart)
 	if err != nil {
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error saving cart: %w", err)
 	}
 
-	return &newCart, nil, nil
+	return &newCart, defers, nil
+}
+
+// Release releases the gift card amounts reserved by Complete without restoring the cart (implements ReleaseBehaviour)
+func (cob *DefaultCartBehaviour) Release(ctx context.Context, cart *domaincart.Cart) error {
+	ctx, span := trace.StartSpan(ctx, "cart/DefaultCartBehaviour/Release")
+	defer span.End()
+
+	reserver, ok := cob.giftCardHandler.(GiftCardReserver)
+	if !ok {
+		return nil
+	}
+
+	err := reserver.ReleaseGiftCards(ctx, cart)
+	if err != nil {
+		return fmt.Errorf("DefaultCartBehaviour: error releasing gift cards: %w", err)
+	}
+
+	return nil
 }
 
 // DeleteItem removes an item from the cart
@@ -176,12 +299,12 @@ func (cob *DefaultCartBehaviour) DeleteItem(ctx context.Context, cart *domaincar
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error collecting totals: %w", err)
 	}
 
-	err = cob.cartStorage.StoreCart(ctx, &newCart)
+	defers, err := cob.storeModifiedCart(ctx, &newCart)
 	if err != nil {
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error saving cart: %w", err)
 	}
 
-	return cob.resetPaymentSelectionIfInvalid(ctx, &newCart)
+	return cob.resetPaymentSelectionIfInvalid(ctx, &newCart, defers)
 }
 
 // UpdateItem updates a cart item
@@ -218,12 +341,12 @@ func (cob *DefaultCartBehaviour) UpdateItems(ctx context.Context, cart *domainca
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error collecting totals: %w", err)
 	}
 
-	err = cob.cartStorage.StoreCart(ctx, &newCart)
+	defers, err := cob.storeModifiedCart(ctx, &newCart)
 	if err != nil {
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error saving cart: %w", err)
 	}
 
-	return cob.resetPaymentSelectionIfInvalid(ctx, &newCart)
+	return cob.resetPaymentSelectionIfInvalid(ctx, &newCart, defers)
 }
 
 func (cob *DefaultCartBehaviour) updateItem(ctx context.Context, cart *domaincart.Cart, itemUpdateCommand domaincart.ItemUpdateCommand) error {
@@ -266,39 +389,9 @@ func (cob *DefaultCartBehaviour) updateItem(ctx context.Context, cart *domaincar
 
 		itemDelivery.Cartitems[index].Qty = *itemUpdateCommand.Qty
 
-		gross := item.SinglePriceGross.Clone().Amount().Mul(item.SinglePriceGross.Amount(), big.NewFloat(float64(*itemUpdateCommand.Qty)))
-		itemDelivery.Cartitems[index].RowPriceGross = priceDomain.NewFromBigFloat(*gross, item.SinglePriceGross.Currency())
-
-		net := item.SinglePriceNet.Clone().Amount().Mul(item.SinglePriceNet.Amount(), big.NewFloat(float64(*itemUpdateCommand.Qty)))
-		itemDelivery.Cartitems[index].RowPriceNet = priceDomain.NewFromBigFloat(*net, item.SinglePriceNet.Currency())
-
-		itemDelivery.Cartitems[index].RowPriceGrossWithDiscount = itemDelivery.Cartitems[index].RowPriceGross
-		if rowPriceGrossWithDiscount, err := itemDelivery.Cartitems[index].RowPriceGross.Sub(itemDelivery.Cartitems[index].TotalDiscountAmount); err == nil {
-			itemDelivery.Cartitems[index].RowPriceGrossWithDiscount = rowPriceGrossWithDiscount
-		}
-
-		itemDelivery.Cartitems[index].RowPriceNetWithDiscount = itemDelivery.Cartitems[index].RowPriceNet
-		if rowPriceNetWithDiscount, err := itemDelivery.Cartitems[index].RowPriceNet.Sub(itemDelivery.Cartitems[index].TotalDiscountAmount); err == nil {
-			itemDelivery.Cartitems[index].RowPriceNetWithDiscount = rowPriceNetWithDiscount
-		}
-
-		itemDelivery.Cartitems[index].RowPriceGrossWithItemRelatedDiscount = itemDelivery.Cartitems[index].RowPriceGross
-		if rowPriceGrossWithItemRelatedDiscount, err := itemDelivery.Cartitems[index].RowPriceGross.Sub(itemDelivery.Cartitems[index].ItemRelatedDiscountAmount); err == nil {
-			itemDelivery.Cartitems[index].RowPriceGrossWithItemRelatedDiscount = rowPriceGrossWithItemRelatedDiscount
-		}
-
-		itemDelivery.Cartitems[index].RowPriceNetWithItemRelatedDiscount = itemDelivery.Cartitems[index].RowPriceNet
-		if rowPriceNetWithItemRelatedDiscount, err := itemDelivery.Cartitems[index].RowPriceNet.Sub(itemDelivery.Cartitems[index].ItemRelatedDiscountAmount); err == nil {
-			itemDelivery.Cartitems[index].RowPriceNetWithItemRelatedDiscount = rowPriceNetWithItemRelatedDiscount
-		}
-
-		if cob.defaultTaxRate > 0.0 {
-			taxAmount, err := itemDelivery.Cartitems[index].RowPriceGross.Sub(itemDelivery.Cartitems[index].RowPriceNet)
-			if err != nil {
-				return fmt.Errorf("DefaultCartBehaviour: error calculating tax amount: %w", err)
-			}
-
-			itemDelivery.Cartitems[index].RowTaxes[0].Amount = taxAmount
+		err = cob.recalculateDelivery(ctx, cart, itemDelivery)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -326,22 +419,48 @@ func (cob *DefaultCartBehaviour) AddToCart(ctx context.Context, cart *domaincart
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error cloning cart: %w", err)
 	}
 
-	// create delivery if it does not yet exist
-	if !newCart.HasDeliveryForCode(deliveryCode) {
-		// create delivery and add item
-		delivery := new(domaincart.Delivery)
-		delivery.DeliveryInfo.Code = deliveryCode
-		newCart.Deliveries = append(newCart.Deliveries, *delivery)
+	err = cob.addToCartDelivery(ctx, &newCart, deliveryCode, addRequest)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	delivery, err := cob.addToDelivery(ctx, newCart.GetDeliveryByCodeWithoutBool(deliveryCode), addRequest)
+	err = cob.collectTotals(&newCart)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error collecting totals: %w", err)
 	}
 
-	for k, del := range newCart.Deliveries {
-		if del.DeliveryInfo.Code == delivery.DeliveryInfo.Code {
-			newCart.Deliveries[k] = *delivery
+	defers, err := cob.storeModifiedCart(ctx, &newCart)
+	if err != nil {
+		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error saving cart: %w", err)
+	}
+
+	return cob.resetPaymentSelectionIfInvalid(ctx, &newCart, defers)
+}
+
+// AddToCartBulk adds all requested items and stores the cart only once
+func (cob *DefaultCartBehaviour) AddToCartBulk(ctx context.Context, cart *domaincart.Cart, addRequests []domaincart.DeliveryAddRequest) (*domaincart.Cart, domaincart.DeferEvents, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/DefaultCartBehaviour/AddToCartBulk")
+	defer span.End()
+
+	if cart != nil && !cob.cartStorage.HasCart(ctx, cart.ID) {
+		return nil, nil, fmt.Errorf("DefaultCartBehaviour: %w for cart id %q during bulk add", domaincart.ErrCartNotFound, cart.ID)
+	}
+
+	newCart, err := cart.Clone()
+	if err != nil {
+		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error cloning cart: %w", err)
+	}
+
+	for _, addRequest := range addRequests {
+		if !newCart.HasDeliveryForCode(addRequest.DeliveryCode) && addRequest.InitialDeliveryInfo != nil {
+			deliveryInfo := *addRequest.InitialDeliveryInfo
+			deliveryInfo.Code = addRequest.DeliveryCode
+			newCart.Deliveries = append(newCart.Deliveries, domaincart.Delivery{DeliveryInfo: deliveryInfo})
+		}
+
+		err = cob.addToCartDelivery(ctx, &newCart, addRequest.DeliveryCode, addRequest.AddRequest)
+		if err != nil {
+			return nil, nil, err
 		}
 	}
 
@@ -350,29 +469,56 @@ func (cob *DefaultCartBehaviour) AddToCart(ctx context.Context, cart *domaincart
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error collecting totals: %w", err)
 	}
 
-	err = cob.cartStorage.StoreCart(ctx, &newCart)
+	defers, err := cob.storeModifiedCart(ctx, &newCart)
 	if err != nil {
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error saving cart: %w", err)
 	}
 
-	return cob.resetPaymentSelectionIfInvalid(ctx, &newCart)
+	return cob.resetPaymentSelectionIfInvalid(ctx, &newCart, defers)
 }
 
-// has cart current delivery, check if there is an item present for this delivery
-func (cob *DefaultCartBehaviour) addToDelivery(ctx context.Context, delivery *domaincart.Delivery, addRequest domaincart.AddRequest) (*domaincart.Delivery, error) {
-	ctx, span := trace.StartSpan(ctx, "cart/DefaultCartBehaviour/addToDelivery")
-	defer span.End()
+// addToCartDelivery adds the item to the delivery of the cart, the delivery is created if it does not yet exist
+func (cob *DefaultCartBehaviour) addToCartDelivery(ctx context.Context, cart *domaincart.Cart, deliveryCode string, addRequest domaincart.AddRequest) error {
+	if !cart.HasDeliveryForCode(deliveryCode) {
+		delivery := new(domaincart.Delivery)
+		delivery.DeliveryInfo.Code = deliveryCode
+		cart.Deliveries = append(cart.Deliveries, *delivery)
+	}
 
-	for index, item := range delivery.Cartitems {
-		if item.MarketplaceCode != addRequest.MarketplaceCode {
-			continue
+	delivery := cart.GetDeliveryByCodeWithoutBool(deliveryCode)
+	// the prices of a cart created from a quote have been negotiated, they must not be replaced by the product prices
+	keepPrices := quote.IDOfCart(*cart) != ""
+
+	for _, lineAddRequest := range cob.lineItemStrategy.SplitAddRequest(addRequest) {
+		var err error
+
+		delivery, err = cob.addToDelivery(ctx, delivery, lineAddRequest, keepPrices)
+		if err != nil {
+			return err
 		}
+	}
 
-		if item.VariantMarketPlaceCode != addRequest.VariantMarketplaceCode {
-			continue
+	err := cob.recalculateDelivery(ctx, cart, delivery)
+	if err != nil {
+		return err
+	}
+
+	for k, del := range cart.Deliveries {
+		if del.DeliveryInfo.Code == delivery.DeliveryInfo.Code {
+			cart.Deliveries[k] = *delivery
 		}
+	}
 
-		if !item.BundleConfig.Equals(addRequest.BundleConfiguration) {
+	return nil
+}
+
+// has cart current delivery, check if there is an item present for this delivery
+func (cob *DefaultCartBehaviour) addToDelivery(ctx context.Context, delivery *domaincart.Delivery, addRequest domaincart.AddRequest, keepPrices bool) (*domaincart.Delivery, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/DefaultCartBehaviour/addToDelivery")
+	defer span.End()
+
+	for index, item := range delivery.Cartitems {
+		if !cob.lineItemStrategy.IsSameLine(item, addRequest) {
 			continue
 		}
 
@@ -387,12 +533,21 @@ func (cob *DefaultCartBehaviour) addToDelivery(ctx context.Context, delivery *do
 			addRequest.AdditionalData[key] = val
 		}
 
+		if item.SourceID != "" {
+			addRequest.SourceID = item.SourceID
+		}
+
 		// create and add new item
 		cartItem, err := cob.buildItemForCart(ctx, addRequest)
 		if err != nil {
 			return nil, err
 		}
 
+		// only the qty of the line changes, the row prices are recalculated with the delivery
+		if keepPrices {
+			cartItem.SinglePriceGross, cartItem.SinglePriceNet = item.SinglePriceGross, item.SinglePriceNet
+		}
+
 		delivery.Cartitems[index] = *cartItem
 
 		return delivery, nil
@@ -442,10 +597,23 @@ func (cob *DefaultCartBehaviour) buildItemForCart(ctx context.Context, addReques
 		product = bundleProductWithActiveChoices
 	}
 
-	return cob.createCartItemFromProduct(addRequest.Qty, addRequest.MarketplaceCode, addRequest.VariantMarketplaceCode, addRequest.AdditionalData, addRequest.BundleConfiguration, product)
+	options, err := domaincart.CreateItemOptions(product.BaseData(), addRequest.Options)
+	if err != nil {
+		return nil, fmt.Errorf("DefaultCartBehaviour: error creating item options: %w", err)
+	}
+
+	item, err := cob.createCartItemFromProduct(addRequest.Qty, addRequest.MarketplaceCode, addRequest.VariantMarketplaceCode, addRequest.AdditionalData, addRequest.BundleConfiguration, options, product)
+	if err != nil {
+		return nil, err
+	}
+
+	item.SourceID = addRequest.SourceID
+
+	return item, nil
 }
+
 func (cob *DefaultCartBehaviour) createCartItemFromProduct(qty int, marketplaceCode string, variantMarketPlaceCode string,
-	additonalData map[string]string, bundleConfig domain.BundleConfiguration, product domain.BasicProduct) (*domaincart.Item, error) {
+	additonalData map[string]string, bundleConfig domain.BundleConfiguration, options []domaincart.ItemOption, product domain.BasicProduct) (*domaincart.Item, error) {
 	item := &domaincart.Item{
 		ID:                     strconv.Itoa(rand.Int()),
 		ExternalReference:      strconv.Itoa(rand.Int()),
@@ -454,43 +622,38 @@ func (cob *DefaultCartBehaviour) createCartItemFromProduct(qty int, marketplaceC
 		ProductName:            product.BaseData().Title,
 		Qty:                    qty,
 		AdditionalData:         additonalData,
+		Options:                options,
 	}
 
 	currency := product.SaleableData().ActivePrice.GetFinalPrice().Currency()
 
-	if cob.grossPricing {
-		item.SinglePriceGross = product.SaleableData().ActivePrice.GetFinalPrice().GetPayable()
-		net := item.SinglePriceGross.Clone().Amount().Quo(item.SinglePriceGross.Amount(), big.NewFloat(1+(cob.defaultTaxRate/100)))
-		item.SinglePriceNet = priceDomain.NewFromBigFloat(*net, currency).GetPayable()
-	} else {
-		item.SinglePriceNet = product.SaleableData().ActivePrice.GetFinalPrice().GetPayable()
-		gross := item.SinglePriceGross.Clone().Amount().Mul(item.SinglePriceNet.Amount(), big.NewFloat(1+(cob.defaultTaxRate/100)))
-		item.SinglePriceGross = priceDomain.NewFromBigFloat(*gross, currency).GetPayable()
-	}
-
-	gross := item.SinglePriceGross.Clone().Amount().Mul(item.SinglePriceGross.Amount(), big.NewFloat(float64(qty)))
-	item.RowPriceGross = priceDomain.NewFromBigFloat(*gross, currency)
-	_ = item.RowPriceGross.FloatAmount()
-	net := item.SinglePriceNet.Clone().Amount().Mul(item.SinglePriceNet.Amount(), big.NewFloat(float64(qty)))
-	item.RowPriceNet = priceDomain.NewFromBigFloat(*net, currency)
-	_ = item.RowPriceNet.FloatAmount()
+	// the option surcharges are priced like the product, so they are part of the single price before taxes are calculated
+	singlePrice := product.SaleableData().ActivePrice.GetFinalPrice()
+	for _, option := range options {
+		var err error
 
-	item.RowPriceGrossWithDiscount, item.RowPriceNetWithDiscount = item.RowPriceGross, item.RowPriceNet
-	item.RowPriceGrossWithItemRelatedDiscount, item.RowPriceNetWithItemRelatedDiscount = item.RowPriceGross, item.RowPriceNet
-
-	if cob.defaultTaxRate > 0.0 {
-		taxAmount, err := item.RowPriceGross.Sub(item.RowPriceNet)
+		singlePrice, err = singlePrice.Add(option.Surcharge)
 		if err != nil {
-			return nil, fmt.Errorf("DefaultCartBehaviour: error calculating tax amount: %w", err)
+			return nil, fmt.Errorf("DefaultCartBehaviour: error adding surcharge of item option %q: %w", option.Code, err)
 		}
+	}
+
+	item.SinglePriceGross, item.SinglePriceNet = singlePrice.GetPayable(), singlePrice.GetPayable()
+	item.TaxClass = product.SaleableData().ActivePrice.TaxClass
 
-		item.RowTaxes = []domaincart.Tax{{
-			Amount: taxAmount,
-			Type:   "default",
-			Rate:   big.NewFloat(cob.defaultTaxRate),
-		}}
+	// the taxes are recalculated for the delivery address as soon as the item is added to a delivery
+	delivery := domaincart.Delivery{Cartitems: []domaincart.Item{*item}}
+
+	err := cob.taxCalculator.CalculateDeliveryTaxes(&delivery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("DefaultCartBehaviour: error calculating taxes: %w", err)
 	}
 
+	*item = delivery.Cartitems[0]
+
+	item.RowPriceGrossWithDiscount, item.RowPriceNetWithDiscount = item.RowPriceGross, item.RowPriceNet
+	item.RowPriceGrossWithItemRelatedDiscount, item.RowPriceNetWithItemRelatedDiscount = item.RowPriceGross, item.RowPriceNet
+
 	item.TotalDiscountAmount = priceDomain.NewZero(currency)
 	item.ItemRelatedDiscountAmount = priceDomain.NewZero(currency)
 	item.NonItemRelatedDiscountAmount = priceDomain.NewZero(currency)
@@ -528,12 +691,12 @@ func (cob *DefaultCartBehaviour) CleanCart(ctx context.Context, cart *domaincart
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error collecting totals: %w", err)
 	}
 
-	err = cob.cartStorage.StoreCart(ctx, &newCart)
+	defers, err := cob.storeModifiedCart(ctx, &newCart)
 	if err != nil {
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error saving cart: %w", err)
 	}
 
-	return &newCart, nil, nil
+	return &newCart, defers, nil
 }
 
 // CleanDelivery removes a complete delivery with its items from the cart
@@ -574,12 +737,12 @@ func (cob *DefaultCartBehaviour) CleanDelivery(ctx context.Context, cart *domain
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error collecting totals: %w", err)
 	}
 
-	err = cob.cartStorage.StoreCart(ctx, &newCart)
+	defers, err := cob.storeModifiedCart(ctx, &newCart)
 	if err != nil {
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error saving cart: %w", err)
 	}
 
-	return cob.resetPaymentSelectionIfInvalid(ctx, &newCart)
+	return cob.resetPaymentSelectionIfInvalid(ctx, &newCart, defers)
 }
 
 // UpdatePurchaser - updates purchaser
@@ -609,12 +772,12 @@ func (cob *DefaultCartBehaviour) UpdatePurchaser(ctx context.Context, cart *doma
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error collecting totals: %w", err)
 	}
 
-	err = cob.cartStorage.StoreCart(ctx, &newCart)
+	defers, err := cob.storeModifiedCart(ctx, &newCart)
 	if err != nil {
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error saving cart: %w", err)
 	}
 
-	return &newCart, nil, nil
+	return &newCart, defers, nil
 }
 
 // UpdateBillingAddress - updates address
@@ -629,17 +792,26 @@ func (cob *DefaultCartBehaviour) UpdateBillingAddress(ctx context.Context, cart
 
 	newCart.BillingAddress = &billingAddress
 
+	for i := range newCart.Deliveries {
+		if newCart.Deliveries[i].DeliveryInfo.DeliveryLocation.UseBillingAddress {
+			err = cob.recalculateDelivery(ctx, &newCart, &newCart.Deliveries[i])
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
 	err = cob.collectTotals(&newCart)
 	if err != nil {
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error collecting totals: %w", err)
 	}
 
-	err = cob.cartStorage.StoreCart(ctx, &newCart)
+	defers, err := cob.storeModifiedCart(ctx, &newCart)
 	if err != nil {
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error saving cart: %w", err)
 	}
 
-	return &newCart, nil, nil
+	return &newCart, defers, nil
 }
 
 // UpdateAdditionalData updates additional data
@@ -659,12 +831,12 @@ func (cob *DefaultCartBehaviour) UpdateAdditionalData(ctx context.Context, cart
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error collecting totals: %w", err)
 	}
 
-	err = cob.cartStorage.StoreCart(ctx, &newCart)
+	defers, err := cob.storeModifiedCart(ctx, &newCart)
 	if err != nil {
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error updating additional data: %w", err)
 	}
 
-	return &newCart, nil, nil
+	return &newCart, defers, nil
 }
 
 // UpdatePaymentSelection updates payment on cart
@@ -691,12 +863,12 @@ func (cob *DefaultCartBehaviour) UpdatePaymentSelection(ctx context.Context, car
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error collecting totals: %w", err)
 	}
 
-	err = cob.cartStorage.StoreCart(ctx, &newCart)
+	defers, err := cob.storeModifiedCart(ctx, &newCart)
 	if err != nil {
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error saving cart: %w", err)
 	}
 
-	return &newCart, nil, nil
+	return &newCart, defers, nil
 }
 
 // UpdateDeliveryInfo updates a delivery info
@@ -714,35 +886,52 @@ func (cob *DefaultCartBehaviour) UpdateDeliveryInfo(ctx context.Context, cart *d
 
 	for key, delivery := range newCart.Deliveries {
 		if delivery.DeliveryInfo.Code == deliveryCode {
+			methodChanged := delivery.DeliveryInfo.Method != deliveryInfo.Method || delivery.DeliveryInfo.Carrier != deliveryInfo.Carrier
 			newCart.Deliveries[key].DeliveryInfo = deliveryInfo
 
+			err = cob.calculateTaxes(&newCart, &newCart.Deliveries[key])
+			if err != nil {
+				return nil, nil, err
+			}
+
+			// a newly chosen shipping method is rejected if it isn't available, e.g. a changed address only removes the shipping costs
+			err = cob.updateShippingItem(ctx, &newCart, &newCart.Deliveries[key])
+			if err != nil && (methodChanged || !errors.Is(err, domaincart.ErrShippingMethodNotAvailable)) {
+				return nil, nil, err
+			}
+
 			err = cob.collectTotals(&newCart)
 			if err != nil {
 				return nil, nil, fmt.Errorf("DefaultCartBehaviour: error collecting totals: %w", err)
 			}
 
-			err := cob.cartStorage.StoreCart(ctx, &newCart)
+			defers, err := cob.storeModifiedCart(ctx, &newCart)
 			if err != nil {
 				return nil, nil, fmt.Errorf("DefaultCartBehaviour: error saving cart: %w", err)
 			}
 
-			return &newCart, nil, nil
+			return &newCart, defers, nil
 		}
 	}
 
 	newCart.Deliveries = append(newCart.Deliveries, domaincart.Delivery{DeliveryInfo: deliveryInfo})
 
+	err = cob.updateShippingItem(ctx, &newCart, &newCart.Deliveries[len(newCart.Deliveries)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+
 	err = cob.collectTotals(&newCart)
 	if err != nil {
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error collecting totals: %w", err)
 	}
 
-	err = cob.cartStorage.StoreCart(ctx, &newCart)
+	defers, err := cob.storeModifiedCart(ctx, &newCart)
 	if err != nil {
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error saving cart: %w", err)
 	}
 
-	return cob.resetPaymentSelectionIfInvalid(ctx, &newCart)
+	return cob.resetPaymentSelectionIfInvalid(ctx, &newCart, defers)
 }
 
 // UpdateDeliveryInfoAdditionalData @todo implement when needed
@@ -771,6 +960,15 @@ func (cob *DefaultCartBehaviour) GetCart(ctx context.Context, cartID string) (*d
 		return nil, domaincart.ErrCartNotFound
 	}
 
+	if cob.cartExpirer != nil && cob.cartExpirer.IsExpired(cart) {
+		err = cob.cartExpirer.Expire(ctx, cart)
+		if err != nil {
+			cob.logger.WithField(flamingo.LogKeyCategory, logCategory).Error(err)
+		}
+
+		return nil, fmt.Errorf("DefaultCartBehaviour: %w for cart id %q during get", domaincart.ErrCartExpired, cartID)
+	}
+
 	newCart, err := cart.Clone()
 	if err != nil {
 		cob.logger.WithField(flamingo.LogKeyCategory, logCategory).Info(fmt.Errorf("DefaultCartBehaviour: cart clone failed: %w ", err))
@@ -780,6 +978,30 @@ func (cob *DefaultCartBehaviour) GetCart(ctx context.Context, cartID string) (*d
 	return &newCart, nil
 }
 
+// RenameCart sets the name of the cart and stores it
+func (cob *DefaultCartBehaviour) RenameCart(ctx context.Context, cart *domaincart.Cart, name string) (*domaincart.Cart, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/DefaultCartBehaviour/RenameCart")
+	defer span.End()
+
+	if !cob.cartStorage.HasCart(ctx, cart.ID) {
+		return nil, fmt.Errorf("DefaultCartBehaviour: %w for cart id %q during rename", domaincart.ErrCartNotFound, cart.ID)
+	}
+
+	newCart, err := cart.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("DefaultCartBehaviour: error cloning cart: %w", err)
+	}
+
+	newCart.Name = name
+
+	err = cob.storeCart(ctx, &newCart)
+	if err != nil {
+		return nil, fmt.Errorf("DefaultCartBehaviour: error saving cart: %w", err)
+	}
+
+	return &newCart, nil
+}
+
 // StoreNewCart created and stores a new cart.
 func (cob *DefaultCartBehaviour) StoreNewCart(ctx context.Context, cart *domaincart.Cart) (*domaincart.Cart, error) {
 	ctx, span := trace.StartSpan(ctx, "cart/DefaultCartBehaviour/StoreNewCart")
@@ -801,7 +1023,7 @@ func (cob *DefaultCartBehaviour) StoreNewCart(ctx context.Context, cart *domainc
 		return nil, fmt.Errorf("DefaultCartBehaviour: error collecting totals: %w", err)
 	}
 
-	err = cob.cartStorage.StoreCart(ctx, &newCart)
+	err = cob.storeCart(ctx, &newCart)
 	if err != nil {
 		return nil, fmt.Errorf("DefaultCartBehaviour: error saving cart: %w", err)
 	}
@@ -829,12 +1051,12 @@ func (cob *DefaultCartBehaviour) ApplyVoucher(ctx context.Context, cart *domainc
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error collecting totals: %w", err)
 	}
 
-	err = cob.cartStorage.StoreCart(ctx, newCartWithVoucher)
+	defers, err := cob.storeModifiedCart(ctx, newCartWithVoucher)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return cob.resetPaymentSelectionIfInvalid(ctx, newCartWithVoucher)
+	return cob.resetPaymentSelectionIfInvalid(ctx, newCartWithVoucher, defers)
 }
 
 // ApplyAny applies a voucher or giftcard to the cart
@@ -872,12 +1094,12 @@ func (cob *DefaultCartBehaviour) RemoveVoucher(ctx context.Context, cart *domain
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error collecting totals: %w", err)
 	}
 
-	err = cob.cartStorage.StoreCart(ctx, newCartWithoutVoucher)
+	defers, err := cob.storeModifiedCart(ctx, newCartWithoutVoucher)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return cob.resetPaymentSelectionIfInvalid(ctx, newCartWithoutVoucher)
+	return cob.resetPaymentSelectionIfInvalid(ctx, newCartWithoutVoucher, defers)
 }
 
 // ApplyGiftCard applies a gift card to the cart
@@ -901,12 +1123,12 @@ func (cob *DefaultCartBehaviour) ApplyGiftCard(ctx context.Context, cart *domain
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error collecting totals: %w", err)
 	}
 
-	err = cob.cartStorage.StoreCart(ctx, newCartWithGiftCard)
+	defers, err := cob.storeModifiedCart(ctx, newCartWithGiftCard)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return cob.resetPaymentSelectionIfInvalid(ctx, newCartWithGiftCard)
+	return cob.resetPaymentSelectionIfInvalid(ctx, newCartWithGiftCard, defers)
 }
 
 // RemoveGiftCard removes a gift card from the cart
@@ -930,12 +1152,72 @@ func (cob *DefaultCartBehaviour) RemoveGiftCard(ctx context.Context, cart *domai
 		return nil, nil, fmt.Errorf("DefaultCartBehaviour: error collecting totals: %w", err)
 	}
 
-	err = cob.cartStorage.StoreCart(ctx, newCartWithOutGiftCard)
+	defers, err := cob.storeModifiedCart(ctx, newCartWithOutGiftCard)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return cob.resetPaymentSelectionIfInvalid(ctx, newCartWithOutGiftCard)
+	return cob.resetPaymentSelectionIfInvalid(ctx, newCartWithOutGiftCard, defers)
+}
+
+// storeCart increases the revision of the given cart and stores it, so that concurrent modifications are detected by the storage
+func (cob *DefaultCartBehaviour) storeCart(ctx context.Context, cart *domaincart.Cart) error {
+	cart.Revision++
+
+	now := time.Now()
+	if cart.CreatedAt.IsZero() {
+		cart.CreatedAt = now
+	}
+	cart.UpdatedAt = now
+
+	return cob.cartStorage.StoreCart(ctx, cart)
+}
+
+// storeModifiedCart runs the post processors on the modified cart and stores the result with a single revision,
+// the events of the processors are returned
+func (cob *DefaultCartBehaviour) storeModifiedCart(ctx context.Context, cart *domaincart.Cart) (domaincart.DeferEvents, error) {
+	defers := cob.postProcess(ctx, cart)
+
+	return defers, cob.storeCart(ctx, cart)
+}
+
+// postProcess runs the post processors with a behaviour which keeps their modifications in memory and applies the
+// resulting cart to the given one, errors of processors are logged and don't fail the modification
+func (cob *DefaultCartBehaviour) postProcess(ctx context.Context, cart *domaincart.Cart) domaincart.DeferEvents {
+	if cob.postProcessors == nil {
+		return nil
+	}
+
+	staged := *cob
+	staged.cartStorage = &stagedCartStorage{CartStorage: cob.cartStorage, carts: map[string]*domaincart.Cart{cart.ID: cart}}
+	staged.postProcessors = nil
+
+	processedCart, defers, err := cob.postProcessors.Process(ctx, cart, &staged)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		cob.logger.WithContext(ctx).WithField(flamingo.LogKeyCategory, logCategory).Error(err)
+	}
+
+	if processedCart != nil && processedCart != cart {
+		// the staged modifications increased the revision, the stored cart must only be one revision ahead
+		revision := cart.Revision
+		*cart = *processedCart
+		cart.Revision = revision
+	}
+
+	return defers
+}
+
+// checkRevision ensures that the cart which should be stored is based on the currently stored revision
+func checkRevision(storedRevision int, cart *domaincart.Cart) error {
+	if cart.Revision != storedRevision+1 {
+		return &domaincart.RevisionConflictError{
+			CartID:           cart.ID,
+			ExpectedRevision: cart.Revision - 1,
+			ActualRevision:   storedRevision,
+		}
+	}
+
+	return nil
 }
 
 // isPaymentSelectionValid checks if the grand total of the cart matches the total of the supplied payment selection
@@ -957,29 +1239,137 @@ func (cob *DefaultCartBehaviour) checkPaymentSelection(ctx context.Context, cart
 }
 
 // resetPaymentSelectionIfInvalid checks for valid paymentselection on given cart and deletes in case it is invalid
-func (cob *DefaultCartBehaviour) resetPaymentSelectionIfInvalid(ctx context.Context, cart *domaincart.Cart) (*domaincart.Cart, domaincart.DeferEvents, error) {
+// the given events of the modification are returned together with the events of the reset
+func (cob *DefaultCartBehaviour) resetPaymentSelectionIfInvalid(ctx context.Context, cart *domaincart.Cart, defers domaincart.DeferEvents) (*domaincart.Cart, domaincart.DeferEvents, error) {
 	ctx, span := trace.StartSpan(ctx, "cart/DefaultCartBehaviour/resetPaymentSelectionIfInvalid")
 	defer span.End()
 
 	if cart.PaymentSelection == nil {
-		return cart, nil, nil
+		return cart, defers, nil
 	}
 
 	err := cob.checkPaymentSelection(ctx, cart, cart.PaymentSelection)
 	if err != nil {
-		cart, defers, err := cob.UpdatePaymentSelection(ctx, cart, nil)
+		cart, resetDefers, err := cob.UpdatePaymentSelection(ctx, cart, nil)
+		defers = append(defers, resetDefers...)
 		defers = append(defers, &events.PaymentSelectionHasBeenResetEvent{Cart: cart})
 
 		return cart, defers, err
 	}
 
-	return cart, nil, nil
+	return cart, defers, nil
+}
+
+// recalculateDelivery calculates the prices and taxes of the delivery and updates its shipping item, a shipping
+// method which isn't available anymore only removes the shipping costs
+func (cob *DefaultCartBehaviour) recalculateDelivery(ctx context.Context, cart *domaincart.Cart, delivery *domaincart.Delivery) error {
+	err := cob.calculateTaxes(cart, delivery)
+	if err != nil {
+		return err
+	}
+
+	err = cob.updateShippingItem(ctx, cart, delivery)
+	if err != nil && !errors.Is(err, domaincart.ErrShippingMethodNotAvailable) {
+		return err
+	}
+
+	return nil
+}
+
+// updateShippingItem sets the shipping costs of the chosen shipping method, the cart items must already be calculated
+// because the availability of the shipping methods might depend on them
+func (cob *DefaultCartBehaviour) updateShippingItem(ctx context.Context, cart *domaincart.Cart, delivery *domaincart.Delivery) error {
+	if cob.shippingRateProvider == nil || delivery.DeliveryInfo.Method == "" {
+		return nil
+	}
+
+	methods, err := cob.shippingRateProvider.ShippingMethods(ctx, cart, delivery)
+	if err != nil {
+		return fmt.Errorf("DefaultCartBehaviour: error getting shipping methods: %w", err)
+	}
+
+	method, found := domaincart.ShippingMethodByCode(methods, delivery.DeliveryInfo.Method, delivery.DeliveryInfo.Carrier)
+	if !found {
+		delivery.ShippingItem = domaincart.ShippingItem{}
+
+		return fmt.Errorf("DefaultCartBehaviour: %w: %q", domaincart.ErrShippingMethodNotAvailable, delivery.DeliveryInfo.Method)
+	}
+
+	// the shipping discounts are applied when the totals are collected, e.g. by the free shipping promotion
+	delivery.ShippingItem = domaincart.ShippingItem{
+		Title:                   method.Title,
+		PriceNet:                method.PriceNet,
+		PriceNetWithDiscounts:   method.PriceNet,
+		PriceGross:              method.PriceGross,
+		PriceGrossWithDiscounts: method.PriceGross,
+		TaxAmount:               method.TaxAmount,
+	}
+
+	return nil
+}
+
+// deliveryAddress returns the address the delivery is shipped to, it might be nil
+func deliveryAddress(cart *domaincart.Cart, delivery *domaincart.Delivery) *domaincart.Address {
+	if delivery.DeliveryInfo.DeliveryLocation.UseBillingAddress {
+		return cart.BillingAddress
+	}
+
+	return delivery.DeliveryInfo.DeliveryLocation.Address
+}
+
+// calculateTaxes calculates the prices and taxes of the delivery for the address it is shipped to
+func (cob *DefaultCartBehaviour) calculateTaxes(cart *domaincart.Cart, delivery *domaincart.Delivery) error {
+	err := cob.taxCalculator.CalculateDeliveryTaxes(delivery, deliveryAddress(cart, delivery))
+	if err != nil {
+		return fmt.Errorf("DefaultCartBehaviour: error calculating taxes: %w", err)
+	}
+
+	for i := range delivery.Cartitems {
+		updateRowPricesWithDiscount(&delivery.Cartitems[i])
+	}
+
+	if len(delivery.ShippingItem.AppliedDiscounts) == 0 && !delivery.ShippingItem.PriceGross.IsZero() {
+		delivery.ShippingItem.PriceGrossWithDiscounts = delivery.ShippingItem.PriceGross
+		delivery.ShippingItem.PriceNetWithDiscounts = delivery.ShippingItem.PriceNet
+	}
+
+	return nil
+}
+
+// updateRowPricesWithDiscount deducts the discount amounts of the item from its row prices
+func updateRowPricesWithDiscount(item *domaincart.Item) {
+	item.RowPriceGrossWithDiscount = item.RowPriceGross
+	if rowPriceGrossWithDiscount, err := item.RowPriceGross.Sub(item.TotalDiscountAmount); err == nil {
+		item.RowPriceGrossWithDiscount = rowPriceGrossWithDiscount
+	}
+
+	item.RowPriceNetWithDiscount = item.RowPriceNet
+	if rowPriceNetWithDiscount, err := item.RowPriceNet.Sub(item.TotalDiscountAmount); err == nil {
+		item.RowPriceNetWithDiscount = rowPriceNetWithDiscount
+	}
+
+	item.RowPriceGrossWithItemRelatedDiscount = item.RowPriceGross
+	if rowPriceGrossWithItemRelatedDiscount, err := item.RowPriceGross.Sub(item.ItemRelatedDiscountAmount); err == nil {
+		item.RowPriceGrossWithItemRelatedDiscount = rowPriceGrossWithItemRelatedDiscount
+	}
+
+	item.RowPriceNetWithItemRelatedDiscount = item.RowPriceNet
+	if rowPriceNetWithItemRelatedDiscount, err := item.RowPriceNet.Sub(item.ItemRelatedDiscountAmount); err == nil {
+		item.RowPriceNetWithItemRelatedDiscount = rowPriceNetWithItemRelatedDiscount
+	}
 }
 
 //nolint:cyclop // collecting total this way is more explicit
 func (cob *DefaultCartBehaviour) collectTotals(cart *domaincart.Cart) error {
 	var err error
 
+	if calculator, ok := cob.voucherHandler.(DiscountCalculator); ok {
+		err = calculator.CalculateDiscounts(cart)
+		if err != nil {
+			return fmt.Errorf("failed to calculate discounts: %w", err)
+		}
+	}
+
 	cart.TotalGiftCardAmount = priceDomain.NewZero(cart.DefaultCurrency)
 	cart.GrandTotalWithGiftCards = priceDomain.NewZero(cart.DefaultCurrency)
 	cart.GrandTotal = priceDomain.NewZero(cart.DefaultCurrency)
@@ -1009,7 +1399,7 @@ func (cob *DefaultCartBehaviour) collectTotals(cart *domaincart.Cart) error {
 		delivery.SubTotalNetWithDiscounts = priceDomain.NewZero(cart.DefaultCurrency)
 		delivery.GrandTotal = priceDomain.NewZero(cart.DefaultCurrency)
 
-		if !delivery.ShippingItem.PriceGrossWithDiscounts.IsZero() {
+		if !delivery.ShippingItem.PriceGrossWithDiscounts.IsZero() || len(delivery.ShippingItem.AppliedDiscounts) > 0 {
 			delivery.GrandTotal = delivery.GrandTotal.ForceAdd(delivery.ShippingItem.PriceGrossWithDiscounts)
 
 			discounts, err := delivery.ShippingItem.AppliedDiscounts.Sum()
@@ -1051,6 +1441,13 @@ func (cob *DefaultCartBehaviour) collectTotals(cart *domaincart.Cart) error {
 		cart.GrandTotal = cart.GrandTotal.ForceAdd(totalitem.Price)
 	}
 
+	if calculator, ok := cob.giftCardHandler.(GiftCardCalculator); ok {
+		err = calculator.CalculateGiftCards(cart)
+		if err != nil {
+			return fmt.Errorf("failed to calculate gift cards: %w", err)
+		}
+	}
+
 	sumAppliedGiftCards := priceDomain.NewZero(cart.DefaultCurrency)
 	for _, card := range cart.AppliedGiftCards {
 		sumAppliedGiftCards = sumAppliedGiftCards.ForceAdd(card.Applied)
@@ -1110,3 +1507,41 @@ func (DefaultGiftCardHandler) RemoveGiftCard(ctx context.Context, cart *domainca
 
 	return cart, nil
 }
+
+// GetCart returns the staged cart or the cart of the underlying storage
+func (s *stagedCartStorage) GetCart(ctx context.Context, id string) (*domaincart.Cart, error) {
+	cart, ok := s.carts[id]
+	if !ok {
+		return s.CartStorage.GetCart(ctx, id)
+	}
+
+	stagedCart, err := cart.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	return &stagedCart, nil
+}
+
+// HasCart checks the staged carts and the underlying storage
+func (s *stagedCartStorage) HasCart(ctx context.Context, id string) bool {
+	if _, ok := s.carts[id]; ok {
+		return true
+	}
+
+	return s.CartStorage.HasCart(ctx, id)
+}
+
+// StoreCart stages the cart without storing it
+func (s *stagedCartStorage) StoreCart(_ context.Context, cart *domaincart.Cart) error {
+	s.carts[cart.ID] = cart
+
+	return nil
+}
+
+// RemoveCart removes the staged cart, the underlying storage is left untouched
+func (s *stagedCartStorage) RemoveCart(_ context.Context, cart *domaincart.Cart) error {
+	delete(s.carts, cart.ID)
+
+	return nil
+}
@@ -10,7 +10,9 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"flamingo.me/flamingo-commerce/v3/cart/application"
 	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/events"
 	"flamingo.me/flamingo-commerce/v3/cart/infrastructure/mocks"
 	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
 	"flamingo.me/flamingo-commerce/v3/product/domain"
@@ -31,6 +33,7 @@ func TestDefaultCartBehaviour_CleanCart(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart := &domaincart.Cart{
@@ -68,6 +71,7 @@ func TestDefaultCartBehaviour_CleanDelivery(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart := &domaincart.Cart{
@@ -108,6 +112,7 @@ func TestDefaultCartBehaviour_CleanDelivery(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart := &domaincart.Cart{
@@ -159,6 +164,7 @@ func TestDefaultCartBehaviour_ApplyVoucher(t *testing.T) {
 			voucherHandler,
 			nil,
 			nil,
+			nil,
 		)
 
 		got, _, err := cob.ApplyVoucher(context.Background(), &domaincart.Cart{ID: "test"}, "voucher")
@@ -182,6 +188,7 @@ func TestDefaultCartBehaviour_ApplyVoucher(t *testing.T) {
 			voucherHandler,
 			nil,
 			nil,
+			nil,
 		)
 
 		got, _, err := cob.ApplyVoucher(context.Background(), &domaincart.Cart{ID: "test"}, "voucher")
@@ -209,6 +216,7 @@ func TestDefaultCartBehaviour_RemoveVoucher(t *testing.T) {
 			voucherHandler,
 			nil,
 			nil,
+			nil,
 		)
 
 		got, _, err := cob.RemoveVoucher(context.Background(), &domaincart.Cart{ID: "test"}, "voucher")
@@ -232,6 +240,7 @@ func TestDefaultCartBehaviour_RemoveVoucher(t *testing.T) {
 			voucherHandler,
 			nil,
 			nil,
+			nil,
 		)
 
 		got, _, err := cob.RemoveVoucher(context.Background(), &domaincart.Cart{ID: "test"}, "voucher")
@@ -259,6 +268,7 @@ func TestDefaultCartBehaviour_ApplyGiftCard(t *testing.T) {
 			nil,
 			giftCardHandler,
 			nil,
+			nil,
 		)
 
 		got, _, err := cob.ApplyGiftCard(context.Background(), &domaincart.Cart{ID: "test"}, "giftCard")
@@ -282,6 +292,7 @@ func TestDefaultCartBehaviour_ApplyGiftCard(t *testing.T) {
 			nil,
 			giftCardHandler,
 			nil,
+			nil,
 		)
 
 		got, _, err := cob.ApplyGiftCard(context.Background(), &domaincart.Cart{ID: "test"}, "giftCard")
@@ -309,6 +320,7 @@ func TestDefaultCartBehaviour_RemoveGiftCard(t *testing.T) {
 			nil,
 			giftCardHandler,
 			nil,
+			nil,
 		)
 
 		got, _, err := cob.RemoveGiftCard(context.Background(), &domaincart.Cart{ID: "test"}, "giftCard")
@@ -332,6 +344,7 @@ func TestDefaultCartBehaviour_RemoveGiftCard(t *testing.T) {
 			nil,
 			giftCardHandler,
 			nil,
+			nil,
 		)
 
 		got, _, err := cob.RemoveGiftCard(context.Background(), &domaincart.Cart{ID: "test"}, "giftCard")
@@ -355,6 +368,7 @@ func TestDefaultCartBehaviour_Complete(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{ID: "test-id"})
 		assert.NoError(t, err)
@@ -382,6 +396,7 @@ func TestDefaultCartBehaviour_Restore(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 		cart := &domaincart.Cart{ID: "1234"}
 
@@ -407,6 +422,7 @@ func TestDefaultCartBehaviour_DeleteItem(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart := &domaincart.Cart{
@@ -440,6 +456,7 @@ func TestDefaultCartBehaviour_DeleteItem(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
@@ -483,6 +500,7 @@ func TestDefaultCartBehaviour_DeleteItem(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
@@ -526,6 +544,7 @@ func TestDefaultCartBehaviour_DeleteItem(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
@@ -569,6 +588,7 @@ func TestDefaultCartBehaviour_DeleteItem(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
@@ -609,6 +629,7 @@ func TestDefaultCartBehaviour_UpdateItems(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart := &domaincart.Cart{
@@ -642,6 +663,7 @@ func TestDefaultCartBehaviour_UpdateItems(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
@@ -669,6 +691,7 @@ func TestDefaultCartBehaviour_UpdateItems(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
@@ -727,6 +750,7 @@ func TestDefaultCartBehaviour_UpdateItems(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
@@ -768,6 +792,7 @@ func TestDefaultCartBehaviour_UpdateItems(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
@@ -852,6 +877,7 @@ func TestDefaultCartBehaviour_UpdateItems(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
@@ -925,6 +951,7 @@ func TestDefaultCartBehaviour_AddToCart(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
@@ -956,6 +983,7 @@ func TestDefaultCartBehaviour_AddToCart(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
@@ -995,6 +1023,59 @@ func TestDefaultCartBehaviour_AddToCart(t *testing.T) {
 		assert.Equal(t, 41.98, got.GrandTotal.FloatAmount())
 	})
 
+	t.Run("source id is set on new items and kept on existing items", func(t *testing.T) {
+		t.Parallel()
+
+		cob := &DefaultCartBehaviour{}
+		cob.Inject(
+			newInMemoryStorage(),
+			&fake.ProductService{},
+			flamingo.NullLogger{},
+			nil,
+			nil,
+			nil,
+			nil,
+		)
+
+		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
+			ID: "1234",
+			Deliveries: []domaincart.Delivery{
+				{
+					DeliveryInfo: domaincart.DeliveryInfo{
+						Code: "delivery",
+					},
+					Cartitems: []domaincart.Item{
+						{
+							MarketplaceCode: "fake_fixed_simple_without_discounts",
+							Qty:             1,
+							SourceID:        "existing-source",
+						},
+					},
+				},
+			},
+		})
+		assert.NoError(t, err)
+
+		got, _, err := cob.AddToCart(context.Background(), cart, "delivery", domaincart.AddRequest{
+			MarketplaceCode: "fake_fixed_simple_without_discounts",
+			Qty:             1,
+			SourceID:        "other-source",
+		})
+		assert.NoError(t, err)
+
+		got, _, err = cob.AddToCart(context.Background(), got, "delivery", domaincart.AddRequest{
+			MarketplaceCode: "fake_simple_with_fixed_price",
+			Qty:             1,
+			SourceID:        "new-source",
+		})
+		assert.NoError(t, err)
+
+		require.Equal(t, 2, len(got.Deliveries[0].Cartitems))
+		assert.Equal(t, 2, got.Deliveries[0].Cartitems[0].Qty)
+		assert.Equal(t, "existing-source", got.Deliveries[0].Cartitems[0].SourceID)
+		assert.Equal(t, "new-source", got.Deliveries[0].Cartitems[1].SourceID)
+	})
+
 	t.Run("adding the same configurable product with different active variant", func(t *testing.T) {
 		t.Parallel()
 
@@ -1006,6 +1087,7 @@ func TestDefaultCartBehaviour_AddToCart(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
@@ -1052,6 +1134,7 @@ func TestDefaultCartBehaviour_AddToCart(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
@@ -1108,6 +1191,113 @@ func TestDefaultCartBehaviour_AddToCart(t *testing.T) {
 	})
 }
 
+func TestDefaultCartBehaviour_AddToCartBulk(t *testing.T) {
+	t.Parallel()
+
+	cob := &DefaultCartBehaviour{}
+	cob.Inject(
+		newInMemoryStorage(),
+		&fake.ProductService{},
+		flamingo.NullLogger{},
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
+		ID: "1234",
+	})
+	require.NoError(t, err)
+
+	got, _, err := cob.AddToCartBulk(context.Background(), cart, []domaincart.DeliveryAddRequest{
+		{
+			DeliveryCode: "delivery",
+			AddRequest:   domaincart.AddRequest{MarketplaceCode: "fake_fixed_simple_without_discounts", Qty: 1},
+		},
+		{
+			DeliveryCode:        "pickup",
+			AddRequest:          domaincart.AddRequest{MarketplaceCode: "fake_fixed_simple_without_discounts", Qty: 1},
+			InitialDeliveryInfo: &domaincart.DeliveryInfo{Method: "pickup"},
+		},
+		{
+			DeliveryCode: "delivery",
+			AddRequest:   domaincart.AddRequest{MarketplaceCode: "fake_fixed_simple_without_discounts", Qty: 2},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, got.Deliveries, 2)
+	require.Len(t, got.Deliveries[0].Cartitems, 1)
+	assert.Equal(t, 3, got.Deliveries[0].Cartitems[0].Qty)
+	assert.Equal(t, "pickup", got.Deliveries[1].DeliveryInfo.Code)
+	assert.Equal(t, "pickup", got.Deliveries[1].DeliveryInfo.Method)
+	assert.Equal(t, cart.Revision+1, got.Revision, "cart must be stored only once")
+}
+
+func TestDefaultCartBehaviour_AddToCartLineItemStrategy(t *testing.T) {
+	t.Parallel()
+
+	strategy := new(domaincart.DefaultLineItemStrategy).Inject(&struct {
+		DistinguishingAdditionalDataKeys []string `inject:"config:commerce.cart.lineItems.distinguishingAdditionalDataKeys,optional"`
+		UnmergeableAdditionalDataKeys    []string `inject:"config:commerce.cart.lineItems.unmergeableAdditionalDataKeys,optional"`
+		SingleUnitAdditionalDataKeys     []string `inject:"config:commerce.cart.lineItems.singleUnitAdditionalDataKeys,optional"`
+	}{
+		DistinguishingAdditionalDataKeys: []string{"engraving"},
+		SingleUnitAdditionalDataKeys:     []string{"personalisation"},
+	})
+
+	cob := &DefaultCartBehaviour{}
+	cob.Inject(
+		newInMemoryStorage(),
+		&fake.ProductService{},
+		flamingo.NullLogger{},
+		nil,
+		nil,
+		nil,
+		&struct {
+			ShippingRateProvider domaincart.ShippingRateProvider        `inject:",optional"`
+			CartExpirer          CartExpirer                            `inject:",optional"`
+			LineItemStrategy     domaincart.LineItemStrategy            `inject:",optional"`
+			TaxCalculator        TaxCalculator                          `inject:",optional"`
+			PostProcessors       *application.CartPostProcessorPipeline `inject:",optional"`
+		}{LineItemStrategy: strategy},
+	)
+
+	cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{ID: "1234"})
+	require.NoError(t, err)
+
+	got, _, err := cob.AddToCartBulk(context.Background(), cart, []domaincart.DeliveryAddRequest{
+		{
+			DeliveryCode: "delivery",
+			AddRequest:   domaincart.AddRequest{MarketplaceCode: "fake_simple", Qty: 1, AdditionalData: map[string]string{"engraving": "Hello"}},
+		},
+		{
+			DeliveryCode: "delivery",
+			AddRequest:   domaincart.AddRequest{MarketplaceCode: "fake_simple", Qty: 1, AdditionalData: map[string]string{"engraving": "World"}},
+		},
+		{
+			DeliveryCode: "delivery",
+			AddRequest:   domaincart.AddRequest{MarketplaceCode: "fake_simple", Qty: 2, AdditionalData: map[string]string{"engraving": "Hello"}},
+		},
+		{
+			DeliveryCode: "delivery",
+			AddRequest:   domaincart.AddRequest{MarketplaceCode: "fake_fixed_simple_without_discounts", Qty: 2, AdditionalData: map[string]string{"personalisation": "A"}},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, got.Deliveries, 1)
+	items := got.Deliveries[0].Cartitems
+	require.Len(t, items, 4)
+	assert.Equal(t, 3, items[0].Qty, "lines with the same engraving are merged")
+	assert.Equal(t, "Hello", items[0].AdditionalData["engraving"])
+	assert.Equal(t, 1, items[1].Qty, "lines with a different engraving are kept separate")
+	assert.Equal(t, "World", items[1].AdditionalData["engraving"])
+	assert.Equal(t, 1, items[2].Qty, "personalised products are split in single units")
+	assert.Equal(t, 1, items[3].Qty)
+}
+
 func TestDefaultCartBehaviour_UpdatePurchaser(t *testing.T) {
 	t.Parallel()
 
@@ -1122,6 +1312,7 @@ func TestDefaultCartBehaviour_UpdatePurchaser(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
@@ -1174,6 +1365,7 @@ func TestDefaultCartBehaviour_UpdateBillingAddress(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{ID: "1234"})
@@ -1197,6 +1389,7 @@ func TestDefaultCartBehaviour_UpdateBillingAddress(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
@@ -1229,6 +1422,7 @@ func TestDefaultCartBehaviour_UpdateAdditionalData(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{ID: "1234"})
@@ -1254,6 +1448,7 @@ func TestDefaultCartBehaviour_UpdateAdditionalData(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
@@ -1289,6 +1484,7 @@ func TestDefaultCartBehaviour_UpdatePaymentSelection(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{ID: "1234"})
@@ -1312,6 +1508,7 @@ func TestDefaultCartBehaviour_UpdatePaymentSelection(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
@@ -1344,6 +1541,7 @@ func TestDefaultCartBehaviour_UpdateDeliveryInfo(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{ID: "1234"})
@@ -1370,6 +1568,7 @@ func TestDefaultCartBehaviour_UpdateDeliveryInfo(t *testing.T) {
 			nil,
 			nil,
 			nil,
+			nil,
 		)
 
 		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{
@@ -1413,9 +1612,9 @@ func TestDefaultCartBehaviour_createCartItemFromProduct(t *testing.T) {
 			DefaultTaxRate  float64 `inject:"config:commerce.cart.defaultCartAdapter.defaultTaxRate,optional"`
 			ProductPricing  string  `inject:"config:commerce.cart.defaultCartAdapter.productPrices"`
 			DefaultCurrency string  `inject:"config:commerce.cart.defaultCartAdapter.defaultCurrency"`
-		}{ProductPricing: "gross", DefaultTaxRate: 10.0, DefaultCurrency: "EUR"})
+		}{ProductPricing: "gross", DefaultTaxRate: 10.0, DefaultCurrency: "EUR"}, nil)
 
-		item, err := cob.createCartItemFromProduct(2, "ma", "", map[string]string{}, nil, domain.SimpleProduct{
+		item, err := cob.createCartItemFromProduct(2, "ma", "", map[string]string{}, nil, nil, domain.SimpleProduct{
 			Saleable: domain.Saleable{
 				IsSaleable: true,
 				ActivePrice: domain.PriceInfo{
@@ -1440,9 +1639,9 @@ func TestDefaultCartBehaviour_createCartItemFromProduct(t *testing.T) {
 			DefaultTaxRate  float64 `inject:"config:commerce.cart.defaultCartAdapter.defaultTaxRate,optional"`
 			ProductPricing  string  `inject:"config:commerce.cart.defaultCartAdapter.productPrices"`
 			DefaultCurrency string  `inject:"config:commerce.cart.defaultCartAdapter.defaultCurrency"`
-		}{ProductPricing: "net", DefaultTaxRate: 10.0, DefaultCurrency: "EUR"})
+		}{ProductPricing: "net", DefaultTaxRate: 10.0, DefaultCurrency: "EUR"}, nil)
 
-		item, err := cob.createCartItemFromProduct(2, "ma", "", map[string]string{}, nil, domain.SimpleProduct{
+		item, err := cob.createCartItemFromProduct(2, "ma", "", map[string]string{}, nil, nil, domain.SimpleProduct{
 			Saleable: domain.Saleable{
 				IsSaleable: true,
 				ActivePrice: domain.PriceInfo{
@@ -1458,4 +1657,252 @@ func TestDefaultCartBehaviour_createCartItemFromProduct(t *testing.T) {
 		assert.Equal(t, 50.00*2, item.RowPriceNet.FloatAmount())
 		assert.Equal(t, 10.0, item.TotalTaxAmount().FloatAmount())
 	})
+
+	t.Run("option surcharges", func(t *testing.T) {
+		t.Parallel()
+
+		cob := DefaultCartBehaviour{}
+		cob.Inject(nil, nil, flamingo.NullLogger{}, nil, nil, &struct {
+			DefaultTaxRate  float64 `inject:"config:commerce.cart.defaultCartAdapter.defaultTaxRate,optional"`
+			ProductPricing  string  `inject:"config:commerce.cart.defaultCartAdapter.productPrices"`
+			DefaultCurrency string  `inject:"config:commerce.cart.defaultCartAdapter.defaultCurrency"`
+		}{ProductPricing: "net", DefaultTaxRate: 10.0, DefaultCurrency: "EUR"}, nil)
+
+		options := []domaincart.ItemOption{
+			{Code: "engraving", Value: "Hello", Surcharge: priceDomain.NewFromFloat(5.00, "USD")},
+			{Code: "giftWrap", Value: "red", Surcharge: priceDomain.NewFromFloat(2.50, "USD")},
+		}
+
+		item, err := cob.createCartItemFromProduct(2, "ma", "", map[string]string{}, nil, options, domain.SimpleProduct{
+			Saleable: domain.Saleable{
+				IsSaleable: true,
+				ActivePrice: domain.PriceInfo{
+					Default: priceDomain.NewFromFloat(50.00, "USD"),
+				},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, options, item.Options)
+		assert.Equal(t, 7.50, item.OptionSurcharge().FloatAmount())
+		assert.Equal(t, 57.50, item.SinglePriceNet.FloatAmount())
+		assert.Equal(t, 63.25, item.SinglePriceGross.FloatAmount())
+		assert.Equal(t, 57.50*2, item.RowPriceNet.FloatAmount())
+		assert.Equal(t, 63.25*2, item.RowPriceGross.FloatAmount())
+		assert.Equal(t, 11.5, item.TotalTaxAmount().FloatAmount())
+
+		cart := domaincart.Cart{Deliveries: []domaincart.Delivery{{Cartitems: []domaincart.Item{*item}}}}
+		assert.Equal(t, 63.25*2, cart.GetAllPaymentRequiredItems().CartItems()[item.ID].FloatAmount())
+	})
+
+	t.Run("option surcharge in different currency", func(t *testing.T) {
+		t.Parallel()
+
+		cob := DefaultCartBehaviour{}
+		cob.Inject(nil, nil, flamingo.NullLogger{}, nil, nil, &struct {
+			DefaultTaxRate  float64 `inject:"config:commerce.cart.defaultCartAdapter.defaultTaxRate,optional"`
+			ProductPricing  string  `inject:"config:commerce.cart.defaultCartAdapter.productPrices"`
+			DefaultCurrency string  `inject:"config:commerce.cart.defaultCartAdapter.defaultCurrency"`
+		}{ProductPricing: "gross", DefaultTaxRate: 10.0, DefaultCurrency: "EUR"}, nil)
+
+		_, err := cob.createCartItemFromProduct(1, "ma", "", nil, nil, []domaincart.ItemOption{
+			{Code: "engraving", Value: "Hello", Surcharge: priceDomain.NewFromFloat(5.00, "EUR")},
+		}, domain.SimpleProduct{
+			Saleable: domain.Saleable{
+				IsSaleable: true,
+				ActivePrice: domain.PriceInfo{
+					Default: priceDomain.NewFromFloat(50.00, "USD"),
+				},
+			},
+		})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestDefaultCartBehaviour_Revision(t *testing.T) {
+	t.Parallel()
+
+	t.Run("modification increases revision", func(t *testing.T) {
+		t.Parallel()
+
+		cob := &DefaultCartBehaviour{}
+		cob.Inject(
+			newInMemoryStorage(),
+			nil,
+			flamingo.NullLogger{},
+			nil,
+			nil,
+			nil,
+			nil,
+		)
+
+		cart := &domaincart.Cart{ID: "17"}
+		require.NoError(t, cob.cartStorage.StoreCart(context.Background(), cart))
+
+		got, _, err := cob.UpdateAdditionalData(context.Background(), cart, &domaincart.AdditionalData{CustomAttributes: map[string]string{"foo": "bar"}})
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.Revision)
+
+		got, _, err = cob.UpdateAdditionalData(context.Background(), got, &domaincart.AdditionalData{CustomAttributes: map[string]string{"foo": "baz"}})
+		require.NoError(t, err)
+		assert.Equal(t, 2, got.Revision)
+	})
+
+	t.Run("modification sets timestamps", func(t *testing.T) {
+		t.Parallel()
+
+		cob := &DefaultCartBehaviour{}
+		cob.Inject(
+			newInMemoryStorage(),
+			nil,
+			flamingo.NullLogger{},
+			nil,
+			nil,
+			nil,
+			nil,
+		)
+
+		got, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{ID: "17"})
+		require.NoError(t, err)
+		assert.False(t, got.CreatedAt.IsZero())
+		assert.Equal(t, got.CreatedAt, got.UpdatedAt)
+
+		createdAt := got.CreatedAt
+
+		got, _, err = cob.UpdateAdditionalData(context.Background(), got, &domaincart.AdditionalData{CustomAttributes: map[string]string{"foo": "bar"}})
+		require.NoError(t, err)
+		assert.Equal(t, createdAt, got.CreatedAt)
+		assert.False(t, got.UpdatedAt.Before(createdAt))
+	})
+
+	t.Run("modification of outdated cart is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		cob := &DefaultCartBehaviour{}
+		cob.Inject(
+			newInMemoryStorage(),
+			nil,
+			flamingo.NullLogger{},
+			nil,
+			nil,
+			nil,
+			nil,
+		)
+
+		cart := &domaincart.Cart{ID: "17"}
+		require.NoError(t, cob.cartStorage.StoreCart(context.Background(), cart))
+
+		_, _, err := cob.UpdateAdditionalData(context.Background(), cart, &domaincart.AdditionalData{CustomAttributes: map[string]string{"foo": "bar"}})
+		require.NoError(t, err)
+
+		_, _, err = cob.UpdateAdditionalData(context.Background(), cart, &domaincart.AdditionalData{CustomAttributes: map[string]string{"foo": "baz"}})
+		var conflictErr *domaincart.RevisionConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, 0, conflictErr.ExpectedRevision)
+		assert.Equal(t, 1, conflictErr.ActualRevision)
+	})
+}
+
+type funcPostProcessor func(ctx context.Context, cart *domaincart.Cart, behaviour domaincart.ModifyBehaviour) (*domaincart.Cart, domaincart.DeferEvents, error)
+
+func (p funcPostProcessor) Priority() int {
+	return 0
+}
+
+func (p funcPostProcessor) Process(ctx context.Context, cart *domaincart.Cart, behaviour domaincart.ModifyBehaviour) (*domaincart.Cart, domaincart.DeferEvents, error) {
+	return p(ctx, cart, behaviour)
+}
+
+func TestDefaultCartBehaviour_PostProcessors(t *testing.T) {
+	t.Parallel()
+
+	newBehaviour := func(processor application.CartPostProcessor) *DefaultCartBehaviour {
+		pipeline := new(application.CartPostProcessorPipeline).Inject(
+			&struct {
+				MaxPasses float64 `inject:"config:commerce.cart.postProcessors.maxPasses,optional"`
+			}{MaxPasses: 3},
+			&struct {
+				Processors []application.CartPostProcessor `inject:",optional"`
+			}{Processors: []application.CartPostProcessor{processor}},
+		)
+
+		cob := &DefaultCartBehaviour{}
+		cob.Inject(
+			newInMemoryStorage(),
+			nil,
+			flamingo.NullLogger{},
+			nil,
+			nil,
+			nil,
+			&struct {
+				ShippingRateProvider domaincart.ShippingRateProvider        `inject:",optional"`
+				CartExpirer          CartExpirer                            `inject:",optional"`
+				LineItemStrategy     domaincart.LineItemStrategy            `inject:",optional"`
+				TaxCalculator        TaxCalculator                          `inject:",optional"`
+				PostProcessors       *application.CartPostProcessorPipeline `inject:",optional"`
+			}{PostProcessors: pipeline},
+		)
+
+		return cob
+	}
+
+	t.Run("processors change the modification before it is stored", func(t *testing.T) {
+		t.Parallel()
+
+		var cob *DefaultCartBehaviour
+		var storedRevision int
+		cob = newBehaviour(funcPostProcessor(func(ctx context.Context, cart *domaincart.Cart, behaviour domaincart.ModifyBehaviour) (*domaincart.Cart, domaincart.DeferEvents, error) {
+			if cart.AdditionalData.CustomAttributes["processed"] != "" {
+				return nil, nil, nil
+			}
+
+			stored, err := cob.cartStorage.GetCart(ctx, cart.ID)
+			require.NoError(t, err)
+			storedRevision = stored.Revision
+
+			additionalData := domaincart.AdditionalData{CustomAttributes: map[string]string{"processed": "true"}}
+			for key, value := range cart.AdditionalData.CustomAttributes {
+				additionalData.CustomAttributes[key] = value
+			}
+
+			got, _, err := behaviour.UpdateAdditionalData(ctx, cart, &additionalData)
+
+			return got, domaincart.DeferEvents{&events.AddToCartEvent{MarketplaceCode: "gift"}}, err
+		}))
+
+		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{ID: "17"})
+		require.NoError(t, err)
+
+		got, defers, err := cob.UpdateAdditionalData(context.Background(), cart, &domaincart.AdditionalData{CustomAttributes: map[string]string{"foo": "bar"}})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"foo": "bar", "processed": "true"}, got.AdditionalData.CustomAttributes)
+		assert.Equal(t, cart.Revision, storedRevision, "the processor must run before the modification is stored")
+		assert.Len(t, defers, 1)
+
+		stored, err := cob.cartStorage.GetCart(context.Background(), "17")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"foo": "bar", "processed": "true"}, stored.AdditionalData.CustomAttributes)
+		assert.Equal(t, cart.Revision+1, stored.Revision, "the modification and the change of the processor are stored once")
+		assert.Equal(t, stored.Revision, got.Revision)
+	})
+
+	t.Run("failing processor doesn't fail the modification", func(t *testing.T) {
+		t.Parallel()
+
+		cob := newBehaviour(funcPostProcessor(func(context.Context, *domaincart.Cart, domaincart.ModifyBehaviour) (*domaincart.Cart, domaincart.DeferEvents, error) {
+			return nil, nil, errors.New("processor failed")
+		}))
+
+		cart, err := cob.StoreNewCart(context.Background(), &domaincart.Cart{ID: "17"})
+		require.NoError(t, err)
+
+		got, _, err := cob.UpdateAdditionalData(context.Background(), cart, &domaincart.AdditionalData{CustomAttributes: map[string]string{"foo": "bar"}})
+		require.NoError(t, err)
+		assert.Equal(t, cart.Revision+1, got.Revision)
+
+		stored, err := cob.cartStorage.GetCart(context.Background(), "17")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"foo": "bar"}, stored.AdditionalData.CustomAttributes)
+	})
 }
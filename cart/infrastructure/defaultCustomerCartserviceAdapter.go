@@ -3,6 +3,11 @@ package infrastructure
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
 
 	"go.opencensus.io/trace"
 
@@ -20,8 +25,14 @@ type (
 	}
 )
 
+const (
+	// defaultCustomerCartID is used by the cart receiver to request the default cart of the customer
+	defaultCustomerCartID = "me"
+)
+
 var (
 	_ cart.CustomerCartService = (*DefaultCustomerCartService)(nil)
+	_ cart.NamedCartService    = (*DefaultCustomerCartService)(nil)
 )
 
 // Inject dependencies
@@ -33,13 +44,17 @@ func (cs *DefaultCustomerCartService) Inject(
 	cs.logger = logger
 }
 
-// GetCart gets a customer cart from the in memory customer cart service
-func (cs *DefaultCustomerCartService) GetCart(ctx context.Context, identity auth.Identity, _ string) (*cart.Cart, error) {
+// GetCart gets a customer cart from the in memory customer cart service, cartID can be left empty or set to "me" to get the default cart
+func (cs *DefaultCustomerCartService) GetCart(ctx context.Context, identity auth.Identity, cartID string) (*cart.Cart, error) {
 	ctx, span := trace.StartSpan(ctx, "cart/DefaultCustomerCartService/GetCart")
 	defer span.End()
 
 	id := identity.Subject()
 
+	if cartID != "" && cartID != defaultCustomerCartID && cartID != id {
+		return cs.getNamedCart(ctx, identity, cartID)
+	}
+
 	foundCart, err := cs.defaultBehaviour.GetCart(ctx, id)
 	if err == nil {
 		return foundCart, nil
@@ -56,6 +71,121 @@ func (cs *DefaultCustomerCartService) GetCart(ctx context.Context, identity auth
 	return nil, err
 }
 
+// getNamedCart returns a named cart if it belongs to the customer
+func (cs *DefaultCustomerCartService) getNamedCart(ctx context.Context, identity auth.Identity, cartID string) (*cart.Cart, error) {
+	foundCart, err := cs.defaultBehaviour.GetCart(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	if foundCart.AuthenticatedUserID != identity.Subject() {
+		return nil, fmt.Errorf("DefaultCustomerCartService: %w for cart id %q of customer", cart.ErrCartNotFound, cartID)
+	}
+
+	return foundCart, nil
+}
+
+// ListCarts returns the default cart and all named carts of the customer, the named carts are looked up in the cart storage
+func (cs *DefaultCustomerCartService) ListCarts(ctx context.Context, identity auth.Identity) ([]*cart.Cart, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/DefaultCustomerCartService/ListCarts")
+	defer span.End()
+
+	namedCartIDs, err := cs.namedCartIDs(ctx, identity.Subject())
+	if err != nil {
+		return nil, err
+	}
+
+	defaultCart, err := cs.GetCart(ctx, identity, defaultCustomerCartID)
+	if err != nil {
+		return nil, err
+	}
+
+	var namedCarts []*cart.Cart
+
+	for _, cartID := range namedCartIDs {
+		namedCart, err := cs.getNamedCart(ctx, identity, cartID)
+		if errors.Is(err, cart.ErrCartNotFound) {
+			// the cart may have expired in the storage
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		namedCarts = append(namedCarts, namedCart)
+	}
+
+	sort.Slice(namedCarts, func(i, j int) bool {
+		if namedCarts[i].CreatedAt.Equal(namedCarts[j].CreatedAt) {
+			return namedCarts[i].ID < namedCarts[j].ID
+		}
+
+		return namedCarts[i].CreatedAt.Before(namedCarts[j].CreatedAt)
+	})
+
+	return append([]*cart.Cart{defaultCart}, namedCarts...), nil
+}
+
+// CreateCart creates a new named cart for the customer
+func (cs *DefaultCustomerCartService) CreateCart(ctx context.Context, identity auth.Identity, name string) (*cart.Cart, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/DefaultCustomerCartService/CreateCart")
+	defer span.End()
+
+	if _, ok := cs.defaultBehaviour.cartStorage.(CustomerCartIndex); !ok {
+		return nil, fmt.Errorf("DefaultCustomerCartService: %w, the cart storage has no customer cart index", cart.ErrNamedCartsNotSupported)
+	}
+
+	return cs.defaultBehaviour.StoreNewCart(ctx, &cart.Cart{
+		ID:                         identity.Subject() + "-" + strconv.Itoa(rand.Int()),
+		Name:                       name,
+		BelongsToAuthenticatedUser: true,
+		AuthenticatedUserID:        identity.Subject(),
+	})
+}
+
+// RenameCart changes the name of a cart of the customer
+func (cs *DefaultCustomerCartService) RenameCart(ctx context.Context, identity auth.Identity, cartID string, name string) (*cart.Cart, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/DefaultCustomerCartService/RenameCart")
+	defer span.End()
+
+	customerCart, err := cs.GetCart(ctx, identity, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	return cs.defaultBehaviour.RenameCart(ctx, customerCart, name)
+}
+
+// namedCartIDs looks up the named carts of the customer in the index of the cart storage, so that the carts are found after a restart
+// and by every instance sharing the storage. Named carts have the id "<customerID>-<number>".
+func (cs *DefaultCustomerCartService) namedCartIDs(ctx context.Context, customerID string) ([]string, error) {
+	index, ok := cs.defaultBehaviour.cartStorage.(CustomerCartIndex)
+	if !ok {
+		return nil, nil
+	}
+
+	cartIDs, err := index.CartIDsOfCustomer(ctx, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("DefaultCustomerCartService: error loading named carts: %w", err)
+	}
+
+	var ids []string
+
+	for _, cartID := range cartIDs {
+		number := strings.TrimPrefix(cartID, customerID+"-")
+		if number == cartID {
+			continue
+		}
+
+		if _, err := strconv.ParseUint(number, 10, 64); err == nil {
+			ids = append(ids, cartID)
+		}
+	}
+
+	return ids, nil
+}
+
 // GetModifyBehaviour gets the cart order behaviour of the service
 func (cs *DefaultCustomerCartService) GetModifyBehaviour(ctx context.Context, _ auth.Identity) (cart.ModifyBehaviour, error) {
 	_, span := trace.StartSpan(ctx, "cart/DefaultCustomerCartService/GetModifyBehaviour")
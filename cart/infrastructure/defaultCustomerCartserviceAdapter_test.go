@@ -0,0 +1,119 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+
+	authMock "flamingo.me/flamingo/v3/core/auth/mock"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+)
+
+func TestDefaultCustomerCartService_NamedCarts(t *testing.T) {
+	t.Parallel()
+
+	newServiceWithStorage := func(storage CartStorage) *DefaultCustomerCartService {
+		cob := &DefaultCartBehaviour{}
+		cob.Inject(
+			storage,
+			nil,
+			flamingo.NullLogger{},
+			nil,
+			nil,
+			nil,
+			nil,
+		)
+
+		cs := &DefaultCustomerCartService{}
+		cs.Inject(cob, flamingo.NullLogger{})
+
+		return cs
+	}
+
+	newService := func() *DefaultCustomerCartService {
+		return newServiceWithStorage(newInMemoryStorage())
+	}
+
+	t.Run("create, list and rename carts", func(t *testing.T) {
+		t.Parallel()
+
+		cs := newService()
+		identity := &authMock.Identity{Sub: "customer-1"}
+
+		wishlist, err := cs.CreateCart(context.Background(), identity, "wishlist")
+		require.NoError(t, err)
+		assert.Equal(t, "wishlist", wishlist.Name)
+		assert.True(t, wishlist.BelongsToAuthenticatedUser)
+		assert.NotEqual(t, "customer-1", wishlist.ID)
+
+		carts, err := cs.ListCarts(context.Background(), identity)
+		require.NoError(t, err)
+		require.Len(t, carts, 2)
+		assert.Equal(t, "customer-1", carts[0].ID)
+		assert.Equal(t, wishlist.ID, carts[1].ID)
+
+		renamed, err := cs.RenameCart(context.Background(), identity, wishlist.ID, "saved for later")
+		require.NoError(t, err)
+		assert.Equal(t, "saved for later", renamed.Name)
+
+		got, err := cs.GetCart(context.Background(), identity, wishlist.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "saved for later", got.Name)
+
+		defaultCart, err := cs.GetCart(context.Background(), identity, "me")
+		require.NoError(t, err)
+		assert.Equal(t, "customer-1", defaultCart.ID)
+	})
+
+	t.Run("carts of other customers are not accessible", func(t *testing.T) {
+		t.Parallel()
+
+		cs := newService()
+
+		wishlist, err := cs.CreateCart(context.Background(), &authMock.Identity{Sub: "customer-1"}, "wishlist")
+		require.NoError(t, err)
+
+		_, err = cs.GetCart(context.Background(), &authMock.Identity{Sub: "customer-2"}, wishlist.ID)
+		assert.ErrorIs(t, err, domaincart.ErrCartNotFound)
+
+		carts, err := cs.ListCarts(context.Background(), &authMock.Identity{Sub: "customer-2"})
+		require.NoError(t, err)
+		assert.Len(t, carts, 1)
+	})
+	t.Run("named carts are found by every service sharing the storage", func(t *testing.T) {
+		t.Parallel()
+
+		storage := newInMemoryStorage()
+		identity := &authMock.Identity{Sub: "customer-1"}
+
+		wishlist, err := newServiceWithStorage(storage).CreateCart(context.Background(), identity, "wishlist")
+		require.NoError(t, err)
+
+		savedForLater, err := newServiceWithStorage(storage).CreateCart(context.Background(), identity, "saved for later")
+		require.NoError(t, err)
+
+		carts, err := newServiceWithStorage(storage).ListCarts(context.Background(), identity)
+		require.NoError(t, err)
+		require.Len(t, carts, 3)
+		assert.Equal(t, "customer-1", carts[0].ID)
+		assert.ElementsMatch(t, []string{wishlist.ID, savedForLater.ID}, []string{carts[1].ID, carts[2].ID})
+	})
+
+	t.Run("storage without customer cart index", func(t *testing.T) {
+		t.Parallel()
+
+		cs := newServiceWithStorage(struct{ CartStorage }{newInMemoryStorage()})
+		identity := &authMock.Identity{Sub: "customer-1"}
+
+		_, err := cs.CreateCart(context.Background(), identity, "wishlist")
+		assert.ErrorIs(t, err, domaincart.ErrNamedCartsNotSupported)
+
+		carts, err := cs.ListCarts(context.Background(), identity)
+		require.NoError(t, err)
+		require.Len(t, carts, 1)
+		assert.Equal(t, "customer-1", carts[0].ID)
+	})
+}
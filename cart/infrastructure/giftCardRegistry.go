@@ -0,0 +1,326 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+type (
+	// InMemoryGiftCardRegistry keeps the gift card balances in memory, the gift cards are configured
+	// via commerce.cart.defaultCartAdapter.giftCards.cards. Since the balances are lost on restart we recommend
+	// the usage only for demo / testing.
+	InMemoryGiftCardRegistry struct {
+		ledger giftCardLedger
+	}
+
+	// FileGiftCardRegistry keeps the gift card balances in a JSON file, the configured gift cards are used
+	// to create the file if it does not exist yet
+	FileGiftCardRegistry struct {
+		ledger giftCardLedger
+		file   string
+	}
+
+	giftCardLedger struct {
+		mu    sync.Mutex
+		cards map[string]*giftCardEntry
+		// save is called after every modification, the modification is reverted if save fails
+		save func(cards map[string]*giftCardEntry) error
+	}
+
+	giftCardEntry struct {
+		Code string `json:"code"`
+		// Balance is the available balance, reserved amounts are already subtracted
+		Balance      priceDomain.Price            `json:"balance"`
+		ValidUntil   time.Time                    `json:"validUntil"`
+		Reservations map[string]priceDomain.Price `json:"reservations,omitempty"`
+	}
+
+	giftCardConfig struct {
+		Code     string  `json:"code"`
+		Balance  float64 `json:"balance"`
+		Currency string  `json:"currency"`
+		// ValidUntil is an optional RFC 3339 date time
+		ValidUntil string `json:"validUntil"`
+	}
+)
+
+var (
+	_ domaincart.GiftCardRegistry = (*InMemoryGiftCardRegistry)(nil)
+	_ domaincart.GiftCardRegistry = (*FileGiftCardRegistry)(nil)
+)
+
+// Inject dependencies
+func (r *InMemoryGiftCardRegistry) Inject(
+	logger flamingo.Logger,
+	cfg *struct {
+		Cards config.Slice `inject:"config:commerce.cart.defaultCartAdapter.giftCards.cards,optional"`
+	},
+) *InMemoryGiftCardRegistry {
+	r.ledger.cards = make(map[string]*giftCardEntry)
+
+	if cfg != nil {
+		r.ledger.cards = configuredGiftCards(logger, cfg.Cards)
+	}
+
+	return r
+}
+
+// GiftCard returns the gift card with its available balance
+func (r *InMemoryGiftCardRegistry) GiftCard(_ context.Context, code string) (*domaincart.GiftCard, error) {
+	return r.ledger.giftCard(code)
+}
+
+// Reserve subtracts the amount from the balance of the gift card
+func (r *InMemoryGiftCardRegistry) Reserve(_ context.Context, code string, reference string, amount priceDomain.Price) error {
+	return r.ledger.reserve(code, reference, amount)
+}
+
+// Release adds the amount reserved for the reference back to the balance of the gift card
+func (r *InMemoryGiftCardRegistry) Release(_ context.Context, code string, reference string) error {
+	return r.ledger.release(code, reference)
+}
+
+// Inject dependencies
+func (r *FileGiftCardRegistry) Inject(
+	logger flamingo.Logger,
+	cfg *struct {
+		File  string       `inject:"config:commerce.cart.defaultCartAdapter.giftCards.file"`
+		Cards config.Slice `inject:"config:commerce.cart.defaultCartAdapter.giftCards.cards,optional"`
+	},
+) *FileGiftCardRegistry {
+	logger = logger.WithField(flamingo.LogKeyCategory, "FileGiftCardRegistry")
+	r.ledger.cards = make(map[string]*giftCardEntry)
+	r.ledger.save = r.write
+
+	if cfg == nil {
+		return r
+	}
+
+	r.file = cfg.File
+
+	cards, err := r.read()
+	if err == nil {
+		r.ledger.cards = cards
+
+		return r
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		logger.Error(fmt.Errorf("failed to read gift card file %q: %w", r.file, err))
+
+		return r
+	}
+
+	r.ledger.cards = configuredGiftCards(logger, cfg.Cards)
+	if err := r.write(r.ledger.cards); err != nil {
+		logger.Error(fmt.Errorf("failed to create gift card file %q: %w", r.file, err))
+	}
+
+	return r
+}
+
+// GiftCard returns the gift card with its available balance
+func (r *FileGiftCardRegistry) GiftCard(_ context.Context, code string) (*domaincart.GiftCard, error) {
+	return r.ledger.giftCard(code)
+}
+
+// Reserve subtracts the amount from the balance of the gift card and writes the file
+func (r *FileGiftCardRegistry) Reserve(_ context.Context, code string, reference string, amount priceDomain.Price) error {
+	return r.ledger.reserve(code, reference, amount)
+}
+
+// Release adds the amount reserved for the reference back to the balance of the gift card and writes the file
+func (r *FileGiftCardRegistry) Release(_ context.Context, code string, reference string) error {
+	return r.ledger.release(code, reference)
+}
+
+func (r *FileGiftCardRegistry) read() (map[string]*giftCardEntry, error) {
+	data, err := os.ReadFile(r.file)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*giftCardEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	cards := make(map[string]*giftCardEntry, len(entries))
+	for _, entry := range entries {
+		cards[entry.Code] = entry
+	}
+
+	return cards, nil
+}
+
+// write replaces the file atomically, so that a crash never leaves a partially written file
+func (r *FileGiftCardRegistry) write(cards map[string]*giftCardEntry) error {
+	entries := make([]*giftCardEntry, 0, len(cards))
+	for _, entry := range cards {
+		entries = append(entries, entry)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.file), filepath.Base(r.file)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), r.file)
+}
+
+func configuredGiftCards(logger flamingo.Logger, cardsConfig config.Slice) map[string]*giftCardEntry {
+	cards := make(map[string]*giftCardEntry)
+
+	var configs []giftCardConfig
+	if err := cardsConfig.MapInto(&configs); err != nil {
+		logger.Error(fmt.Errorf("failed to map gift card config: %w", err))
+
+		return cards
+	}
+
+	for _, cardConfig := range configs {
+		entry := &giftCardEntry{
+			Code:    cardConfig.Code,
+			Balance: priceDomain.NewFromFloat(cardConfig.Balance, cardConfig.Currency).GetPayable(),
+		}
+
+		if cardConfig.ValidUntil != "" {
+			validUntil, err := time.Parse(time.RFC3339, cardConfig.ValidUntil)
+			if err != nil {
+				logger.Warn(fmt.Sprintf("gift card %q is skipped, invalid validUntil: %v", cardConfig.Code, err))
+
+				continue
+			}
+
+			entry.ValidUntil = validUntil
+		}
+
+		cards[entry.Code] = entry
+	}
+
+	return cards
+}
+
+func (l *giftCardLedger) giftCard(code string) (*domaincart.GiftCard, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, found := l.cards[code]
+	if !found {
+		return nil, fmt.Errorf("%w: %q", domaincart.ErrGiftCardNotFound, code)
+	}
+
+	return &domaincart.GiftCard{
+		Code:       entry.Code,
+		Balance:    entry.Balance,
+		ValidUntil: entry.ValidUntil,
+	}, nil
+}
+
+func (l *giftCardLedger) reserve(code string, reference string, amount priceDomain.Price) error {
+	return l.modify(code, func(entry *giftCardEntry) error {
+		if !(domaincart.GiftCard{ValidUntil: entry.ValidUntil}).IsValid(time.Now()) {
+			return fmt.Errorf("%w: %q", domaincart.ErrGiftCardExpired, code)
+		}
+
+		if entry.Balance.Currency() != amount.Currency() {
+			return fmt.Errorf("%w: %q", domaincart.ErrGiftCardCurrencyMismatch, code)
+		}
+
+		// a repeated reservation for the same reference replaces the previous one
+		balance := entry.Balance.ForceAdd(entry.Reservations[reference])
+
+		if amount.IsGreaterThen(balance) {
+			return fmt.Errorf("%w: %q", domaincart.ErrGiftCardInsufficientBalance, code)
+		}
+
+		newBalance, err := balance.Sub(amount)
+		if err != nil {
+			return err
+		}
+
+		entry.Balance = newBalance
+		entry.Reservations[reference] = amount
+
+		return nil
+	})
+}
+
+func (l *giftCardLedger) release(code string, reference string) error {
+	return l.modify(code, func(entry *giftCardEntry) error {
+		reserved, found := entry.Reservations[reference]
+		if !found {
+			return nil
+		}
+
+		entry.Balance = entry.Balance.ForceAdd(reserved)
+		delete(entry.Reservations, reference)
+
+		return nil
+	})
+}
+
+// modify applies the change to a copy of the gift card and keeps it only if it could be saved
+func (l *giftCardLedger) modify(code string, change func(entry *giftCardEntry) error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, found := l.cards[code]
+	if !found {
+		return fmt.Errorf("%w: %q", domaincart.ErrGiftCardNotFound, code)
+	}
+
+	modified := *entry
+	modified.Reservations = make(map[string]priceDomain.Price, len(entry.Reservations)+1)
+
+	for reference, amount := range entry.Reservations {
+		modified.Reservations[reference] = amount
+	}
+
+	if err := change(&modified); err != nil {
+		return err
+	}
+
+	l.cards[code] = &modified
+
+	if l.save == nil {
+		return nil
+	}
+
+	if err := l.save(l.cards); err != nil {
+		l.cards[code] = entry
+
+		return fmt.Errorf("failed to save gift card %q: %w", code, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package infrastructure
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+func giftCardTestConfig() config.Slice {
+	return config.Slice{
+		config.Map{"code": "gift-50", "balance": 50.0, "currency": "EUR"},
+		config.Map{"code": "gift-expired", "balance": 50.0, "currency": "EUR", "validUntil": "2000-01-01T00:00:00Z"},
+		config.Map{"code": "gift-invalid-date", "balance": 50.0, "currency": "EUR", "validUntil": "tomorrow"},
+	}
+}
+
+func newInMemoryGiftCardRegistry() *InMemoryGiftCardRegistry {
+	return new(InMemoryGiftCardRegistry).Inject(flamingo.NullLogger{}, &struct {
+		Cards config.Slice `inject:"config:commerce.cart.defaultCartAdapter.giftCards.cards,optional"`
+	}{Cards: giftCardTestConfig()})
+}
+
+func newFileGiftCardRegistry(file string) *FileGiftCardRegistry {
+	return new(FileGiftCardRegistry).Inject(flamingo.NullLogger{}, &struct {
+		File  string       `inject:"config:commerce.cart.defaultCartAdapter.giftCards.file"`
+		Cards config.Slice `inject:"config:commerce.cart.defaultCartAdapter.giftCards.cards,optional"`
+	}{File: file, Cards: giftCardTestConfig()})
+}
+
+func TestInMemoryGiftCardRegistry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("configured gift cards", func(t *testing.T) {
+		t.Parallel()
+
+		registry := newInMemoryGiftCardRegistry()
+
+		giftCard, err := registry.GiftCard(context.Background(), "gift-50")
+		require.NoError(t, err)
+		assert.Equal(t, 50.0, giftCard.Balance.FloatAmount())
+		assert.True(t, giftCard.ValidUntil.IsZero())
+
+		_, err = registry.GiftCard(context.Background(), "gift-invalid-date")
+		assert.ErrorIs(t, err, domaincart.ErrGiftCardNotFound)
+
+		_, err = registry.GiftCard(context.Background(), "unknown")
+		assert.ErrorIs(t, err, domaincart.ErrGiftCardNotFound)
+	})
+
+	t.Run("reserve and release", func(t *testing.T) {
+		t.Parallel()
+
+		registry := newInMemoryGiftCardRegistry()
+
+		require.NoError(t, registry.Reserve(context.Background(), "gift-50", "cart-1", priceDomain.NewFromFloat(20, "EUR")))
+		require.NoError(t, registry.Reserve(context.Background(), "gift-50", "cart-2", priceDomain.NewFromFloat(10, "EUR")))
+
+		giftCard, err := registry.GiftCard(context.Background(), "gift-50")
+		require.NoError(t, err)
+		assert.Equal(t, 20.0, giftCard.Balance.FloatAmount())
+
+		// a repeated reservation replaces the previous one
+		require.NoError(t, registry.Reserve(context.Background(), "gift-50", "cart-1", priceDomain.NewFromFloat(30, "EUR")))
+
+		giftCard, err = registry.GiftCard(context.Background(), "gift-50")
+		require.NoError(t, err)
+		assert.Equal(t, 10.0, giftCard.Balance.FloatAmount())
+
+		require.NoError(t, registry.Release(context.Background(), "gift-50", "cart-1"))
+		require.NoError(t, registry.Release(context.Background(), "gift-50", "cart-1"), "releasing twice is a no-op")
+
+		giftCard, err = registry.GiftCard(context.Background(), "gift-50")
+		require.NoError(t, err)
+		assert.Equal(t, 40.0, giftCard.Balance.FloatAmount())
+	})
+
+	t.Run("reservation errors", func(t *testing.T) {
+		t.Parallel()
+
+		registry := newInMemoryGiftCardRegistry()
+
+		err := registry.Reserve(context.Background(), "gift-50", "cart", priceDomain.NewFromFloat(50.01, "EUR"))
+		assert.ErrorIs(t, err, domaincart.ErrGiftCardInsufficientBalance)
+
+		err = registry.Reserve(context.Background(), "gift-50", "cart", priceDomain.NewFromFloat(10, "USD"))
+		assert.ErrorIs(t, err, domaincart.ErrGiftCardCurrencyMismatch)
+
+		err = registry.Reserve(context.Background(), "gift-expired", "cart", priceDomain.NewFromFloat(10, "EUR"))
+		assert.ErrorIs(t, err, domaincart.ErrGiftCardExpired)
+
+		err = registry.Release(context.Background(), "unknown", "cart")
+		assert.ErrorIs(t, err, domaincart.ErrGiftCardNotFound)
+
+		giftCard, err := registry.GiftCard(context.Background(), "gift-50")
+		require.NoError(t, err)
+		assert.Equal(t, 50.0, giftCard.Balance.FloatAmount(), "failed reservations must not change the balance")
+	})
+}
+
+func TestFileGiftCardRegistry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("balances survive a restart", func(t *testing.T) {
+		t.Parallel()
+
+		file := filepath.Join(t.TempDir(), "giftcards.json")
+
+		registry := newFileGiftCardRegistry(file)
+		require.FileExists(t, file)
+		require.NoError(t, registry.Reserve(context.Background(), "gift-50", "cart", priceDomain.NewFromFloat(15, "EUR")))
+
+		reloaded := newFileGiftCardRegistry(file)
+
+		giftCard, err := reloaded.GiftCard(context.Background(), "gift-50")
+		require.NoError(t, err)
+		assert.Equal(t, 35.0, giftCard.Balance.FloatAmount())
+
+		require.NoError(t, reloaded.Release(context.Background(), "gift-50", "cart"))
+
+		giftCard, err = newFileGiftCardRegistry(file).GiftCard(context.Background(), "gift-50")
+		require.NoError(t, err)
+		assert.Equal(t, 50.0, giftCard.Balance.FloatAmount())
+	})
+
+	t.Run("modification is reverted if the file can't be written", func(t *testing.T) {
+		t.Parallel()
+
+		registry := newFileGiftCardRegistry(filepath.Join(t.TempDir(), "giftcards.json"))
+		registry.file = filepath.Join(t.TempDir(), "missing", "giftcards.json")
+
+		err := registry.Reserve(context.Background(), "gift-50", "cart", priceDomain.NewFromFloat(15, "EUR"))
+		assert.Error(t, err)
+
+		giftCard, err := registry.GiftCard(context.Background(), "gift-50")
+		require.NoError(t, err)
+		assert.Equal(t, 50.0, giftCard.Balance.FloatAmount())
+	})
+}
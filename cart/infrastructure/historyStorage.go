@@ -0,0 +1,220 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"flamingo.me/flamingo/v3/core/healthcheck/domain/healthcheck"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/gomodule/redigo/redis"
+	"go.opencensus.io/trace"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+)
+
+type (
+	// InMemoryHistoryStorage keeps the latest history entries of each cart in memory, for development and single instance setups
+	InMemoryHistoryStorage struct {
+		mutex      sync.RWMutex
+		entries    map[string][]domaincart.HistoryEntry
+		maxEntries int
+	}
+
+	// RedisHistoryStorage stores the latest history entries of each cart as gob encoded list in redis,
+	// the history expires after the configured ttl without modifications
+	RedisHistoryStorage struct {
+		pool       *redis.Pool
+		logger     flamingo.Logger
+		maxEntries int
+		ttl        time.Duration
+		keyPrefix  string
+	}
+)
+
+var (
+	_ domaincart.HistoryStorage = &InMemoryHistoryStorage{}
+	_ domaincart.HistoryStorage = &RedisHistoryStorage{}
+	_ healthcheck.Status        = &RedisHistoryStorage{}
+)
+
+// Inject dependencies
+func (s *InMemoryHistoryStorage) Inject(
+	cfg *struct {
+		MaxEntries float64 `inject:"config:commerce.cart.history.maxEntries,optional"`
+	},
+) *InMemoryHistoryStorage {
+	s.entries = make(map[string][]domaincart.HistoryEntry)
+
+	if cfg != nil {
+		s.maxEntries = int(cfg.MaxEntries)
+	}
+
+	return s
+}
+
+// AppendEntry adds the entry to the history of the cart and drops the oldest entries exceeding commerce.cart.history.maxEntries
+func (s *InMemoryHistoryStorage) AppendEntry(ctx context.Context, entry domaincart.HistoryEntry) error {
+	_, span := trace.StartSpan(ctx, "cart/InMemoryHistoryStorage/AppendEntry")
+	defer span.End()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entries := append(s.entries[entry.CartID], entry)
+	if s.maxEntries > 0 && len(entries) > s.maxEntries {
+		entries = entries[len(entries)-s.maxEntries:]
+	}
+
+	s.entries[entry.CartID] = entries
+
+	return nil
+}
+
+// GetEntries returns the history of the cart, oldest entry first
+func (s *InMemoryHistoryStorage) GetEntries(ctx context.Context, cartID string) ([]domaincart.HistoryEntry, error) {
+	_, span := trace.StartSpan(ctx, "cart/InMemoryHistoryStorage/GetEntries")
+	defer span.End()
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	entries := make([]domaincart.HistoryEntry, len(s.entries[cartID]))
+	copy(entries, s.entries[cartID])
+
+	return entries, nil
+}
+
+// Inject dependencies
+func (r *RedisHistoryStorage) Inject(
+	logger flamingo.Logger,
+	cfg *struct {
+		MaxEntries              float64 `inject:"config:commerce.cart.history.maxEntries,optional"`
+		MaxIdle                 int     `inject:"config:commerce.cart.history.redis.maxIdle"`
+		IdleTimeoutMilliseconds int     `inject:"config:commerce.cart.history.redis.idleTimeoutMilliseconds"`
+		Network                 string  `inject:"config:commerce.cart.history.redis.network"`
+		Address                 string  `inject:"config:commerce.cart.history.redis.address"`
+		Database                int     `inject:"config:commerce.cart.history.redis.database"`
+		Username                string  `inject:"config:commerce.cart.history.redis.username,optional"`
+		Password                string  `inject:"config:commerce.cart.history.redis.password,optional"`
+		UseTLS                  bool    `inject:"config:commerce.cart.history.redis.useTLS,optional"`
+		TTL                     string  `inject:"config:commerce.cart.history.redis.ttl"`
+		KeyPrefix               string  `inject:"config:commerce.cart.history.redis.keyPrefix"`
+	},
+) *RedisHistoryStorage {
+	r.logger = logger.WithField(flamingo.LogKeyModule, "cart").WithField(flamingo.LogKeyCategory, "RedisHistoryStorage")
+
+	if cfg == nil {
+		return r
+	}
+
+	var err error
+	r.ttl, err = time.ParseDuration(cfg.TTL)
+	if err != nil {
+		panic("can't parse commerce.cart.history.redis.ttl")
+	}
+
+	r.maxEntries = int(cfg.MaxEntries)
+	r.keyPrefix = cfg.KeyPrefix
+
+	r.pool = newRedisPool(redisPoolConfig{
+		MaxIdle:                 cfg.MaxIdle,
+		IdleTimeoutMilliseconds: cfg.IdleTimeoutMilliseconds,
+		Network:                 cfg.Network,
+		Address:                 cfg.Address,
+		Database:                cfg.Database,
+		Username:                cfg.Username,
+		Password:                cfg.Password,
+		UseTLS:                  cfg.UseTLS,
+	})
+	runtime.SetFinalizer(r, func(r *RedisHistoryStorage) { r.pool.Close() }) // close all connections on destruction
+
+	return r
+}
+
+// AppendEntry pushes the entry to the history list of the cart, trims the list to commerce.cart.history.maxEntries and resets its expiry
+func (r *RedisHistoryStorage) AppendEntry(ctx context.Context, entry domaincart.HistoryEntry) error {
+	_, span := trace.StartSpan(ctx, "cart/RedisHistoryStorage/AppendEntry")
+	defer span.End()
+
+	conn := r.pool.Get()
+	defer conn.Close()
+	if conn.Err() != nil {
+		r.logger.WithContext(ctx).Error("AppendEntry:", conn.Err())
+		return ErrNoRedisConnection
+	}
+
+	buffer := new(bytes.Buffer)
+	err := gob.NewEncoder(buffer).Encode(entry)
+	if err != nil {
+		return fmt.Errorf("RedisHistoryStorage: error encoding history entry of cart %q: %w", entry.CartID, err)
+	}
+
+	key := r.key(entry.CartID)
+
+	_ = conn.Send("MULTI")
+	_ = conn.Send("RPUSH", key, buffer.Bytes())
+	if r.maxEntries > 0 {
+		_ = conn.Send("LTRIM", key, -r.maxEntries, -1)
+	}
+	_ = conn.Send("EXPIRE", key, int(r.ttl.Round(time.Second).Seconds()))
+
+	_, err = conn.Do("EXEC")
+	if err != nil {
+		return fmt.Errorf("RedisHistoryStorage: error saving history entry of cart %q: %w", entry.CartID, err)
+	}
+
+	return nil
+}
+
+// GetEntries returns the history of the cart, oldest entry first
+func (r *RedisHistoryStorage) GetEntries(ctx context.Context, cartID string) ([]domaincart.HistoryEntry, error) {
+	_, span := trace.StartSpan(ctx, "cart/RedisHistoryStorage/GetEntries")
+	defer span.End()
+
+	conn := r.pool.Get()
+	defer conn.Close()
+	if conn.Err() != nil {
+		r.logger.WithContext(ctx).Error("GetEntries:", conn.Err())
+		return nil, ErrNoRedisConnection
+	}
+
+	values, err := redis.ByteSlices(conn.Do("LRANGE", r.key(cartID), 0, -1))
+	if err != nil {
+		return nil, fmt.Errorf("RedisHistoryStorage: error loading history of cart %q: %w", cartID, err)
+	}
+
+	entries := make([]domaincart.HistoryEntry, 0, len(values))
+	for _, value := range values {
+		var entry domaincart.HistoryEntry
+		err = gob.NewDecoder(bytes.NewBuffer(value)).Decode(&entry)
+		if err != nil {
+			return nil, fmt.Errorf("RedisHistoryStorage: history entry of cart %q is not decodable: %w", cartID, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Status handles the health check of redis
+func (r *RedisHistoryStorage) Status() (alive bool, details string) {
+	conn := r.pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("PING")
+	if err != nil {
+		return false, err.Error()
+	}
+
+	return true, "redis for cart history replies to PING"
+}
+
+func (r *RedisHistoryStorage) key(cartID string) string {
+	return r.keyPrefix + cartID
+}
@@ -0,0 +1,94 @@
+package infrastructure_test
+
+import (
+	"context"
+	"testing"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/infrastructure"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+func historyTestEntry(cartID string, revision int) domaincart.HistoryEntry {
+	return domaincart.NewHistoryEntry(
+		&domaincart.Cart{ID: cartID, Revision: revision, GrandTotal: priceDomain.NewFromFloat(9.99, "EUR")},
+		"AddProduct",
+		map[string]string{"marketplaceCode": "code-1"},
+		domaincart.HistoryActor{Type: domaincart.HistoryActorCustomer, ID: "customer-1"},
+		nil,
+	)
+}
+
+func historyRevisions(entries []domaincart.HistoryEntry) []int {
+	revisions := make([]int, 0, len(entries))
+	for _, entry := range entries {
+		revisions = append(revisions, entry.Revision)
+	}
+
+	return revisions
+}
+
+func TestInMemoryHistoryStorage(t *testing.T) {
+	t.Parallel()
+
+	storage := new(infrastructure.InMemoryHistoryStorage).Inject(&struct {
+		MaxEntries float64 `inject:"config:commerce.cart.history.maxEntries,optional"`
+	}{MaxEntries: 3})
+
+	ctx := context.Background()
+	for revision := 1; revision <= 4; revision++ {
+		require.NoError(t, storage.AppendEntry(ctx, historyTestEntry("cart-1", revision)))
+	}
+
+	require.NoError(t, storage.AppendEntry(ctx, historyTestEntry("cart-2", 1)))
+
+	entries, err := storage.GetEntries(ctx, "cart-1")
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 3, 4}, historyRevisions(entries), "only the latest entries are kept")
+
+	entries[0].Operation = "changed"
+	stored, err := storage.GetEntries(ctx, "cart-1")
+	require.NoError(t, err)
+	assert.Equal(t, "AddProduct", stored[0].Operation, "the stored history can't be changed")
+
+	entries, err = storage.GetEntries(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestRedisHistoryStorage(t *testing.T) {
+	server, _ := startUpLocalCartRedis(t)
+
+	storage := new(infrastructure.RedisHistoryStorage).Inject(new(flamingo.NullLogger), &struct {
+		MaxEntries              float64 `inject:"config:commerce.cart.history.maxEntries,optional"`
+		MaxIdle                 int     `inject:"config:commerce.cart.history.redis.maxIdle"`
+		IdleTimeoutMilliseconds int     `inject:"config:commerce.cart.history.redis.idleTimeoutMilliseconds"`
+		Network                 string  `inject:"config:commerce.cart.history.redis.network"`
+		Address                 string  `inject:"config:commerce.cart.history.redis.address"`
+		Database                int     `inject:"config:commerce.cart.history.redis.database"`
+		Username                string  `inject:"config:commerce.cart.history.redis.username,optional"`
+		Password                string  `inject:"config:commerce.cart.history.redis.password,optional"`
+		UseTLS                  bool    `inject:"config:commerce.cart.history.redis.useTLS,optional"`
+		TTL                     string  `inject:"config:commerce.cart.history.redis.ttl"`
+		KeyPrefix               string  `inject:"config:commerce.cart.history.redis.keyPrefix"`
+	}{MaxEntries: 3, MaxIdle: 3, IdleTimeoutMilliseconds: 240000, Network: "unix", Address: server.Socket(), TTL: "1h", KeyPrefix: "cartHistory:"})
+
+	ctx := context.Background()
+	for revision := 1; revision <= 4; revision++ {
+		require.NoError(t, storage.AppendEntry(ctx, historyTestEntry("cart-1", revision)))
+	}
+
+	entries, err := storage.GetEntries(ctx, "cart-1")
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 3, 4}, historyRevisions(entries))
+	assert.Equal(t, domaincart.HistoryActor{Type: domaincart.HistoryActorCustomer, ID: "customer-1"}, entries[0].Actor)
+	assert.True(t, entries[0].Totals.GrandTotal.Equal(priceDomain.NewFromFloat(9.99, "EUR")))
+
+	entries, err = storage.GetEntries(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -3,6 +3,7 @@ package infrastructure
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 
 	"go.opencensus.io/trace"
@@ -14,17 +15,24 @@ type (
 	// InMemoryCartStorage - for now the default implementation of GuestCartStorage
 	InMemoryCartStorage struct {
 		guestCarts map[string]*domaincart.Cart
-		locker     sync.Locker
+		// customerCarts indexes the ids of the carts by their AuthenticatedUserID
+		customerCarts map[string]map[string]struct{}
+		locker        sync.Locker
 	}
 )
 
-var _ CartStorage = &InMemoryCartStorage{}
+var (
+	_ CartStorage         = &InMemoryCartStorage{}
+	_ IterableCartStorage = &InMemoryCartStorage{}
+	_ CustomerCartIndex   = &InMemoryCartStorage{}
+)
 
 // Inject dependencies and prepare storage
 // Important: InMemoryStorage MUST be bound AsEagerSingleton, Inject MUST be called in tests to behave as expected
 func (s *InMemoryCartStorage) Inject() *InMemoryCartStorage {
 	s.locker = &sync.Mutex{}
 	s.guestCarts = make(map[string]*domaincart.Cart)
+	s.customerCarts = make(map[string]map[string]struct{})
 
 	return s
 }
@@ -57,7 +65,7 @@ func (s *InMemoryCartStorage) GetCart(ctx context.Context, id string) (*domainca
 	return nil, errors.New("no cart stored")
 }
 
-// StoreCart stores a cart in the storage
+// StoreCart stores a cart in the storage if it is based on the currently stored revision
 func (s *InMemoryCartStorage) StoreCart(ctx context.Context, cart *domaincart.Cart) error {
 	_, span := trace.StartSpan(ctx, "cart/InMemoryCartStorage/StoreCart")
 	defer span.End()
@@ -65,7 +73,17 @@ func (s *InMemoryCartStorage) StoreCart(ctx context.Context, cart *domaincart.Ca
 	s.locker.Lock()
 	defer s.locker.Unlock()
 
+	if storedCart, ok := s.guestCarts[cart.ID]; ok {
+		if err := checkRevision(storedCart.Revision, cart); err != nil {
+			return err
+		}
+
+		s.unindex(storedCart)
+	}
+
 	s.guestCarts[cart.ID] = cart
+	s.index(cart)
+
 	return nil
 }
 
@@ -77,6 +95,72 @@ func (s *InMemoryCartStorage) RemoveCart(ctx context.Context, cart *domaincart.C
 	s.locker.Lock()
 	defer s.locker.Unlock()
 
+	if storedCart, ok := s.guestCarts[cart.ID]; ok {
+		s.unindex(storedCart)
+	}
+
 	delete(s.guestCarts, cart.ID)
 	return nil
 }
+
+// ForEachCart calls fn with a copy of every stored cart, so that fn (e.g. an event subscriber) can't modify the stored cart
+func (s *InMemoryCartStorage) ForEachCart(ctx context.Context, fn func(cart *domaincart.Cart) error) error {
+	_, span := trace.StartSpan(ctx, "cart/InMemoryCartStorage/ForEachCart")
+	defer span.End()
+
+	s.locker.Lock()
+	carts := make([]*domaincart.Cart, 0, len(s.guestCarts))
+	for _, cart := range s.guestCarts {
+		carts = append(carts, cart)
+	}
+	s.locker.Unlock()
+
+	for _, cart := range carts {
+		clone, err := cart.Clone()
+		if err != nil {
+			return fmt.Errorf("InMemoryCartStorage: error cloning cart %q: %w", cart.ID, err)
+		}
+
+		if err := fn(&clone); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CartIDsOfCustomer returns the ids of the stored carts of the customer
+func (s *InMemoryCartStorage) CartIDsOfCustomer(ctx context.Context, customerID string) ([]string, error) {
+	_, span := trace.StartSpan(ctx, "cart/InMemoryCartStorage/CartIDsOfCustomer")
+	defer span.End()
+
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	ids := make([]string, 0, len(s.customerCarts[customerID]))
+	for id := range s.customerCarts[customerID] {
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (s *InMemoryCartStorage) index(cart *domaincart.Cart) {
+	if cart.AuthenticatedUserID == "" {
+		return
+	}
+
+	if s.customerCarts[cart.AuthenticatedUserID] == nil {
+		s.customerCarts[cart.AuthenticatedUserID] = make(map[string]struct{})
+	}
+
+	s.customerCarts[cart.AuthenticatedUserID][cart.ID] = struct{}{}
+}
+
+func (s *InMemoryCartStorage) unindex(cart *domaincart.Cart) {
+	delete(s.customerCarts[cart.AuthenticatedUserID], cart.ID)
+
+	if len(s.customerCarts[cart.AuthenticatedUserID]) == 0 {
+		delete(s.customerCarts, cart.AuthenticatedUserID)
+	}
+}
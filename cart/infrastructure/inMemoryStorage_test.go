@@ -0,0 +1,54 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+)
+
+func TestInMemoryCartStorage_ForEachCart(t *testing.T) {
+	t.Parallel()
+
+	storage := newInMemoryStorage()
+	require.NoError(t, storage.StoreCart(context.Background(), &domaincart.Cart{ID: "cart-1", Revision: 1, Name: "stored"}))
+
+	require.NoError(t, storage.ForEachCart(context.Background(), func(cart *domaincart.Cart) error {
+		cart.Name = "modified"
+		cart.Revision = 5
+
+		return nil
+	}))
+
+	stored, err := storage.GetCart(context.Background(), "cart-1")
+	require.NoError(t, err)
+	assert.Equal(t, "stored", stored.Name, "the stored cart is not modified via the iterated cart")
+	assert.Equal(t, 1, stored.Revision)
+}
+
+func TestInMemoryCartStorage_CartIDsOfCustomer(t *testing.T) {
+	t.Parallel()
+
+	storage := newInMemoryStorage()
+	require.NoError(t, storage.StoreCart(context.Background(), &domaincart.Cart{ID: "customer-1", Revision: 1, AuthenticatedUserID: "customer-1"}))
+	require.NoError(t, storage.StoreCart(context.Background(), &domaincart.Cart{ID: "customer-1-2", Revision: 1, AuthenticatedUserID: "customer-1"}))
+	require.NoError(t, storage.StoreCart(context.Background(), &domaincart.Cart{ID: "customer-2", Revision: 1, AuthenticatedUserID: "customer-2"}))
+	require.NoError(t, storage.StoreCart(context.Background(), &domaincart.Cart{ID: "guest", Revision: 1}))
+
+	ids, err := storage.CartIDsOfCustomer(context.Background(), "customer-1")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"customer-1", "customer-1-2"}, ids)
+
+	require.NoError(t, storage.RemoveCart(context.Background(), &domaincart.Cart{ID: "customer-1-2"}))
+
+	ids, err = storage.CartIDsOfCustomer(context.Background(), "customer-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"customer-1"}, ids)
+
+	ids, err = storage.CartIDsOfCustomer(context.Background(), "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
@@ -0,0 +1,495 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"go.opencensus.io/trace"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+type (
+	// PromotionEngine is a rule based VoucherHandler for the default cart adapter.
+	// Rules without coupon code are applied automatically, rules with coupon code only if the coupon is applied to the cart.
+	PromotionEngine struct {
+		logger flamingo.Logger
+		rules  []PromotionRule
+	}
+
+	// PromotionRule configures a single promotion of the PromotionEngine
+	PromotionRule struct {
+		// Code is used as campaign code of the applied discounts
+		Code  string `json:"code"`
+		Label string `json:"label"`
+		// Type is one of the PromotionType constants
+		Type string `json:"type"`
+		// CouponCode is optional, if set the rule is only applied if the coupon is applied to the cart
+		CouponCode string `json:"couponCode"`
+		// SortOrder defines the order in which the rules are applied, low values first
+		SortOrder int `json:"sortOrder"`
+		// MarketplaceCodes restricts the rule to these products, all products are discounted if empty
+		MarketplaceCodes []string `json:"marketplaceCodes"`
+		// Percentage is the discount in percent for percentage item discounts and percentage cart threshold discounts
+		Percentage float64 `json:"percentage"`
+		// Amount is the fixed discount per unit for fixed item discounts and the fixed discount for cart threshold discounts
+		Amount float64 `json:"amount"`
+		// Buy and Pay define the buy-X-pay-Y discount, e.g. buy 3 pay 2
+		Buy int `json:"buy"`
+		Pay int `json:"pay"`
+		// Threshold is the minimum gross sub total of the cart for the rule to be applied
+		Threshold float64 `json:"threshold"`
+	}
+)
+
+const (
+	// PromotionTypePercentageItemDiscount reduces the price of the matching items by a percentage
+	PromotionTypePercentageItemDiscount = "percentageItemDiscount"
+	// PromotionTypeFixedItemDiscount reduces the price of every unit of the matching items by a fixed amount
+	PromotionTypeFixedItemDiscount = "fixedItemDiscount"
+	// PromotionTypeBuyXPayY makes every Buy-Pay units out of Buy units of the matching items free
+	PromotionTypeBuyXPayY = "buyXPayY"
+	// PromotionTypeFreeShipping removes the shipping costs of all deliveries
+	PromotionTypeFreeShipping = "freeShipping"
+	// PromotionTypeCartThreshold reduces the cart by a percentage or a fixed amount, which is spread over the matching items
+	PromotionTypeCartThreshold = "cartThreshold"
+)
+
+var (
+	_ VoucherHandler     = (*PromotionEngine)(nil)
+	_ DiscountCalculator = (*PromotionEngine)(nil)
+
+	// ErrInvalidCouponCode is returned if no promotion rule exists for the coupon code
+	ErrInvalidCouponCode = errors.New("voucher code invalid")
+	// ErrCouponCodeNotApplied is returned if a coupon code should be removed which is not applied to the cart
+	ErrCouponCodeNotApplied = errors.New("voucher code not applied")
+)
+
+// Inject dependencies
+func (e *PromotionEngine) Inject(
+	logger flamingo.Logger,
+	cfg *struct {
+		Rules config.Slice `inject:"config:commerce.cart.defaultCartAdapter.promotions.rules,optional"`
+	},
+) *PromotionEngine {
+	e.logger = logger.WithField(flamingo.LogKeyCategory, "PromotionEngine")
+
+	if cfg != nil {
+		var rules []PromotionRule
+		if err := cfg.Rules.MapInto(&rules); err != nil {
+			e.logger.Error(fmt.Errorf("failed to map promotion rules: %w", err))
+		}
+
+		for _, rule := range rules {
+			if err := rule.validate(); err != nil {
+				e.logger.Warn(fmt.Sprintf("promotion rule %q is skipped: %v", rule.Code, err))
+
+				continue
+			}
+
+			e.rules = append(e.rules, rule)
+		}
+	}
+
+	sort.SliceStable(e.rules, func(i, j int) bool {
+		return e.rules[i].SortOrder < e.rules[j].SortOrder
+	})
+
+	return e
+}
+
+func (r PromotionRule) validate() error {
+	if r.Code == "" {
+		return errors.New("code missing")
+	}
+
+	switch r.Type {
+	case PromotionTypePercentageItemDiscount:
+		if r.Percentage <= 0 || r.Percentage > 100 {
+			return errors.New("percentage must be between 0 and 100")
+		}
+	case PromotionTypeFixedItemDiscount:
+		if r.Amount <= 0 {
+			return errors.New("amount must be positive")
+		}
+	case PromotionTypeBuyXPayY:
+		if r.Pay < 0 || r.Buy <= r.Pay {
+			return errors.New("buy must be greater than pay")
+		}
+	case PromotionTypeFreeShipping:
+	case PromotionTypeCartThreshold:
+		if (r.Percentage <= 0 || r.Percentage > 100) && r.Amount <= 0 {
+			return errors.New("percentage or amount required")
+		}
+	default:
+		return fmt.Errorf("unknown type %q", r.Type)
+	}
+
+	return nil
+}
+
+func (r PromotionRule) matchesItem(item domaincart.Item) bool {
+	if len(r.MarketplaceCodes) == 0 {
+		return true
+	}
+
+	for _, code := range r.MarketplaceCodes {
+		if code == item.MarketplaceCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r PromotionRule) isItemRelated() bool {
+	return r.Type != PromotionTypeFreeShipping && r.Type != PromotionTypeCartThreshold
+}
+
+func (r PromotionRule) discount(applied priceDomain.Price) domaincart.AppliedDiscount {
+	return domaincart.AppliedDiscount{
+		CampaignCode:  r.Code,
+		CouponCode:    r.CouponCode,
+		Label:         r.Label,
+		Applied:       applied.Inverse(),
+		Type:          r.Type,
+		IsItemRelated: r.isItemRelated(),
+		SortOrder:     r.SortOrder,
+	}
+}
+
+// ApplyVoucher applies the coupon code if a promotion rule exists for it and recalculates the discounts
+func (e *PromotionEngine) ApplyVoucher(ctx context.Context, cart *domaincart.Cart, couponCode string) (*domaincart.Cart, error) {
+	_, span := trace.StartSpan(ctx, "cart/PromotionEngine/ApplyVoucher")
+	defer span.End()
+
+	if !e.hasCouponCode(couponCode) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCouponCode, couponCode)
+	}
+
+	if !hasAppliedCouponCode(cart, couponCode) {
+		cart.AppliedCouponCodes = append(cart.AppliedCouponCodes, domaincart.CouponCode{Code: couponCode})
+	}
+
+	if err := e.CalculateDiscounts(cart); err != nil {
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+// RemoveVoucher removes the coupon code and recalculates the discounts
+func (e *PromotionEngine) RemoveVoucher(ctx context.Context, cart *domaincart.Cart, couponCode string) (*domaincart.Cart, error) {
+	_, span := trace.StartSpan(ctx, "cart/PromotionEngine/RemoveVoucher")
+	defer span.End()
+
+	if !hasAppliedCouponCode(cart, couponCode) {
+		return nil, fmt.Errorf("%w: %q", ErrCouponCodeNotApplied, couponCode)
+	}
+
+	couponCodes := make([]domaincart.CouponCode, 0, len(cart.AppliedCouponCodes))
+	for _, coupon := range cart.AppliedCouponCodes {
+		if coupon.Code != couponCode {
+			couponCodes = append(couponCodes, coupon)
+		}
+	}
+
+	cart.AppliedCouponCodes = couponCodes
+
+	if err := e.CalculateDiscounts(cart); err != nil {
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+func (e *PromotionEngine) hasCouponCode(couponCode string) bool {
+	for _, rule := range e.rules {
+		if rule.CouponCode != "" && rule.CouponCode == couponCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasAppliedCouponCode(cart *domaincart.Cart, couponCode string) bool {
+	for _, coupon := range cart.AppliedCouponCodes {
+		if coupon.Code == couponCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CalculateDiscounts replaces all discounts of the items and shipping items with the discounts of the active rules
+// and recalculates the discounted item and shipping prices
+func (e *PromotionEngine) CalculateDiscounts(cart *domaincart.Cart) error {
+	resetDiscounts(cart)
+
+	subTotal := priceDomain.NewZero(cart.DefaultCurrency)
+	for _, delivery := range cart.Deliveries {
+		for _, item := range delivery.Cartitems {
+			subTotal = subTotal.ForceAdd(item.RowPriceGross)
+		}
+	}
+
+	for _, rule := range e.rules {
+		if rule.CouponCode != "" && !hasAppliedCouponCode(cart, rule.CouponCode) {
+			continue
+		}
+
+		if rule.Threshold > 0 && subTotal.FloatAmount() < rule.Threshold {
+			continue
+		}
+
+		var err error
+
+		switch rule.Type {
+		case PromotionTypeFreeShipping:
+			applyFreeShipping(cart, rule)
+		case PromotionTypeCartThreshold:
+			err = applyCartDiscount(cart, rule)
+		default:
+			err = applyItemDiscounts(cart, rule)
+		}
+
+		if err != nil {
+			return fmt.Errorf("PromotionEngine: failed to apply rule %q: %w", rule.Code, err)
+		}
+	}
+
+	for d := range cart.Deliveries {
+		for i := range cart.Deliveries[d].Cartitems {
+			if err := calculateDiscountedItemPrices(&cart.Deliveries[d].Cartitems[i]); err != nil {
+				return fmt.Errorf("PromotionEngine: failed to calculate discounted prices: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func resetDiscounts(cart *domaincart.Cart) {
+	for d := range cart.Deliveries {
+		delivery := &cart.Deliveries[d]
+
+		delivery.ShippingItem.AppliedDiscounts = nil
+		delivery.ShippingItem.PriceGrossWithDiscounts = delivery.ShippingItem.PriceGross
+		delivery.ShippingItem.PriceNetWithDiscounts = delivery.ShippingItem.PriceNet
+
+		for i := range delivery.Cartitems {
+			delivery.Cartitems[i].AppliedDiscounts = nil
+		}
+	}
+}
+
+// remainingRowPrice is the row gross price reduced by the discounts applied so far
+func remainingRowPrice(item domaincart.Item) (priceDomain.Price, error) {
+	discounts, err := item.AppliedDiscounts.Sum()
+	if err != nil {
+		return priceDomain.Price{}, err
+	}
+
+	return item.RowPriceGross.Add(discounts)
+}
+
+func applyItemDiscounts(cart *domaincart.Cart, rule PromotionRule) error {
+	for d := range cart.Deliveries {
+		for i := range cart.Deliveries[d].Cartitems {
+			item := &cart.Deliveries[d].Cartitems[i]
+			if !rule.matchesItem(*item) {
+				continue
+			}
+
+			remaining, err := remainingRowPrice(*item)
+			if err != nil {
+				return err
+			}
+
+			var discount priceDomain.Price
+
+			switch rule.Type {
+			case PromotionTypePercentageItemDiscount:
+				discount, err = remaining.Sub(remaining.Discounted(rule.Percentage))
+			case PromotionTypeFixedItemDiscount:
+				discount = priceDomain.NewFromFloat(rule.Amount, remaining.Currency()).Multiply(item.Qty)
+			case PromotionTypeBuyXPayY:
+				discount = item.SinglePriceGross.Multiply((item.Qty / rule.Buy) * (rule.Buy - rule.Pay))
+			}
+
+			if err != nil {
+				return err
+			}
+
+			addItemDiscount(item, rule, discount.GetPayable(), remaining)
+		}
+	}
+
+	return nil
+}
+
+func applyCartDiscount(cart *domaincart.Cart, rule PromotionRule) error {
+	type itemWithRemaining struct {
+		item      *domaincart.Item
+		remaining priceDomain.Price
+	}
+
+	var items []itemWithRemaining
+
+	total := priceDomain.NewZero(cart.DefaultCurrency)
+
+	for d := range cart.Deliveries {
+		for i := range cart.Deliveries[d].Cartitems {
+			item := &cart.Deliveries[d].Cartitems[i]
+			if !rule.matchesItem(*item) {
+				continue
+			}
+
+			remaining, err := remainingRowPrice(*item)
+			if err != nil {
+				return err
+			}
+
+			if !remaining.IsPositive() {
+				continue
+			}
+
+			items = append(items, itemWithRemaining{item: item, remaining: remaining})
+			total = total.ForceAdd(remaining)
+		}
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	if rule.Amount <= 0 {
+		for _, i := range items {
+			discount, err := i.remaining.Sub(i.remaining.Discounted(rule.Percentage))
+			if err != nil {
+				return err
+			}
+
+			addItemDiscount(i.item, rule, discount.GetPayable(), i.remaining)
+		}
+
+		return nil
+	}
+
+	// the fixed amount is spread over the items by their share of the total, the last item gets the rounding difference
+	amount := priceDomain.NewFromFloat(rule.Amount, total.Currency())
+	if amount.IsGreaterThen(total) {
+		amount = total
+	}
+
+	left := amount
+	for index, i := range items {
+		share := left
+		if index < len(items)-1 {
+			ratio := new(big.Float).Quo(i.remaining.Amount(), total.Amount())
+			shareAmount := new(big.Float).Mul(amount.Amount(), ratio)
+			share = priceDomain.NewFromBigFloat(*shareAmount, amount.Currency()).GetPayable()
+		}
+
+		var err error
+
+		left, err = left.Sub(share)
+		if err != nil {
+			return err
+		}
+
+		addItemDiscount(i.item, rule, share, i.remaining)
+	}
+
+	return nil
+}
+
+// addItemDiscount adds the discount to the item, the discount is limited to the remaining row price
+func addItemDiscount(item *domaincart.Item, rule PromotionRule, discount priceDomain.Price, remaining priceDomain.Price) {
+	if discount.IsGreaterThen(remaining) {
+		discount = remaining
+	}
+
+	if !discount.IsPositive() {
+		return
+	}
+
+	item.AppliedDiscounts = append(item.AppliedDiscounts, rule.discount(discount))
+}
+
+func applyFreeShipping(cart *domaincart.Cart, rule PromotionRule) {
+	for d := range cart.Deliveries {
+		shippingItem := &cart.Deliveries[d].ShippingItem
+		if !shippingItem.PriceGrossWithDiscounts.IsPositive() {
+			continue
+		}
+
+		shippingItem.AppliedDiscounts = append(shippingItem.AppliedDiscounts, rule.discount(shippingItem.PriceGrossWithDiscounts))
+		shippingItem.PriceGrossWithDiscounts = priceDomain.NewZero(shippingItem.PriceGross.Currency())
+		shippingItem.PriceNetWithDiscounts = priceDomain.NewZero(shippingItem.PriceNet.Currency())
+	}
+}
+
+// calculateDiscountedItemPrices sums the applied discounts of the item and calculates the discounted row prices,
+// the net prices are reduced by the same ratio as the gross prices
+func calculateDiscountedItemPrices(item *domaincart.Item) error {
+	currency := item.RowPriceGross.Currency()
+	item.TotalDiscountAmount = priceDomain.NewZero(currency)
+	item.ItemRelatedDiscountAmount = priceDomain.NewZero(currency)
+	item.NonItemRelatedDiscountAmount = priceDomain.NewZero(currency)
+
+	for _, discount := range item.AppliedDiscounts {
+		var err error
+
+		item.TotalDiscountAmount, err = item.TotalDiscountAmount.Add(discount.Applied)
+		if err != nil {
+			return err
+		}
+
+		if discount.IsItemRelated {
+			item.ItemRelatedDiscountAmount, err = item.ItemRelatedDiscountAmount.Add(discount.Applied)
+		} else {
+			item.NonItemRelatedDiscountAmount, err = item.NonItemRelatedDiscountAmount.Add(discount.Applied)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	var err error
+
+	item.RowPriceGrossWithDiscount, err = item.RowPriceGross.Add(item.TotalDiscountAmount)
+	if err != nil {
+		return err
+	}
+
+	item.RowPriceGrossWithItemRelatedDiscount, err = item.RowPriceGross.Add(item.ItemRelatedDiscountAmount)
+	if err != nil {
+		return err
+	}
+
+	item.RowPriceNetWithDiscount = netForGross(*item, item.RowPriceGrossWithDiscount)
+	item.RowPriceNetWithItemRelatedDiscount = netForGross(*item, item.RowPriceGrossWithItemRelatedDiscount)
+
+	return nil
+}
+
+func netForGross(item domaincart.Item, gross priceDomain.Price) priceDomain.Price {
+	if item.RowPriceGross.IsZero() || gross.Equal(item.RowPriceGross) {
+		return item.RowPriceNet
+	}
+
+	ratio := new(big.Float).Quo(gross.Amount(), item.RowPriceGross.Amount())
+	net := new(big.Float).Mul(item.RowPriceNet.Amount(), ratio)
+
+	return priceDomain.NewFromBigFloat(*net, item.RowPriceNet.Currency()).GetPayable()
+}
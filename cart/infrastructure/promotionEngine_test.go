@@ -0,0 +1,252 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+func newPromotionEngine(rules ...config.Map) *PromotionEngine {
+	slice := make(config.Slice, 0, len(rules))
+	for _, rule := range rules {
+		slice = append(slice, rule)
+	}
+
+	return new(PromotionEngine).Inject(flamingo.NullLogger{}, &struct {
+		Rules config.Slice `inject:"config:commerce.cart.defaultCartAdapter.promotions.rules,optional"`
+	}{Rules: slice})
+}
+
+func promotionTestItem(id string, marketplaceCode string, qty int, singlePriceGross float64) domaincart.Item {
+	return domaincart.Item{
+		ID:               id,
+		MarketplaceCode:  marketplaceCode,
+		Qty:              qty,
+		SinglePriceGross: priceDomain.NewFromFloat(singlePriceGross, "EUR"),
+		SinglePriceNet:   priceDomain.NewFromFloat(singlePriceGross/2, "EUR"),
+		RowPriceGross:    priceDomain.NewFromFloat(singlePriceGross*float64(qty), "EUR"),
+		RowPriceNet:      priceDomain.NewFromFloat(singlePriceGross*float64(qty)/2, "EUR"),
+	}
+}
+
+func promotionTestCart(items ...domaincart.Item) *domaincart.Cart {
+	return &domaincart.Cart{
+		ID:              "cart",
+		DefaultCurrency: "EUR",
+		Deliveries: []domaincart.Delivery{
+			{
+				DeliveryInfo: domaincart.DeliveryInfo{Code: "delivery"},
+				Cartitems:    items,
+				ShippingItem: domaincart.ShippingItem{
+					PriceGross:              priceDomain.NewFromFloat(4.99, "EUR"),
+					PriceGrossWithDiscounts: priceDomain.NewFromFloat(4.99, "EUR"),
+					PriceNet:                priceDomain.NewFromFloat(4.19, "EUR"),
+					PriceNetWithDiscounts:   priceDomain.NewFromFloat(4.19, "EUR"),
+				},
+			},
+		},
+	}
+}
+
+func TestPromotionEngine_Inject(t *testing.T) {
+	t.Parallel()
+
+	engine := newPromotionEngine(
+		config.Map{"code": "second", "type": PromotionTypeFreeShipping, "sortOrder": 2.0},
+		config.Map{"code": "first", "type": PromotionTypePercentageItemDiscount, "percentage": 10.0, "sortOrder": 1.0},
+		config.Map{"code": "invalid-percentage", "type": PromotionTypePercentageItemDiscount, "percentage": 120.0},
+		config.Map{"code": "invalid-buy-x-pay-y", "type": PromotionTypeBuyXPayY, "buy": 2.0, "pay": 2.0},
+		config.Map{"code": "unknown-type", "type": "unknown"},
+	)
+
+	require.Len(t, engine.rules, 2)
+	assert.Equal(t, "first", engine.rules[0].Code)
+	assert.Equal(t, "second", engine.rules[1].Code)
+}
+
+func TestPromotionEngine_CalculateDiscounts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("percentage item discount for matching products", func(t *testing.T) {
+		t.Parallel()
+
+		engine := newPromotionEngine(config.Map{
+			"code":             "summer",
+			"label":            "Summer Sale",
+			"type":             PromotionTypePercentageItemDiscount,
+			"percentage":       10.0,
+			"marketplaceCodes": config.Slice{"a"},
+			"sortOrder":        3.0,
+		})
+
+		cart := promotionTestCart(promotionTestItem("1", "a", 2, 50), promotionTestItem("2", "b", 1, 20))
+		require.NoError(t, engine.CalculateDiscounts(cart))
+
+		item := cart.Deliveries[0].Cartitems[0]
+		require.Len(t, item.AppliedDiscounts, 1)
+		assert.Equal(t, "summer", item.AppliedDiscounts[0].CampaignCode)
+		assert.Equal(t, "Summer Sale", item.AppliedDiscounts[0].Label)
+		assert.Equal(t, -10.0, item.AppliedDiscounts[0].Applied.FloatAmount())
+		assert.Equal(t, PromotionTypePercentageItemDiscount, item.AppliedDiscounts[0].Type)
+		assert.True(t, item.AppliedDiscounts[0].IsItemRelated)
+		assert.Equal(t, 3, item.AppliedDiscounts[0].SortOrder)
+		assert.Equal(t, -10.0, item.TotalDiscountAmount.FloatAmount())
+		assert.Equal(t, -10.0, item.ItemRelatedDiscountAmount.FloatAmount())
+		assert.Equal(t, 90.0, item.RowPriceGrossWithDiscount.FloatAmount())
+		assert.Equal(t, 45.0, item.RowPriceNetWithDiscount.FloatAmount())
+		assert.Equal(t, 90.0, item.RowPriceGrossWithItemRelatedDiscount.FloatAmount())
+
+		assert.Empty(t, cart.Deliveries[0].Cartitems[1].AppliedDiscounts)
+		assert.Equal(t, 20.0, cart.Deliveries[0].Cartitems[1].RowPriceGrossWithDiscount.FloatAmount())
+	})
+
+	t.Run("fixed item discount is limited to the row price", func(t *testing.T) {
+		t.Parallel()
+
+		engine := newPromotionEngine(config.Map{"code": "fixed", "type": PromotionTypeFixedItemDiscount, "amount": 15.0})
+
+		cart := promotionTestCart(promotionTestItem("1", "a", 2, 50), promotionTestItem("2", "b", 1, 10))
+		require.NoError(t, engine.CalculateDiscounts(cart))
+
+		assert.Equal(t, -30.0, cart.Deliveries[0].Cartitems[0].TotalDiscountAmount.FloatAmount())
+		assert.Equal(t, -10.0, cart.Deliveries[0].Cartitems[1].TotalDiscountAmount.FloatAmount())
+		assert.True(t, cart.Deliveries[0].Cartitems[1].RowPriceGrossWithDiscount.IsZero())
+	})
+
+	t.Run("buy x pay y", func(t *testing.T) {
+		t.Parallel()
+
+		engine := newPromotionEngine(config.Map{"code": "3for2", "type": PromotionTypeBuyXPayY, "buy": 3.0, "pay": 2.0})
+
+		cart := promotionTestCart(promotionTestItem("1", "a", 7, 10), promotionTestItem("2", "b", 2, 10))
+		require.NoError(t, engine.CalculateDiscounts(cart))
+
+		assert.Equal(t, -20.0, cart.Deliveries[0].Cartitems[0].TotalDiscountAmount.FloatAmount())
+		assert.Empty(t, cart.Deliveries[0].Cartitems[1].AppliedDiscounts)
+	})
+
+	t.Run("free shipping with threshold", func(t *testing.T) {
+		t.Parallel()
+
+		engine := newPromotionEngine(config.Map{"code": "free-shipping", "type": PromotionTypeFreeShipping, "threshold": 50.0})
+
+		cart := promotionTestCart(promotionTestItem("1", "a", 1, 40))
+		require.NoError(t, engine.CalculateDiscounts(cart))
+		assert.Empty(t, cart.Deliveries[0].ShippingItem.AppliedDiscounts)
+		assert.Equal(t, 4.99, cart.Deliveries[0].ShippingItem.PriceGrossWithDiscounts.FloatAmount())
+
+		cart = promotionTestCart(promotionTestItem("1", "a", 2, 40))
+		require.NoError(t, engine.CalculateDiscounts(cart))
+		require.Len(t, cart.Deliveries[0].ShippingItem.AppliedDiscounts, 1)
+		assert.Equal(t, -4.99, cart.Deliveries[0].ShippingItem.AppliedDiscounts[0].Applied.FloatAmount())
+		assert.False(t, cart.Deliveries[0].ShippingItem.AppliedDiscounts[0].IsItemRelated)
+		assert.True(t, cart.Deliveries[0].ShippingItem.PriceGrossWithDiscounts.IsZero())
+		assert.True(t, cart.Deliveries[0].ShippingItem.PriceNetWithDiscounts.IsZero())
+	})
+
+	t.Run("cart threshold amount is spread over the items", func(t *testing.T) {
+		t.Parallel()
+
+		engine := newPromotionEngine(config.Map{"code": "ten-off", "type": PromotionTypeCartThreshold, "amount": 10.0, "threshold": 40.0})
+
+		cart := promotionTestCart(promotionTestItem("1", "a", 1, 30), promotionTestItem("2", "b", 1, 10))
+		require.NoError(t, engine.CalculateDiscounts(cart))
+
+		assert.Equal(t, -7.5, cart.Deliveries[0].Cartitems[0].NonItemRelatedDiscountAmount.FloatAmount())
+		assert.Equal(t, -2.5, cart.Deliveries[0].Cartitems[1].NonItemRelatedDiscountAmount.FloatAmount())
+		assert.False(t, cart.Deliveries[0].Cartitems[0].AppliedDiscounts[0].IsItemRelated)
+		assert.Equal(t, 30.0, cart.Deliveries[0].Cartitems[0].RowPriceGrossWithItemRelatedDiscount.FloatAmount())
+	})
+
+	t.Run("rules are applied in sort order on the remaining price", func(t *testing.T) {
+		t.Parallel()
+
+		engine := newPromotionEngine(
+			config.Map{"code": "percent", "type": PromotionTypeCartThreshold, "percentage": 50.0, "sortOrder": 2.0},
+			config.Map{"code": "fixed", "type": PromotionTypeFixedItemDiscount, "amount": 20.0, "sortOrder": 1.0},
+		)
+
+		cart := promotionTestCart(promotionTestItem("1", "a", 1, 100))
+		require.NoError(t, engine.CalculateDiscounts(cart))
+
+		item := cart.Deliveries[0].Cartitems[0]
+		require.Len(t, item.AppliedDiscounts, 2)
+		assert.Equal(t, "fixed", item.AppliedDiscounts[0].CampaignCode)
+		assert.Equal(t, -40.0, item.AppliedDiscounts[1].Applied.FloatAmount())
+		assert.Equal(t, 40.0, item.RowPriceGrossWithDiscount.FloatAmount())
+	})
+
+	t.Run("discounts are recalculated", func(t *testing.T) {
+		t.Parallel()
+
+		engine := newPromotionEngine(config.Map{"code": "3for2", "type": PromotionTypeBuyXPayY, "buy": 3.0, "pay": 2.0})
+
+		cart := promotionTestCart(promotionTestItem("1", "a", 3, 10))
+		require.NoError(t, engine.CalculateDiscounts(cart))
+		assert.Equal(t, -10.0, cart.Deliveries[0].Cartitems[0].TotalDiscountAmount.FloatAmount())
+
+		cart.Deliveries[0].Cartitems[0] = promotionTestItem("1", "a", 2, 10)
+		require.NoError(t, engine.CalculateDiscounts(cart))
+		assert.Empty(t, cart.Deliveries[0].Cartitems[0].AppliedDiscounts)
+		assert.True(t, cart.Deliveries[0].Cartitems[0].TotalDiscountAmount.IsZero())
+	})
+}
+
+func TestPromotionEngine_Vouchers(t *testing.T) {
+	t.Parallel()
+
+	engine := newPromotionEngine(config.Map{"code": "welcome", "type": PromotionTypePercentageItemDiscount, "percentage": 20.0, "couponCode": "WELCOME20"})
+
+	cart := promotionTestCart(promotionTestItem("1", "a", 1, 50))
+	require.NoError(t, engine.CalculateDiscounts(cart))
+	assert.Empty(t, cart.Deliveries[0].Cartitems[0].AppliedDiscounts)
+
+	_, err := engine.ApplyVoucher(context.Background(), cart, "UNKNOWN")
+	assert.ErrorIs(t, err, ErrInvalidCouponCode)
+
+	cart, err = engine.ApplyVoucher(context.Background(), cart, "WELCOME20")
+	require.NoError(t, err)
+	assert.Equal(t, []domaincart.CouponCode{{Code: "WELCOME20"}}, cart.AppliedCouponCodes)
+	require.Len(t, cart.Deliveries[0].Cartitems[0].AppliedDiscounts, 1)
+	assert.Equal(t, "WELCOME20", cart.Deliveries[0].Cartitems[0].AppliedDiscounts[0].CouponCode)
+	assert.Equal(t, 40.0, cart.Deliveries[0].Cartitems[0].RowPriceGrossWithDiscount.FloatAmount())
+
+	cart, err = engine.RemoveVoucher(context.Background(), cart, "WELCOME20")
+	require.NoError(t, err)
+	assert.Empty(t, cart.AppliedCouponCodes)
+	assert.Empty(t, cart.Deliveries[0].Cartitems[0].AppliedDiscounts)
+
+	_, err = engine.RemoveVoucher(context.Background(), cart, "WELCOME20")
+	assert.ErrorIs(t, err, ErrCouponCodeNotApplied)
+}
+
+func TestDefaultCartBehaviour_PromotionEngine(t *testing.T) {
+	t.Parallel()
+
+	engine := newPromotionEngine(
+		config.Map{"code": "welcome", "type": PromotionTypePercentageItemDiscount, "percentage": 10.0, "couponCode": "WELCOME10"},
+		config.Map{"code": "free-shipping", "type": PromotionTypeFreeShipping},
+	)
+
+	cob := &DefaultCartBehaviour{}
+	cob.Inject(newInMemoryStorage(), nil, flamingo.NullLogger{}, engine, nil, nil, nil)
+
+	cart := promotionTestCart(promotionTestItem("1", "a", 2, 50))
+	require.NoError(t, cob.cartStorage.StoreCart(context.Background(), cart))
+
+	cart, _, err := cob.ApplyVoucher(context.Background(), cart, "WELCOME10")
+	require.NoError(t, err)
+
+	assert.Equal(t, 100.0, cart.SubTotalGross.FloatAmount())
+	assert.Equal(t, 90.0, cart.SubTotalGrossWithDiscounts.FloatAmount())
+	assert.True(t, cart.ShippingGrossWithDiscounts.IsZero())
+	assert.InDelta(t, -14.99, cart.TotalDiscountAmount.FloatAmount(), 0.001)
+	assert.Equal(t, 90.0, cart.GrandTotal.FloatAmount())
+}
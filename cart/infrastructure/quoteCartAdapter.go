@@ -0,0 +1,149 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+
+	"flamingo.me/flamingo/v3/core/auth"
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"go.opencensus.io/trace"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/quote"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+type (
+	// DefaultQuoteCartAdapter prices the quote carts and creates the order carts with the DefaultCartBehaviour
+	DefaultQuoteCartAdapter struct {
+		defaultBehaviour *DefaultCartBehaviour
+	}
+
+	// ConfigSalesRepResolver treats the subjects configured via commerce.cart.quotes.salesReps as sales reps
+	ConfigSalesRepResolver struct {
+		salesReps map[string]struct{}
+	}
+)
+
+var (
+	_ quote.CartAdapter      = &DefaultQuoteCartAdapter{}
+	_ quote.SalesRepResolver = &ConfigSalesRepResolver{}
+)
+
+// Inject dependencies
+func (a *DefaultQuoteCartAdapter) Inject(
+	behaviour *DefaultCartBehaviour,
+) *DefaultQuoteCartAdapter {
+	a.defaultBehaviour = behaviour
+
+	return a
+}
+
+// UpdateItemPrice sets the negotiated single price of the item, the taxes, shipping and totals of the cart are recalculated
+// but the prices of the other items are kept
+func (a *DefaultQuoteCartAdapter) UpdateItemPrice(ctx context.Context, quoteCart domaincart.Cart, itemID string, singlePrice priceDomain.Price) (*domaincart.Cart, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/DefaultQuoteCartAdapter/UpdateItemPrice")
+	defer span.End()
+
+	newCart, err := quoteCart.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("DefaultQuoteCartAdapter: error cloning cart: %w", err)
+	}
+
+	itemDelivery, err := newCart.GetDeliveryByItemID(itemID)
+	if err != nil {
+		return nil, fmt.Errorf("DefaultQuoteCartAdapter: error finding delivery of item: %w", err)
+	}
+
+	for index, item := range itemDelivery.Cartitems {
+		if item.ID != itemID {
+			continue
+		}
+
+		itemDelivery.Cartitems[index].SinglePriceGross = singlePrice.GetPayable()
+		itemDelivery.Cartitems[index].SinglePriceNet = singlePrice.GetPayable()
+	}
+
+	err = a.defaultBehaviour.recalculateDelivery(ctx, &newCart, itemDelivery)
+	if err != nil {
+		return nil, err
+	}
+
+	for index, delivery := range newCart.Deliveries {
+		if itemDelivery.DeliveryInfo.Code == delivery.DeliveryInfo.Code {
+			newCart.Deliveries[index] = *itemDelivery
+		}
+	}
+
+	err = a.defaultBehaviour.collectTotals(&newCart)
+	if err != nil {
+		return nil, fmt.Errorf("DefaultQuoteCartAdapter: error collecting totals: %w", err)
+	}
+
+	return &newCart, nil
+}
+
+// CreateOrderCart stores a copy of the quote cart as new cart of the customer, the cart is marked with the quote id
+func (a *DefaultQuoteCartAdapter) CreateOrderCart(ctx context.Context, identity auth.Identity, q quote.Quote) (*domaincart.Cart, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/DefaultQuoteCartAdapter/CreateOrderCart")
+	defer span.End()
+
+	orderCart, err := q.Cart.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("DefaultQuoteCartAdapter: error cloning cart: %w", err)
+	}
+
+	orderCart.ID = identity.Subject() + "-quote-" + strconv.Itoa(rand.Int())
+	orderCart.Revision = 0
+	orderCart.BelongsToAuthenticatedUser = true
+	orderCart.AuthenticatedUserID = identity.Subject()
+
+	customAttributes := make(map[string]string, len(orderCart.AdditionalData.CustomAttributes)+1)
+	for key, value := range orderCart.AdditionalData.CustomAttributes {
+		customAttributes[key] = value
+	}
+
+	customAttributes[quote.CartAttributeQuoteID] = q.ID
+	orderCart.AdditionalData.CustomAttributes = customAttributes
+
+	return a.defaultBehaviour.StoreNewCart(ctx, &orderCart)
+}
+
+// Inject dependencies
+func (r *ConfigSalesRepResolver) Inject(
+	logger flamingo.Logger,
+	cfg *struct {
+		SalesReps config.Slice `inject:"config:commerce.cart.quotes.salesReps,optional"`
+	},
+) *ConfigSalesRepResolver {
+	r.salesReps = make(map[string]struct{})
+
+	if cfg == nil {
+		return r
+	}
+
+	var salesReps []string
+	if err := cfg.SalesReps.MapInto(&salesReps); err != nil {
+		logger.WithField(flamingo.LogKeyCategory, "ConfigSalesRepResolver").Error(fmt.Errorf("failed to map sales reps: %w", err))
+	}
+
+	for _, salesRep := range salesReps {
+		r.salesReps[salesRep] = struct{}{}
+	}
+
+	return r
+}
+
+// IsSalesRep checks if the subject of the identity is configured as sales rep
+func (r *ConfigSalesRepResolver) IsSalesRep(_ context.Context, identity auth.Identity) bool {
+	if identity == nil {
+		return false
+	}
+
+	_, found := r.salesReps[identity.Subject()]
+
+	return found
+}
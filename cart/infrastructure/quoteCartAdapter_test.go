@@ -0,0 +1,157 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+
+	authMock "flamingo.me/flamingo/v3/core/auth/mock"
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/quote"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+	"flamingo.me/flamingo-commerce/v3/product/infrastructure/fake"
+)
+
+func TestDefaultQuoteCartAdapter(t *testing.T) {
+	t.Parallel()
+
+	newAdapter := func() *DefaultQuoteCartAdapter {
+		cob := &DefaultCartBehaviour{}
+		cob.Inject(
+			newInMemoryStorage(),
+			nil,
+			flamingo.NullLogger{},
+			nil,
+			nil,
+			&struct {
+				DefaultTaxRate  float64 `inject:"config:commerce.cart.defaultCartAdapter.defaultTaxRate,optional"`
+				ProductPricing  string  `inject:"config:commerce.cart.defaultCartAdapter.productPrices"`
+				DefaultCurrency string  `inject:"config:commerce.cart.defaultCartAdapter.defaultCurrency"`
+			}{ProductPricing: "gross", DefaultCurrency: "EUR"},
+			nil,
+		)
+
+		return new(DefaultQuoteCartAdapter).Inject(cob)
+	}
+
+	quoteCart := domaincart.Cart{
+		ID:              "rep-1",
+		DefaultCurrency: "EUR",
+		Deliveries: []domaincart.Delivery{
+			{
+				DeliveryInfo: domaincart.DeliveryInfo{Code: "delivery"},
+				Cartitems: []domaincart.Item{
+					{
+						ID:               "item-1",
+						Qty:              2,
+						SinglePriceGross: priceDomain.NewFromFloat(10, "EUR"),
+						SinglePriceNet:   priceDomain.NewFromFloat(10, "EUR"),
+						RowPriceGross:    priceDomain.NewFromFloat(20, "EUR"),
+						RowPriceNet:      priceDomain.NewFromFloat(20, "EUR"),
+					},
+					{
+						ID:               "item-2",
+						Qty:              1,
+						SinglePriceGross: priceDomain.NewFromFloat(5, "EUR"),
+						SinglePriceNet:   priceDomain.NewFromFloat(5, "EUR"),
+						RowPriceGross:    priceDomain.NewFromFloat(5, "EUR"),
+						RowPriceNet:      priceDomain.NewFromFloat(5, "EUR"),
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("update item price", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := newAdapter().UpdateItemPrice(context.Background(), quoteCart, "item-1", priceDomain.NewFromFloat(7.5, "EUR"))
+		require.NoError(t, err)
+
+		item, err := got.GetByItemID("item-1")
+		require.NoError(t, err)
+		assert.Equal(t, priceDomain.NewFromFloat(7.5, "EUR"), item.SinglePriceGross)
+		assert.Equal(t, priceDomain.NewFromFloat(15, "EUR"), item.RowPriceGross)
+
+		item, err = got.GetByItemID("item-2")
+		require.NoError(t, err)
+		assert.Equal(t, priceDomain.NewFromFloat(5, "EUR"), item.SinglePriceGross, "other items keep their price")
+
+		assert.Equal(t, priceDomain.NewFromFloat(20, "EUR"), got.GrandTotal)
+
+		original, err := quoteCart.GetByItemID("item-1")
+		require.NoError(t, err)
+		assert.Equal(t, priceDomain.NewFromFloat(10, "EUR"), original.SinglePriceGross, "the given cart is not changed")
+	})
+
+	t.Run("unknown item", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newAdapter().UpdateItemPrice(context.Background(), quoteCart, "unknown", priceDomain.NewFromFloat(7.5, "EUR"))
+		assert.Error(t, err)
+	})
+
+	t.Run("create order cart", func(t *testing.T) {
+		t.Parallel()
+
+		adapter := newAdapter()
+
+		got, err := adapter.CreateOrderCart(context.Background(), &authMock.Identity{Sub: "customer-1"}, quote.Quote{ID: "quote-1", Cart: quoteCart})
+		require.NoError(t, err)
+		assert.NotEqual(t, quoteCart.ID, got.ID)
+		assert.True(t, got.BelongsToAuthenticatedUser)
+		assert.Equal(t, "customer-1", got.AuthenticatedUserID)
+		assert.Equal(t, "quote-1", quote.IDOfCart(*got))
+		assert.Equal(t, priceDomain.NewFromFloat(25, "EUR"), got.GrandTotal)
+
+		stored, err := adapter.defaultBehaviour.GetCart(context.Background(), got.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "quote-1", quote.IDOfCart(*stored))
+	})
+
+	t.Run("adding to the order cart keeps the locked prices", func(t *testing.T) {
+		t.Parallel()
+
+		adapter := newAdapter()
+		adapter.defaultBehaviour.productService = &fake.ProductService{}
+
+		lockedCart, err := quoteCart.Clone()
+		require.NoError(t, err)
+		lockedCart.Deliveries[0].Cartitems[0].MarketplaceCode = "fake_fixed_simple_without_discounts"
+
+		orderCart, err := adapter.CreateOrderCart(context.Background(), &authMock.Identity{Sub: "customer-1"}, quote.Quote{ID: "quote-1", Cart: lockedCart})
+		require.NoError(t, err)
+
+		got, _, err := adapter.defaultBehaviour.AddToCart(context.Background(), orderCart, "delivery", domaincart.AddRequest{MarketplaceCode: "fake_fixed_simple_without_discounts", Qty: 1})
+		require.NoError(t, err)
+
+		got, _, err = adapter.defaultBehaviour.AddToCart(context.Background(), got, "delivery", domaincart.AddRequest{MarketplaceCode: "fake_simple_with_fixed_price", Qty: 1})
+		require.NoError(t, err)
+
+		delivery, found := got.GetDeliveryByCode("delivery")
+		require.True(t, found)
+		require.Len(t, delivery.Cartitems, 3)
+
+		assert.Equal(t, 3, delivery.Cartitems[0].Qty)
+		assert.Equal(t, priceDomain.NewFromFloat(10, "EUR"), delivery.Cartitems[0].SinglePriceGross, "the negotiated price of the line is kept")
+		assert.Equal(t, priceDomain.NewFromFloat(30, "EUR"), delivery.Cartitems[0].RowPriceGross)
+		assert.Equal(t, priceDomain.NewFromFloat(5, "EUR"), delivery.Cartitems[1].SinglePriceGross)
+		assert.Equal(t, 10.49, delivery.Cartitems[2].SinglePriceGross.FloatAmount(), "new lines are priced by the product service")
+	})
+}
+
+func TestConfigSalesRepResolver(t *testing.T) {
+	t.Parallel()
+
+	resolver := new(ConfigSalesRepResolver).Inject(flamingo.NullLogger{}, &struct {
+		SalesReps config.Slice `inject:"config:commerce.cart.quotes.salesReps,optional"`
+	}{SalesReps: config.Slice{"rep-1"}})
+
+	assert.True(t, resolver.IsSalesRep(context.Background(), &authMock.Identity{Sub: "rep-1"}))
+	assert.False(t, resolver.IsSalesRep(context.Background(), &authMock.Identity{Sub: "customer-1"}))
+	assert.False(t, resolver.IsSalesRep(context.Background(), nil))
+}
@@ -0,0 +1,112 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.opencensus.io/trace"
+
+	"flamingo.me/flamingo-commerce/v3/cart/domain/quote"
+)
+
+type (
+	// InMemoryQuoteStorage keeps the quotes in memory, for development and single instance setups, MUST be bound as singleton
+	InMemoryQuoteStorage struct {
+		mutex  sync.RWMutex
+		quotes map[string]quote.Quote
+	}
+)
+
+var _ quote.Storage = &InMemoryQuoteStorage{}
+
+// StoreQuote creates or replaces a copy of the quote
+func (s *InMemoryQuoteStorage) StoreQuote(ctx context.Context, q *quote.Quote) error {
+	_, span := trace.StartSpan(ctx, "cart/InMemoryQuoteStorage/StoreQuote")
+	defer span.End()
+
+	stored, err := cloneQuote(*q)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.quotes == nil {
+		s.quotes = make(map[string]quote.Quote)
+	}
+
+	s.quotes[q.ID] = stored
+
+	return nil
+}
+
+// GetQuote returns a copy of the quote or quote.ErrQuoteNotFound
+func (s *InMemoryQuoteStorage) GetQuote(ctx context.Context, id string) (*quote.Quote, error) {
+	_, span := trace.StartSpan(ctx, "cart/InMemoryQuoteStorage/GetQuote")
+	defer span.End()
+
+	s.mutex.RLock()
+	stored, found := s.quotes[id]
+	s.mutex.RUnlock()
+
+	if !found {
+		return nil, fmt.Errorf("InMemoryQuoteStorage: %w for id %q", quote.ErrQuoteNotFound, id)
+	}
+
+	q, err := cloneQuote(stored)
+	if err != nil {
+		return nil, err
+	}
+
+	return &q, nil
+}
+
+// ListQuotes returns copies of all quotes of the customer, newest quote first
+func (s *InMemoryQuoteStorage) ListQuotes(ctx context.Context, customerID string) ([]*quote.Quote, error) {
+	_, span := trace.StartSpan(ctx, "cart/InMemoryQuoteStorage/ListQuotes")
+	defer span.End()
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	quotes := make([]*quote.Quote, 0)
+
+	for _, stored := range s.quotes {
+		if stored.CustomerID != customerID {
+			continue
+		}
+
+		q, err := cloneQuote(stored)
+		if err != nil {
+			return nil, err
+		}
+
+		quotes = append(quotes, &q)
+	}
+
+	sort.SliceStable(quotes, func(i, j int) bool {
+		if quotes[i].CreatedAt.Equal(quotes[j].CreatedAt) {
+			return quotes[i].ID < quotes[j].ID
+		}
+
+		return quotes[i].CreatedAt.After(quotes[j].CreatedAt)
+	})
+
+	return quotes, nil
+}
+
+// cloneQuote returns a deep copy of the quote, so that the stored quotes can't be changed by the callers
+func cloneQuote(q quote.Quote) (quote.Quote, error) {
+	clonedCart, err := q.Cart.Clone()
+	if err != nil {
+		return quote.Quote{}, fmt.Errorf("InMemoryQuoteStorage: error cloning cart of quote %q: %w", q.ID, err)
+	}
+
+	q.Cart = clonedCart
+	q.PlacedOrderInfos = append(q.PlacedOrderInfos[:0:0], q.PlacedOrderInfos...)
+
+	return q, nil
+}
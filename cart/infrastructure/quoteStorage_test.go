@@ -0,0 +1,56 @@
+package infrastructure_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/quote"
+	"flamingo.me/flamingo-commerce/v3/cart/infrastructure"
+)
+
+func TestInMemoryQuoteStorage(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage := &infrastructure.InMemoryQuoteStorage{}
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	for i, id := range []string{"quote-1", "quote-2"} {
+		q, err := quote.NewQuote(id, "customer-1", "rep-1", domaincart.Cart{ID: "cart-" + id}, now.Add(time.Duration(i)*time.Hour), now.Add(24*time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, storage.StoreQuote(ctx, q))
+	}
+
+	other, err := quote.NewQuote("quote-3", "customer-2", "rep-1", domaincart.Cart{}, now, now.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, storage.StoreQuote(ctx, other))
+
+	got, err := storage.GetQuote(ctx, "quote-1")
+	require.NoError(t, err)
+	assert.Equal(t, "cart-quote-1", got.Cart.ID)
+
+	got.State = quote.StateSent
+	got.Cart.ID = "changed"
+	stored, err := storage.GetQuote(ctx, "quote-1")
+	require.NoError(t, err)
+	assert.Equal(t, quote.StateDraft, stored.State, "the stored quote can't be changed")
+	assert.Equal(t, "cart-quote-1", stored.Cart.ID)
+
+	quotes, err := storage.ListQuotes(ctx, "customer-1")
+	require.NoError(t, err)
+	require.Len(t, quotes, 2)
+	assert.Equal(t, "quote-2", quotes[0].ID, "newest quote first")
+	assert.Equal(t, "quote-1", quotes[1].ID)
+
+	quotes, err = storage.ListQuotes(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, quotes)
+
+	_, err = storage.GetQuote(ctx, "unknown")
+	assert.ErrorIs(t, err, quote.ErrQuoteNotFound)
+}
@@ -0,0 +1,52 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+type (
+	// redisPoolConfig contains the connection settings shared by the redis backed storages of the cart module
+	redisPoolConfig struct {
+		MaxIdle                 int
+		IdleTimeoutMilliseconds int
+		Network                 string
+		Address                 string
+		Database                int
+		Username                string
+		Password                string
+		UseTLS                  bool
+	}
+)
+
+// newRedisPool creates a connection pool for the given settings, idle connections are checked with a PING before they are reused
+func newRedisPool(cfg redisPoolConfig) *redis.Pool {
+	options := []redis.DialOption{
+		redis.DialDatabase(cfg.Database),
+	}
+
+	if cfg.Username != "" {
+		options = append(options, redis.DialUsername(cfg.Username))
+	}
+
+	if cfg.Password != "" {
+		options = append(options, redis.DialPassword(cfg.Password))
+	}
+
+	if cfg.UseTLS {
+		options = append(options, redis.DialUseTLS(cfg.UseTLS))
+	}
+
+	return &redis.Pool{
+		MaxIdle:     cfg.MaxIdle,
+		IdleTimeout: time.Duration(cfg.IdleTimeoutMilliseconds) * time.Millisecond,
+		TestOnBorrow: func(c redis.Conn, t time.Time) error {
+			_, err := c.Do("PING")
+			return err
+		},
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial(cfg.Network, cfg.Address, options...)
+		},
+	}
+}
@@ -0,0 +1,353 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"flamingo.me/flamingo/v3/core/healthcheck/domain/healthcheck"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/gomodule/redigo/redis"
+	"go.opencensus.io/trace"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+)
+
+type (
+	// RedisCartStorage stores gob encoded carts together with their revision in redis, expiry is handled by redis via the configured ttl
+	RedisCartStorage struct {
+		pool      *redis.Pool
+		logger    flamingo.Logger
+		ttl       time.Duration
+		keyPrefix string
+	}
+)
+
+// storeCartScript stores the cart data and revision in a hash if the stored revision (if any) is exactly one lower than the new one.
+// It returns -1 on success and the currently stored revision otherwise.
+// Carts stored as plain string before revisions were introduced have revision 0 and are replaced by the hash.
+// If a customer index key is given the cart id is added to it, the index expires with the last stored cart of the customer.
+var storeCartScript = redis.NewScript(2, `
+local legacy = redis.call('TYPE', KEYS[1]).ok == 'string'
+local stored
+if legacy then
+	stored = '0'
+else
+	stored = redis.call('HGET', KEYS[1], 'revision')
+end
+if stored and tonumber(stored) + 1 ~= tonumber(ARGV[1]) then
+	return tonumber(stored)
+end
+if legacy then
+	redis.call('DEL', KEYS[1])
+end
+redis.call('HSET', KEYS[1], 'revision', ARGV[1], 'data', ARGV[2])
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+if KEYS[2] ~= '' then
+	redis.call('SADD', KEYS[2], ARGV[4])
+	redis.call('EXPIRE', KEYS[2], ARGV[3])
+end
+return -1
+`)
+
+// getCartScript returns the cart data of the hash or of a cart stored as plain string before revisions were introduced
+var getCartScript = redis.NewScript(1, `
+if redis.call('TYPE', KEYS[1]).ok == 'string' then
+	return redis.call('GET', KEYS[1])
+end
+return redis.call('HGET', KEYS[1], 'data')
+`)
+
+// customerIndexKeyPrefix prefixes the sets holding the cart ids of a customer
+const customerIndexKeyPrefix = "customerCarts:"
+
+var (
+	_ CartStorage         = &RedisCartStorage{}
+	_ IterableCartStorage = &RedisCartStorage{}
+	_ CustomerCartIndex   = &RedisCartStorage{}
+	_ healthcheck.Status  = &RedisCartStorage{}
+	// ErrNoRedisConnection is returned if the underlying connection is erroneous
+	ErrNoRedisConnection = errors.New("no redis connection, see healthcheck")
+)
+
+// Inject dependencies
+func (r *RedisCartStorage) Inject(
+	logger flamingo.Logger,
+	cfg *struct {
+		MaxIdle                 int    `inject:"config:commerce.cart.defaultCartAdapter.redis.maxIdle"`
+		IdleTimeoutMilliseconds int    `inject:"config:commerce.cart.defaultCartAdapter.redis.idleTimeoutMilliseconds"`
+		Network                 string `inject:"config:commerce.cart.defaultCartAdapter.redis.network"`
+		Address                 string `inject:"config:commerce.cart.defaultCartAdapter.redis.address"`
+		Database                int    `inject:"config:commerce.cart.defaultCartAdapter.redis.database"`
+		Username                string `inject:"config:commerce.cart.defaultCartAdapter.redis.username,optional"`
+		Password                string `inject:"config:commerce.cart.defaultCartAdapter.redis.password,optional"`
+		UseTLS                  bool   `inject:"config:commerce.cart.defaultCartAdapter.redis.useTLS,optional"`
+		TTL                     string `inject:"config:commerce.cart.defaultCartAdapter.redis.ttl"`
+		KeyPrefix               string `inject:"config:commerce.cart.defaultCartAdapter.redis.keyPrefix"`
+	},
+) *RedisCartStorage {
+	r.logger = logger.WithField(flamingo.LogKeyModule, "cart").WithField(flamingo.LogKeyCategory, "RedisCartStorage")
+
+	if cfg != nil {
+		var err error
+		r.ttl, err = time.ParseDuration(cfg.TTL)
+		if err != nil {
+			panic("can't parse commerce.cart.defaultCartAdapter.redis.ttl")
+		}
+
+		r.keyPrefix = cfg.KeyPrefix
+
+		r.pool = newRedisPool(redisPoolConfig{
+			MaxIdle:                 cfg.MaxIdle,
+			IdleTimeoutMilliseconds: cfg.IdleTimeoutMilliseconds,
+			Network:                 cfg.Network,
+			Address:                 cfg.Address,
+			Database:                cfg.Database,
+			Username:                cfg.Username,
+			Password:                cfg.Password,
+			UseTLS:                  cfg.UseTLS,
+		})
+		runtime.SetFinalizer(r, func(r *RedisCartStorage) { r.pool.Close() }) // close all connections on destruction
+	}
+
+	return r
+}
+
+// HasCart checks if the cart storage has a cart with a given id
+func (r *RedisCartStorage) HasCart(ctx context.Context, id string) bool {
+	_, span := trace.StartSpan(ctx, "cart/RedisCartStorage/HasCart")
+	defer span.End()
+
+	conn := r.pool.Get()
+	defer conn.Close()
+	if conn.Err() != nil {
+		r.logger.WithContext(ctx).Error("HasCart:", conn.Err())
+		return false
+	}
+
+	exists, err := redis.Bool(conn.Do("EXISTS", r.key(id)))
+	if err != nil {
+		r.logger.WithContext(ctx).Error("HasCart:", err)
+		return false
+	}
+
+	return exists
+}
+
+// GetCart returns a cart with the given id from the cart storage
+func (r *RedisCartStorage) GetCart(ctx context.Context, id string) (*domaincart.Cart, error) {
+	_, span := trace.StartSpan(ctx, "cart/RedisCartStorage/GetCart")
+	defer span.End()
+
+	conn := r.pool.Get()
+	defer conn.Close()
+	if conn.Err() != nil {
+		r.logger.WithContext(ctx).Error("GetCart:", conn.Err())
+		return nil, ErrNoRedisConnection
+	}
+
+	content, err := redis.Bytes(getCartScript.Do(conn, r.key(id)))
+	if errors.Is(err, redis.ErrNil) {
+		return nil, fmt.Errorf("RedisCartStorage: %w for cart id %q", domaincart.ErrCartNotFound, id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("RedisCartStorage: error loading cart %q: %w", id, err)
+	}
+
+	cart := new(domaincart.Cart)
+	err = gob.NewDecoder(bytes.NewBuffer(content)).Decode(cart)
+	if err != nil {
+		return nil, fmt.Errorf("RedisCartStorage: cart %q is not decodable: %w", id, err)
+	}
+
+	return cart, nil
+}
+
+// StoreCart stores a cart in the storage if it is based on the currently stored revision and (re)sets its expiry
+func (r *RedisCartStorage) StoreCart(ctx context.Context, cart *domaincart.Cart) error {
+	_, span := trace.StartSpan(ctx, "cart/RedisCartStorage/StoreCart")
+	defer span.End()
+
+	conn := r.pool.Get()
+	defer conn.Close()
+	if conn.Err() != nil {
+		r.logger.WithContext(ctx).Error("StoreCart:", conn.Err())
+		return ErrNoRedisConnection
+	}
+
+	buffer := new(bytes.Buffer)
+	err := gob.NewEncoder(buffer).Encode(cart)
+	if err != nil {
+		return fmt.Errorf("RedisCartStorage: error encoding cart %q: %w", cart.ID, err)
+	}
+
+	customerIndexKey := ""
+	if cart.AuthenticatedUserID != "" {
+		customerIndexKey = r.customerIndexKey(cart.AuthenticatedUserID)
+	}
+
+	storedRevision, err := redis.Int(storeCartScript.Do(
+		conn,
+		r.key(cart.ID),
+		customerIndexKey,
+		cart.Revision,
+		buffer.Bytes(),
+		int(r.ttl.Round(time.Second).Seconds()),
+		cart.ID,
+	))
+	if err != nil {
+		return fmt.Errorf("RedisCartStorage: error saving cart %q: %w", cart.ID, err)
+	}
+
+	if storedRevision >= 0 {
+		return checkRevision(storedRevision, cart)
+	}
+
+	return nil
+}
+
+// RemoveCart from storage
+func (r *RedisCartStorage) RemoveCart(ctx context.Context, cart *domaincart.Cart) error {
+	_, span := trace.StartSpan(ctx, "cart/RedisCartStorage/RemoveCart")
+	defer span.End()
+
+	conn := r.pool.Get()
+	defer conn.Close()
+	if conn.Err() != nil {
+		r.logger.WithContext(ctx).Error("RemoveCart:", conn.Err())
+		return ErrNoRedisConnection
+	}
+
+	_, err := conn.Do("DEL", r.key(cart.ID))
+	if err != nil || cart.AuthenticatedUserID == "" {
+		return err
+	}
+
+	_, err = conn.Do("SREM", r.customerIndexKey(cart.AuthenticatedUserID), cart.ID)
+
+	return err
+}
+
+// CartIDsOfCustomer returns the ids of the carts in the index of the customer, ids of expired carts are removed from the index
+func (r *RedisCartStorage) CartIDsOfCustomer(ctx context.Context, customerID string) ([]string, error) {
+	_, span := trace.StartSpan(ctx, "cart/RedisCartStorage/CartIDsOfCustomer")
+	defer span.End()
+
+	conn := r.pool.Get()
+	defer conn.Close()
+	if conn.Err() != nil {
+		r.logger.WithContext(ctx).Error("CartIDsOfCustomer:", conn.Err())
+		return nil, ErrNoRedisConnection
+	}
+
+	indexKey := r.customerIndexKey(customerID)
+
+	ids, err := redis.Strings(conn.Do("SMEMBERS", indexKey))
+	if err != nil {
+		return nil, fmt.Errorf("RedisCartStorage: error loading carts of customer: %w", err)
+	}
+
+	storedIDs := make([]string, 0, len(ids))
+	expiredIDs := []interface{}{indexKey}
+
+	for _, id := range ids {
+		exists, err := redis.Bool(conn.Do("EXISTS", r.key(id)))
+		if err != nil {
+			return nil, fmt.Errorf("RedisCartStorage: error loading carts of customer: %w", err)
+		}
+
+		if exists {
+			storedIDs = append(storedIDs, id)
+		} else {
+			expiredIDs = append(expiredIDs, id)
+		}
+	}
+
+	if len(expiredIDs) > 1 {
+		if _, err := conn.Do("SREM", expiredIDs...); err != nil {
+			r.logger.WithContext(ctx).Error("CartIDsOfCustomer:", err)
+		}
+	}
+
+	return storedIDs, nil
+}
+
+// ForEachCart scans all keys with the configured prefix and calls fn for every stored cart
+func (r *RedisCartStorage) ForEachCart(ctx context.Context, fn func(cart *domaincart.Cart) error) error {
+	ctx, span := trace.StartSpan(ctx, "cart/RedisCartStorage/ForEachCart")
+	defer span.End()
+
+	conn := r.pool.Get()
+	defer conn.Close()
+	if conn.Err() != nil {
+		r.logger.WithContext(ctx).Error("ForEachCart:", conn.Err())
+		return ErrNoRedisConnection
+	}
+
+	cursor := 0
+	for {
+		values, err := redis.Values(conn.Do("SCAN", cursor, "MATCH", r.keyPrefix+"*", "COUNT", 100))
+		if err != nil {
+			return fmt.Errorf("RedisCartStorage: error scanning carts: %w", err)
+		}
+
+		var keys []string
+		_, err = redis.Scan(values, &cursor, &keys)
+		if err != nil {
+			return fmt.Errorf("RedisCartStorage: error scanning carts: %w", err)
+		}
+
+		for _, key := range keys {
+			if strings.HasPrefix(key, customerIndexKeyPrefix) {
+				// the customer indexes match an empty or short key prefix
+				continue
+			}
+
+			cart, err := r.GetCart(ctx, strings.TrimPrefix(key, r.keyPrefix))
+			if errors.Is(err, domaincart.ErrCartNotFound) {
+				// the cart expired in the meantime
+				continue
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if err := fn(cart); err != nil {
+				return err
+			}
+		}
+
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// Status handles the health check of redis
+func (r *RedisCartStorage) Status() (alive bool, details string) {
+	conn := r.pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("PING")
+	if err == nil {
+		return true, "redis for cart storage replies to PING"
+	}
+
+	return false, err.Error()
+}
+
+func (r *RedisCartStorage) key(id string) string {
+	return r.keyPrefix + id
+}
+
+// customerIndexKey is outside of the key prefix, so that scanning the carts doesn't return the indexes
+func (r *RedisCartStorage) customerIndexKey(customerID string) string {
+	return customerIndexKeyPrefix + r.keyPrefix + customerID
+}
@@ -0,0 +1,160 @@
+package infrastructure_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"os/exec"
+	"testing"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stvp/tempredis"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/infrastructure"
+)
+
+func getRedisCartStorage(network, address string) *infrastructure.RedisCartStorage {
+	return new(infrastructure.RedisCartStorage).Inject(
+		new(flamingo.NullLogger),
+		&struct {
+			MaxIdle                 int    `inject:"config:commerce.cart.defaultCartAdapter.redis.maxIdle"`
+			IdleTimeoutMilliseconds int    `inject:"config:commerce.cart.defaultCartAdapter.redis.idleTimeoutMilliseconds"`
+			Network                 string `inject:"config:commerce.cart.defaultCartAdapter.redis.network"`
+			Address                 string `inject:"config:commerce.cart.defaultCartAdapter.redis.address"`
+			Database                int    `inject:"config:commerce.cart.defaultCartAdapter.redis.database"`
+			Username                string `inject:"config:commerce.cart.defaultCartAdapter.redis.username,optional"`
+			Password                string `inject:"config:commerce.cart.defaultCartAdapter.redis.password,optional"`
+			UseTLS                  bool   `inject:"config:commerce.cart.defaultCartAdapter.redis.useTLS,optional"`
+			TTL                     string `inject:"config:commerce.cart.defaultCartAdapter.redis.ttl"`
+			KeyPrefix               string `inject:"config:commerce.cart.defaultCartAdapter.redis.keyPrefix"`
+		}{MaxIdle: 3, IdleTimeoutMilliseconds: 240000, Network: network, Address: address, Database: 0, TTL: "1h", KeyPrefix: "cart:"})
+}
+
+func startUpLocalCartRedis(t *testing.T) (*tempredis.Server, redis.Conn) {
+	t.Helper()
+
+	if _, err := exec.LookPath("redis-server"); err != nil {
+		t.Skip("redis-server not installed")
+	}
+
+	server, err := tempredis.Start(tempredis.Config{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = server.Term() })
+
+	conn, err := redis.Dial("unix", server.Socket())
+	require.NoError(t, err)
+
+	return server, conn
+}
+
+func TestRedisCartStorage(t *testing.T) {
+	server, conn := startUpLocalCartRedis(t)
+	storage := getRedisCartStorage("unix", server.Socket())
+
+	cart := &domaincart.Cart{
+		ID:              "cart-1",
+		DefaultCurrency: "EUR",
+		Deliveries: []domaincart.Delivery{
+			{
+				DeliveryInfo: domaincart.DeliveryInfo{Code: "delivery"},
+				Cartitems:    []domaincart.Item{{ID: "item-1", MarketplaceCode: "sku-1", Qty: 2}},
+			},
+		},
+	}
+
+	t.Run("unknown cart", func(t *testing.T) {
+		assert.False(t, storage.HasCart(context.Background(), "unknown"))
+
+		_, err := storage.GetCart(context.Background(), "unknown")
+		assert.ErrorIs(t, err, domaincart.ErrCartNotFound)
+	})
+
+	t.Run("store and get cart", func(t *testing.T) {
+		require.NoError(t, storage.StoreCart(context.Background(), cart))
+		assert.True(t, storage.HasCart(context.Background(), cart.ID))
+
+		got, err := storage.GetCart(context.Background(), cart.ID)
+		require.NoError(t, err)
+		assert.Equal(t, cart.ID, got.ID)
+		assert.Equal(t, "sku-1", got.Deliveries[0].Cartitems[0].MarketplaceCode)
+		assert.Equal(t, 2, got.Deliveries[0].Cartitems[0].Qty)
+
+		ttl, err := redis.Int(conn.Do("TTL", "cart:"+cart.ID))
+		require.NoError(t, err)
+		assert.InDelta(t, 3600, ttl, 5, "ttl should be set on store")
+	})
+
+	t.Run("store outdated cart", func(t *testing.T) {
+		updated := *cart
+		updated.Revision = cart.Revision + 1
+		require.NoError(t, storage.StoreCart(context.Background(), &updated))
+
+		err := storage.StoreCart(context.Background(), cart)
+		var conflictErr *domaincart.RevisionConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, updated.Revision, conflictErr.ActualRevision)
+
+		got, err := storage.GetCart(context.Background(), cart.ID)
+		require.NoError(t, err)
+		assert.Equal(t, updated.Revision, got.Revision)
+	})
+
+	t.Run("cart stored before revisions were introduced", func(t *testing.T) {
+		legacy := domaincart.Cart{ID: "legacy", DefaultCurrency: "EUR"}
+		buffer := new(bytes.Buffer)
+		require.NoError(t, gob.NewEncoder(buffer).Encode(legacy))
+		_, err := conn.Do("SET", "cart:legacy", buffer.Bytes())
+		require.NoError(t, err)
+
+		got, err := storage.GetCart(context.Background(), "legacy")
+		require.NoError(t, err)
+		assert.Equal(t, 0, got.Revision)
+
+		got.Revision = 1
+		require.NoError(t, storage.StoreCart(context.Background(), got))
+
+		got, err = storage.GetCart(context.Background(), "legacy")
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.Revision)
+	})
+
+	t.Run("carts of customer", func(t *testing.T) {
+		for _, id := range []string{"customer-1", "customer-1-2", "customer-1-3"} {
+			require.NoError(t, storage.StoreCart(context.Background(), &domaincart.Cart{ID: id, Revision: 1, AuthenticatedUserID: "customer-1"}))
+		}
+
+		ids, err := storage.CartIDsOfCustomer(context.Background(), "customer-1")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"customer-1", "customer-1-2", "customer-1-3"}, ids)
+
+		require.NoError(t, storage.RemoveCart(context.Background(), &domaincart.Cart{ID: "customer-1-2", AuthenticatedUserID: "customer-1"}))
+
+		// simulates a cart expired by its ttl
+		_, err = conn.Do("DEL", "cart:customer-1-3")
+		require.NoError(t, err)
+
+		ids, err = storage.CartIDsOfCustomer(context.Background(), "customer-1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"customer-1"}, ids)
+
+		indexed, err := redis.Strings(conn.Do("SMEMBERS", "customerCarts:cart:customer-1"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"customer-1"}, indexed, "expired carts are removed from the index")
+
+		var scanned []string
+		require.NoError(t, storage.ForEachCart(context.Background(), func(cart *domaincart.Cart) error {
+			scanned = append(scanned, cart.ID)
+			return nil
+		}))
+		assert.Contains(t, scanned, "customer-1")
+	})
+
+	t.Run("remove cart", func(t *testing.T) {
+		require.NoError(t, storage.RemoveCart(context.Background(), cart))
+		assert.False(t, storage.HasCart(context.Background(), cart.ID))
+	})
+}
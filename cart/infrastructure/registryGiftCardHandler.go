@@ -0,0 +1,169 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opencensus.io/trace"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+type (
+	// RegistryGiftCardHandler applies gift cards with the balance of the bound GiftCardRegistry, the applied amounts are
+	// reserved when the cart is completed and released when it is restored
+	RegistryGiftCardHandler struct {
+		registry domaincart.GiftCardRegistry
+		now      func() time.Time
+	}
+)
+
+var (
+	_ GiftCardHandler    = (*RegistryGiftCardHandler)(nil)
+	_ GiftCardCalculator = (*RegistryGiftCardHandler)(nil)
+	_ GiftCardReserver   = (*RegistryGiftCardHandler)(nil)
+)
+
+// Inject dependencies
+func (h *RegistryGiftCardHandler) Inject(registry domaincart.GiftCardRegistry) *RegistryGiftCardHandler {
+	h.registry = registry
+	h.now = time.Now
+
+	return h
+}
+
+// ApplyGiftCard checks the validity, the currency and the balance of the gift card and adds it to the cart,
+// the applied amount is calculated when the cart totals are collected
+func (h *RegistryGiftCardHandler) ApplyGiftCard(ctx context.Context, cart *domaincart.Cart, giftCardCode string) (*domaincart.Cart, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/RegistryGiftCardHandler/ApplyGiftCard")
+	defer span.End()
+
+	appliedGiftCards := domaincart.AppliedGiftCards(cart.AppliedGiftCards)
+	if _, found := appliedGiftCards.GiftCardByCode(giftCardCode); found {
+		return cart, nil
+	}
+
+	giftCard, err := h.registry.GiftCard(ctx, giftCardCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if !giftCard.IsValid(h.now()) {
+		return nil, fmt.Errorf("%w: %q", domaincart.ErrGiftCardExpired, giftCardCode)
+	}
+
+	if giftCard.Balance.Currency() != cart.DefaultCurrency {
+		return nil, fmt.Errorf("%w: %q", domaincart.ErrGiftCardCurrencyMismatch, giftCardCode)
+	}
+
+	if !giftCard.Balance.IsPositive() {
+		return nil, fmt.Errorf("%w: %q", domaincart.ErrGiftCardInsufficientBalance, giftCardCode)
+	}
+
+	cart.AppliedGiftCards = append(cart.AppliedGiftCards, domaincart.AppliedGiftCard{
+		Code:      giftCard.Code,
+		Applied:   priceDomain.NewZero(cart.DefaultCurrency),
+		Remaining: giftCard.Balance,
+	})
+
+	return cart, nil
+}
+
+// RemoveGiftCard removes the gift card from the cart
+func (h *RegistryGiftCardHandler) RemoveGiftCard(ctx context.Context, cart *domaincart.Cart, giftCardCode string) (*domaincart.Cart, error) {
+	_, span := trace.StartSpan(ctx, "cart/RegistryGiftCardHandler/RemoveGiftCard")
+	defer span.End()
+
+	giftCards := make([]domaincart.AppliedGiftCard, 0, len(cart.AppliedGiftCards))
+	for _, giftCard := range cart.AppliedGiftCards {
+		if giftCard.Code != giftCardCode {
+			giftCards = append(giftCards, giftCard)
+		}
+	}
+
+	if len(giftCards) == len(cart.AppliedGiftCards) {
+		return nil, fmt.Errorf("%w: %q is not applied", domaincart.ErrGiftCardNotFound, giftCardCode)
+	}
+
+	cart.AppliedGiftCards = giftCards
+
+	return cart, nil
+}
+
+// CalculateGiftCards spreads the grand total over the applied gift cards in the order they have been applied
+func (h *RegistryGiftCardHandler) CalculateGiftCards(cart *domaincart.Cart) error {
+	left := cart.GrandTotal
+	if left.IsNegative() {
+		left = priceDomain.NewZero(cart.DefaultCurrency)
+	}
+
+	for i, giftCard := range cart.AppliedGiftCards {
+		total, err := giftCard.Total()
+		if err != nil {
+			return fmt.Errorf("failed to calculate total of gift card %q: %w", giftCard.Code, err)
+		}
+
+		applied := total
+		if applied.IsGreaterThen(left) {
+			applied = left
+		}
+
+		cart.AppliedGiftCards[i].Applied = applied
+
+		cart.AppliedGiftCards[i].Remaining, err = total.Sub(applied)
+		if err != nil {
+			return fmt.Errorf("failed to calculate remaining amount of gift card %q: %w", giftCard.Code, err)
+		}
+
+		left, err = left.Sub(applied)
+		if err != nil {
+			return fmt.Errorf("failed to calculate amount left for gift cards: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ReserveGiftCards reserves the applied amounts of all gift cards for the cart, already made reservations are
+// released again if a gift card can't be reserved
+func (h *RegistryGiftCardHandler) ReserveGiftCards(ctx context.Context, cart *domaincart.Cart) error {
+	ctx, span := trace.StartSpan(ctx, "cart/RegistryGiftCardHandler/ReserveGiftCards")
+	defer span.End()
+
+	for i, giftCard := range cart.AppliedGiftCards {
+		if !giftCard.Applied.IsPositive() {
+			continue
+		}
+
+		err := h.registry.Reserve(ctx, giftCard.Code, cart.ID, giftCard.Applied)
+		if err == nil {
+			continue
+		}
+
+		for _, reserved := range cart.AppliedGiftCards[:i] {
+			_ = h.registry.Release(ctx, reserved.Code, cart.ID)
+		}
+
+		return fmt.Errorf("failed to reserve gift card %q: %w", giftCard.Code, err)
+	}
+
+	return nil
+}
+
+// ReleaseGiftCards releases the reservations of all gift cards of the cart
+func (h *RegistryGiftCardHandler) ReleaseGiftCards(ctx context.Context, cart *domaincart.Cart) error {
+	ctx, span := trace.StartSpan(ctx, "cart/RegistryGiftCardHandler/ReleaseGiftCards")
+	defer span.End()
+
+	var releaseErr error
+
+	for _, giftCard := range cart.AppliedGiftCards {
+		if err := h.registry.Release(ctx, giftCard.Code, cart.ID); err != nil && releaseErr == nil {
+			releaseErr = fmt.Errorf("failed to release gift card %q: %w", giftCard.Code, err)
+		}
+	}
+
+	return releaseErr
+}
@@ -0,0 +1,142 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+func TestRegistryGiftCardHandler_ApplyGiftCard(t *testing.T) {
+	t.Parallel()
+
+	registry := new(InMemoryGiftCardRegistry).Inject(flamingo.NullLogger{}, &struct {
+		Cards config.Slice `inject:"config:commerce.cart.defaultCartAdapter.giftCards.cards,optional"`
+	}{Cards: config.Slice{
+		config.Map{"code": "gift-50", "balance": 50.0, "currency": "EUR"},
+		config.Map{"code": "gift-usd", "balance": 50.0, "currency": "USD"},
+		config.Map{"code": "gift-empty", "balance": 0.0, "currency": "EUR"},
+		config.Map{"code": "gift-expired", "balance": 50.0, "currency": "EUR", "validUntil": "2000-01-01T00:00:00Z"},
+	}})
+	handler := new(RegistryGiftCardHandler).Inject(registry)
+
+	tests := []struct {
+		code    string
+		wantErr error
+	}{
+		{code: "unknown", wantErr: domaincart.ErrGiftCardNotFound},
+		{code: "gift-usd", wantErr: domaincart.ErrGiftCardCurrencyMismatch},
+		{code: "gift-empty", wantErr: domaincart.ErrGiftCardInsufficientBalance},
+		{code: "gift-expired", wantErr: domaincart.ErrGiftCardExpired},
+	}
+
+	for _, tt := range tests {
+		_, err := handler.ApplyGiftCard(context.Background(), promotionTestCart(), tt.code)
+		assert.ErrorIs(t, err, tt.wantErr, tt.code)
+	}
+
+	cart, err := handler.ApplyGiftCard(context.Background(), promotionTestCart(), "gift-50")
+	require.NoError(t, err)
+
+	cart, err = handler.ApplyGiftCard(context.Background(), cart, "gift-50")
+	require.NoError(t, err)
+	require.Len(t, cart.AppliedGiftCards, 1, "applying a gift card twice is ignored")
+	assert.Equal(t, 50.0, cart.AppliedGiftCards[0].Remaining.FloatAmount())
+
+	cart, err = handler.RemoveGiftCard(context.Background(), cart, "gift-50")
+	require.NoError(t, err)
+	assert.Empty(t, cart.AppliedGiftCards)
+
+	_, err = handler.RemoveGiftCard(context.Background(), cart, "gift-50")
+	assert.ErrorIs(t, err, domaincart.ErrGiftCardNotFound)
+}
+
+func TestRegistryGiftCardHandler_CalculateGiftCards(t *testing.T) {
+	t.Parallel()
+
+	cart := &domaincart.Cart{
+		DefaultCurrency: "EUR",
+		GrandTotal:      priceDomain.NewFromFloat(60, "EUR"),
+		AppliedGiftCards: []domaincart.AppliedGiftCard{
+			{Code: "first", Applied: priceDomain.NewFromFloat(10, "EUR"), Remaining: priceDomain.NewFromFloat(30, "EUR")},
+			{Code: "second", Applied: priceDomain.NewZero("EUR"), Remaining: priceDomain.NewFromFloat(50, "EUR")},
+		},
+	}
+
+	require.NoError(t, new(RegistryGiftCardHandler).CalculateGiftCards(cart))
+
+	assert.Equal(t, 40.0, cart.AppliedGiftCards[0].Applied.FloatAmount())
+	assert.Equal(t, 0.0, cart.AppliedGiftCards[0].Remaining.FloatAmount())
+	assert.Equal(t, 20.0, cart.AppliedGiftCards[1].Applied.FloatAmount())
+	assert.Equal(t, 30.0, cart.AppliedGiftCards[1].Remaining.FloatAmount())
+}
+
+func TestRegistryGiftCardHandler_ReserveGiftCards(t *testing.T) {
+	t.Parallel()
+
+	registry := newInMemoryGiftCardRegistry()
+	handler := new(RegistryGiftCardHandler).Inject(registry)
+
+	cart := &domaincart.Cart{
+		ID: "cart",
+		AppliedGiftCards: []domaincart.AppliedGiftCard{
+			{Code: "gift-50", Applied: priceDomain.NewFromFloat(20, "EUR")},
+			{Code: "gift-expired", Applied: priceDomain.NewFromFloat(10, "EUR")},
+		},
+	}
+
+	err := handler.ReserveGiftCards(context.Background(), cart)
+	assert.ErrorIs(t, err, domaincart.ErrGiftCardExpired)
+
+	giftCard, err := registry.GiftCard(context.Background(), "gift-50")
+	require.NoError(t, err)
+	assert.Equal(t, 50.0, giftCard.Balance.FloatAmount(), "reservations are rolled back")
+}
+
+func TestDefaultCartBehaviour_RegistryGiftCardHandler(t *testing.T) {
+	t.Parallel()
+
+	registry := newInMemoryGiftCardRegistry()
+
+	cob := &DefaultCartBehaviour{}
+	cob.Inject(newInMemoryStorage(), nil, flamingo.NullLogger{}, nil, new(RegistryGiftCardHandler).Inject(registry), nil, nil)
+
+	cart := promotionTestCart(promotionTestItem("1", "a", 2, 50))
+	require.NoError(t, cob.cartStorage.StoreCart(context.Background(), cart))
+
+	cart, _, err := cob.ApplyGiftCard(context.Background(), cart, "gift-50")
+	require.NoError(t, err)
+	require.Len(t, cart.AppliedGiftCards, 1)
+	assert.Equal(t, 50.0, cart.AppliedGiftCards[0].Applied.FloatAmount())
+	assert.Equal(t, 50.0, cart.TotalGiftCardAmount.FloatAmount())
+
+	_, _, err = cob.Complete(context.Background(), cart)
+	require.NoError(t, err)
+
+	giftCard, err := registry.GiftCard(context.Background(), "gift-50")
+	require.NoError(t, err)
+	assert.True(t, giftCard.Balance.IsZero(), "applied amount is reserved on complete")
+
+	_, _, err = cob.Restore(context.Background(), cart)
+	require.NoError(t, err)
+
+	giftCard, err = registry.GiftCard(context.Background(), "gift-50")
+	require.NoError(t, err)
+	assert.Equal(t, 50.0, giftCard.Balance.FloatAmount(), "reservation is released on restore")
+
+	_, _, err = cob.Complete(context.Background(), cart)
+	require.NoError(t, err)
+
+	err = cob.Release(context.Background(), cart)
+	require.NoError(t, err)
+
+	giftCard, err = registry.GiftCard(context.Background(), "gift-50")
+	require.NoError(t, err)
+	assert.Equal(t, 50.0, giftCard.Balance.FloatAmount(), "reservation is released without restore")
+}
@@ -0,0 +1,251 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"go.opencensus.io/trace"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+	"flamingo.me/flamingo-commerce/v3/product/domain"
+)
+
+type (
+	// DefaultShippingRateProvider provides the shipping methods configured via commerce.cart.defaultCartAdapter.shipping.methods,
+	// the shipping costs are taxed by the TaxCalculator
+	DefaultShippingRateProvider struct {
+		productService  domain.ProductService
+		taxCalculator   TaxCalculator
+		rates           []ShippingRate
+		grossPricing    bool
+		weightAttribute string
+	}
+
+	// ShippingRate configures a shipping method and its price, restrictions with zero values don't apply
+	ShippingRate struct {
+		Code    string `json:"code"`
+		Carrier string `json:"carrier"`
+		Title   string `json:"title"`
+		// Price is the gross or net price depending on commerce.cart.defaultCartAdapter.productPrices
+		Price float64 `json:"price"`
+		// Countries the delivery address must be located in
+		Countries []string `json:"countries"`
+		// MinWeight and MaxWeight limit the sum of the item weights
+		MinWeight float64 `json:"minWeight"`
+		MaxWeight float64 `json:"maxWeight"`
+		// MinCartValue and MaxCartValue limit the sum of the row prices of the delivery
+		MinCartValue float64 `json:"minCartValue"`
+		MaxCartValue float64 `json:"maxCartValue"`
+		// ExcludedItemAttributes are product attributes which prevent the shipping method if enabled on any item, e.g. "bulky"
+		ExcludedItemAttributes []string `json:"excludedItemAttributes"`
+	}
+
+	// shippingProduct is the product of a cart item with its qty
+	shippingProduct struct {
+		product domain.BasicProduct
+		qty     int
+	}
+)
+
+var (
+	_ domaincart.ShippingRateProvider = (*DefaultShippingRateProvider)(nil)
+)
+
+// Inject dependencies
+func (p *DefaultShippingRateProvider) Inject(
+	productService domain.ProductService,
+	taxCalculator TaxCalculator,
+	logger flamingo.Logger,
+	cfg *struct {
+		ProductPricing  string       `inject:"config:commerce.cart.defaultCartAdapter.productPrices,optional"`
+		WeightAttribute string       `inject:"config:commerce.cart.defaultCartAdapter.shipping.weightAttribute,optional"`
+		Methods         config.Slice `inject:"config:commerce.cart.defaultCartAdapter.shipping.methods,optional"`
+	},
+) *DefaultShippingRateProvider {
+	p.productService = productService
+	p.taxCalculator = taxCalculator
+	p.weightAttribute = "weight"
+
+	if cfg == nil {
+		return p
+	}
+
+	p.grossPricing = cfg.ProductPricing == "gross"
+
+	if cfg.WeightAttribute != "" {
+		p.weightAttribute = cfg.WeightAttribute
+	}
+
+	if err := cfg.Methods.MapInto(&p.rates); err != nil {
+		logger.WithField(flamingo.LogKeyCategory, "DefaultShippingRateProvider").Error(fmt.Errorf("failed to map shipping methods: %w", err))
+	}
+
+	return p
+}
+
+// ShippingMethods returns the configured shipping methods available for the delivery, ordered like the configuration
+func (p *DefaultShippingRateProvider) ShippingMethods(ctx context.Context, cart *domaincart.Cart, delivery *domaincart.Delivery) ([]domaincart.ShippingMethod, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/DefaultShippingRateProvider/ShippingMethods")
+	defer span.End()
+
+	address := deliveryAddress(cart, delivery)
+
+	cartValue := 0.0
+	for _, item := range delivery.Cartitems {
+		if p.grossPricing {
+			cartValue += item.RowPriceGross.FloatAmount()
+		} else {
+			cartValue += item.RowPriceNet.FloatAmount()
+		}
+	}
+
+	products, err := p.products(ctx, delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	weight := 0.0
+	for _, product := range products {
+		weight += p.weight(product.product) * float64(product.qty)
+	}
+
+	methods := make([]domaincart.ShippingMethod, 0, len(p.rates))
+
+	for _, rate := range p.rates {
+		if !rate.isAvailable(address, weight, cartValue, products) {
+			continue
+		}
+
+		method, err := p.shippingMethod(rate, cart.DefaultCurrency, address)
+		if err != nil {
+			return nil, err
+		}
+
+		methods = append(methods, method)
+	}
+
+	return methods, nil
+}
+
+// products loads the products of the items, only if a shipping rate depends on them
+func (p *DefaultShippingRateProvider) products(ctx context.Context, delivery *domaincart.Delivery) ([]shippingProduct, error) {
+	needed := false
+	for _, rate := range p.rates {
+		needed = needed || rate.MinWeight > 0 || rate.MaxWeight > 0 || len(rate.ExcludedItemAttributes) > 0
+	}
+
+	if !needed {
+		return nil, nil
+	}
+
+	products := make([]shippingProduct, 0, len(delivery.Cartitems))
+
+	for _, item := range delivery.Cartitems {
+		product, err := p.productService.Get(ctx, item.MarketplaceCode)
+		if err != nil {
+			return nil, fmt.Errorf("DefaultShippingRateProvider: error getting product %q: %w", item.MarketplaceCode, err)
+		}
+
+		if configurableProduct, ok := product.(domain.ConfigurableProduct); ok && item.VariantMarketPlaceCode != "" {
+			product, err = configurableProduct.GetConfigurableWithActiveVariant(item.VariantMarketPlaceCode)
+			if err != nil {
+				return nil, fmt.Errorf("DefaultShippingRateProvider: error getting variant %q: %w", item.VariantMarketPlaceCode, err)
+			}
+		}
+
+		products = append(products, shippingProduct{product: product, qty: item.Qty})
+	}
+
+	return products, nil
+}
+
+// weight of a single product, products without a valid weight attribute weigh nothing
+func (p *DefaultShippingRateProvider) weight(product domain.BasicProduct) float64 {
+	if !product.BaseData().HasAttribute(p.weightAttribute) {
+		return 0
+	}
+
+	weight, err := strconv.ParseFloat(product.BaseData().Attribute(p.weightAttribute).Value(), 64)
+	if err != nil {
+		return 0
+	}
+
+	return weight
+}
+
+// shippingMethod calculates the net / gross price and the tax amount of the shipping rate
+func (p *DefaultShippingRateProvider) shippingMethod(rate ShippingRate, currency string, address *domaincart.Address) (domaincart.ShippingMethod, error) {
+	price := priceDomain.NewFromFloat(rate.Price, currency).GetPayable()
+	delivery := domaincart.Delivery{ShippingItem: domaincart.ShippingItem{
+		PriceNet:   priceDomain.NewZero(currency),
+		PriceGross: priceDomain.NewZero(currency),
+		TaxAmount:  priceDomain.NewZero(currency),
+	}}
+
+	if p.grossPricing {
+		delivery.ShippingItem.PriceGross = price
+	} else {
+		delivery.ShippingItem.PriceNet = price
+	}
+
+	err := p.taxCalculator.CalculateDeliveryTaxes(&delivery, address)
+	if err != nil {
+		return domaincart.ShippingMethod{}, fmt.Errorf("DefaultShippingRateProvider: error calculating taxes of shipping method %q: %w", rate.Code, err)
+	}
+
+	// without taxes the net and the gross price are equal
+	if delivery.ShippingItem.TaxAmount.IsZero() {
+		delivery.ShippingItem.PriceNet = price
+		delivery.ShippingItem.PriceGross = price
+	}
+
+	return domaincart.ShippingMethod{
+		Code:       rate.Code,
+		Carrier:    rate.Carrier,
+		Title:      rate.Title,
+		PriceNet:   delivery.ShippingItem.PriceNet,
+		PriceGross: delivery.ShippingItem.PriceGross,
+		TaxAmount:  delivery.ShippingItem.TaxAmount,
+	}, nil
+}
+
+func (r ShippingRate) isAvailable(address *domaincart.Address, weight float64, cartValue float64, products []shippingProduct) bool {
+	if len(r.Countries) > 0 {
+		if address == nil || !containsFold(r.Countries, address.CountryCode) {
+			return false
+		}
+	}
+
+	if (r.MinWeight > 0 && weight < r.MinWeight) || (r.MaxWeight > 0 && weight > r.MaxWeight) {
+		return false
+	}
+
+	if (r.MinCartValue > 0 && cartValue < r.MinCartValue) || (r.MaxCartValue > 0 && cartValue > r.MaxCartValue) {
+		return false
+	}
+
+	for _, product := range products {
+		for _, attribute := range r.ExcludedItemAttributes {
+			if product.product.BaseData().HasAttribute(attribute) && product.product.BaseData().Attribute(attribute).IsEnabledValue() {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
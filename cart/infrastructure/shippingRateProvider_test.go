@@ -0,0 +1,140 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo-commerce/v3/cart/application"
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/product/domain"
+)
+
+type shippingTestProductService map[string]domain.Attributes
+
+func (s shippingTestProductService) Get(_ context.Context, marketplaceCode string) (domain.BasicProduct, error) {
+	attributes, found := s[marketplaceCode]
+	if !found {
+		return nil, domain.ErrProductNotFound
+	}
+
+	return domain.SimpleProduct{BasicProductData: domain.BasicProductData{MarketPlaceCode: marketplaceCode, Attributes: attributes}}, nil
+}
+
+func newTestShippingRateProvider() *DefaultShippingRateProvider {
+	productService := shippingTestProductService{
+		"light": {"weight": {Code: "weight", RawValue: "1"}},
+		"heavy": {"weight": {Code: "weight", RawValue: 6.0}},
+		"sofa":  {"weight": {Code: "weight", RawValue: "30"}, "bulky": {Code: "bulky", RawValue: true}},
+	}
+
+	taxCalculator := newTestTaxCalculator("gross", TaxRoundingRow, 0, config.Slice{
+		config.Map{"type": "vat", "taxClass": "shipping", "rate": 19.0},
+	})
+
+	return new(DefaultShippingRateProvider).Inject(productService, taxCalculator, flamingo.NullLogger{}, &struct {
+		ProductPricing  string       `inject:"config:commerce.cart.defaultCartAdapter.productPrices,optional"`
+		WeightAttribute string       `inject:"config:commerce.cart.defaultCartAdapter.shipping.weightAttribute,optional"`
+		Methods         config.Slice `inject:"config:commerce.cart.defaultCartAdapter.shipping.methods,optional"`
+	}{
+		ProductPricing: "gross",
+		Methods: config.Slice{
+			config.Map{"code": "standard", "title": "Standard", "price": 5.95, "countries": config.Slice{"DE"}, "excludedItemAttributes": config.Slice{"bulky"}},
+			config.Map{"code": "express", "carrier": "DHL", "title": "Express", "price": 11.90, "maxWeight": 5.0},
+			config.Map{"code": "premium", "title": "Premium", "minCartValue": 100.0},
+		},
+	})
+}
+
+func shippingTestCart(country string, items ...domaincart.Item) *domaincart.Cart {
+	cart := promotionTestCart(items...)
+	cart.Deliveries[0].DeliveryInfo.DeliveryLocation.Address = &domaincart.Address{CountryCode: country}
+
+	return cart
+}
+
+func shippingMethodCodes(methods []domaincart.ShippingMethod) []string {
+	codes := make([]string, 0, len(methods))
+	for _, method := range methods {
+		codes = append(codes, method.Code)
+	}
+
+	return codes
+}
+
+func TestDefaultShippingRateProvider_ShippingMethods(t *testing.T) {
+	t.Parallel()
+
+	provider := newTestShippingRateProvider()
+
+	tests := []struct {
+		name string
+		cart *domaincart.Cart
+		want []string
+	}{
+		{name: "light items", cart: shippingTestCart("DE", promotionTestItem("1", "light", 2, 25)), want: []string{"standard", "express"}},
+		{name: "country", cart: shippingTestCart("AT", promotionTestItem("1", "light", 2, 25)), want: []string{"express"}},
+		{name: "weight", cart: shippingTestCart("DE", promotionTestItem("1", "heavy", 1, 25)), want: []string{"standard"}},
+		{name: "item attribute", cart: shippingTestCart("DE", promotionTestItem("1", "sofa", 1, 25)), want: []string{}},
+		{name: "cart value", cart: shippingTestCart("DE", promotionTestItem("1", "light", 2, 50)), want: []string{"standard", "express", "premium"}},
+	}
+
+	for _, tt := range tests {
+		methods, err := provider.ShippingMethods(context.Background(), tt.cart, &tt.cart.Deliveries[0])
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, shippingMethodCodes(methods), tt.name)
+	}
+
+	cart := shippingTestCart("DE", promotionTestItem("1", "light", 1, 25))
+	methods, err := provider.ShippingMethods(context.Background(), cart, &cart.Deliveries[0])
+	require.NoError(t, err)
+	require.Len(t, methods, 2)
+	assert.Equal(t, "DHL", methods[1].Carrier)
+	assertAmount(t, 11.90, methods[1].PriceGross, "gross price")
+	assertAmount(t, 10.00, methods[1].PriceNet, "net price")
+	assertAmount(t, 1.90, methods[1].TaxAmount, "tax amount")
+
+	cart = shippingTestCart("DE", promotionTestItem("1", "unknown", 1, 25))
+	_, err = provider.ShippingMethods(context.Background(), cart, &cart.Deliveries[0])
+	assert.Error(t, err)
+}
+
+func TestDefaultCartBehaviour_ShippingMethods(t *testing.T) {
+	t.Parallel()
+
+	cob := &DefaultCartBehaviour{}
+	cob.Inject(newInMemoryStorage(), nil, flamingo.NullLogger{}, nil, nil, nil, &struct {
+		ShippingRateProvider domaincart.ShippingRateProvider        `inject:",optional"`
+		CartExpirer          CartExpirer                            `inject:",optional"`
+		LineItemStrategy     domaincart.LineItemStrategy            `inject:",optional"`
+		TaxCalculator        TaxCalculator                          `inject:",optional"`
+		PostProcessors       *application.CartPostProcessorPipeline `inject:",optional"`
+	}{ShippingRateProvider: newTestShippingRateProvider()})
+
+	cart := shippingTestCart("DE", promotionTestItem("1", "light", 2, 25))
+	require.NoError(t, cob.cartStorage.StoreCart(context.Background(), cart))
+
+	deliveryInfo := cart.Deliveries[0].DeliveryInfo
+	deliveryInfo.Method = "standard"
+
+	cart, _, err := cob.UpdateDeliveryInfo(context.Background(), cart, "delivery", domaincart.CreateDeliveryInfoUpdateCommand(deliveryInfo))
+	require.NoError(t, err)
+	assertAmount(t, 5.95, cart.Deliveries[0].ShippingItem.PriceGross, "shipping costs of the chosen method")
+	assertAmount(t, 55.95, cart.GrandTotal, "shipping costs are part of the grand total")
+
+	deliveryInfo.Method = "premium"
+	_, _, err = cob.UpdateDeliveryInfo(context.Background(), cart, "delivery", domaincart.CreateDeliveryInfoUpdateCommand(deliveryInfo))
+	assert.ErrorIs(t, err, domaincart.ErrShippingMethodNotAvailable)
+
+	// a changed address only removes the shipping costs of the method which isn't available anymore
+	deliveryInfo.Method = "standard"
+	deliveryInfo.DeliveryLocation.Address = &domaincart.Address{CountryCode: "AT"}
+	cart, _, err = cob.UpdateDeliveryInfo(context.Background(), cart, "delivery", domaincart.CreateDeliveryInfoUpdateCommand(deliveryInfo))
+	require.NoError(t, err)
+	assert.True(t, cart.Deliveries[0].ShippingItem.PriceGross.IsZero())
+	assertAmount(t, 50.00, cart.GrandTotal, "grand total without shipping costs")
+}
@@ -0,0 +1,435 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"flamingo.me/flamingo/v3/core/healthcheck/domain/healthcheck"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"go.opencensus.io/trace"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+)
+
+type (
+	// SQLCartStorage stores gob encoded carts together with their revision in a database/sql table.
+	// The statements use "?" placeholders and a BLOB column, so the storage supports SQLite and MySQL/MariaDB
+	// but not e.g. PostgreSQL. The matching driver (e.g. a SQLite driver registered as "sqlite3") must be imported by the project.
+	SQLCartStorage struct {
+		db          *sql.DB
+		logger      flamingo.Logger
+		ttl         time.Duration
+		table       string
+		prepareLock sync.Mutex
+		prepared    bool
+		now         func() time.Time
+	}
+)
+
+var (
+	errSQLCartInsertFailed = errors.New("insert failed")
+
+	_ CartStorage         = &SQLCartStorage{}
+	_ IterableCartStorage = &SQLCartStorage{}
+	_ CustomerCartIndex   = &SQLCartStorage{}
+	_ healthcheck.Status  = &SQLCartStorage{}
+)
+
+// Inject dependencies
+func (s *SQLCartStorage) Inject(
+	logger flamingo.Logger,
+	cfg *struct {
+		Driver string `inject:"config:commerce.cart.defaultCartAdapter.sql.driver"`
+		DSN    string `inject:"config:commerce.cart.defaultCartAdapter.sql.dsn"`
+		Table  string `inject:"config:commerce.cart.defaultCartAdapter.sql.table"`
+		TTL    string `inject:"config:commerce.cart.defaultCartAdapter.sql.ttl"`
+	},
+) *SQLCartStorage {
+	s.logger = logger.WithField(flamingo.LogKeyModule, "cart").WithField(flamingo.LogKeyCategory, "SQLCartStorage")
+	s.now = time.Now
+
+	if cfg != nil {
+		var err error
+		s.ttl, err = time.ParseDuration(cfg.TTL)
+		if err != nil {
+			panic("can't parse commerce.cart.defaultCartAdapter.sql.ttl")
+		}
+
+		s.table = cfg.Table
+
+		// sql.Open only validates the arguments, the connection is established lazily
+		s.db, err = sql.Open(cfg.Driver, cfg.DSN)
+		if err != nil {
+			panic(fmt.Sprintf("can't open cart storage database with driver %q: %v", cfg.Driver, err))
+		}
+	}
+
+	return s
+}
+
+// prepare creates the cart table if it does not exist yet and migrates tables created without the revision or customer column,
+// a failed preparation is retried with the next call
+func (s *SQLCartStorage) prepare() error {
+	s.prepareLock.Lock()
+	defer s.prepareLock.Unlock()
+
+	if s.prepared {
+		return nil
+	}
+
+	// the preparation is shared by all requests, a cancelled request must not break it
+	ctx := context.Background()
+
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s (id VARCHAR(255) NOT NULL PRIMARY KEY, revision BIGINT NOT NULL, data BLOB NOT NULL, expires_at BIGINT NOT NULL)",
+		s.table,
+	))
+	if err != nil {
+		return err
+	}
+
+	if err := s.addRevisionColumn(ctx); err != nil {
+		return err
+	}
+
+	if err := s.addCustomerColumn(ctx); err != nil {
+		return err
+	}
+
+	s.prepared = true
+
+	return nil
+}
+
+// addRevisionColumn migrates tables created before cart revisions were introduced, their carts start at revision 0
+func (s *SQLCartStorage) addRevisionColumn(ctx context.Context) error {
+	if s.hasColumn(ctx, "revision") {
+		return nil
+	}
+
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN revision BIGINT NOT NULL DEFAULT 0", s.table))
+	if err != nil {
+		return fmt.Errorf("can't add revision column to table %s: %w", s.table, err)
+	}
+
+	return nil
+}
+
+// addCustomerColumn adds the indexed customer column used to look up the carts of a customer and fills it for the stored carts.
+// The column is added by this migration for new tables as well, since SQLite and MySQL differ in creating an index only if it doesn't exist.
+func (s *SQLCartStorage) addCustomerColumn(ctx context.Context) error {
+	if s.hasColumn(ctx, "customer_id") {
+		return nil
+	}
+
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN customer_id VARCHAR(255) NOT NULL DEFAULT ''", s.table))
+	if err != nil {
+		return fmt.Errorf("can't add customer column to table %s: %w", s.table, err)
+	}
+
+	_, err = s.db.ExecContext(ctx, fmt.Sprintf("CREATE INDEX %s_customer_id ON %s (customer_id)", s.table, s.table))
+	if err != nil {
+		return fmt.Errorf("can't add customer index to table %s: %w", s.table, err)
+	}
+
+	customerIDs := make(map[string]string)
+
+	err = s.forEachRow(ctx, fmt.Sprintf("SELECT id, data FROM %s", s.table), nil, func(cart *domaincart.Cart) error {
+		if cart.AuthenticatedUserID != "" {
+			customerIDs[cart.ID] = cart.AuthenticatedUserID
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for id, customerID := range customerIDs {
+		_, err := s.db.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET customer_id = ? WHERE id = ?", s.table), customerID, id)
+		if err != nil {
+			return fmt.Errorf("can't set customer of cart %q: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *SQLCartStorage) hasColumn(ctx context.Context, column string) bool {
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s WHERE 1 = 0", column, s.table))
+	if err != nil {
+		return false
+	}
+
+	_ = rows.Close()
+
+	return true
+}
+
+// HasCart checks if the cart storage has a not yet expired cart with a given id
+func (s *SQLCartStorage) HasCart(ctx context.Context, id string) bool {
+	ctx, span := trace.StartSpan(ctx, "cart/SQLCartStorage/HasCart")
+	defer span.End()
+
+	if err := s.prepare(); err != nil {
+		s.logger.WithContext(ctx).Error("HasCart:", err)
+		return false
+	}
+
+	var count int
+	err := s.db.QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE id = ? AND expires_at > ?", s.table), id, s.now().Unix()).Scan(&count)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("HasCart:", err)
+		return false
+	}
+
+	return count > 0
+}
+
+// GetCart returns a not yet expired cart with the given id from the cart storage
+func (s *SQLCartStorage) GetCart(ctx context.Context, id string) (*domaincart.Cart, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/SQLCartStorage/GetCart")
+	defer span.End()
+
+	if err := s.prepare(); err != nil {
+		return nil, fmt.Errorf("SQLCartStorage: error preparing table: %w", err)
+	}
+
+	var content []byte
+	err := s.db.QueryRowContext(ctx, fmt.Sprintf("SELECT data FROM %s WHERE id = ? AND expires_at > ?", s.table), id, s.now().Unix()).Scan(&content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("SQLCartStorage: %w for cart id %q", domaincart.ErrCartNotFound, id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("SQLCartStorage: error loading cart %q: %w", id, err)
+	}
+
+	cart := new(domaincart.Cart)
+	err = gob.NewDecoder(bytes.NewBuffer(content)).Decode(cart)
+	if err != nil {
+		return nil, fmt.Errorf("SQLCartStorage: cart %q is not decodable: %w", id, err)
+	}
+
+	return cart, nil
+}
+
+// StoreCart stores a cart in the storage if it is based on the currently stored revision and (re)sets its expiry
+func (s *SQLCartStorage) StoreCart(ctx context.Context, cart *domaincart.Cart) error {
+	ctx, span := trace.StartSpan(ctx, "cart/SQLCartStorage/StoreCart")
+	defer span.End()
+
+	if err := s.prepare(); err != nil {
+		return fmt.Errorf("SQLCartStorage: error preparing table: %w", err)
+	}
+
+	buffer := new(bytes.Buffer)
+	err := gob.NewEncoder(buffer).Encode(cart)
+	if err != nil {
+		return fmt.Errorf("SQLCartStorage: error encoding cart %q: %w", cart.ID, err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("SQLCartStorage: error starting transaction: %w", err)
+	}
+
+	err = s.storeCart(ctx, tx, cart, buffer.Bytes())
+	if err != nil {
+		_ = tx.Rollback()
+
+		if errors.Is(err, errSQLCartInsertFailed) {
+			return s.checkConcurrentInsert(ctx, cart, err)
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// checkConcurrentInsert reports a failed insert as revision conflict if a concurrent store inserted the cart in the meantime,
+// the primary key violation itself is driver specific
+func (s *SQLCartStorage) checkConcurrentInsert(ctx context.Context, cart *domaincart.Cart, insertErr error) error {
+	var storedRevision int
+	err := s.db.QueryRowContext(ctx, fmt.Sprintf("SELECT revision FROM %s WHERE id = ?", s.table), cart.ID).Scan(&storedRevision)
+	if err != nil {
+		return insertErr
+	}
+
+	if err := checkRevision(storedRevision, cart); err != nil {
+		return err
+	}
+
+	return insertErr
+}
+
+// storeCart updates the row of the previous revision or inserts a new one, plain statements are used since SQLite and MySQL differ in their upsert syntax
+func (s *SQLCartStorage) storeCart(ctx context.Context, tx *sql.Tx, cart *domaincart.Cart, data []byte) error {
+	now := s.now()
+
+	// expired carts are treated as not existing
+	_, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = ? AND expires_at <= ?", s.table), cart.ID, now.Unix())
+	if err != nil {
+		return fmt.Errorf("SQLCartStorage: error saving cart %q: %w", cart.ID, err)
+	}
+
+	result, err := tx.ExecContext(
+		ctx,
+		fmt.Sprintf("UPDATE %s SET revision = ?, data = ?, expires_at = ?, customer_id = ? WHERE id = ? AND revision = ?", s.table),
+		cart.Revision, data, now.Add(s.ttl).Unix(), cart.AuthenticatedUserID, cart.ID, cart.Revision-1,
+	)
+	if err != nil {
+		return fmt.Errorf("SQLCartStorage: error saving cart %q: %w", cart.ID, err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+		return nil
+	}
+
+	var storedRevision int
+	err = tx.QueryRowContext(ctx, fmt.Sprintf("SELECT revision FROM %s WHERE id = ?", s.table), cart.ID).Scan(&storedRevision)
+	if err == nil {
+		return checkRevision(storedRevision, cart)
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("SQLCartStorage: error saving cart %q: %w", cart.ID, err)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		fmt.Sprintf("INSERT INTO %s (id, revision, data, expires_at, customer_id) VALUES (?, ?, ?, ?, ?)", s.table),
+		cart.ID, cart.Revision, data, now.Add(s.ttl).Unix(), cart.AuthenticatedUserID,
+	)
+	if err != nil {
+		return fmt.Errorf("SQLCartStorage: error saving cart %q: %w: %w", cart.ID, errSQLCartInsertFailed, err)
+	}
+
+	return nil
+}
+
+// RemoveCart from storage
+func (s *SQLCartStorage) RemoveCart(ctx context.Context, cart *domaincart.Cart) error {
+	ctx, span := trace.StartSpan(ctx, "cart/SQLCartStorage/RemoveCart")
+	defer span.End()
+
+	if err := s.prepare(); err != nil {
+		return fmt.Errorf("SQLCartStorage: error preparing table: %w", err)
+	}
+
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = ?", s.table), cart.ID)
+
+	return err
+}
+
+// ForEachCart calls fn for every not yet expired cart, the rows are read completely before fn is called
+func (s *SQLCartStorage) ForEachCart(ctx context.Context, fn func(cart *domaincart.Cart) error) error {
+	ctx, span := trace.StartSpan(ctx, "cart/SQLCartStorage/ForEachCart")
+	defer span.End()
+
+	if err := s.prepare(); err != nil {
+		return fmt.Errorf("SQLCartStorage: error preparing table: %w", err)
+	}
+
+	var carts []*domaincart.Cart
+
+	err := s.forEachRow(ctx, fmt.Sprintf("SELECT id, data FROM %s WHERE expires_at > ?", s.table), []interface{}{s.now().Unix()}, func(cart *domaincart.Cart) error {
+		carts = append(carts, cart)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("SQLCartStorage: %w", err)
+	}
+
+	for _, cart := range carts {
+		if err := fn(cart); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CartIDsOfCustomer returns the ids of the not yet expired carts of the customer
+func (s *SQLCartStorage) CartIDsOfCustomer(ctx context.Context, customerID string) ([]string, error) {
+	ctx, span := trace.StartSpan(ctx, "cart/SQLCartStorage/CartIDsOfCustomer")
+	defer span.End()
+
+	if err := s.prepare(); err != nil {
+		return nil, fmt.Errorf("SQLCartStorage: error preparing table: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf("SELECT id FROM %s WHERE customer_id = ? AND expires_at > ?", s.table), customerID, s.now().Unix())
+	if err != nil {
+		return nil, fmt.Errorf("SQLCartStorage: error loading carts of customer: %w", err)
+	}
+
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("SQLCartStorage: error loading carts of customer: %w", err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("SQLCartStorage: error loading carts of customer: %w", err)
+	}
+
+	return ids, nil
+}
+
+// forEachRow decodes the carts of a query selecting id and data, fn is called while the rows are read
+func (s *SQLCartStorage) forEachRow(ctx context.Context, query string, args []interface{}, fn func(cart *domaincart.Cart) error) error {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("error loading carts: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var content []byte
+		if err := rows.Scan(&id, &content); err != nil {
+			return fmt.Errorf("error loading carts: %w", err)
+		}
+
+		cart := new(domaincart.Cart)
+		if err := gob.NewDecoder(bytes.NewBuffer(content)).Decode(cart); err != nil {
+			return fmt.Errorf("cart %q is not decodable: %w", id, err)
+		}
+
+		if err := fn(cart); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error loading carts: %w", err)
+	}
+
+	return nil
+}
+
+// Status handles the health check of the database
+func (s *SQLCartStorage) Status() (alive bool, details string) {
+	err := s.db.Ping()
+	if err == nil {
+		return true, "database for cart storage is reachable"
+	}
+
+	return false, err.Error()
+}
@@ -0,0 +1,566 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+)
+
+type (
+	// fakeSQLDriver is a minimal database/sql driver which understands the statements of the SQLCartStorage on the table "carts",
+	// transactions are not isolated, statements are applied immediately
+	fakeSQLDriver struct{}
+
+	fakeSQLDatabase struct {
+		mutex        sync.Mutex
+		tableCreated bool
+		hasRevision  bool
+		hasCustomer  bool
+		rows         map[string]fakeSQLRow
+		// beforeInsert is called before a row is inserted, e.g. to simulate a concurrent insert
+		beforeInsert func(db *fakeSQLDatabase)
+		// failingCreates is the number of CREATE TABLE statements which fail, e.g. to simulate an unreachable database
+		failingCreates int
+	}
+
+	fakeSQLRow struct {
+		revision   int64
+		data       []byte
+		expiresAt  int64
+		customerID string
+	}
+
+	fakeSQLConn struct {
+		db *fakeSQLDatabase
+	}
+
+	fakeSQLStmt struct {
+		db    *fakeSQLDatabase
+		query string
+	}
+
+	fakeSQLRows struct {
+		columns []string
+		values  [][]driver.Value
+	}
+)
+
+var (
+	fakeSQLDatabases     = map[string]*fakeSQLDatabase{}
+	fakeSQLDatabasesLock sync.Mutex
+	errFakeSQLNoColumn   = errors.New("no such column")
+)
+
+func init() {
+	sql.Register("fakecartsql", fakeSQLDriver{})
+}
+
+func (fakeSQLDriver) Open(name string) (driver.Conn, error) {
+	fakeSQLDatabasesLock.Lock()
+	defer fakeSQLDatabasesLock.Unlock()
+
+	db, ok := fakeSQLDatabases[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown fake database %q", name)
+	}
+
+	return &fakeSQLConn{db: db}, nil
+}
+
+func (c *fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeSQLStmt{db: c.db, query: query}, nil
+}
+
+func (c *fakeSQLConn) Close() error {
+	return nil
+}
+
+func (c *fakeSQLConn) Begin() (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *fakeSQLConn) Commit() error {
+	return nil
+}
+
+func (c *fakeSQLConn) Rollback() error {
+	return nil
+}
+
+func (s *fakeSQLStmt) Close() error {
+	return nil
+}
+
+func (s *fakeSQLStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	db := s.db
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	if strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS carts ") {
+		if db.failingCreates > 0 {
+			db.failingCreates--
+			return nil, errors.New("database not reachable")
+		}
+
+		if !db.tableCreated {
+			db.tableCreated = true
+			db.hasRevision = true
+		}
+
+		return driver.RowsAffected(0), nil
+	}
+
+	if !db.tableCreated {
+		return nil, errors.New("no such table: carts")
+	}
+
+	switch {
+	case strings.HasPrefix(s.query, "ALTER TABLE carts ADD COLUMN revision "):
+		db.hasRevision = true
+
+		return driver.RowsAffected(0), nil
+	case strings.HasPrefix(s.query, "ALTER TABLE carts ADD COLUMN customer_id "):
+		db.hasCustomer = true
+
+		return driver.RowsAffected(0), nil
+	case s.query == "CREATE INDEX carts_customer_id ON carts (customer_id)":
+		return driver.RowsAffected(0), nil
+	case s.query == "UPDATE carts SET customer_id = ? WHERE id = ?":
+		row := db.rows[args[1].(string)]
+		row.customerID = args[0].(string)
+		db.rows[args[1].(string)] = row
+
+		return driver.RowsAffected(1), nil
+	case s.query == "DELETE FROM carts WHERE id = ? AND expires_at <= ?":
+		if row, ok := db.rows[args[0].(string)]; ok && row.expiresAt <= args[1].(int64) {
+			delete(db.rows, args[0].(string))
+			return driver.RowsAffected(1), nil
+		}
+
+		return driver.RowsAffected(0), nil
+	case s.query == "DELETE FROM carts WHERE id = ?":
+		delete(db.rows, args[0].(string))
+
+		return driver.RowsAffected(1), nil
+	case s.query == "UPDATE carts SET revision = ?, data = ?, expires_at = ?, customer_id = ? WHERE id = ? AND revision = ?":
+		if !db.hasRevision || !db.hasCustomer {
+			return nil, errFakeSQLNoColumn
+		}
+
+		row, ok := db.rows[args[4].(string)]
+		if !ok || row.revision != args[5].(int64) {
+			return driver.RowsAffected(0), nil
+		}
+
+		db.rows[args[4].(string)] = fakeSQLRow{revision: args[0].(int64), data: args[1].([]byte), expiresAt: args[2].(int64), customerID: args[3].(string)}
+
+		return driver.RowsAffected(1), nil
+	case s.query == "INSERT INTO carts (id, revision, data, expires_at, customer_id) VALUES (?, ?, ?, ?, ?)":
+		if !db.hasRevision || !db.hasCustomer {
+			return nil, errFakeSQLNoColumn
+		}
+
+		if db.beforeInsert != nil {
+			db.beforeInsert(db)
+		}
+
+		if _, ok := db.rows[args[0].(string)]; ok {
+			return nil, errors.New("UNIQUE constraint failed: carts.id")
+		}
+
+		db.rows[args[0].(string)] = fakeSQLRow{revision: args[1].(int64), data: args[2].([]byte), expiresAt: args[3].(int64), customerID: args[4].(string)}
+
+		return driver.RowsAffected(1), nil
+	}
+
+	return nil, fmt.Errorf("unsupported statement %q", s.query)
+}
+
+func (s *fakeSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	db := s.db
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	if !db.tableCreated {
+		return nil, errors.New("no such table: carts")
+	}
+
+	switch s.query {
+	case "SELECT revision FROM carts WHERE 1 = 0":
+		if !db.hasRevision {
+			return nil, errFakeSQLNoColumn
+		}
+
+		return &fakeSQLRows{columns: []string{"revision"}}, nil
+	case "SELECT customer_id FROM carts WHERE 1 = 0":
+		if !db.hasCustomer {
+			return nil, errFakeSQLNoColumn
+		}
+
+		return &fakeSQLRows{columns: []string{"customer_id"}}, nil
+	case "SELECT revision FROM carts WHERE id = ?":
+		if !db.hasRevision {
+			return nil, errFakeSQLNoColumn
+		}
+
+		rows := &fakeSQLRows{columns: []string{"revision"}}
+		if row, ok := db.rows[args[0].(string)]; ok {
+			rows.values = append(rows.values, []driver.Value{row.revision})
+		}
+
+		return rows, nil
+	case "SELECT COUNT(*) FROM carts WHERE id = ? AND expires_at > ?":
+		count := int64(0)
+		if row, ok := db.rows[args[0].(string)]; ok && row.expiresAt > args[1].(int64) {
+			count = 1
+		}
+
+		return &fakeSQLRows{columns: []string{"count"}, values: [][]driver.Value{{count}}}, nil
+	case "SELECT data FROM carts WHERE id = ? AND expires_at > ?":
+		rows := &fakeSQLRows{columns: []string{"data"}}
+		if row, ok := db.rows[args[0].(string)]; ok && row.expiresAt > args[1].(int64) {
+			rows.values = append(rows.values, []driver.Value{row.data})
+		}
+
+		return rows, nil
+	case "SELECT id, data FROM carts":
+		rows := &fakeSQLRows{columns: []string{"id", "data"}}
+		for id, row := range db.rows {
+			rows.values = append(rows.values, []driver.Value{id, row.data})
+		}
+
+		return rows, nil
+	case "SELECT id FROM carts WHERE customer_id = ? AND expires_at > ?":
+		rows := &fakeSQLRows{columns: []string{"id"}}
+		for id, row := range db.rows {
+			if row.customerID == args[0].(string) && row.expiresAt > args[1].(int64) {
+				rows.values = append(rows.values, []driver.Value{id})
+			}
+		}
+
+		return rows, nil
+	case "SELECT id, data FROM carts WHERE expires_at > ?":
+		rows := &fakeSQLRows{columns: []string{"id", "data"}}
+		for id, row := range db.rows {
+			if row.expiresAt > args[0].(int64) {
+				rows.values = append(rows.values, []driver.Value{id, row.data})
+			}
+		}
+
+		return rows, nil
+	}
+
+	return nil, fmt.Errorf("unsupported query %q", s.query)
+}
+
+func (r *fakeSQLRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeSQLRows) Close() error {
+	return nil
+}
+
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+
+	return nil
+}
+
+func newFakeSQLCartStorage(t *testing.T, db *fakeSQLDatabase, now *time.Time) *SQLCartStorage {
+	t.Helper()
+
+	if db.rows == nil {
+		db.rows = make(map[string]fakeSQLRow)
+	}
+
+	fakeSQLDatabasesLock.Lock()
+	fakeSQLDatabases[t.Name()] = db
+	fakeSQLDatabasesLock.Unlock()
+
+	storage := new(SQLCartStorage).Inject(
+		flamingo.NullLogger{},
+		&struct {
+			Driver string `inject:"config:commerce.cart.defaultCartAdapter.sql.driver"`
+			DSN    string `inject:"config:commerce.cart.defaultCartAdapter.sql.dsn"`
+			Table  string `inject:"config:commerce.cart.defaultCartAdapter.sql.table"`
+			TTL    string `inject:"config:commerce.cart.defaultCartAdapter.sql.ttl"`
+		}{Driver: "fakecartsql", DSN: t.Name(), Table: "carts", TTL: "1h"},
+	)
+	storage.now = func() time.Time { return *now }
+
+	return storage
+}
+
+func newSQLiteCartStorage(t *testing.T, now *time.Time) (*SQLCartStorage, *sql.DB) {
+	t.Helper()
+
+	dsn := filepath.Join(t.TempDir(), "carts.db")
+	db, err := sql.Open("sqlite3", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	if err := db.Ping(); err != nil {
+		// the driver is only functional if built with cgo
+		t.Skipf("sqlite not available: %s", err)
+	}
+
+	storage := new(SQLCartStorage).Inject(
+		flamingo.NullLogger{},
+		&struct {
+			Driver string `inject:"config:commerce.cart.defaultCartAdapter.sql.driver"`
+			DSN    string `inject:"config:commerce.cart.defaultCartAdapter.sql.dsn"`
+			Table  string `inject:"config:commerce.cart.defaultCartAdapter.sql.table"`
+			TTL    string `inject:"config:commerce.cart.defaultCartAdapter.sql.ttl"`
+		}{Driver: "sqlite3", DSN: dsn, Table: "carts", TTL: "1h"},
+	)
+	storage.now = func() time.Time { return *now }
+	t.Cleanup(func() { _ = storage.db.Close() })
+
+	return storage, db
+}
+
+func TestSQLCartStorage(t *testing.T) {
+	t.Run("fake driver", func(t *testing.T) {
+		now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		testSQLCartStorage(t, newFakeSQLCartStorage(t, new(fakeSQLDatabase), &now), &now)
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		storage, _ := newSQLiteCartStorage(t, &now)
+		testSQLCartStorage(t, storage, &now)
+	})
+}
+
+func testSQLCartStorage(t *testing.T, storage *SQLCartStorage, now *time.Time) {
+	t.Helper()
+
+	cart := &domaincart.Cart{
+		ID:              "cart-1",
+		Revision:        1,
+		DefaultCurrency: "EUR",
+		Deliveries: []domaincart.Delivery{
+			{
+				DeliveryInfo: domaincart.DeliveryInfo{Code: "delivery"},
+				Cartitems:    []domaincart.Item{{ID: "item-1", MarketplaceCode: "sku-1", Qty: 2}},
+			},
+		},
+	}
+
+	t.Run("unknown cart", func(t *testing.T) {
+		assert.False(t, storage.HasCart(context.Background(), "unknown"))
+
+		_, err := storage.GetCart(context.Background(), "unknown")
+		assert.ErrorIs(t, err, domaincart.ErrCartNotFound)
+	})
+
+	t.Run("store and get cart", func(t *testing.T) {
+		require.NoError(t, storage.StoreCart(context.Background(), cart))
+		assert.True(t, storage.HasCart(context.Background(), cart.ID))
+
+		got, err := storage.GetCart(context.Background(), cart.ID)
+		require.NoError(t, err)
+		assert.Equal(t, cart.ID, got.ID)
+		assert.Equal(t, 1, got.Revision)
+		assert.Equal(t, "sku-1", got.Deliveries[0].Cartitems[0].MarketplaceCode)
+		assert.Equal(t, 2, got.Deliveries[0].Cartitems[0].Qty)
+	})
+
+	t.Run("store outdated cart", func(t *testing.T) {
+		updated := *cart
+		updated.Revision = 2
+		require.NoError(t, storage.StoreCart(context.Background(), &updated))
+
+		err := storage.StoreCart(context.Background(), &updated)
+		var conflictErr *domaincart.RevisionConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, 1, conflictErr.ExpectedRevision)
+		assert.Equal(t, 2, conflictErr.ActualRevision)
+
+		skipped := *cart
+		skipped.Revision = 4
+		require.ErrorAs(t, storage.StoreCart(context.Background(), &skipped), &conflictErr)
+
+		got, err := storage.GetCart(context.Background(), cart.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, got.Revision)
+	})
+
+	t.Run("for each cart", func(t *testing.T) {
+		require.NoError(t, storage.StoreCart(context.Background(), &domaincart.Cart{ID: "cart-2", Revision: 1}))
+
+		var ids []string
+		require.NoError(t, storage.ForEachCart(context.Background(), func(cart *domaincart.Cart) error {
+			ids = append(ids, cart.ID)
+			return nil
+		}))
+		assert.ElementsMatch(t, []string{"cart-1", "cart-2"}, ids)
+	})
+
+	t.Run("carts of customer", func(t *testing.T) {
+		require.NoError(t, storage.StoreCart(context.Background(), &domaincart.Cart{ID: "cart-3", Revision: 1, AuthenticatedUserID: "customer-1"}))
+
+		ids, err := storage.CartIDsOfCustomer(context.Background(), "customer-1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cart-3"}, ids)
+
+		ids, err = storage.CartIDsOfCustomer(context.Background(), "customer-2")
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+	})
+
+	t.Run("expired carts", func(t *testing.T) {
+		*now = now.Add(30 * time.Minute)
+		require.NoError(t, storage.StoreCart(context.Background(), &domaincart.Cart{ID: "cart-2", Revision: 2}), "store resets the expiry")
+
+		*now = now.Add(45 * time.Minute)
+		assert.False(t, storage.HasCart(context.Background(), cart.ID))
+
+		_, err := storage.GetCart(context.Background(), cart.ID)
+		assert.ErrorIs(t, err, domaincart.ErrCartNotFound)
+
+		var ids []string
+		require.NoError(t, storage.ForEachCart(context.Background(), func(cart *domaincart.Cart) error {
+			ids = append(ids, cart.ID)
+			return nil
+		}))
+		assert.Equal(t, []string{"cart-2"}, ids)
+
+		ids, err = storage.CartIDsOfCustomer(context.Background(), "customer-1")
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+
+		require.NoError(t, storage.StoreCart(context.Background(), cart), "an expired cart is treated as not existing")
+		assert.True(t, storage.HasCart(context.Background(), cart.ID))
+	})
+
+	t.Run("remove cart", func(t *testing.T) {
+		require.NoError(t, storage.RemoveCart(context.Background(), cart))
+		assert.False(t, storage.HasCart(context.Background(), cart.ID))
+		assert.True(t, storage.HasCart(context.Background(), "cart-2"))
+	})
+}
+
+func TestSQLCartStorage_ConcurrentFirstStore(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	db := &fakeSQLDatabase{
+		beforeInsert: func(db *fakeSQLDatabase) {
+			db.rows["cart-1"] = fakeSQLRow{revision: 1, data: []byte("concurrent"), expiresAt: now.Add(time.Hour).Unix()}
+		},
+	}
+	storage := newFakeSQLCartStorage(t, db, &now)
+
+	err := storage.StoreCart(context.Background(), &domaincart.Cart{ID: "cart-1", Revision: 1})
+
+	var conflictErr *domaincart.RevisionConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, 1, conflictErr.ActualRevision)
+	assert.Equal(t, []byte("concurrent"), db.rows["cart-1"].data)
+}
+
+func TestSQLCartStorage_Migration(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	buffer := new(bytes.Buffer)
+	require.NoError(t, gob.NewEncoder(buffer).Encode(domaincart.Cart{ID: "legacy", DefaultCurrency: "EUR", AuthenticatedUserID: "customer-1"}))
+
+	t.Run("fake driver", func(t *testing.T) {
+		db := &fakeSQLDatabase{
+			tableCreated: true,
+			rows:         map[string]fakeSQLRow{"legacy": {data: buffer.Bytes(), expiresAt: now.Add(time.Hour).Unix()}},
+		}
+		storage := newFakeSQLCartStorage(t, db, &now)
+
+		testSQLCartStorageMigration(t, storage)
+		assert.True(t, db.hasRevision, "the revision column is added")
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		storage, db := newSQLiteCartStorage(t, &now)
+
+		_, err := db.Exec("CREATE TABLE carts (id VARCHAR(255) NOT NULL PRIMARY KEY, data BLOB NOT NULL, expires_at BIGINT NOT NULL)")
+		require.NoError(t, err)
+		_, err = db.Exec("INSERT INTO carts (id, data, expires_at) VALUES (?, ?, ?)", "legacy", buffer.Bytes(), now.Add(time.Hour).Unix())
+		require.NoError(t, err)
+
+		testSQLCartStorageMigration(t, storage)
+	})
+}
+
+func testSQLCartStorageMigration(t *testing.T, storage *SQLCartStorage) {
+	t.Helper()
+
+	got, err := storage.GetCart(context.Background(), "legacy")
+	require.NoError(t, err)
+	assert.Equal(t, 0, got.Revision)
+
+	ids, err := storage.CartIDsOfCustomer(context.Background(), "customer-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"legacy"}, ids, "the customer of stored carts is migrated")
+
+	got.Revision = 1
+	require.NoError(t, storage.StoreCart(context.Background(), got))
+
+	got, err = storage.GetCart(context.Background(), "legacy")
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Revision)
+}
+
+func TestSQLCartStorage_Prepare(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("failed preparation is retried", func(t *testing.T) {
+		db := &fakeSQLDatabase{failingCreates: 1}
+		storage := newFakeSQLCartStorage(t, db, &now)
+
+		_, err := storage.GetCart(context.Background(), "cart-1")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, domaincart.ErrCartNotFound)
+
+		_, err = storage.GetCart(context.Background(), "cart-1")
+		assert.ErrorIs(t, err, domaincart.ErrCartNotFound)
+		assert.True(t, db.tableCreated)
+	})
+
+	t.Run("cancelled request doesn't break the preparation", func(t *testing.T) {
+		db := new(fakeSQLDatabase)
+		storage := newFakeSQLCartStorage(t, db, &now)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := storage.GetCart(ctx, "cart-1")
+		require.ErrorIs(t, err, context.Canceled)
+		assert.True(t, db.tableCreated, "the table is created independent of the request")
+
+		require.NoError(t, storage.StoreCart(context.Background(), &domaincart.Cart{ID: "cart-1", Revision: 1}))
+		assert.True(t, storage.HasCart(context.Background(), "cart-1"))
+	})
+}
@@ -0,0 +1,353 @@
+package infrastructure
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+type (
+	// DefaultTaxCalculator calculates the taxes with the rates configured via commerce.cart.defaultCartAdapter.taxes.rules,
+	// if no rule matches the defaultTaxRate is used
+	DefaultTaxCalculator struct {
+		rules            []TaxRule
+		defaultTaxRate   float64
+		grossPricing     bool
+		rounding         string
+		shippingTaxClass string
+	}
+
+	// TaxRule defines the rate of a tax type, empty tax class, country or region match everything.
+	// If multiple rules of the same type match, the most specific one is used.
+	TaxRule struct {
+		TaxClass string  `json:"taxClass"`
+		Country  string  `json:"country"`
+		Region   string  `json:"region"`
+		Type     string  `json:"type"`
+		Rate     float64 `json:"rate"`
+	}
+
+	taxRate struct {
+		Type string
+		Rate float64
+	}
+)
+
+const (
+	// TaxRoundingRow rounds the single prices and the taxes of every row
+	TaxRoundingRow = "row"
+	// TaxRoundingTotal rounds the taxes of every tax type on the delivery total and spreads the rounded amounts over the rows
+	TaxRoundingTotal = "total"
+)
+
+var (
+	_ TaxCalculator = (*DefaultTaxCalculator)(nil)
+)
+
+// Inject dependencies
+func (c *DefaultTaxCalculator) Inject(
+	logger flamingo.Logger,
+	cfg *struct {
+		DefaultTaxRate   float64      `inject:"config:commerce.cart.defaultCartAdapter.defaultTaxRate,optional"`
+		ProductPricing   string       `inject:"config:commerce.cart.defaultCartAdapter.productPrices,optional"`
+		Rules            config.Slice `inject:"config:commerce.cart.defaultCartAdapter.taxes.rules,optional"`
+		Rounding         string       `inject:"config:commerce.cart.defaultCartAdapter.taxes.rounding,optional"`
+		ShippingTaxClass string       `inject:"config:commerce.cart.defaultCartAdapter.taxes.shippingTaxClass,optional"`
+	},
+) *DefaultTaxCalculator {
+	c.rounding = TaxRoundingRow
+
+	if cfg == nil {
+		return c
+	}
+
+	c.defaultTaxRate = cfg.DefaultTaxRate
+	c.grossPricing = cfg.ProductPricing == "gross"
+	c.shippingTaxClass = cfg.ShippingTaxClass
+
+	if cfg.Rounding == TaxRoundingTotal {
+		c.rounding = TaxRoundingTotal
+	}
+
+	var rules []TaxRule
+	if err := cfg.Rules.MapInto(&rules); err != nil {
+		logger.WithField(flamingo.LogKeyCategory, "DefaultTaxCalculator").Error(fmt.Errorf("failed to map tax rules: %w", err))
+
+		return c
+	}
+
+	for _, rule := range rules {
+		if rule.Type == "" {
+			rule.Type = "default"
+		}
+
+		c.rules = append(c.rules, rule)
+	}
+
+	return c
+}
+
+// CalculateDeliveryTaxes calculates the single and row prices and the row taxes of all items and the prices and the tax
+// amount of the shipping item. The gross or the net prices are taken as given, depending on the product pricing.
+// Items without any tax rate keep their single prices.
+func (c *DefaultTaxCalculator) CalculateDeliveryTaxes(delivery *domaincart.Delivery, address *domaincart.Address) error {
+	exactTaxes := make([][]domaincart.Tax, len(delivery.Cartitems))
+
+	for i := range delivery.Cartitems {
+		item := &delivery.Cartitems[i]
+
+		rates := c.taxRates(item.TaxClass, address)
+		if len(rates) == 0 {
+			item.RowPriceGross = item.SinglePriceGross.Multiply(item.Qty)
+			item.RowPriceNet = item.SinglePriceNet.Multiply(item.Qty)
+			item.RowTaxes = nil
+
+			continue
+		}
+
+		var err error
+
+		exactTaxes[i], err = c.calculateItem(item, rates)
+		if err != nil {
+			return fmt.Errorf("failed to calculate taxes of item %q: %w", item.ID, err)
+		}
+	}
+
+	if c.rounding == TaxRoundingTotal {
+		if err := c.spreadRoundedTotals(delivery, exactTaxes); err != nil {
+			return err
+		}
+	}
+
+	return c.calculateShippingItem(&delivery.ShippingItem, address)
+}
+
+// calculateItem calculates the prices of the item and returns its exact (unrounded) taxes
+func (c *DefaultTaxCalculator) calculateItem(item *domaincart.Item, rates []taxRate) ([]domaincart.Tax, error) {
+	sumRate := sumTaxRates(rates)
+	factor := big.NewFloat(1 + (sumRate / 100))
+
+	var exactNet priceDomain.Price
+
+	if c.grossPricing {
+		item.SinglePriceNet = quo(item.SinglePriceGross, factor).GetPayable()
+		item.RowPriceGross = item.SinglePriceGross.Multiply(item.Qty)
+		item.RowPriceNet = item.SinglePriceNet.Multiply(item.Qty)
+		exactNet = quo(item.RowPriceGross, factor)
+	} else {
+		item.SinglePriceGross = mul(item.SinglePriceNet, factor).GetPayable()
+		item.RowPriceNet = item.SinglePriceNet.Multiply(item.Qty)
+		item.RowPriceGross = item.SinglePriceGross.Multiply(item.Qty)
+		exactNet = item.RowPriceNet
+	}
+
+	exactTaxes := make([]domaincart.Tax, 0, len(rates))
+	for _, rate := range rates {
+		exactTaxes = append(exactTaxes, domaincart.Tax{
+			Type:   rate.Type,
+			Rate:   big.NewFloat(rate.Rate),
+			Amount: mul(exactNet, big.NewFloat(rate.Rate/100)),
+		})
+	}
+
+	total, err := item.RowPriceGross.Sub(item.RowPriceNet)
+	if err != nil {
+		return nil, err
+	}
+
+	// the rounded taxes of the row are split by their rates, the last tax gets the rounding difference
+	item.RowTaxes = make(domaincart.Taxes, 0, len(rates))
+	remaining := total
+
+	for i, rate := range rates {
+		amount := remaining
+		if i < len(rates)-1 {
+			amount = mul(total, big.NewFloat(rate.Rate/sumRate)).GetPayable()
+		}
+
+		remaining, err = remaining.Sub(amount)
+		if err != nil {
+			return nil, err
+		}
+
+		item.RowTaxes = append(item.RowTaxes, domaincart.Tax{
+			Type:   rate.Type,
+			Rate:   big.NewFloat(rate.Rate),
+			Amount: amount,
+		})
+	}
+
+	return exactTaxes, nil
+}
+
+// spreadRoundedTotals replaces the row taxes with the rounded delivery totals of every tax type, the rounding
+// differences are spread over the rows by rounding the cumulated amounts
+func (c *DefaultTaxCalculator) spreadRoundedTotals(delivery *domaincart.Delivery, exactTaxes [][]domaincart.Tax) error {
+	cumulated := make(map[string]priceDomain.Price)
+	rounded := make(map[string]priceDomain.Price)
+
+	for i, taxes := range exactTaxes {
+		if len(taxes) == 0 {
+			continue
+		}
+
+		item := &delivery.Cartitems[i]
+		item.RowTaxes = make(domaincart.Taxes, 0, len(taxes))
+		rowTaxes := priceDomain.NewZero(item.RowPriceGross.Currency())
+
+		for _, tax := range taxes {
+			key := tax.Type + "/" + tax.Rate.String()
+
+			sum, err := cumulated[key].Add(tax.Amount)
+			if err != nil {
+				return err
+			}
+
+			amount, err := sum.GetPayable().Sub(rounded[key])
+			if err != nil {
+				return err
+			}
+
+			cumulated[key] = sum
+			rounded[key] = sum.GetPayable()
+			rowTaxes = rowTaxes.ForceAdd(amount)
+
+			item.RowTaxes = append(item.RowTaxes, domaincart.Tax{Type: tax.Type, Rate: tax.Rate, Amount: amount})
+		}
+
+		var err error
+		if c.grossPricing {
+			item.RowPriceNet, err = item.RowPriceGross.Sub(rowTaxes)
+		} else {
+			item.RowPriceGross, err = item.RowPriceNet.Add(rowTaxes)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// calculateShippingItem calculates the net or gross price and the tax amount of the shipping item
+func (c *DefaultTaxCalculator) calculateShippingItem(shippingItem *domaincart.ShippingItem, address *domaincart.Address) error {
+	price := shippingItem.PriceNet
+	if c.grossPricing {
+		price = shippingItem.PriceGross
+	}
+
+	rates := c.taxRates(c.shippingTaxClass, address)
+	if price.IsZero() || len(rates) == 0 {
+		return nil
+	}
+
+	sumRate := sumTaxRates(rates)
+
+	if c.grossPricing {
+		shippingItem.TaxAmount = mul(price, big.NewFloat(sumRate/(100+sumRate))).GetPayable()
+
+		var err error
+
+		shippingItem.PriceNet, err = price.Sub(shippingItem.TaxAmount)
+
+		return err
+	}
+
+	shippingItem.TaxAmount = mul(price, big.NewFloat(sumRate/100)).GetPayable()
+
+	var err error
+
+	shippingItem.PriceGross, err = price.Add(shippingItem.TaxAmount)
+
+	return err
+}
+
+// taxRates returns the most specific matching rate of every tax type ordered like the rules
+func (c *DefaultTaxCalculator) taxRates(taxClass string, address *domaincart.Address) []taxRate {
+	var country, region string
+	if address != nil {
+		country, region = address.CountryCode, address.RegionCode
+	}
+
+	var rates []taxRate
+
+	specificities := make(map[string]int)
+
+	for _, rule := range c.rules {
+		if !matchesTaxCriterion(rule.TaxClass, taxClass) || !matchesTaxCriterion(rule.Country, country) || !matchesTaxCriterion(rule.Region, region) {
+			continue
+		}
+
+		specificity := 0
+		for _, criterion := range []string{rule.TaxClass, rule.Country, rule.Region} {
+			if criterion != "" {
+				specificity++
+			}
+		}
+
+		known, found := specificities[rule.Type]
+		if !found {
+			specificities[rule.Type] = specificity
+			rates = append(rates, taxRate{Type: rule.Type, Rate: rule.Rate})
+
+			continue
+		}
+
+		if specificity > known {
+			specificities[rule.Type] = specificity
+
+			for i := range rates {
+				if rates[i].Type == rule.Type {
+					rates[i].Rate = rule.Rate
+				}
+			}
+		}
+	}
+
+	if len(rates) == 0 && c.defaultTaxRate > 0.0 {
+		rates = []taxRate{{Type: "default", Rate: c.defaultTaxRate}}
+	}
+
+	// rates of 0 % don't result in taxes
+	result := rates[:0]
+
+	for _, rate := range rates {
+		if rate.Rate > 0.0 {
+			result = append(result, rate)
+		}
+	}
+
+	return result
+}
+
+func matchesTaxCriterion(criterion string, value string) bool {
+	return criterion == "" || strings.EqualFold(criterion, value)
+}
+
+func sumTaxRates(rates []taxRate) float64 {
+	sum := 0.0
+	for _, rate := range rates {
+		sum += rate.Rate
+	}
+
+	return sum
+}
+
+func mul(price priceDomain.Price, factor *big.Float) priceDomain.Price {
+	amount := new(big.Float).Mul(price.Amount(), factor)
+
+	return priceDomain.NewFromBigFloat(*amount, price.Currency())
+}
+
+func quo(price priceDomain.Price, factor *big.Float) priceDomain.Price {
+	amount := new(big.Float).Quo(price.Amount(), factor)
+
+	return priceDomain.NewFromBigFloat(*amount, price.Currency())
+}
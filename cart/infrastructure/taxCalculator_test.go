@@ -0,0 +1,193 @@
+package infrastructure
+
+import (
+	"testing"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domaincart "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+func newTestTaxCalculator(pricing string, rounding string, defaultTaxRate float64, rules config.Slice) *DefaultTaxCalculator {
+	return new(DefaultTaxCalculator).Inject(flamingo.NullLogger{}, &struct {
+		DefaultTaxRate   float64      `inject:"config:commerce.cart.defaultCartAdapter.defaultTaxRate,optional"`
+		ProductPricing   string       `inject:"config:commerce.cart.defaultCartAdapter.productPrices,optional"`
+		Rules            config.Slice `inject:"config:commerce.cart.defaultCartAdapter.taxes.rules,optional"`
+		Rounding         string       `inject:"config:commerce.cart.defaultCartAdapter.taxes.rounding,optional"`
+		ShippingTaxClass string       `inject:"config:commerce.cart.defaultCartAdapter.taxes.shippingTaxClass,optional"`
+	}{
+		DefaultTaxRate:   defaultTaxRate,
+		ProductPricing:   pricing,
+		Rules:            rules,
+		Rounding:         rounding,
+		ShippingTaxClass: "shipping",
+	})
+}
+
+func taxTestItem(id string, price float64, qty int, grossPricing bool) domaincart.Item {
+	item := domaincart.Item{ID: id, Qty: qty}
+	if grossPricing {
+		item.SinglePriceGross = priceDomain.NewFromFloat(price, "EUR")
+	} else {
+		item.SinglePriceNet = priceDomain.NewFromFloat(price, "EUR")
+	}
+
+	return item
+}
+
+func assertAmount(t *testing.T, expected float64, price priceDomain.Price, msg string) {
+	t.Helper()
+
+	assert.InDelta(t, expected, price.FloatAmount(), 0.0001, msg)
+}
+
+func TestDefaultTaxCalculator_CalculateDeliveryTaxes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("gross prices", func(t *testing.T) {
+		t.Parallel()
+
+		calculator := newTestTaxCalculator("gross", TaxRoundingRow, 0, config.Slice{
+			config.Map{"type": "vat", "country": "DE", "rate": 19.0},
+		})
+
+		delivery := &domaincart.Delivery{Cartitems: []domaincart.Item{taxTestItem("1", 11.90, 2, true)}}
+		require.NoError(t, calculator.CalculateDeliveryTaxes(delivery, &domaincart.Address{CountryCode: "DE"}))
+
+		item := delivery.Cartitems[0]
+		assertAmount(t, 10.00, item.SinglePriceNet, "single net")
+		assertAmount(t, 23.80, item.RowPriceGross, "row gross")
+		assertAmount(t, 20.00, item.RowPriceNet, "row net")
+		require.Len(t, item.RowTaxes, 1)
+		assert.Equal(t, "vat", item.RowTaxes[0].Type)
+		assertAmount(t, 3.80, item.RowTaxes[0].Amount, "vat")
+	})
+
+	t.Run("multiple tax types with net prices", func(t *testing.T) {
+		t.Parallel()
+
+		calculator := newTestTaxCalculator("net", TaxRoundingRow, 0, config.Slice{
+			config.Map{"type": "vat", "rate": 10.0},
+			config.Map{"type": "city", "country": "US", "region": "NY", "rate": 5.0},
+		})
+
+		delivery := &domaincart.Delivery{Cartitems: []domaincart.Item{taxTestItem("1", 10, 3, false)}}
+		require.NoError(t, calculator.CalculateDeliveryTaxes(delivery, &domaincart.Address{CountryCode: "US", RegionCode: "NY"}))
+
+		item := delivery.Cartitems[0]
+		assertAmount(t, 11.50, item.SinglePriceGross, "single gross")
+		assertAmount(t, 34.50, item.RowPriceGross, "row gross")
+		require.Len(t, item.RowTaxes, 2)
+		assertAmount(t, 3.00, item.RowTaxes[0].Amount, "vat")
+		assert.Equal(t, "city", item.RowTaxes[1].Type)
+		assertAmount(t, 1.50, item.RowTaxes[1].Amount, "city tax")
+
+		// the city tax doesn't apply outside of the region
+		require.NoError(t, calculator.CalculateDeliveryTaxes(delivery, &domaincart.Address{CountryCode: "US", RegionCode: "CA"}))
+
+		item = delivery.Cartitems[0]
+		assertAmount(t, 11.00, item.SinglePriceGross, "single gross")
+		require.Len(t, item.RowTaxes, 1)
+		assertAmount(t, 3.00, item.RowTaxes[0].Amount, "vat")
+	})
+
+	t.Run("total rounding", func(t *testing.T) {
+		t.Parallel()
+
+		rules := config.Slice{config.Map{"type": "vat", "rate": 19.0}}
+		items := func() []domaincart.Item {
+			return []domaincart.Item{taxTestItem("1", 0.10, 1, true), taxTestItem("2", 0.10, 1, true), taxTestItem("3", 0.10, 1, true)}
+		}
+
+		delivery := &domaincart.Delivery{Cartitems: items()}
+		require.NoError(t, newTestTaxCalculator("gross", TaxRoundingRow, 0, rules).CalculateDeliveryTaxes(delivery, nil))
+
+		rowTotal := priceDomain.NewZero("EUR")
+		for _, item := range delivery.Cartitems {
+			rowTotal = rowTotal.ForceAdd(item.TotalTaxAmount())
+		}
+
+		assertAmount(t, 0.06, rowTotal, "taxes rounded per row")
+
+		delivery = &domaincart.Delivery{Cartitems: items()}
+		require.NoError(t, newTestTaxCalculator("gross", TaxRoundingTotal, 0, rules).CalculateDeliveryTaxes(delivery, nil))
+
+		expectedTaxes := []float64{0.02, 0.01, 0.02}
+		for i, item := range delivery.Cartitems {
+			assertAmount(t, expectedTaxes[i], item.TotalTaxAmount(), "spread tax")
+			assertAmount(t, 0.10-expectedTaxes[i], item.RowPriceNet, "row net")
+			assertAmount(t, 0.10, item.RowPriceGross, "row gross is kept")
+		}
+	})
+
+	t.Run("default tax rate and items without taxes", func(t *testing.T) {
+		t.Parallel()
+
+		delivery := &domaincart.Delivery{Cartitems: []domaincart.Item{taxTestItem("1", 10, 2, false)}}
+		require.NoError(t, newTestTaxCalculator("net", TaxRoundingRow, 10, nil).CalculateDeliveryTaxes(delivery, nil))
+
+		item := delivery.Cartitems[0]
+		assertAmount(t, 22.00, item.RowPriceGross, "row gross")
+		require.Len(t, item.RowTaxes, 1)
+		assert.Equal(t, "default", item.RowTaxes[0].Type)
+		assertAmount(t, 2.00, item.RowTaxes[0].Amount, "default tax")
+
+		delivery = &domaincart.Delivery{Cartitems: []domaincart.Item{taxTestItem("1", 10, 2, false)}}
+		require.NoError(t, newTestTaxCalculator("net", TaxRoundingRow, 0, nil).CalculateDeliveryTaxes(delivery, nil))
+
+		item = delivery.Cartitems[0]
+		assertAmount(t, 20.00, item.RowPriceNet, "row net")
+		assert.Nil(t, item.RowTaxes)
+	})
+
+	t.Run("shipping", func(t *testing.T) {
+		t.Parallel()
+
+		rules := config.Slice{
+			config.Map{"type": "vat", "taxClass": "shipping", "rate": 19.0},
+		}
+
+		delivery := &domaincart.Delivery{ShippingItem: domaincart.ShippingItem{PriceGross: priceDomain.NewFromFloat(5.95, "EUR")}}
+		require.NoError(t, newTestTaxCalculator("gross", TaxRoundingRow, 0, rules).CalculateDeliveryTaxes(delivery, nil))
+		assertAmount(t, 0.95, delivery.ShippingItem.TaxAmount, "gross shipping tax")
+		assertAmount(t, 5.00, delivery.ShippingItem.PriceNet, "shipping net")
+
+		delivery = &domaincart.Delivery{ShippingItem: domaincart.ShippingItem{PriceNet: priceDomain.NewFromFloat(5, "EUR")}}
+		require.NoError(t, newTestTaxCalculator("net", TaxRoundingRow, 0, rules).CalculateDeliveryTaxes(delivery, nil))
+		assertAmount(t, 0.95, delivery.ShippingItem.TaxAmount, "net shipping tax")
+		assertAmount(t, 5.95, delivery.ShippingItem.PriceGross, "shipping gross")
+	})
+}
+
+func TestDefaultTaxCalculator_taxRates(t *testing.T) {
+	t.Parallel()
+
+	calculator := newTestTaxCalculator("gross", TaxRoundingRow, 0, config.Slice{
+		config.Map{"type": "vat", "rate": 19.0},
+		config.Map{"type": "vat", "country": "AT", "rate": 20.0},
+		config.Map{"type": "vat", "taxClass": "food", "rate": 7.0},
+		config.Map{"type": "vat", "taxClass": "food", "country": "at", "rate": 10.0},
+		config.Map{"type": "vat", "taxClass": "books", "rate": 0.0},
+	})
+
+	tests := []struct {
+		name     string
+		taxClass string
+		address  *domaincart.Address
+		want     []taxRate
+	}{
+		{name: "no address", want: []taxRate{{Type: "vat", Rate: 19}}},
+		{name: "country", address: &domaincart.Address{CountryCode: "AT"}, want: []taxRate{{Type: "vat", Rate: 20}}},
+		{name: "tax class", taxClass: "food", address: &domaincart.Address{CountryCode: "DE"}, want: []taxRate{{Type: "vat", Rate: 7}}},
+		{name: "tax class and country", taxClass: "food", address: &domaincart.Address{CountryCode: "AT"}, want: []taxRate{{Type: "vat", Rate: 10}}},
+		{name: "zero rate", taxClass: "books", want: []taxRate{}},
+	}
+
+	for _, tt := range tests {
+		assert.ElementsMatch(t, tt.want, calculator.taxRates(tt.taxClass, tt.address), tt.name)
+	}
+}
@@ -2,8 +2,14 @@ package controller
 
 import (
 	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"go.opencensus.io/trace"
 
@@ -18,8 +24,12 @@ import (
 
 	"flamingo.me/flamingo-commerce/v3/cart/application"
 	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/decorator"
 )
 
+// errBulkAddLimitExceeded is used if an uploaded CSV file exceeds the configured maximum file size or number of rows
+var errBulkAddLimitExceeded = errors.New("bulk add limit exceeded")
+
 type (
 	// CartAPIController for cart api
 	CartAPIController struct {
@@ -30,6 +40,8 @@ type (
 		billingAddressFormController *forms.BillingAddressFormController
 		deliveryFormController       *forms.DeliveryFormController
 		simplePaymentFormController  *forms.SimplePaymentFormController
+		bulkAddMaxItems              int
+		bulkAddMaxFileSize           int64
 	}
 
 	// CartAPIResult view data
@@ -46,6 +58,8 @@ type (
 	getCartResult struct {
 		Cart                 *cart.Cart
 		CartValidationResult *validation.Result
+		// ItemChangeNotices contains the items whose price or saleable state changed since they have been added
+		ItemChangeNotices []decorator.ItemChangeNotice
 	}
 
 	resultError struct {
@@ -70,6 +84,10 @@ func (cc *CartAPIController) Inject(
 	deliveryFormController *forms.DeliveryFormController,
 	simplePaymentFormController *forms.SimplePaymentFormController,
 	Logger flamingo.Logger,
+	config *struct {
+		BulkAddMaxItems    float64 `inject:"config:commerce.cart.bulkAdd.maxItems,optional"`
+		BulkAddMaxFileSize float64 `inject:"config:commerce.cart.bulkAdd.maxFileSize,optional"`
+	},
 ) {
 	cc.responder = responder
 	cc.cartService = ApplicationCartService
@@ -78,6 +96,11 @@ func (cc *CartAPIController) Inject(
 	cc.billingAddressFormController = billingAddressFormController
 	cc.deliveryFormController = deliveryFormController
 	cc.simplePaymentFormController = simplePaymentFormController
+
+	if config != nil {
+		cc.bulkAddMaxItems = int(config.BulkAddMaxItems)
+		cc.bulkAddMaxFileSize = int64(config.BulkAddMaxFileSize)
+	}
 }
 
 // GetAction Get JSON Format of API
@@ -99,10 +122,11 @@ func (cc *CartAPIController) GetAction(ctx context.Context, r *web.Request) web.
 		return cc.responder.Data(result).Status(500)
 	}
 	validationResult := cc.cartService.ValidateCart(ctx, web.SessionFromContext(ctx), decoratedCart)
-	return cc.responder.Data(getCartResult{
+	return withETag(cc.responder.Data(getCartResult{
 		CartValidationResult: &validationResult,
 		Cart:                 &decoratedCart.Cart,
-	})
+		ItemChangeNotices:    decoratedCart.ChangeNotices,
+	}), &decoratedCart.Cart)
 }
 
 // DeleteCartAction removes all cart content and returns a blank cart
@@ -111,11 +135,15 @@ func (cc *CartAPIController) GetAction(ctx context.Context, r *web.Request) web.
 // @Produce json
 // @Success 200 {object} CartAPIResult
 // @Failure 500 {object} CartAPIResult
+// @Failure 412 {object} CartAPIResult
+// @Param If-Match header string false "optional revision (ETag) of the cart the modification is based on"
 // @Router /api/v1/cart [delete]
 func (cc *CartAPIController) DeleteCartAction(ctx context.Context, r *web.Request) web.Result {
 	ctx, span := trace.StartSpan(ctx, "cart/CartAPIController/DeleteCartAction")
 	defer span.End()
 
+	ctx = contextWithIfMatch(ctx, r)
+
 	err := cc.cartService.Clean(ctx, r.Session())
 
 	result := newResult()
@@ -124,11 +152,11 @@ func (cc *CartAPIController) DeleteCartAction(ctx context.Context, r *web.Reques
 
 		result.SetError(err, "delete_cart_error")
 		response := cc.responder.Data(result)
-		response.Status(500)
+		response.Status(errorStatus(err))
 		return response
 	}
-	cc.enrichResultWithCartInfos(ctx, &result)
-	return cc.responder.Data(result)
+	currentCart := cc.enrichResultWithCartInfos(ctx, &result)
+	return withETag(cc.responder.Data(result), currentCart)
 }
 
 // AddAction Add Item to cart
@@ -141,11 +169,16 @@ func (cc *CartAPIController) DeleteCartAction(ctx context.Context, r *web.Reques
 // @Param marketplaceCode query string true "the product identifier that should be added"
 // @Param variantMarketplaceCode query string false "optional the product identifier of the variant (for configurable products) that should be added"
 // @Param qty query integer false "optional the qty that should be added"
+// @Param options[code] query string false "optional value of the item option with the given code, e.g. options[engraving]=Hello"
+// @Failure 412 {object} CartAPIResult
+// @Param If-Match header string false "optional revision (ETag) of the cart the modification is based on"
 // @Router /api/v1/cart/delivery/{deliveryCode}/item [post]
 func (cc *CartAPIController) AddAction(ctx context.Context, r *web.Request) web.Result {
 	ctx, span := trace.StartSpan(ctx, "cart/CartAPIController/AddAction")
 	defer span.End()
 
+	ctx = contextWithIfMatch(ctx, r)
+
 	variantMarketplaceCode := r.Params["variantMarketplaceCode"]
 
 	qty, ok := r.Params["qty"]
@@ -156,6 +189,7 @@ func (cc *CartAPIController) AddAction(ctx context.Context, r *web.Request) web.
 	deliveryCode := r.Params["deliveryCode"]
 
 	addRequest := cc.cartService.BuildAddRequest(ctx, r.Params["marketplaceCode"], variantMarketplaceCode, qtyInt, nil)
+	addRequest.Options = itemOptionsFromQuery(r)
 	_, err := cc.cartService.AddProduct(ctx, r.Session(), deliveryCode, addRequest)
 
 	result := newResult()
@@ -164,11 +198,181 @@ func (cc *CartAPIController) AddAction(ctx context.Context, r *web.Request) web.
 
 		result.SetError(err, "add_product_error")
 		response := cc.responder.Data(result)
-		response.Status(500)
+		response.Status(errorStatus(err))
 		return response
 	}
-	cc.enrichResultWithCartInfos(ctx, &result)
-	return cc.responder.Data(result)
+	currentCart := cc.enrichResultWithCartInfos(ctx, &result)
+	return withETag(cc.responder.Data(result), currentCart)
+}
+
+// itemOptionsFromQuery collects the item options passed as options[<code>]=<value>
+func itemOptionsFromQuery(r *web.Request) map[string]string {
+	var options map[string]string
+
+	for key, values := range r.QueryAll() {
+		if len(values) == 0 || !strings.HasPrefix(key, "options[") || !strings.HasSuffix(key, "]") {
+			continue
+		}
+
+		if options == nil {
+			options = make(map[string]string)
+		}
+
+		options[strings.TrimSuffix(strings.TrimPrefix(key, "options["), "]")] = values[0]
+	}
+
+	return options
+}
+
+// BulkAddAction adds all items of an uploaded CSV file to the cart
+// @Summary Add many items to the cart with a single modification (quick order)
+// @Description The CSV rows contain the columns marketplaceCode, qty, variantMarketplaceCode (optional) and deliveryCode (optional),
+// @Description a header row starting with "marketplaceCode" is skipped. The file can be sent as multipart form field "file" or as request body.
+// @Description The size of the request and the number of rows are limited by commerce.cart.bulkAdd.maxFileSize and commerce.cart.bulkAdd.maxItems.
+// @Description The result of every row is returned in Data.
+// @Tags Cart
+// @Accept text/csv
+// @Accept multipart/form-data
+// @Produce json
+// @Success 200 {object} CartAPIResult{Data=application.BulkAddResults}
+// @Failure 400 {object} CartAPIResult
+// @Failure 412 {object} CartAPIResult
+// @Failure 413 {object} CartAPIResult
+// @Failure 500 {object} CartAPIResult
+// @Param deliveryCode query string false "optional delivery code used for rows without a delivery code"
+// @Param file formData file false "the CSV file"
+// @Param If-Match header string false "optional revision (ETag) of the cart the modification is based on"
+// @Router /api/v1/cart/items/bulk [post]
+func (cc *CartAPIController) BulkAddAction(ctx context.Context, r *web.Request) web.Result {
+	ctx, span := trace.StartSpan(ctx, "cart/CartAPIController/BulkAddAction")
+	defer span.End()
+
+	ctx = contextWithIfMatch(ctx, r)
+
+	result := newResult()
+
+	addRequests, err := cc.readBulkAddRequests(ctx, r)
+	if errors.Is(err, errBulkAddLimitExceeded) {
+		cc.logger.WithContext(ctx).Warn("cart.cartapicontroller.bulkadd: %v", err.Error())
+
+		result.SetError(err, "bulk_add_limit_exceeded")
+		return cc.responder.Data(result).Status(http.StatusRequestEntityTooLarge)
+	}
+
+	if err != nil {
+		cc.logger.WithContext(ctx).Warn("cart.cartapicontroller.bulkadd: %v", err.Error())
+
+		result.SetError(err, "bulk_add_invalid_csv")
+		return cc.responder.Data(result).Status(http.StatusBadRequest)
+	}
+
+	results, err := cc.cartService.AddProductsBulk(ctx, r.Session(), addRequests)
+	if err != nil {
+		cc.logger.WithContext(ctx).Error("cart.cartapicontroller.bulkadd: %v", err.Error())
+
+		result.SetError(err, "bulk_add_error")
+		return cc.responder.Data(result).Status(errorStatus(err))
+	}
+
+	result.Data = results
+
+	currentCart := cc.enrichResultWithCartInfos(ctx, &result)
+	return withETag(cc.responder.Data(result), currentCart)
+}
+
+// readBulkAddRequests reads the CSV file row by row, the request size and the number of rows are limited if configured
+func (cc *CartAPIController) readBulkAddRequests(ctx context.Context, r *web.Request) ([]cart.DeliveryAddRequest, error) {
+	deliveryCode := r.Params["deliveryCode"]
+
+	if cc.bulkAddMaxFileSize > 0 {
+		r.Request().Body = http.MaxBytesReader(nil, r.Request().Body, cc.bulkAddMaxFileSize)
+	}
+
+	var body io.Reader = r.Request().Body
+
+	if strings.HasPrefix(r.Request().Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.Request().FormFile("file")
+		if err != nil {
+			return nil, bulkAddReadError("missing CSV file", err)
+		}
+		defer file.Close()
+
+		body = file
+	}
+
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var addRequests []cart.DeliveryAddRequest
+
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, bulkAddReadError("invalid CSV file", err)
+		}
+
+		if first && strings.EqualFold(strings.TrimSpace(record[0]), "marketplaceCode") {
+			continue
+		}
+
+		if cc.bulkAddMaxItems > 0 && len(addRequests) >= cc.bulkAddMaxItems {
+			return nil, fmt.Errorf("%w: more than %d rows", errBulkAddLimitExceeded, cc.bulkAddMaxItems)
+		}
+
+		addRequest, err := cc.bulkAddRequestFromCSV(ctx, record, deliveryCode)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", len(addRequests)+1, err)
+		}
+
+		addRequests = append(addRequests, addRequest)
+	}
+
+	if len(addRequests) == 0 {
+		return nil, errors.New("CSV file contains no items")
+	}
+
+	return addRequests, nil
+}
+
+// bulkAddReadError marks errors of a request exceeding the maximum file size
+func bulkAddReadError(msg string, err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return fmt.Errorf("%w: file larger than %d bytes", errBulkAddLimitExceeded, maxBytesErr.Limit)
+	}
+
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// bulkAddRequestFromCSV maps a CSV record (marketplaceCode, qty, variantMarketplaceCode, deliveryCode) to an add request
+func (cc *CartAPIController) bulkAddRequestFromCSV(ctx context.Context, record []string, deliveryCode string) (cart.DeliveryAddRequest, error) {
+	if len(record) < 2 {
+		return cart.DeliveryAddRequest{}, errors.New("marketplaceCode and qty are required")
+	}
+
+	qty, err := strconv.Atoi(strings.TrimSpace(record[1]))
+	if err != nil {
+		return cart.DeliveryAddRequest{}, fmt.Errorf("invalid qty %q", record[1])
+	}
+
+	variantMarketplaceCode := ""
+	if len(record) > 2 {
+		variantMarketplaceCode = strings.TrimSpace(record[2])
+	}
+
+	if len(record) > 3 && strings.TrimSpace(record[3]) != "" {
+		deliveryCode = strings.TrimSpace(record[3])
+	}
+
+	return cart.DeliveryAddRequest{
+		DeliveryCode: deliveryCode,
+		AddRequest:   cc.cartService.BuildAddRequest(ctx, strings.TrimSpace(record[0]), variantMarketplaceCode, qty, nil),
+	}, nil
 }
 
 // DeleteItemAction deletes an item from the cart
@@ -179,11 +383,15 @@ func (cc *CartAPIController) AddAction(ctx context.Context, r *web.Request) web.
 // @Failure 500 {object} CartAPIResult
 // @Param deliveryCode path string true "the identifier for the delivery in the cart"
 // @Param itemID query string true "the item that should be deleted"
+// @Failure 412 {object} CartAPIResult
+// @Param If-Match header string false "optional revision (ETag) of the cart the modification is based on"
 // @Router /api/v1/cart/delivery/{deliveryCode}/item [delete]
 func (cc *CartAPIController) DeleteItemAction(ctx context.Context, r *web.Request) web.Result {
 	ctx, span := trace.StartSpan(ctx, "cart/CartAPIController/DeleteItemAction")
 	defer span.End()
 
+	ctx = contextWithIfMatch(ctx, r)
+
 	itemID, _ := r.Query1("itemID")
 	deliveryCode := r.Params["deliveryCode"]
 
@@ -195,11 +403,11 @@ func (cc *CartAPIController) DeleteItemAction(ctx context.Context, r *web.Reques
 
 		result.SetError(err, "delete_item_error")
 		response := cc.responder.Data(result)
-		response.Status(500)
+		response.Status(errorStatus(err))
 		return response
 	}
-	cc.enrichResultWithCartInfos(ctx, &result)
-	return cc.responder.Data(result)
+	currentCart := cc.enrichResultWithCartInfos(ctx, &result)
+	return withETag(cc.responder.Data(result), currentCart)
 }
 
 // UpdateItemAction updates the item qty in the current cart
@@ -211,11 +419,15 @@ func (cc *CartAPIController) DeleteItemAction(ctx context.Context, r *web.Reques
 // @Param deliveryCode path string true "the identifier for the delivery in the cart"
 // @Param itemID query string true "the item that should be updated"
 // @Param qty query integer true "the new qty"
+// @Failure 412 {object} CartAPIResult
+// @Param If-Match header string false "optional revision (ETag) of the cart the modification is based on"
 // @Router /api/v1/cart/delivery/{deliveryCode}/item [put]
 func (cc *CartAPIController) UpdateItemAction(ctx context.Context, r *web.Request) web.Result {
 	ctx, span := trace.StartSpan(ctx, "cart/CartAPIController/UpdateItemAction")
 	defer span.End()
 
+	ctx = contextWithIfMatch(ctx, r)
+
 	itemID, _ := r.Query1("itemID")
 	deliveryCode := r.Params["deliveryCode"]
 	qty, ok := r.Params["qty"]
@@ -232,11 +444,11 @@ func (cc *CartAPIController) UpdateItemAction(ctx context.Context, r *web.Reques
 
 		result.SetError(err, "update_item_error")
 		response := cc.responder.Data(result)
-		response.Status(500)
+		response.Status(errorStatus(err))
 		return response
 	}
-	cc.enrichResultWithCartInfos(ctx, &result)
-	return cc.responder.Data(result)
+	currentCart := cc.enrichResultWithCartInfos(ctx, &result)
+	return withETag(cc.responder.Data(result), currentCart)
 }
 
 // ApplyVoucherAndGetAction applies the given voucher and returns the cart
@@ -246,6 +458,8 @@ func (cc *CartAPIController) UpdateItemAction(ctx context.Context, r *web.Reques
 // @Success 200 {object} CartAPIResult
 // @Failure 500 {object} CartAPIResult
 // @Param couponCode query string true "the couponCode that should be applied"
+// @Failure 412 {object} CartAPIResult
+// @Param If-Match header string false "optional revision (ETag) of the cart the modification is based on"
 // @Router /api/v1/cart/voucher [post]
 func (cc *CartAPIController) ApplyVoucherAndGetAction(ctx context.Context, r *web.Request) web.Result {
 	ctx, span := trace.StartSpan(ctx, "cart/CartAPIController/ApplyVoucherAndGetAction")
@@ -261,6 +475,8 @@ func (cc *CartAPIController) ApplyVoucherAndGetAction(ctx context.Context, r *we
 // @Success 200 {object} CartAPIResult
 // @Failure 500 {object} CartAPIResult
 // @Param couponCode query string true "the couponCode that should be applied"
+// @Failure 412 {object} CartAPIResult
+// @Param If-Match header string false "optional revision (ETag) of the cart the modification is based on"
 // @Router /api/v1/cart/voucher [delete]
 func (cc *CartAPIController) RemoveVoucherAndGetAction(ctx context.Context, r *web.Request) web.Result {
 	ctx, span := trace.StartSpan(ctx, "cart/CartAPIController/RemoveVoucherAndGetAction")
@@ -275,17 +491,21 @@ func (cc *CartAPIController) RemoveVoucherAndGetAction(ctx context.Context, r *w
 // @Produce json
 // @Success 200 {object} CartAPIResult
 // @Failure 500 {object} CartAPIResult
+// @Failure 412 {object} CartAPIResult
+// @Param If-Match header string false "optional revision (ETag) of the cart the modification is based on"
 // @Router /api/v1/cart/deliveries/items [delete]
 func (cc *CartAPIController) DeleteAllItemsAction(ctx context.Context, r *web.Request) web.Result {
 	ctx, span := trace.StartSpan(ctx, "cart/CartAPIController/DeleteAllItemsAction")
 	defer span.End()
 
+	ctx = contextWithIfMatch(ctx, r)
+
 	err := cc.cartService.DeleteAllItems(ctx, r.Session())
 	result := newResult()
 	if err != nil {
 		result.SetError(err, "delete_items_error")
 		response := cc.responder.Data(result)
-		response.Status(500)
+		response.Status(errorStatus(err))
 		return response
 	}
 	return cc.responder.Data(result)
@@ -299,6 +519,8 @@ func (cc *CartAPIController) DeleteAllItemsAction(ctx context.Context, r *web.Re
 // @Success 200 {object} CartAPIResult
 // @Failure 500 {object} CartAPIResult
 // @Param couponCode query string true "the gift card code"
+// @Failure 412 {object} CartAPIResult
+// @Param If-Match header string false "optional revision (ETag) of the cart the modification is based on"
 // @Router /api/v1/cart/gift-card [post]
 func (cc *CartAPIController) ApplyGiftCardAndGetAction(ctx context.Context, r *web.Request) web.Result {
 	ctx, span := trace.StartSpan(ctx, "cart/CartAPIController/ApplyGiftCardAndGetAction")
@@ -316,6 +538,8 @@ func (cc *CartAPIController) ApplyGiftCardAndGetAction(ctx context.Context, r *w
 // @Success 200 {object} CartAPIResult
 // @Failure 500 {object} CartAPIResult
 // @Param couponCode query string true "the couponCode that should be applied as gift card or voucher"
+// @Failure 412 {object} CartAPIResult
+// @Param If-Match header string false "optional revision (ETag) of the cart the modification is based on"
 // @Router /api/v1/cart/voucher-gift-card [post]
 func (cc *CartAPIController) ApplyCombinedVoucherGift(ctx context.Context, r *web.Request) web.Result {
 	ctx, span := trace.StartSpan(ctx, "cart/CartAPIController/ApplyCombinedVoucherGift")
@@ -332,6 +556,8 @@ func (cc *CartAPIController) ApplyCombinedVoucherGift(ctx context.Context, r *we
 // @Success 200 {object} CartAPIResult
 // @Failure 500 {object} CartAPIResult
 // @Param couponCode query string true "the couponCode that should be deleted as gift card"
+// @Failure 412 {object} CartAPIResult
+// @Param If-Match header string false "optional revision (ETag) of the cart the modification is based on"
 // @Router /api/v1/cart/gift-card [delete]
 func (cc *CartAPIController) RemoveGiftCardAndGetAction(ctx context.Context, r *web.Request) web.Result {
 	ctx, span := trace.StartSpan(ctx, "cart/CartAPIController/RemoveGiftCardAndGetAction")
@@ -345,6 +571,8 @@ func (cc *CartAPIController) handlePromotionAction(ctx context.Context, r *web.R
 	ctx, span := trace.StartSpan(ctx, "cart/CartAPIController/handlePromotionAction")
 	defer span.End()
 
+	ctx = contextWithIfMatch(ctx, r)
+
 	couponCode := r.Params["couponCode"]
 	result := newResult()
 	_, err := fn(ctx, r.Session(), couponCode)
@@ -352,12 +580,12 @@ func (cc *CartAPIController) handlePromotionAction(ctx context.Context, r *web.R
 		cc.enrichResultWithCartInfos(ctx, &result)
 		result.SetError(err, errorCode)
 		response := cc.responder.Data(result)
-		response.Status(500)
+		response.Status(errorStatus(err))
 
 		return response
 	}
-	cc.enrichResultWithCartInfos(ctx, &result)
-	return cc.responder.Data(result)
+	currentCart := cc.enrichResultWithCartInfos(ctx, &result)
+	return withETag(cc.responder.Data(result), currentCart)
 }
 
 // DeleteDelivery cleans the given delivery from the cart and returns the cleaned cart
@@ -367,20 +595,24 @@ func (cc *CartAPIController) handlePromotionAction(ctx context.Context, r *web.R
 // @Success 200 {object} CartAPIResult
 // @Failure 500 {object} CartAPIResult
 // @Param deliveryCode path string true "the identifier for the delivery in the cart"
+// @Failure 412 {object} CartAPIResult
+// @Param If-Match header string false "optional revision (ETag) of the cart the modification is based on"
 // @Router /api/v1/cart/delivery/{deliveryCode} [delete]
 func (cc *CartAPIController) DeleteDelivery(ctx context.Context, r *web.Request) web.Result {
 	ctx, span := trace.StartSpan(ctx, "cart/CartAPIController/DeleteDelivery")
 	defer span.End()
 
+	ctx = contextWithIfMatch(ctx, r)
+
 	result := newResult()
 	deliveryCode := r.Params["deliveryCode"]
 	_, err := cc.cartService.DeleteDelivery(ctx, r.Session(), deliveryCode)
 	if err != nil {
 		result.SetError(err, "delete_delivery_error")
-		return cc.responder.Data(result).Status(500)
+		return cc.responder.Data(result).Status(errorStatus(err))
 	}
-	cc.enrichResultWithCartInfos(ctx, &result)
-	return cc.responder.Data(result)
+	currentCart := cc.enrichResultWithCartInfos(ctx, &result)
+	return withETag(cc.responder.Data(result), currentCart)
 }
 
 // BillingAction adds billing infos
@@ -411,11 +643,15 @@ func (cc *CartAPIController) DeleteDelivery(ctx context.Context, r *web.Request)
 // @Param phoneCountryCode formData string false "phoneCountryCode"
 // @Param phoneNumber formData string false "phoneNumber"
 // @Param email formData string true "email"
+// @Failure 412 {object} CartAPIResult
+// @Param If-Match header string false "optional revision (ETag) of the cart the modification is based on"
 // @Router /api/v1/cart/billing [put]
 func (cc *CartAPIController) BillingAction(ctx context.Context, r *web.Request) web.Result {
 	ctx, span := trace.StartSpan(ctx, "cart/CartAPIController/BillingAction")
 	defer span.End()
 
+	ctx = contextWithIfMatch(ctx, r)
+
 	result := newResult()
 	form, success, err := cc.billingAddressFormController.HandleFormAction(ctx, r)
 	result.Success = success
@@ -428,8 +664,8 @@ func (cc *CartAPIController) BillingAction(ctx context.Context, r *web.Request)
 		result.Data = form.Data
 		result.DataValidationInfo = &form.ValidationInfo
 	}
-	cc.enrichResultWithCartInfos(ctx, &result)
-	return cc.responder.Data(result)
+	currentCart := cc.enrichResultWithCartInfos(ctx, &result)
+	return withETag(cc.responder.Data(result), currentCart)
 }
 
 // UpdateDeliveryInfoAction updates the delivery info
@@ -466,11 +702,15 @@ func (cc *CartAPIController) BillingAction(ctx context.Context, r *web.Request)
 // @Param shippingCarrier formData string false "shippingCarrier"
 // @Param locationCode formData string false "locationCode"
 // @Param desiredTime formData string false "desired date/time in RFC3339" format(date-time)
+// @Failure 412 {object} CartAPIResult
+// @Param If-Match header string false "optional revision (ETag) of the cart the modification is based on"
 // @Router /api/v1/cart/delivery/{deliveryCode} [put]
 func (cc *CartAPIController) UpdateDeliveryInfoAction(ctx context.Context, r *web.Request) web.Result {
 	ctx, span := trace.StartSpan(ctx, "cart/CartAPIController/UpdateDeliveryInfoAction")
 	defer span.End()
 
+	ctx = contextWithIfMatch(ctx, r)
+
 	result := newResult()
 	form, success, err := cc.deliveryFormController.HandleFormAction(ctx, r)
 	result.Success = success
@@ -482,8 +722,8 @@ func (cc *CartAPIController) UpdateDeliveryInfoAction(ctx context.Context, r *we
 		result.Data = form.Data
 		result.DataValidationInfo = &form.ValidationInfo
 	}
-	cc.enrichResultWithCartInfos(ctx, &result)
-	return cc.responder.Data(result)
+	currentCart := cc.enrichResultWithCartInfos(ctx, &result)
+	return withETag(cc.responder.Data(result), currentCart)
 }
 
 // UpdatePaymentSelectionAction to set / update the cart payment selection
@@ -494,11 +734,15 @@ func (cc *CartAPIController) UpdateDeliveryInfoAction(ctx context.Context, r *we
 // @Failure 500 {object} CartAPIResult
 // @Param gateway query string true "name of the payment gateway - e.g. 'offline'"
 // @Param method query string true "name of the payment method - e.g. 'offlinepayment_cashondelivery'"
+// @Failure 412 {object} CartAPIResult
+// @Param If-Match header string false "optional revision (ETag) of the cart the modification is based on"
 // @Router /api/v1/cart/payment-selection [put]
 func (cc *CartAPIController) UpdatePaymentSelectionAction(ctx context.Context, r *web.Request) web.Result {
 	ctx, span := trace.StartSpan(ctx, "cart/CartAPIController/UpdatePaymentSelectionAction")
 	defer span.End()
 
+	ctx = contextWithIfMatch(ctx, r)
+
 	result := newResult()
 	gateway, _ := r.Query1("gateway")
 	method, _ := r.Query1("method")
@@ -514,18 +758,18 @@ func (cc *CartAPIController) UpdatePaymentSelectionAction(ctx context.Context, r
 	if err != nil {
 		result.SetError(err, "form_error")
 		response := cc.responder.Data(result)
-		response.Status(500)
+		response.Status(errorStatus(err))
 		return response
 	}
 	if form != nil {
 		result.Data = form.Data
 		result.DataValidationInfo = &form.ValidationInfo
 	}
-	cc.enrichResultWithCartInfos(ctx, &result)
-	return cc.responder.Data(result)
+	currentCart := cc.enrichResultWithCartInfos(ctx, &result)
+	return withETag(cc.responder.Data(result), currentCart)
 }
 
-func (cc *CartAPIController) enrichResultWithCartInfos(ctx context.Context, result *CartAPIResult) {
+func (cc *CartAPIController) enrichResultWithCartInfos(ctx context.Context, result *CartAPIResult) *cart.Cart {
 	ctx, span := trace.StartSpan(ctx, "cart/CartAPIController/enrichResultWithCartInfos")
 	defer span.End()
 
@@ -538,6 +782,54 @@ func (cc *CartAPIController) enrichResultWithCartInfos(ctx context.Context, resu
 	validationResult := cc.cartService.ValidateCart(ctx, session, decoratedCart)
 	result.CartTeaser = decoratedCart.Cart.GetCartTeaser()
 	result.CartValidationResult = &validationResult
+
+	return &decoratedCart.Cart
+}
+
+// contextWithIfMatch passes the cart revision of the If-Match header on to the cart service, so that
+// the modification fails if the cart has been modified in the meantime
+func contextWithIfMatch(ctx context.Context, r *web.Request) context.Context {
+	ifMatch := strings.TrimPrefix(strings.TrimSpace(r.Request().Header.Get("If-Match")), "W/")
+	ifMatch = strings.Trim(ifMatch, `"`)
+
+	// the cart id may contain dashes itself, the revision follows the last one
+	separator := strings.LastIndex(ifMatch, "-")
+	if separator <= 0 {
+		return ctx
+	}
+
+	revision, err := strconv.Atoi(ifMatch[separator+1:])
+	if err != nil {
+		return ctx
+	}
+
+	return application.ContextWithExpectedCart(ctx, ifMatch[:separator], revision)
+}
+
+// withETag exposes the id and revision of the cart as ETag "<cartID>-<revision>", it can be sent back as If-Match header with the next modification.
+// The cart id is part of the ETag since different carts of a customer (e.g. named carts) can have the same revision.
+func withETag(response *web.DataResponse, currentCart *cart.Cart) *web.DataResponse {
+	if currentCart == nil {
+		return response
+	}
+
+	if response.Header == nil {
+		response.Header = make(http.Header)
+	}
+
+	response.Header.Set("ETag", strconv.Quote(fmt.Sprintf("%s-%d", currentCart.ID, currentCart.Revision)))
+
+	return response
+}
+
+// errorStatus returns 412 if the modification was based on an outdated cart revision and 500 otherwise
+func errorStatus(err error) uint {
+	var conflictErr *cart.RevisionConflictError
+	if errors.As(err, &conflictErr) {
+		return http.StatusPreconditionFailed
+	}
+
+	return http.StatusInternalServerError
 }
 
 // newResult factory to get new CartApiResult (with success true)
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo/v3/framework/web"
+
+	"flamingo.me/flamingo-commerce/v3/cart/application"
+)
+
+func TestCartAPIController_readBulkAddRequests(t *testing.T) {
+	t.Parallel()
+
+	controller := func(maxItems int, maxFileSize int64) *CartAPIController {
+		return &CartAPIController{
+			cartService:        &application.CartService{},
+			bulkAddMaxItems:    maxItems,
+			bulkAddMaxFileSize: maxFileSize,
+		}
+	}
+
+	csvRequest := func(body string) *web.Request {
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/cart/items/bulk", strings.NewReader(body))
+		request.Header.Set("Content-Type", "text/csv")
+
+		return web.CreateRequest(request, web.EmptySession())
+	}
+
+	multipartRequest := func(t *testing.T, body string) *web.Request {
+		t.Helper()
+
+		buf := new(bytes.Buffer)
+		writer := multipart.NewWriter(buf)
+		file, err := writer.CreateFormFile("file", "items.csv")
+		require.NoError(t, err)
+		_, err = file.Write([]byte(body))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/cart/items/bulk", buf)
+		request.Header.Set("Content-Type", writer.FormDataContentType())
+
+		return web.CreateRequest(request, web.EmptySession())
+	}
+
+	t.Run("rows are mapped to add requests", func(t *testing.T) {
+		t.Parallel()
+
+		addRequests, err := controller(2, 1024).readBulkAddRequests(context.Background(), csvRequest("marketplaceCode,qty\ncode-1,2\ncode-2, 1, variant-2, other"))
+		require.NoError(t, err)
+		require.Len(t, addRequests, 2)
+		assert.Equal(t, "code-1", addRequests[0].AddRequest.MarketplaceCode)
+		assert.Equal(t, 2, addRequests[0].AddRequest.Qty)
+		assert.Equal(t, "variant-2", addRequests[1].AddRequest.VariantMarketplaceCode)
+		assert.Equal(t, "other", addRequests[1].DeliveryCode)
+	})
+
+	t.Run("invalid row", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := controller(0, 0).readBulkAddRequests(context.Background(), csvRequest("code-1,2\ncode-2,many"))
+		assert.EqualError(t, err, `row 2: invalid qty "many"`)
+		assert.NotErrorIs(t, err, errBulkAddLimitExceeded)
+	})
+
+	t.Run("too many rows", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := controller(2, 0).readBulkAddRequests(context.Background(), csvRequest("marketplaceCode,qty\ncode-1,1\ncode-2,1\ncode-3,1"))
+		assert.ErrorIs(t, err, errBulkAddLimitExceeded)
+	})
+
+	t.Run("too large body", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := controller(0, 16).readBulkAddRequests(context.Background(), csvRequest(strings.Repeat("code-1,1\n", 10)))
+		assert.ErrorIs(t, err, errBulkAddLimitExceeded)
+	})
+
+	t.Run("too large multipart file", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := controller(0, 64).readBulkAddRequests(context.Background(), multipartRequest(t, strings.Repeat("code-1,1\n", 100)))
+		assert.ErrorIs(t, err, errBulkAddLimitExceeded)
+	})
+
+	t.Run("multipart file", func(t *testing.T) {
+		t.Parallel()
+
+		addRequests, err := controller(0, 0).readBulkAddRequests(context.Background(), multipartRequest(t, "code-1,1\ncode-2,3"))
+		require.NoError(t, err)
+		assert.Len(t, addRequests, 2)
+	})
+}
@@ -23,6 +23,12 @@ type (
 		CartValidationResult  validation.Result
 		AddToCartProductsData []productDomain.BasicProductData
 		CartRestrictionError  application.RestrictionError
+		// CartImportResults contains the items of an imported shared cart that could not be added completely
+		CartImportResults application.CartImportResults
+		// CartImportError is set if a shared cart could not be imported (e.g. expired link)
+		CartImportError string
+		// CartMergeReport is set once after login if a guest cart has been merged into the customer cart
+		CartMergeReport *application.CartMergeReport
 	}
 
 	// CartViewController for carts
@@ -113,6 +119,21 @@ func (cc *CartViewController) ViewAction(ctx context.Context, r *web.Request) we
 		}
 	}
 
+	importFlashes := r.Session().Flashes("cart.view.import.results")
+	if len(importFlashes) > 0 {
+		if importResults, ok := importFlashes[0].(application.CartImportResults); ok {
+			cartViewData.CartImportResults = importResults
+		}
+	}
+	importErrorFlashes := r.Session().Flashes("cart.view.import.error")
+	if len(importErrorFlashes) > 0 {
+		if importError, ok := importErrorFlashes[0].(string); ok {
+			cartViewData.CartImportError = importError
+		}
+	}
+
+	cartViewData.CartMergeReport = application.LastCartMergeReport(r.Session())
+
 	return cc.responder.Render("checkout/cart", cartViewData).SetNoCache()
 }
 
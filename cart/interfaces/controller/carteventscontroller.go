@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+	"go.opencensus.io/trace"
+
+	"flamingo.me/flamingo-commerce/v3/cart/application"
+	cartDomain "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+)
+
+type (
+	// CartEventsController streams the change notifications of the current cart as server-sent events
+	CartEventsController struct {
+		responder                 *web.Responder
+		changeNotificationService *application.ChangeNotificationService
+		logger                    flamingo.Logger
+		heartbeatInterval         time.Duration
+	}
+
+	// cartEventsResult writes the notifications as text/event-stream until the client disconnects
+	cartEventsResult struct {
+		current           cartDomain.ChangeNotification
+		notifications     <-chan cartDomain.ChangeNotification
+		heartbeatInterval time.Duration
+	}
+)
+
+const cartUpdatedEvent = "cart-updated"
+
+// Inject dependencies
+func (cc *CartEventsController) Inject(
+	responder *web.Responder,
+	changeNotificationService *application.ChangeNotificationService,
+	logger flamingo.Logger,
+	cfg *struct {
+		HeartbeatInterval string `inject:"config:commerce.cart.notifications.heartbeatInterval,optional"`
+	},
+) *CartEventsController {
+	cc.responder = responder
+	cc.changeNotificationService = changeNotificationService
+	cc.logger = logger.WithField(flamingo.LogKeyCategory, "carteventscontroller").WithField(flamingo.LogKeyModule, "cart")
+	cc.heartbeatInterval = 30 * time.Second
+
+	if cfg != nil && cfg.HeartbeatInterval != "" {
+		var err error
+		cc.heartbeatInterval, err = time.ParseDuration(cfg.HeartbeatInterval)
+		if err != nil {
+			panic("can't parse commerce.cart.notifications.heartbeatInterval")
+		}
+	}
+
+	return cc
+}
+
+// EventsAction streams the changes of the current cart and the carts of the logged-in customer
+// @Summary Subscribe to the modifications of the current cart as server-sent events
+// @Description The stream starts with a cart-updated event describing the current cart, followed by one cart-updated event per modification.
+// @Tags Cart
+// @Produce text/event-stream
+// @Success 200 {object} cart.ChangeNotification
+// @Failure 404 {object} CartAPIResult
+// @Failure 500 {object} CartAPIResult
+// @Router /api/v1/cart/events [get]
+func (cc *CartEventsController) EventsAction(ctx context.Context, r *web.Request) web.Result {
+	ctx, span := trace.StartSpan(ctx, "cart/CartEventsController/EventsAction")
+	defer span.End()
+
+	// the subscription ends with the request, when the client disconnects
+	current, notifications, err := cc.changeNotificationService.SubscribeCurrentCart(r.Request().Context(), r.Session())
+	if errors.Is(err, application.ErrNoChangeNotifier) {
+		result := newResult()
+		result.SetError(err, "notifications_disabled")
+		return cc.responder.Data(result).Status(http.StatusNotFound)
+	}
+
+	if err != nil {
+		cc.logger.WithContext(ctx).Error("cart.carteventscontroller.events: %v", err.Error())
+
+		result := newResult()
+		result.SetError(err, "notifications_error")
+		return cc.responder.Data(result).Status(http.StatusInternalServerError)
+	}
+
+	return &cartEventsResult{
+		current:           current,
+		notifications:     notifications,
+		heartbeatInterval: cc.heartbeatInterval,
+	}
+}
+
+// Apply writes the event stream
+func (r *cartEventsResult) Apply(ctx context.Context, rw http.ResponseWriter) error {
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		return errors.New("cartEventsResult: response writer does not support streaming")
+	}
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("Connection", "keep-alive")
+	rw.Header().Set("X-Accel-Buffering", "no")
+	rw.WriteHeader(http.StatusOK)
+
+	if err := writeCartChangeEvent(rw, r.current); err != nil {
+		return err
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(r.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := io.WriteString(rw, ": ping\n\n"); err != nil {
+				return nil
+			}
+		case notification, ok := <-r.notifications:
+			if !ok {
+				return nil
+			}
+
+			if err := writeCartChangeEvent(rw, notification); err != nil {
+				return nil
+			}
+		}
+
+		flusher.Flush()
+	}
+}
+
+func writeCartChangeEvent(w io.Writer, notification cartDomain.ChangeNotification) error {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("cartEventsResult: error encoding notification of cart %q: %w", notification.CartID, err)
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", cartUpdatedEvent, data)
+
+	return err
+}
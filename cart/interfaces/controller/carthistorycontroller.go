@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+	"go.opencensus.io/trace"
+
+	"flamingo.me/flamingo-commerce/v3/cart/application"
+)
+
+type (
+	// CartHistoryController returns the change history of the current cart
+	CartHistoryController struct {
+		responder      *web.Responder
+		historyService *application.HistoryService
+		logger         flamingo.Logger
+	}
+)
+
+// Inject dependencies
+func (cc *CartHistoryController) Inject(
+	responder *web.Responder,
+	historyService *application.HistoryService,
+	logger flamingo.Logger,
+) *CartHistoryController {
+	cc.responder = responder
+	cc.historyService = historyService
+	cc.logger = logger.WithField(flamingo.LogKeyCategory, "carthistorycontroller").WithField(flamingo.LogKeyModule, "cart")
+
+	return cc
+}
+
+// HistoryAction returns the change history of the current cart
+// @Summary Get the modifications of the current cart, oldest modification first
+// @Tags Cart
+// @Produce json
+// @Success 200 {object} CartAPIResult{Data=[]cart.HistoryEntry}
+// @Failure 404 {object} CartAPIResult
+// @Failure 500 {object} CartAPIResult
+// @Router /api/v1/cart/history [get]
+func (cc *CartHistoryController) HistoryAction(ctx context.Context, r *web.Request) web.Result {
+	ctx, span := trace.StartSpan(ctx, "cart/CartHistoryController/HistoryAction")
+	defer span.End()
+
+	result := newResult()
+
+	entries, err := cc.historyService.CurrentCartHistory(ctx, r.Session())
+	if errors.Is(err, application.ErrNoHistoryStorage) {
+		result.SetError(err, "history_disabled")
+		return cc.responder.Data(result).Status(http.StatusNotFound)
+	}
+
+	if err != nil {
+		cc.logger.WithContext(ctx).Error("cart.carthistorycontroller.history: %v", err.Error())
+
+		result.SetError(err, "history_error")
+		return cc.responder.Data(result).Status(http.StatusInternalServerError)
+	}
+
+	result.Data = entries
+
+	return cc.responder.Data(result)
+}
@@ -0,0 +1,228 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+	"go.opencensus.io/trace"
+
+	"flamingo.me/flamingo-commerce/v3/cart/application"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/quote"
+	customerApplication "flamingo.me/flamingo-commerce/v3/customer/application"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+type (
+	// CartQuoteController manages the quotes which sales reps offer to customers
+	CartQuoteController struct {
+		responder    *web.Responder
+		quoteService *application.QuoteService
+		logger       flamingo.Logger
+	}
+)
+
+// Inject dependencies
+func (cc *CartQuoteController) Inject(
+	responder *web.Responder,
+	quoteService *application.QuoteService,
+	logger flamingo.Logger,
+) *CartQuoteController {
+	cc.responder = responder
+	cc.quoteService = quoteService
+	cc.logger = logger.WithField(flamingo.LogKeyCategory, "cartquotecontroller").WithField(flamingo.LogKeyModule, "cart")
+
+	return cc
+}
+
+// ListAction returns the quotes of the logged in customer
+// @Summary Get the quotes of the logged in customer, sales reps can request the quotes of any customer, newest quote first
+// @Tags Cart
+// @Produce json
+// @Success 200 {object} CartAPIResult{Data=[]quote.Quote}
+// @Failure 401 {object} CartAPIResult
+// @Failure 404 {object} CartAPIResult
+// @Failure 500 {object} CartAPIResult
+// @Param customerID query string false "the customer of the quotes, only used for sales reps"
+// @Router /api/v1/cart/quotes [get]
+func (cc *CartQuoteController) ListAction(ctx context.Context, r *web.Request) web.Result {
+	ctx, span := trace.StartSpan(ctx, "cart/CartQuoteController/ListAction")
+	defer span.End()
+
+	customerID, _ := r.Query1("customerID")
+
+	quotes, err := cc.quoteService.ListQuotes(ctx, customerID)
+
+	return cc.quoteResult(ctx, quotes, err, "list_quotes_error")
+}
+
+// CreateAction creates a draft quote for a customer from the current cart of the sales rep
+// @Summary Create a draft quote for a customer from the current cart of the sales rep
+// @Tags Cart
+// @Produce json
+// @Success 200 {object} CartAPIResult{Data=quote.Quote}
+// @Failure 400 {object} CartAPIResult
+// @Failure 401 {object} CartAPIResult
+// @Failure 403 {object} CartAPIResult
+// @Failure 500 {object} CartAPIResult
+// @Param customerID query string true "the customer the quote is offered to"
+// @Param expiresAt query string false "the expiry date of the quote in RFC3339 format, defaults to now + commerce.cart.quotes.lifetime"
+// @Router /api/v1/cart/quotes [post]
+func (cc *CartQuoteController) CreateAction(ctx context.Context, r *web.Request) web.Result {
+	ctx, span := trace.StartSpan(ctx, "cart/CartQuoteController/CreateAction")
+	defer span.End()
+
+	customerID, _ := r.Query1("customerID")
+
+	var expiresAt *time.Time
+
+	if rawExpiresAt, err := r.Query1("expiresAt"); err == nil && rawExpiresAt != "" {
+		parsed, err := time.Parse(time.RFC3339, rawExpiresAt)
+		if err != nil {
+			result := newResult()
+			result.SetError(err, "invalid_expiry_date")
+
+			return cc.responder.Data(result).Status(http.StatusBadRequest)
+		}
+
+		expiresAt = &parsed
+	}
+
+	createdQuote, err := cc.quoteService.CreateQuote(ctx, r.Session(), customerID, expiresAt)
+
+	return cc.quoteResult(ctx, createdQuote, err, "create_quote_error")
+}
+
+// GetAction returns a quote
+// @Summary Get a quote of the logged in customer, sales reps can get any quote
+// @Tags Cart
+// @Produce json
+// @Success 200 {object} CartAPIResult{Data=quote.Quote}
+// @Failure 401 {object} CartAPIResult
+// @Failure 404 {object} CartAPIResult
+// @Failure 500 {object} CartAPIResult
+// @Param id path string true "the id of the quote"
+// @Router /api/v1/cart/quotes/{id} [get]
+func (cc *CartQuoteController) GetAction(ctx context.Context, r *web.Request) web.Result {
+	ctx, span := trace.StartSpan(ctx, "cart/CartQuoteController/GetAction")
+	defer span.End()
+
+	foundQuote, err := cc.quoteService.GetQuote(ctx, r.Params["id"])
+
+	return cc.quoteResult(ctx, foundQuote, err, "get_quote_error")
+}
+
+// UpdateItemPriceAction locks the negotiated single price of an item of a draft quote
+// @Summary Set the negotiated single price of an item of a draft quote, only allowed for sales reps
+// @Tags Cart
+// @Produce json
+// @Success 200 {object} CartAPIResult{Data=quote.Quote}
+// @Failure 400 {object} CartAPIResult
+// @Failure 403 {object} CartAPIResult
+// @Failure 404 {object} CartAPIResult
+// @Failure 409 {object} CartAPIResult
+// @Failure 500 {object} CartAPIResult
+// @Param id path string true "the id of the quote"
+// @Param itemID path string true "the id of the item"
+// @Param price query number true "the single price of the item"
+// @Param currency query string false "the currency of the price, defaults to the currency of the item"
+// @Router /api/v1/cart/quotes/{id}/items/{itemID}/price [put]
+func (cc *CartQuoteController) UpdateItemPriceAction(ctx context.Context, r *web.Request) web.Result {
+	ctx, span := trace.StartSpan(ctx, "cart/CartQuoteController/UpdateItemPriceAction")
+	defer span.End()
+
+	amount, err := strconv.ParseFloat(r.Params["price"], 64)
+	if err != nil {
+		result := newResult()
+		result.SetError(err, "invalid_price")
+
+		return cc.responder.Data(result).Status(http.StatusBadRequest)
+	}
+
+	updatedQuote, err := cc.quoteService.UpdateItemPrice(ctx, r.Params["id"], r.Params["itemID"], priceDomain.NewFromFloat(amount, r.Params["currency"]))
+
+	return cc.quoteResult(ctx, updatedQuote, err, "update_quote_item_price_error")
+}
+
+// SendAction offers a draft quote to the customer
+// @Summary Offer a draft quote to the customer, only allowed for sales reps
+// @Tags Cart
+// @Produce json
+// @Success 200 {object} CartAPIResult{Data=quote.Quote}
+// @Failure 403 {object} CartAPIResult
+// @Failure 404 {object} CartAPIResult
+// @Failure 409 {object} CartAPIResult
+// @Failure 500 {object} CartAPIResult
+// @Param id path string true "the id of the quote"
+// @Router /api/v1/cart/quotes/{id}/send [post]
+func (cc *CartQuoteController) SendAction(ctx context.Context, r *web.Request) web.Result {
+	ctx, span := trace.StartSpan(ctx, "cart/CartQuoteController/SendAction")
+	defer span.End()
+
+	sentQuote, err := cc.quoteService.SendQuote(ctx, r.Params["id"])
+
+	return cc.quoteResult(ctx, sentQuote, err, "send_quote_error")
+}
+
+// AcceptAction accepts a sent quote
+// @Summary Accept a sent quote by the logged in customer, the quote can be converted via the checkout afterwards
+// @Tags Cart
+// @Produce json
+// @Success 200 {object} CartAPIResult{Data=quote.Quote}
+// @Failure 401 {object} CartAPIResult
+// @Failure 404 {object} CartAPIResult
+// @Failure 409 {object} CartAPIResult
+// @Failure 500 {object} CartAPIResult
+// @Param id path string true "the id of the quote"
+// @Router /api/v1/cart/quotes/{id}/accept [post]
+func (cc *CartQuoteController) AcceptAction(ctx context.Context, r *web.Request) web.Result {
+	ctx, span := trace.StartSpan(ctx, "cart/CartQuoteController/AcceptAction")
+	defer span.End()
+
+	acceptedQuote, err := cc.quoteService.AcceptQuote(ctx, r.Params["id"])
+
+	return cc.quoteResult(ctx, acceptedQuote, err, "accept_quote_error")
+}
+
+func (cc *CartQuoteController) quoteResult(ctx context.Context, data interface{}, err error, errorCode string) web.Result {
+	result := newResult()
+
+	if err != nil {
+		status := quoteErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			cc.logger.WithContext(ctx).Error("cart.cartquotecontroller: %v", err.Error())
+		}
+
+		if errors.Is(err, application.ErrNoQuoteStorage) {
+			errorCode = "quotes_disabled"
+		}
+
+		result.SetError(err, errorCode)
+
+		return cc.responder.Data(result).Status(status)
+	}
+
+	result.Data = data
+
+	return cc.responder.Data(result)
+}
+
+// quoteErrorStatus maps the errors of the quote service to the http status
+func quoteErrorStatus(err error) uint {
+	switch {
+	case errors.Is(err, application.ErrNoQuoteStorage), errors.Is(err, quote.ErrQuoteNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, customerApplication.ErrNoIdentity):
+		return http.StatusUnauthorized
+	case errors.Is(err, application.ErrNotSalesRep):
+		return http.StatusForbidden
+	case errors.Is(err, quote.ErrInvalidTransition), errors.Is(err, quote.ErrQuoteExpired), errors.Is(err, quote.ErrQuoteNotEditable):
+		return http.StatusConflict
+	}
+
+	return errorStatus(err)
+}
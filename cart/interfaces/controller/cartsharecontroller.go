@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+	"go.opencensus.io/trace"
+
+	"flamingo.me/flamingo-commerce/v3/cart/application"
+)
+
+type (
+	// CartShareController creates share links for the current cart and imports shared carts
+	CartShareController struct {
+		responder           *web.Responder
+		cartShareService    *application.CartShareService
+		cartService         *application.CartService
+		cartReceiverService *application.CartReceiverService
+		router              *web.Router
+		logger              flamingo.Logger
+	}
+
+	shareCartResult struct {
+		Token string
+		URL   string
+	} // @name cartShareResult
+)
+
+// Inject dependencies
+func (cc *CartShareController) Inject(
+	responder *web.Responder,
+	cartShareService *application.CartShareService,
+	cartService *application.CartService,
+	cartReceiverService *application.CartReceiverService,
+	router *web.Router,
+	logger flamingo.Logger,
+) *CartShareController {
+	cc.responder = responder
+	cc.cartShareService = cartShareService
+	cc.cartService = cartService
+	cc.cartReceiverService = cartReceiverService
+	cc.router = router
+	cc.logger = logger.WithField(flamingo.LogKeyCategory, "cartsharecontroller").WithField(flamingo.LogKeyModule, "cart")
+
+	return cc
+}
+
+// ImportAndViewAction adds the items of a shared cart to the current cart and redirects to the cart view,
+// items that could not be added are passed to the cart view in CartViewData.CartImportResults
+func (cc *CartShareController) ImportAndViewAction(ctx context.Context, r *web.Request) web.Result {
+	ctx, span := trace.StartSpan(ctx, "cart/CartShareController/ImportAndViewAction")
+	defer span.End()
+
+	results, err := cc.cartShareService.ImportCart(ctx, r.Session(), r.Params["token"])
+	if err != nil {
+		cc.logger.WithContext(ctx).Warn("cart.cartsharecontroller.importandviewaction: Error %v", err)
+		r.Session().AddFlash(err.Error(), "cart.view.import.error")
+	}
+
+	if len(results) > 0 {
+		r.Session().AddFlash(results, "cart.view.import.results")
+	}
+
+	return cc.responder.RouteRedirect("cart.view", nil)
+}
+
+// ShareAction returns a share token and link for the current cart
+// @Summary Create a signed and expiring link which allows to import the items of the current cart into another cart
+// @Tags Cart
+// @Produce json
+// @Success 200 {object} CartAPIResult{Data=shareCartResult}
+// @Failure 500 {object} CartAPIResult
+// @Router /api/v1/cart/share [post]
+func (cc *CartShareController) ShareAction(ctx context.Context, r *web.Request) web.Result {
+	ctx, span := trace.StartSpan(ctx, "cart/CartShareController/ShareAction")
+	defer span.End()
+
+	result := newResult()
+
+	token, err := cc.cartShareService.ShareCart(ctx, r.Session())
+	if err != nil {
+		cc.logger.WithContext(ctx).Error("cart.cartsharecontroller.share: %v", err.Error())
+
+		result.SetError(err, "share_cart_error")
+		return cc.responder.Data(result).Status(http.StatusInternalServerError)
+	}
+
+	shareResult := shareCartResult{Token: token}
+
+	shareURL, err := cc.router.Absolute(r, "cart.import", map[string]string{"token": token})
+	if err == nil {
+		shareResult.URL = shareURL.String()
+	}
+
+	result.Data = shareResult
+
+	return cc.responder.Data(result)
+}
+
+// ImportAction adds the items of a shared cart to the current cart
+// @Summary Add the items of a shared cart to the current cart, items that could not be added completely are returned in Data
+// @Tags Cart
+// @Produce json
+// @Success 200 {object} CartAPIResult{Data=application.CartImportResults}
+// @Failure 400 {object} CartAPIResult
+// @Failure 500 {object} CartAPIResult
+// @Param token path string true "the share token"
+// @Router /api/v1/cart/import/{token} [post]
+func (cc *CartShareController) ImportAction(ctx context.Context, r *web.Request) web.Result {
+	ctx, span := trace.StartSpan(ctx, "cart/CartShareController/ImportAction")
+	defer span.End()
+
+	result := newResult()
+
+	results, err := cc.cartShareService.ImportCart(ctx, r.Session(), r.Params["token"])
+	if err != nil {
+		cc.logger.WithContext(ctx).Warn("cart.cartsharecontroller.import: %v", err.Error())
+
+		result.SetError(err, "import_cart_error")
+		status := errorStatus(err)
+		if errors.Is(err, application.ErrInvalidShareToken) || errors.Is(err, application.ErrShareTokenExpired) {
+			status = http.StatusBadRequest
+		}
+
+		return cc.responder.Data(result).Status(status)
+	}
+
+	result.Data = results
+
+	session := web.SessionFromContext(ctx)
+	decoratedCart, err := cc.cartReceiverService.ViewDecoratedCart(ctx, session)
+	if err != nil {
+		result.SetError(err, "view_cart_error")
+		return cc.responder.Data(result)
+	}
+
+	validationResult := cc.cartService.ValidateCart(ctx, session, decoratedCart)
+	result.CartTeaser = decoratedCart.Cart.GetCartTeaser()
+	result.CartValidationResult = &validationResult
+
+	return withETag(cc.responder.Data(result), &decoratedCart.Cart)
+}
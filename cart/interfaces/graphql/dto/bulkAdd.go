@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"flamingo.me/flamingo-commerce/v3/cart/application"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/validation"
+)
+
+type (
+	// AddToCartBulkResult is the result of the Commerce_Cart_AddToCartBulk mutation
+	AddToCartBulkResult struct {
+		Lines         []BulkAddLineResult
+		DecoratedCart *DecoratedCart
+	}
+
+	// BulkAddLineResult is the GraphQL representation of application.BulkAddResult
+	BulkAddLineResult struct {
+		Line                   int
+		MarketplaceCode        string
+		VariantMarketplaceCode string
+		DeliveryCode           string
+		Qty                    int
+		Status                 string
+		RestrictionResult      *validation.RestrictionResult
+		Error                  string
+	}
+)
+
+// MapBulkAddResults maps the results of application.CartService.AddProductsBulk
+func MapBulkAddResults(results application.BulkAddResults) []BulkAddLineResult {
+	lines := make([]BulkAddLineResult, 0, len(results))
+	for _, result := range results {
+		lines = append(lines, BulkAddLineResult{
+			Line:                   result.Line,
+			MarketplaceCode:        result.AddRequest.MarketplaceCode,
+			VariantMarketplaceCode: result.AddRequest.VariantMarketplaceCode,
+			DeliveryCode:           result.DeliveryCode,
+			Qty:                    result.AddRequest.Qty,
+			Status:                 string(result.Status),
+			RestrictionResult:      result.RestrictionResult,
+			Error:                  result.Error,
+		})
+	}
+
+	return lines
+}
@@ -61,6 +61,11 @@ func (dc *DecoratedCart) CartSummary() CartSummary {
 	return CartSummary{cart: &dcCart}
 }
 
+// ChangeNotices – returns the items whose price or saleable state changed since they have been added
+func (dc *DecoratedCart) ChangeNotices() []ItemChangeNotice {
+	return MapItemChangeNotices(dc.decoratedCart.ChangeNotices)
+}
+
 // NewDecoratedCart – factory method
 func NewDecoratedCart(dc *decorator.DecoratedCart) *DecoratedCart {
 	return &DecoratedCart{decoratedCart: dc}
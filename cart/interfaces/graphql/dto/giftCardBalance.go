@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+type (
+	// GiftCardBalance is the result of the gift card balance check
+	GiftCardBalance struct {
+		Code    string
+		Balance domain.Price
+		// ValidUntil is nil if the gift card does not expire
+		ValidUntil *time.Time
+		IsValid    bool
+	}
+)
+
+// NewGiftCardBalance maps the gift card of the registry
+func NewGiftCardBalance(giftCard cart.GiftCard, now time.Time) *GiftCardBalance {
+	balance := &GiftCardBalance{
+		Code:    giftCard.Code,
+		Balance: giftCard.Balance,
+		IsValid: giftCard.IsValid(now),
+	}
+
+	if !giftCard.ValidUntil.IsZero() {
+		validUntil := giftCard.ValidUntil
+		balance.ValidUntil = &validUntil
+	}
+
+	return balance
+}
@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"sort"
+	"time"
+
+	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+)
+
+type (
+	// HistoryEntry is a cart history entry with its parameters as sorted key value list
+	HistoryEntry struct {
+		CartID     string
+		Revision   int
+		Operation  string
+		Parameters []KeyValue
+		Actor      cart.HistoryActor
+		Timestamp  time.Time
+		Totals     cart.HistoryTotals
+		Events     []string
+	}
+)
+
+// NewHistoryEntries maps the history entries of the cart
+func NewHistoryEntries(entries []cart.HistoryEntry) []HistoryEntry {
+	result := make([]HistoryEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		parameters := make([]KeyValue, 0, len(entry.Parameters))
+		for key, value := range entry.Parameters {
+			parameters = append(parameters, KeyValue{Key: key, Value: value})
+		}
+
+		sort.Slice(parameters, func(i, j int) bool {
+			return parameters[i].Key < parameters[j].Key
+		})
+
+		events := entry.Events
+		if events == nil {
+			events = []string{}
+		}
+
+		result = append(result, HistoryEntry{
+			CartID:     entry.CartID,
+			Revision:   entry.Revision,
+			Operation:  entry.Operation,
+			Parameters: parameters,
+			Actor:      entry.Actor,
+			Timestamp:  entry.Timestamp,
+			Totals:     entry.Totals,
+			Events:     events,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"flamingo.me/flamingo-commerce/v3/cart/domain/decorator"
+	"flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+type (
+	// ItemChangeNotice is the GraphQL representation of decorator.ItemChangeNotice
+	ItemChangeNotice struct {
+		ItemID                 string
+		DeliveryCode           string
+		MarketplaceCode        string
+		VariantMarketplaceCode string
+		Type                   string
+		StoredPrice            domain.Price
+		CurrentPrice           *domain.Price
+	}
+)
+
+// MapItemChangeNotices maps the change notices of the decorated cart
+func MapItemChangeNotices(notices []decorator.ItemChangeNotice) []ItemChangeNotice {
+	result := make([]ItemChangeNotice, 0, len(notices))
+	for _, notice := range notices {
+		mapped := ItemChangeNotice{
+			ItemID:                 notice.ItemID,
+			DeliveryCode:           notice.DeliveryCode,
+			MarketplaceCode:        notice.MarketplaceCode,
+			VariantMarketplaceCode: notice.VariantMarketplaceCode,
+			Type:                   string(notice.Type),
+			StoredPrice:            notice.StoredPrice,
+		}
+
+		if notice.Type != decorator.ItemChangeNoticeNotSaleable {
+			currentPrice := notice.CurrentPrice
+			mapped.CurrentPrice = &currentPrice
+		}
+
+		result = append(result, mapped)
+	}
+
+	return result
+}
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"time"
+
+	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/placeorder"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/quote"
+)
+
+type (
+	// Quote is a quote with an optional expiry date
+	Quote struct {
+		ID               string
+		State            string
+		CustomerID       string
+		SalesRepID       string
+		Cart             cart.Cart
+		CreatedAt        time.Time
+		UpdatedAt        time.Time
+		ExpiresAt        *time.Time
+		OrderCartID      string
+		PlacedOrderInfos []placeorder.PlacedOrderInfo
+	}
+)
+
+// NewQuote maps the quote
+func NewQuote(q *quote.Quote) *Quote {
+	var expiresAt *time.Time
+	if !q.ExpiresAt.IsZero() {
+		expiry := q.ExpiresAt
+		expiresAt = &expiry
+	}
+
+	return &Quote{
+		ID:               q.ID,
+		State:            string(q.State),
+		CustomerID:       q.CustomerID,
+		SalesRepID:       q.SalesRepID,
+		Cart:             q.Cart,
+		CreatedAt:        q.CreatedAt,
+		UpdatedAt:        q.UpdatedAt,
+		ExpiresAt:        expiresAt,
+		OrderCartID:      q.OrderCartID,
+		PlacedOrderInfos: q.PlacedOrderInfos,
+	}
+}
+
+// NewQuotes maps the quotes
+func NewQuotes(quotes []*quote.Quote) []*Quote {
+	result := make([]*Quote, 0, len(quotes))
+	for _, q := range quotes {
+		result = append(result, NewQuote(q))
+	}
+
+	return result
+}
@@ -0,0 +1,31 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"flamingo.me/flamingo-commerce/v3/cart/application"
+	"flamingo.me/flamingo-commerce/v3/cart/interfaces/graphql/dto"
+)
+
+// CommerceCartGiftCardResolver resolver for the gift card balance check
+type CommerceCartGiftCardResolver struct {
+	giftCardService *application.GiftCardService
+}
+
+// Inject dependencies
+func (r *CommerceCartGiftCardResolver) Inject(giftCardService *application.GiftCardService) *CommerceCartGiftCardResolver {
+	r.giftCardService = giftCardService
+
+	return r
+}
+
+// CommerceCartGiftCardBalance returns the currently available balance of the gift card
+func (r *CommerceCartGiftCardResolver) CommerceCartGiftCardBalance(ctx context.Context, code string) (*dto.GiftCardBalance, error) {
+	giftCard, err := r.giftCardService.Balance(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewGiftCardBalance(*giftCard, time.Now()), nil
+}
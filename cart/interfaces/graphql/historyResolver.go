@@ -0,0 +1,32 @@
+package graphql
+
+import (
+	"context"
+
+	"flamingo.me/flamingo/v3/framework/web"
+
+	"flamingo.me/flamingo-commerce/v3/cart/application"
+	"flamingo.me/flamingo-commerce/v3/cart/interfaces/graphql/dto"
+)
+
+// CommerceCartHistoryResolver resolver for the change history of the cart
+type CommerceCartHistoryResolver struct {
+	historyService *application.HistoryService
+}
+
+// Inject dependencies
+func (r *CommerceCartHistoryResolver) Inject(historyService *application.HistoryService) *CommerceCartHistoryResolver {
+	r.historyService = historyService
+
+	return r
+}
+
+// CommerceCartHistory returns the modifications of the current cart
+func (r *CommerceCartHistoryResolver) CommerceCartHistory(ctx context.Context) ([]dto.HistoryEntry, error) {
+	entries, err := r.historyService.CurrentCartHistory(ctx, web.SessionFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewHistoryEntries(entries), nil
+}
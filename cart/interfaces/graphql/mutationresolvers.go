@@ -56,7 +56,8 @@ func (r *CommerceCartMutationResolver) Inject(q *CommerceCartQueryResolver,
 }
 
 // CommerceAddToCart mutation for adding products to the current users cart
-func (r *CommerceCartMutationResolver) CommerceAddToCart(ctx context.Context, graphqlAddRequest dto.AddToCart) (*dto.DecoratedCart, error) {
+func (r *CommerceCartMutationResolver) CommerceAddToCart(ctx context.Context, graphqlAddRequest dto.AddToCart, revision *int) (*dto.DecoratedCart, error) {
+	ctx = contextWithRevision(ctx, revision)
 	req := web.RequestFromContext(ctx)
 
 	if graphqlAddRequest.Qty < 0 {
@@ -79,8 +80,43 @@ func (r *CommerceCartMutationResolver) CommerceAddToCart(ctx context.Context, gr
 	return r.q.CommerceCart(ctx)
 }
 
+// CommerceAddToCartBulk mutation for adding many products with a single modification of the current users cart
+func (r *CommerceCartMutationResolver) CommerceAddToCartBulk(ctx context.Context, graphqlAddRequests []dto.AddToCart, revision *int) (*dto.AddToCartBulkResult, error) {
+	ctx = contextWithRevision(ctx, revision)
+	req := web.RequestFromContext(ctx)
+
+	addRequests := make([]cartDomain.DeliveryAddRequest, 0, len(graphqlAddRequests))
+	for _, graphqlAddRequest := range graphqlAddRequests {
+		addRequests = append(addRequests, cartDomain.DeliveryAddRequest{
+			DeliveryCode: graphqlAddRequest.DeliveryCode,
+			AddRequest: cartDomain.AddRequest{
+				MarketplaceCode:        graphqlAddRequest.MarketplaceCode,
+				Qty:                    graphqlAddRequest.Qty,
+				VariantMarketplaceCode: graphqlAddRequest.VariantMarketplaceCode,
+				BundleConfiguration:    dto.MapBundleConfigToDomain(graphqlAddRequest.BundleConfiguration),
+			},
+		})
+	}
+
+	results, err := r.cartService.AddProductsBulk(ctx, req.Session(), addRequests)
+	if err != nil {
+		return nil, err
+	}
+
+	decoratedCart, err := r.q.CommerceCart(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AddToCartBulkResult{
+		Lines:         dto.MapBulkAddResults(results),
+		DecoratedCart: decoratedCart,
+	}, nil
+}
+
 // CommerceDeleteItem resolver
-func (r *CommerceCartMutationResolver) CommerceDeleteItem(ctx context.Context, itemID string, deliveryCode string) (*dto.DecoratedCart, error) {
+func (r *CommerceCartMutationResolver) CommerceDeleteItem(ctx context.Context, itemID string, deliveryCode string, revision *int) (*dto.DecoratedCart, error) {
+	ctx = contextWithRevision(ctx, revision)
 	req := web.RequestFromContext(ctx)
 
 	err := r.cartService.DeleteItem(ctx, req.Session(), itemID, deliveryCode)
@@ -93,7 +129,8 @@ func (r *CommerceCartMutationResolver) CommerceDeleteItem(ctx context.Context, i
 }
 
 // CommerceDeleteCartDelivery mutation for removing deliveries from current users cart
-func (r *CommerceCartMutationResolver) CommerceDeleteCartDelivery(ctx context.Context, deliveryCode string) (*dto.DecoratedCart, error) {
+func (r *CommerceCartMutationResolver) CommerceDeleteCartDelivery(ctx context.Context, deliveryCode string, revision *int) (*dto.DecoratedCart, error) {
+	ctx = contextWithRevision(ctx, revision)
 	req := web.RequestFromContext(ctx)
 	_, err := r.cartService.DeleteDelivery(ctx, req.Session(), deliveryCode)
 	if err != nil {
@@ -103,7 +140,8 @@ func (r *CommerceCartMutationResolver) CommerceDeleteCartDelivery(ctx context.Co
 }
 
 // CommerceUpdateItemQty mutation for updating item quantity
-func (r *CommerceCartMutationResolver) CommerceUpdateItemQty(ctx context.Context, itemID string, deliveryCode string, qty int) (*dto.DecoratedCart, error) {
+func (r *CommerceCartMutationResolver) CommerceUpdateItemQty(ctx context.Context, itemID string, deliveryCode string, qty int, revision *int) (*dto.DecoratedCart, error) {
+	ctx = contextWithRevision(ctx, revision)
 	req := web.RequestFromContext(ctx)
 	err := r.cartService.UpdateItemQty(ctx, req.Session(), itemID, deliveryCode, qty)
 	if err != nil {
@@ -113,7 +151,8 @@ func (r *CommerceCartMutationResolver) CommerceUpdateItemQty(ctx context.Context
 }
 
 // CommerceUpdateItemBundleConfig mutation for updating item quantity
-func (r *CommerceCartMutationResolver) CommerceUpdateItemBundleConfig(ctx context.Context, itemID string, bundleConfig []*dto.ChoiceConfiguration) (*dto.DecoratedCart, error) {
+func (r *CommerceCartMutationResolver) CommerceUpdateItemBundleConfig(ctx context.Context, itemID string, bundleConfig []*dto.ChoiceConfiguration, revision *int) (*dto.DecoratedCart, error) {
+	ctx = contextWithRevision(ctx, revision)
 	req := web.RequestFromContext(ctx)
 
 	var bundleConfigDto []dto.ChoiceConfiguration
@@ -196,7 +235,8 @@ func (r *CommerceCartMutationResolver) CommerceCartUpdateSelectedPayment(ctx con
 }
 
 // CommerceCartApplyCouponCodeOrGiftCard – apply coupon code or gift card
-func (r *CommerceCartMutationResolver) CommerceCartApplyCouponCodeOrGiftCard(ctx context.Context, code string) (*dto.DecoratedCart, error) {
+func (r *CommerceCartMutationResolver) CommerceCartApplyCouponCodeOrGiftCard(ctx context.Context, code string, revision *int) (*dto.DecoratedCart, error) {
+	ctx = contextWithRevision(ctx, revision)
 	req := web.RequestFromContext(ctx)
 
 	_, err := r.cartService.ApplyAny(ctx, req.Session(), code)
@@ -209,7 +249,8 @@ func (r *CommerceCartMutationResolver) CommerceCartApplyCouponCodeOrGiftCard(ctx
 }
 
 // CommerceCartRemoveCouponCode - remove coupon code
-func (r *CommerceCartMutationResolver) CommerceCartRemoveCouponCode(ctx context.Context, couponCode string) (*dto.DecoratedCart, error) {
+func (r *CommerceCartMutationResolver) CommerceCartRemoveCouponCode(ctx context.Context, couponCode string, revision *int) (*dto.DecoratedCart, error) {
+	ctx = contextWithRevision(ctx, revision)
 	req := web.RequestFromContext(ctx)
 
 	_, err := r.cartService.RemoveVoucher(ctx, req.Session(), couponCode)
@@ -222,7 +263,8 @@ func (r *CommerceCartMutationResolver) CommerceCartRemoveCouponCode(ctx context.
 }
 
 // CommerceCartRemoveGiftCard - remove gift card
-func (r *CommerceCartMutationResolver) CommerceCartRemoveGiftCard(ctx context.Context, giftCardCode string) (*dto.DecoratedCart, error) {
+func (r *CommerceCartMutationResolver) CommerceCartRemoveGiftCard(ctx context.Context, giftCardCode string, revision *int) (*dto.DecoratedCart, error) {
+	ctx = contextWithRevision(ctx, revision)
 	req := web.RequestFromContext(ctx)
 
 	_, err := r.cartService.RemoveGiftCard(ctx, req.Session(), giftCardCode)
@@ -299,7 +341,8 @@ func (r *CommerceCartMutationResolver) CartClean(ctx context.Context) (bool, err
 }
 
 // UpdateAdditionalData of cart
-func (r *CommerceCartMutationResolver) UpdateAdditionalData(ctx context.Context, additionalDataList []*dto.KeyValue) (*dto.DecoratedCart, error) {
+func (r *CommerceCartMutationResolver) UpdateAdditionalData(ctx context.Context, additionalDataList []*dto.KeyValue, revision *int) (*dto.DecoratedCart, error) {
+	ctx = contextWithRevision(ctx, revision)
 	session := web.SessionFromContext(ctx)
 	additionalDataMap := map[string]string{}
 	for _, additionalData := range additionalDataList {
@@ -315,23 +358,73 @@ func (r *CommerceCartMutationResolver) UpdateAdditionalData(ctx context.Context,
 }
 
 // UpdateDeliveriesAdditionalData of cart
-func (r *CommerceCartMutationResolver) UpdateDeliveriesAdditionalData(ctx context.Context, additionalDataList []*dto.DeliveryAdditionalData) (*dto.DecoratedCart, error) {
+func (r *CommerceCartMutationResolver) UpdateDeliveriesAdditionalData(ctx context.Context, additionalDataList []*dto.DeliveryAdditionalData, revision *int) (*dto.DecoratedCart, error) {
 	session := web.SessionFromContext(ctx)
+	// only the first modification must be based on the given revision, the following ones are based on the previous result
+	modifyCtx := contextWithRevision(ctx, revision)
 	for _, additionalData := range additionalDataList {
 		additionalDataMap := map[string]string{}
 		for _, deliveryAdditionalData := range additionalData.AdditionalData {
 			additionalDataMap[deliveryAdditionalData.Key] = deliveryAdditionalData.Value
 		}
 
-		_, err := r.cartService.UpdateDeliveryAdditionalData(ctx, session, additionalData.DeliveryCode, additionalDataMap)
+		_, err := r.cartService.UpdateDeliveryAdditionalData(modifyCtx, session, additionalData.DeliveryCode, additionalDataMap)
 		if err != nil {
 			return nil, err
 		}
+
+		modifyCtx = ctx
 	}
 
 	return r.q.CommerceCart(ctx)
 }
 
+// CreateCustomerCart creates a new named cart for the logged in customer
+func (r *CommerceCartMutationResolver) CreateCustomerCart(ctx context.Context, name string) (*cartDomain.Cart, error) {
+	return r.cartReceiverService.CreateCustomerCart(ctx, name)
+}
+
+// RenameCustomerCart renames a cart of the logged in customer
+func (r *CommerceCartMutationResolver) RenameCustomerCart(ctx context.Context, cartID string, name string) (*cartDomain.Cart, error) {
+	return r.cartReceiverService.RenameCustomerCart(ctx, web.SessionFromContext(ctx), cartID, name)
+}
+
+// SwitchCustomerCart makes the given cart the current cart of the logged in customer
+func (r *CommerceCartMutationResolver) SwitchCustomerCart(ctx context.Context, cartID string) (*dto.DecoratedCart, error) {
+	_, err := r.cartReceiverService.SwitchCustomerCart(ctx, web.SessionFromContext(ctx), cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.q.CommerceCart(ctx)
+}
+
+// MoveItem moves an item of the current cart to another cart of the logged in customer
+func (r *CommerceCartMutationResolver) MoveItem(ctx context.Context, itemID string, deliveryCode string, targetCartID string, revision *int, targetDeliveryCode *string) (*dto.DecoratedCart, error) {
+	ctx = contextWithRevision(ctx, revision)
+
+	var targetDelivery string
+	if targetDeliveryCode != nil {
+		targetDelivery = *targetDeliveryCode
+	}
+
+	err := r.cartService.MoveItem(ctx, web.SessionFromContext(ctx), itemID, deliveryCode, targetCartID, targetDelivery)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.q.CommerceCart(ctx)
+}
+
+// contextWithRevision passes the optional revision argument on to the cart service
+func contextWithRevision(ctx context.Context, revision *int) context.Context {
+	if revision == nil {
+		return ctx
+	}
+
+	return application.ContextWithExpectedRevision(ctx, *revision)
+}
+
 func mapCommerceDeliveryAddressForm(form *domain.Form, success bool) (dto.DeliveryAddressForm, error) {
 	formData, ok := form.Data.(forms.DeliveryForm)
 	if !ok {
@@ -0,0 +1,94 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/web"
+
+	"flamingo.me/flamingo-commerce/v3/cart/application"
+	"flamingo.me/flamingo-commerce/v3/cart/interfaces/graphql/dto"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+// CommerceCartQuoteResolver resolver for the quotes offered by sales reps to customers
+type CommerceCartQuoteResolver struct {
+	quoteService *application.QuoteService
+}
+
+// Inject dependencies
+func (r *CommerceCartQuoteResolver) Inject(quoteService *application.QuoteService) *CommerceCartQuoteResolver {
+	r.quoteService = quoteService
+
+	return r
+}
+
+// CommerceCartQuotes returns the quotes of the logged in customer, sales reps may request the quotes of any customer
+func (r *CommerceCartQuoteResolver) CommerceCartQuotes(ctx context.Context, customerID *string) ([]*dto.Quote, error) {
+	var customer string
+	if customerID != nil {
+		customer = *customerID
+	}
+
+	quotes, err := r.quoteService.ListQuotes(ctx, customer)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewQuotes(quotes), nil
+}
+
+// CommerceCartQuote returns the quote
+func (r *CommerceCartQuoteResolver) CommerceCartQuote(ctx context.Context, id string) (*dto.Quote, error) {
+	q, err := r.quoteService.GetQuote(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewQuote(q), nil
+}
+
+// CommerceCartCreateQuote creates a draft quote for the customer from the current cart of the sales rep
+func (r *CommerceCartQuoteResolver) CommerceCartCreateQuote(ctx context.Context, customerID string, expiresAt *time.Time) (*dto.Quote, error) {
+	q, err := r.quoteService.CreateQuote(ctx, web.SessionFromContext(ctx), customerID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewQuote(q), nil
+}
+
+// CommerceCartUpdateQuoteItemPrice locks the negotiated single price of an item of a draft quote
+func (r *CommerceCartQuoteResolver) CommerceCartUpdateQuoteItemPrice(ctx context.Context, quoteID string, itemID string, price float64, currency *string) (*dto.Quote, error) {
+	var currencyCode string
+	if currency != nil {
+		currencyCode = *currency
+	}
+
+	q, err := r.quoteService.UpdateItemPrice(ctx, quoteID, itemID, priceDomain.NewFromFloat(price, currencyCode))
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewQuote(q), nil
+}
+
+// CommerceCartSendQuote offers a draft quote to the customer
+func (r *CommerceCartQuoteResolver) CommerceCartSendQuote(ctx context.Context, quoteID string) (*dto.Quote, error) {
+	q, err := r.quoteService.SendQuote(ctx, quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewQuote(q), nil
+}
+
+// CommerceCartAcceptQuote accepts a sent quote as the customer
+func (r *CommerceCartQuoteResolver) CommerceCartAcceptQuote(ctx context.Context, quoteID string) (*dto.Quote, error) {
+	q, err := r.quoteService.AcceptQuote(ctx, quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewQuote(q), nil
+}
@@ -58,6 +58,32 @@ func (r *CommerceCartQueryResolver) CommerceCartValidator(ctx context.Context) (
 	return &result, nil
 }
 
+// CommerceCartCustomerCarts returns all carts of the logged in customer
+func (r *CommerceCartQueryResolver) CommerceCartCustomerCarts(ctx context.Context) ([]*cart.Cart, error) {
+	return r.applicationCartReceiverService.ListCustomerCarts(ctx)
+}
+
+// CommerceCartLastMergeReport returns the report of the last guest cart merge, it is removed from the session afterwards
+func (r *CommerceCartQueryResolver) CommerceCartLastMergeReport(ctx context.Context) (*application.CartMergeReport, error) {
+	return application.LastCartMergeReport(web.SessionFromContext(ctx)), nil
+}
+
+// CommercePreviewBundleConfig returns the price and availability of an item with the proposed bundle configuration, the cart is not changed
+func (r *CommerceCartQueryResolver) CommercePreviewBundleConfig(ctx context.Context, itemID string, bundleConfig []*dto.ChoiceConfiguration) (*application.BundleConfigPreview, error) {
+	var bundleConfigDto []dto.ChoiceConfiguration
+
+	for _, config := range bundleConfig {
+		bundleConfigDto = append(bundleConfigDto, *config)
+	}
+
+	updateCommand := cart.ItemUpdateCommand{
+		ItemID:              itemID,
+		BundleConfiguration: dto.MapBundleConfigToDomain(bundleConfigDto),
+	}
+
+	return r.applicationCartService.PreviewItemBundleConfig(ctx, web.SessionFromContext(ctx), updateCommand)
+}
+
 // CommerceCartQtyRestriction checks if given sku is restricted in terms of qty
 func (r *CommerceCartQueryResolver) CommerceCartQtyRestriction(ctx context.Context, marketplaceCode string, variantCode *string, deliveryCode string) (*validation.RestrictionResult, error) {
 	session := web.SessionFromContext(ctx)
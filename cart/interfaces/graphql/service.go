@@ -8,6 +8,7 @@ import (
 	formDomain "flamingo.me/form/domain"
 	"flamingo.me/graphql"
 
+	"flamingo.me/flamingo-commerce/v3/cart/application"
 	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
 	"flamingo.me/flamingo-commerce/v3/cart/domain/placeorder"
 	"flamingo.me/flamingo-commerce/v3/cart/domain/validation"
@@ -38,6 +39,7 @@ func (*Service) Types(types *graphql.Types) {
 	types.Map("Commerce_Cart_Cart", cart.Cart{})
 	types.Resolve("Commerce_Cart_Cart", "getDeliveryByCode", Resolver{}, "GetDeliveryByCodeWithoutBool")
 	types.Map("Commerce_Cart_Summary", dto.CartSummary{})
+	types.Map("Commerce_Cart_ItemChangeNotice", dto.ItemChangeNotice{})
 	types.Map("Commerce_Cart_DecoratedDelivery", dto.DecoratedDelivery{})
 	types.Map("Commerce_Cart_Delivery", cart.Delivery{})
 	types.Map("Commerce_Cart_DeliveryInfo", cart.DeliveryInfo{})
@@ -62,6 +64,17 @@ func (*Service) Types(types *graphql.Types) {
 	types.Map("Commerce_Cart_AppliedDiscounts", dto.CartAppliedDiscounts{})
 	types.Map("Commerce_Cart_AppliedDiscount", cart.AppliedDiscount{})
 	types.Map("Commerce_Cart_AppliedGiftCard", cart.AppliedGiftCard{})
+	types.Map("Commerce_Cart_GiftCardBalance", dto.GiftCardBalance{})
+	types.Map("Commerce_Cart_ShippingMethod", cart.ShippingMethod{})
+	types.Map("Commerce_Cart_HistoryEntry", dto.HistoryEntry{})
+	types.Map("Commerce_Cart_HistoryActor", cart.HistoryActor{})
+	types.Map("Commerce_Cart_HistoryTotals", cart.HistoryTotals{})
+	types.Map("Commerce_Cart_MergeReport", application.CartMergeReport{})
+	types.Map("Commerce_Cart_MergeReportItem", application.CartMergeReportItem{})
+	types.Map("Commerce_Cart_MergeReportCode", application.CartMergeReportCode{})
+	types.Map("Commerce_Cart_BundleConfigPreview", application.BundleConfigPreview{})
+	types.Map("Commerce_Cart_BundleConfigPreviewChoice", application.BundleConfigPreviewChoice{})
+	types.Map("Commerce_Cart_Quote", dto.Quote{})
 	types.Map("Commerce_Cart_PricedItems", dto.PricedItems{})
 	types.Map("Commerce_Cart_PricedCartItem", dto.PricedCartItem{})
 	types.Map("Commerce_Cart_PricedShippingItem", dto.PricedShippingItem{})
@@ -93,6 +106,8 @@ func (*Service) Types(types *graphql.Types) {
 	types.GoField("Commerce_Cart_DeliveryAddressInput", "method", "ShippingMethod")
 	types.Map("Commerce_Cart_DeliveryShippingOptionInput", dto.DeliveryShippingOption{})
 	types.Map("Commerce_Cart_QtyRestrictionResult", validation.RestrictionResult{})
+	types.Map("Commerce_Cart_BulkAddLineResult", dto.BulkAddLineResult{})
+	types.Map("Commerce_Cart_AddToCartBulkResult", dto.AddToCartBulkResult{})
 	types.Map("Commerce_Cart_PaymentSelection_Split", dto.PaymentSelectionSplit{})
 	types.Map("Commerce_Cart_PaymentSelection_SplitQualifier", cart.SplitQualifier{})
 	types.GoField("Commerce_Cart_PaymentSelection_SplitQualifier", "type", "ChargeType")
@@ -101,8 +116,17 @@ func (*Service) Types(types *graphql.Types) {
 	types.Resolve("Query", "Commerce_Cart_DecoratedCart", CommerceCartQueryResolver{}, "CommerceCart")
 	types.Resolve("Query", "Commerce_Cart_Validator", CommerceCartQueryResolver{}, "CommerceCartValidator")
 	types.Resolve("Query", "Commerce_Cart_QtyRestriction", CommerceCartQueryResolver{}, "CommerceCartQtyRestriction")
+	types.Resolve("Query", "Commerce_Cart_CustomerCarts", CommerceCartQueryResolver{}, "CommerceCartCustomerCarts")
+	types.Resolve("Query", "Commerce_Cart_GiftCardBalance", CommerceCartGiftCardResolver{}, "CommerceCartGiftCardBalance")
+	types.Resolve("Query", "Commerce_Cart_AvailableShippingMethods", CommerceCartShippingResolver{}, "CommerceCartAvailableShippingMethods")
+	types.Resolve("Query", "Commerce_Cart_History", CommerceCartHistoryResolver{}, "CommerceCartHistory")
+	types.Resolve("Query", "Commerce_Cart_LastMergeReport", CommerceCartQueryResolver{}, "CommerceCartLastMergeReport")
+	types.Resolve("Query", "Commerce_Cart_PreviewBundleConfig", CommerceCartQueryResolver{}, "CommercePreviewBundleConfig")
+	types.Resolve("Query", "Commerce_Cart_Quotes", CommerceCartQuoteResolver{}, "CommerceCartQuotes")
+	types.Resolve("Query", "Commerce_Cart_Quote", CommerceCartQuoteResolver{}, "CommerceCartQuote")
 
 	types.Resolve("Mutation", "Commerce_Cart_AddToCart", CommerceCartMutationResolver{}, "CommerceAddToCart")
+	types.Resolve("Mutation", "Commerce_Cart_AddToCartBulk", CommerceCartMutationResolver{}, "CommerceAddToCartBulk")
 	types.Resolve("Mutation", "Commerce_Cart_DeleteCartDelivery", CommerceCartMutationResolver{}, "CommerceDeleteCartDelivery")
 	types.Resolve("Mutation", "Commerce_Cart_DeleteCartDelivery", CommerceCartMutationResolver{}, "CommerceDeleteCartDelivery")
 	types.Resolve("Mutation", "Commerce_Cart_DeleteItem", CommerceCartMutationResolver{}, "CommerceDeleteItem")
@@ -119,6 +143,14 @@ func (*Service) Types(types *graphql.Types) {
 	types.Resolve("Mutation", "Commerce_Cart_Clean", CommerceCartMutationResolver{}, "CartClean")
 	types.Resolve("Mutation", "Commerce_Cart_UpdateAdditionalData", CommerceCartMutationResolver{}, "UpdateAdditionalData")
 	types.Resolve("Mutation", "Commerce_Cart_UpdateDeliveriesAdditionalData", CommerceCartMutationResolver{}, "UpdateDeliveriesAdditionalData")
+	types.Resolve("Mutation", "Commerce_Cart_CreateCustomerCart", CommerceCartMutationResolver{}, "CreateCustomerCart")
+	types.Resolve("Mutation", "Commerce_Cart_RenameCustomerCart", CommerceCartMutationResolver{}, "RenameCustomerCart")
+	types.Resolve("Mutation", "Commerce_Cart_SwitchCustomerCart", CommerceCartMutationResolver{}, "SwitchCustomerCart")
+	types.Resolve("Mutation", "Commerce_Cart_MoveItem", CommerceCartMutationResolver{}, "MoveItem")
+	types.Resolve("Mutation", "Commerce_Cart_CreateQuote", CommerceCartQuoteResolver{}, "CommerceCartCreateQuote")
+	types.Resolve("Mutation", "Commerce_Cart_UpdateQuoteItemPrice", CommerceCartQuoteResolver{}, "CommerceCartUpdateQuoteItemPrice")
+	types.Resolve("Mutation", "Commerce_Cart_SendQuote", CommerceCartQuoteResolver{}, "CommerceCartSendQuote")
+	types.Resolve("Mutation", "Commerce_Cart_AcceptQuote", CommerceCartQuoteResolver{}, "CommerceCartAcceptQuote")
 }
 
 // Resolver helper
@@ -0,0 +1,27 @@
+package graphql
+
+import (
+	"context"
+
+	"flamingo.me/flamingo/v3/framework/web"
+
+	"flamingo.me/flamingo-commerce/v3/cart/application"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+)
+
+// CommerceCartShippingResolver resolver for the available shipping methods
+type CommerceCartShippingResolver struct {
+	shippingService *application.ShippingService
+}
+
+// Inject dependencies
+func (r *CommerceCartShippingResolver) Inject(shippingService *application.ShippingService) *CommerceCartShippingResolver {
+	r.shippingService = shippingService
+
+	return r
+}
+
+// CommerceCartAvailableShippingMethods returns the shipping methods available for the delivery of the current cart
+func (r *CommerceCartShippingResolver) CommerceCartAvailableShippingMethods(ctx context.Context, deliveryCode string) ([]cart.ShippingMethod, error) {
+	return r.shippingService.AvailableShippingMethods(ctx, web.SessionFromContext(ctx), deliveryCode)
+}
@@ -2,6 +2,7 @@ package cart
 
 import (
 	"flamingo.me/dingo"
+	"flamingo.me/flamingo/v3/core/healthcheck/domain/healthcheck"
 	"flamingo.me/flamingo/v3/framework/flamingo"
 	"flamingo.me/flamingo/v3/framework/web"
 	"flamingo.me/form"
@@ -12,6 +13,8 @@ import (
 	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
 	"flamingo.me/flamingo-commerce/v3/cart/domain/events"
 	"flamingo.me/flamingo-commerce/v3/cart/domain/placeorder"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/quote"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/validation"
 	"flamingo.me/flamingo-commerce/v3/cart/infrastructure"
 	placeorderAdapter "flamingo.me/flamingo-commerce/v3/cart/infrastructure/placeorder"
 	"flamingo.me/flamingo-commerce/v3/cart/interfaces/controller"
@@ -27,9 +30,20 @@ type (
 	Module struct {
 		routerRegistry                *web.RouterRegistry
 		enableDefaultCartAdapter      bool
+		defaultCartAdapterStorage     string
+		giftCardRegistry              string
+		enableShippingRates           bool
+		enableAbandonedCartDetection  bool
+		enableCartExpiry              bool
 		enablePlaceOrderLoggerAdapter bool
 		enableCartCache               bool
+		cartCacheBackend              string
+		sharedCartCacheStorage        string
 		cartMergeStrategy             string
+		enableCartHistory             bool
+		cartHistoryStorage            string
+		enableCartNotifications       bool
+		cartNotificationsPubSub       string
 	}
 )
 
@@ -38,30 +52,96 @@ func (m *Module) Inject(
 	routerRegistry *web.RouterRegistry,
 	config *struct {
 		EnableDefaultCartAdapter      bool   `inject:"config:commerce.cart.defaultCartAdapter.enabled,optional"`
+		DefaultCartAdapterStorage     string `inject:"config:commerce.cart.defaultCartAdapter.storage,optional"`
+		GiftCardRegistry              string `inject:"config:commerce.cart.defaultCartAdapter.giftCards.registry,optional"`
+		EnableShippingRates           bool   `inject:"config:commerce.cart.defaultCartAdapter.shipping.enabled,optional"`
+		EnableAbandonedCartDetection  bool   `inject:"config:commerce.cart.defaultCartAdapter.abandonedCarts.enabled,optional"`
+		EnableCartExpiry              bool   `inject:"config:commerce.cart.defaultCartAdapter.expiry.enabled,optional"`
 		EnableCartCache               bool   `inject:"config:commerce.cart.enableCartCache,optional"`
+		CartCacheBackend              string `inject:"config:commerce.cart.cacheBackend,optional"`
+		SharedCartCacheStorage        string `inject:"config:commerce.cart.sharedCache.storage,optional"`
 		CartMergeStrategy             string `inject:"config:commerce.cart.mergeStrategy,optional"`
 		EnablePlaceOrderLoggerAdapter bool   `inject:"config:commerce.cart.placeOrderLogger.enabled,optional"`
+		EnableCartHistory             bool   `inject:"config:commerce.cart.history.enabled,optional"`
+		CartHistoryStorage            string `inject:"config:commerce.cart.history.storage,optional"`
+		EnableCartNotifications       bool   `inject:"config:commerce.cart.notifications.enabled,optional"`
+		CartNotificationsPubSub       string `inject:"config:commerce.cart.notifications.pubSub,optional"`
 	},
 ) {
 	m.routerRegistry = routerRegistry
 	if config != nil {
 		m.enableDefaultCartAdapter = config.EnableDefaultCartAdapter
+		m.defaultCartAdapterStorage = config.DefaultCartAdapterStorage
+		m.giftCardRegistry = config.GiftCardRegistry
+		m.enableShippingRates = config.EnableShippingRates
+		m.enableAbandonedCartDetection = config.EnableAbandonedCartDetection
+		m.enableCartExpiry = config.EnableCartExpiry
 		m.enableCartCache = config.EnableCartCache
+		m.cartCacheBackend = config.CartCacheBackend
+		m.sharedCartCacheStorage = config.SharedCartCacheStorage
 		m.cartMergeStrategy = config.CartMergeStrategy
 		m.enablePlaceOrderLoggerAdapter = config.EnablePlaceOrderLoggerAdapter
+		m.enableCartHistory = config.EnableCartHistory
+		m.cartHistoryStorage = config.CartHistoryStorage
+		m.enableCartNotifications = config.EnableCartNotifications
+		m.cartNotificationsPubSub = config.CartNotificationsPubSub
 	}
 }
 
 // Configure module
 func (m *Module) Configure(injector *dingo.Injector) {
 	if m.enableDefaultCartAdapter {
-		injector.Bind((*infrastructure.CartStorage)(nil)).To(infrastructure.InMemoryCartStorage{}).AsEagerSingleton()
-		injector.Bind((*infrastructure.GiftCardHandler)(nil)).To(infrastructure.DefaultGiftCardHandler{})
-		injector.Bind((*infrastructure.VoucherHandler)(nil)).To(infrastructure.DefaultVoucherHandler{})
+		switch m.defaultCartAdapterStorage {
+		case "redis":
+			injector.Bind(new(infrastructure.RedisCartStorage)).In(dingo.Singleton)
+			injector.Bind((*infrastructure.CartStorage)(nil)).To(new(infrastructure.RedisCartStorage))
+			injector.BindMap(new(healthcheck.Status), "cart.storage.redis").To(new(infrastructure.RedisCartStorage))
+		case "sql":
+			injector.Bind(new(infrastructure.SQLCartStorage)).In(dingo.Singleton)
+			injector.Bind((*infrastructure.CartStorage)(nil)).To(new(infrastructure.SQLCartStorage))
+			injector.BindMap(new(healthcheck.Status), "cart.storage.sql").To(new(infrastructure.SQLCartStorage))
+		default:
+			injector.Bind((*infrastructure.CartStorage)(nil)).To(infrastructure.InMemoryCartStorage{}).AsEagerSingleton()
+		}
+		// singleton, the registries keep the gift card balances
+		switch m.giftCardRegistry {
+		case "file":
+			injector.Bind((*cart.GiftCardRegistry)(nil)).To(infrastructure.FileGiftCardRegistry{}).In(dingo.Singleton)
+		default:
+			injector.Bind((*cart.GiftCardRegistry)(nil)).To(infrastructure.InMemoryGiftCardRegistry{}).In(dingo.Singleton)
+		}
+		injector.Bind((*infrastructure.GiftCardHandler)(nil)).To(infrastructure.RegistryGiftCardHandler{})
+		injector.Bind((*infrastructure.VoucherHandler)(nil)).To(infrastructure.PromotionEngine{})
+		injector.Bind((*infrastructure.TaxCalculator)(nil)).To(infrastructure.DefaultTaxCalculator{})
+
+		if m.enableShippingRates {
+			injector.Bind((*cart.ShippingRateProvider)(nil)).To(infrastructure.DefaultShippingRateProvider{})
+		}
+
 		injector.Bind((*cart.GuestCartService)(nil)).To(infrastructure.DefaultGuestCartService{})
 		injector.Bind((*cart.CustomerCartService)(nil)).To(infrastructure.DefaultCustomerCartService{})
+
+		if m.enableAbandonedCartDetection {
+			// singleton, the detector runs in the background and remembers the already notified carts
+			injector.Bind(new(infrastructure.AbandonedCartDetector)).In(dingo.Singleton)
+			flamingo.BindEventSubscriber(injector).To(new(infrastructure.AbandonedCartDetector))
+		}
+
+		if m.enableCartExpiry {
+			// singleton, the expiry sweeps the storage in the background
+			injector.Bind(new(infrastructure.CartExpiry)).In(dingo.Singleton)
+			injector.Bind((*infrastructure.CartExpirer)(nil)).To(new(infrastructure.CartExpiry))
+			flamingo.BindEventSubscriber(injector).To(new(infrastructure.CartExpiry))
+		}
+
+		// singleton, the storage keeps the quotes
+		injector.Bind((*quote.Storage)(nil)).To(infrastructure.InMemoryQuoteStorage{}).In(dingo.Singleton)
+		injector.Bind((*quote.CartAdapter)(nil)).To(infrastructure.DefaultQuoteCartAdapter{})
 	}
 
+	injector.Bind((*quote.SalesRepResolver)(nil)).To(infrastructure.ConfigSalesRepResolver{})
+	flamingo.BindEventSubscriber(injector).To(application.QuoteService{})
+
 	if m.enablePlaceOrderLoggerAdapter {
 		injector.Bind((*placeorder.Service)(nil)).To(placeorderAdapter.PlaceOrderLoggerAdapter{})
 	}
@@ -90,9 +170,58 @@ func (m *Module) Configure(injector *dingo.Injector) {
 	flamingo.BindTemplateFunc(injector, "removeQuantityAdjustmentMessages", new(templatefunctions.RemoveQuantityAdjustmentMessages))
 
 	injector.Bind((*cart.DeliveryInfoBuilder)(nil)).To(cart.DefaultDeliveryInfoBuilder{})
+	injector.Bind((*cart.LineItemStrategy)(nil)).To(cart.DefaultLineItemStrategy{})
+
+	// built-in cart validators, they don't validate anything unless configured via commerce.cart.validation
+	injector.BindMulti((*validation.Validator)(nil)).To(infrastructure.OrderValueValidator{})
+	injector.BindMulti((*validation.Validator)(nil)).To(infrastructure.MaxDistinctItemsValidator{})
+	injector.BindMulti((*validation.Validator)(nil)).To(infrastructure.ProductCombinationValidator{})
+	injector.BindMulti((*validation.Validator)(nil)).To(infrastructure.BillingAddressValidator{})
 
 	if m.enableCartCache {
-		injector.Bind((*application.CartCache)(nil)).To(application.CartSessionCache{})
+		switch m.cartCacheBackend {
+		case "shared":
+			injector.Bind((*application.CartCache)(nil)).To(application.SharedCartCache{})
+			injector.Bind((*application.CartCacheInvalidator)(nil)).To(application.SharedCartCache{})
+
+			if m.sharedCartCacheStorage == "redis" {
+				// singleton, the store keeps the local entries and the connection pool
+				injector.Bind(new(infrastructure.RedisCartCacheStore)).In(dingo.Singleton)
+				injector.Bind((*application.CartCacheStore)(nil)).To(new(infrastructure.RedisCartCacheStore))
+				injector.BindMap(new(healthcheck.Status), "cart.cache.redis").To(new(infrastructure.RedisCartCacheStore))
+			} else {
+				// singleton, the store keeps the cached carts
+				injector.Bind((*application.CartCacheStore)(nil)).To(infrastructure.LRUCartCacheStore{}).In(dingo.Singleton)
+			}
+		default:
+			injector.Bind((*application.CartCache)(nil)).To(application.CartSessionCache{})
+		}
+	}
+
+	if m.enableCartHistory {
+		switch m.cartHistoryStorage {
+		case "redis":
+			injector.Bind(new(infrastructure.RedisHistoryStorage)).In(dingo.Singleton)
+			injector.Bind((*cart.HistoryStorage)(nil)).To(new(infrastructure.RedisHistoryStorage))
+			injector.BindMap(new(healthcheck.Status), "cart.history.redis").To(new(infrastructure.RedisHistoryStorage))
+		default:
+			// singleton, the storage keeps the history entries
+			injector.Bind((*cart.HistoryStorage)(nil)).To(infrastructure.InMemoryHistoryStorage{}).In(dingo.Singleton)
+		}
+	}
+
+	if m.enableCartNotifications {
+		switch m.cartNotificationsPubSub {
+		case "redis":
+			// singleton, the notifier keeps the subscribers and the connection listening to the redis channel
+			injector.Bind(new(infrastructure.RedisChangeNotifier)).In(dingo.Singleton)
+			injector.Bind((*cart.ChangeNotifier)(nil)).To(new(infrastructure.RedisChangeNotifier))
+			injector.BindMap(new(healthcheck.Status), "cart.notifications.redis").To(new(infrastructure.RedisChangeNotifier))
+			flamingo.BindEventSubscriber(injector).To(new(infrastructure.RedisChangeNotifier))
+		default:
+			// singleton, the notifier keeps the subscribers
+			injector.Bind((*cart.ChangeNotifier)(nil)).To(infrastructure.InMemoryChangeNotifier{}).In(dingo.Singleton)
+		}
 	}
 
 	// Register Form Data Provider
@@ -113,12 +242,101 @@ func (*Module) CueConfig() string {
 	return `
 commerce: {
 	cart: {
+		// connection settings shared by all redis backends of the cart module, hidden so that it is not part of the config
+		_redis: {
+			maxIdle:                 number | *25
+			idleTimeoutMilliseconds: number | *240000
+			network:                 string | *"tcp"
+			address:                 string | *"localhost:6379"
+			database:                number | *0
+			username?:               string & != ""
+			password?:               string & != ""
+			useTLS?:                 bool
+		}
 		defaultCartAdapter: {
 			enabled: bool | *true
-			storage: "inmemory"
+			storage: *"inmemory" | "redis" | "sql"
+			if storage == "redis" {
+				redis: _redis & {
+					ttl:       string | *"720h"
+					keyPrefix: string | *"cart:"
+				}
+			}
+			if storage == "sql" {
+				sql: {
+					// the driver must be imported by the project, only SQLite and MySQL/MariaDB are supported
+					driver: string
+					dsn:    string
+					table:  string | *"carts"
+					ttl:    string | *"720h"
+				}
+			}
+			abandonedCarts: {
+				enabled:        bool | *false
+				interval:       string | *"10m"
+				abandonedAfter: string | *"24h"
+			}
+			expiry: {
+				enabled:              bool | *false
+				guestCartLifetime:    string | *"720h"
+				customerCartLifetime: string | *"0s"
+				sweepInterval:        string | *"1h"
+			}
 			defaultTaxRate?: number
 			productPrices: *"gross" | "net"
 			defaultCurrency: string | *"EUR"
+			shipping: {
+				enabled:         bool | *false
+				weightAttribute: string | *"weight"
+				methods: [...{
+					code:                   string
+					carrier:                string | *""
+					title:                  string | *""
+					price:                  number | *0
+					countries:              [...string] | *[]
+					minWeight:              number | *0
+					maxWeight:              number | *0
+					minCartValue:           number | *0
+					maxCartValue:           number | *0
+					excludedItemAttributes: [...string] | *[]
+				}] | *[]
+			}
+			taxes: {
+				rounding:         *"row" | "total"
+				shippingTaxClass: string | *""
+				rules: [...{
+					taxClass: string | *""
+					country:  string | *""
+					region:   string | *""
+					type:     string | *"default"
+					rate:     number
+				}] | *[]
+			}
+			promotions: {
+				rules: [...{
+					code:             string
+					label:            string | *""
+					type:             "percentageItemDiscount" | "fixedItemDiscount" | "buyXPayY" | "freeShipping" | "cartThreshold"
+					couponCode:       string | *""
+					sortOrder:        number | *0
+					marketplaceCodes: [...string] | *[]
+					percentage:       number | *0
+					amount:           number | *0
+					buy:              number | *0
+					pay:              number | *0
+					threshold:        number | *0
+				}] | *[]
+			}
+			giftCards: {
+				registry: *"inmemory" | "file"
+				file:     string | *"giftcards.json"
+				cards: [...{
+					code:       string
+					balance:    number
+					currency:   string | *"EUR"
+					validUntil: string | *""
+				}] | *[]
+			}
 		}
 		placeOrderLogger: {
 			enabled: bool | *true
@@ -128,6 +346,17 @@ commerce: {
 		}
 		enableCartCache: bool | *true
 		cacheLifetime: number | *1200
+		cacheBackend: *"session" | "shared"
+		sharedCache: {
+			storage: *"inmemory" | "redis"
+			size:    number | *10000
+			if storage == "redis" {
+				redis: _redis & {
+					localLifetime: string | *"1s"
+					keyPrefix:     string | *"cartCache:"
+				}
+			}
+		}
 		defaultUseBillingAddress: bool | *false
 		defaultDeliveryCode: string | *"delivery"
 		deleteEmptyDelivery: bool | *false
@@ -144,6 +373,63 @@ commerce: {
 		simplePaymentForm: {
 			giftCardPaymentMethod: string | *"voucher"
 		}
+		share: {
+			secret:        string | *""
+			tokenLifetime: string | *"168h"
+		}
+		itemChangeNotices: {
+			compareNetPrice: bool | *false
+		}
+		lineItems: {
+			distinguishingAdditionalDataKeys: [...string] | *[]
+			unmergeableAdditionalDataKeys:    [...string] | *[]
+			singleUnitAdditionalDataKeys:     [...string] | *[]
+		}
+		bulkAdd: {
+			// limits of the CSV upload, 0 disables a limit
+			maxItems:    number | *1000
+			maxFileSize: number | *1048576
+		}
+		history: {
+			enabled:    bool | *false
+			storage:    *"inmemory" | "redis"
+			maxEntries: number | *100
+			if storage == "redis" {
+				redis: _redis & {
+					ttl:       string | *"720h"
+					keyPrefix: string | *"cartHistory:"
+				}
+			}
+		}
+		postProcessors: {
+			maxPasses: number | *5
+		}
+		notifications: {
+			enabled:           bool | *false
+			pubSub:            *"inmemory" | "redis"
+			bufferSize:        number | *10
+			heartbeatInterval: string | *"30s"
+			if pubSub == "redis" {
+				redis: _redis & {
+					channel: string | *"cartChanges"
+				}
+			}
+		}
+		quotes: {
+			lifetime:  string | *"720h"
+			salesReps: [...string] | *[]
+		}
+		validation: {
+			orderValue: {
+				[string]: {
+					min: number | *0
+					max: number | *0
+				}
+			}
+			maxDistinctItems:                        number | *0
+			disallowedCombinations:                  [...[...string]] | *[]
+			billingAddressRequiredForPaymentMethods: [...string] | *[]
+		}
 	}
 }`
 }
@@ -167,13 +453,21 @@ func (m *Module) Depends() []dingo.Module {
 }
 
 type routes struct {
-	viewController *controller.CartViewController
-	apiController  *controller.CartAPIController
+	viewController    *controller.CartViewController
+	apiController     *controller.CartAPIController
+	shareController   *controller.CartShareController
+	historyController *controller.CartHistoryController
+	eventsController  *controller.CartEventsController
+	quoteController   *controller.CartQuoteController
 }
 
-func (r *routes) Inject(viewController *controller.CartViewController, apiController *controller.CartAPIController) {
+func (r *routes) Inject(viewController *controller.CartViewController, apiController *controller.CartAPIController, shareController *controller.CartShareController, historyController *controller.CartHistoryController, eventsController *controller.CartEventsController, quoteController *controller.CartQuoteController) {
 	r.viewController = viewController
 	r.apiController = apiController
+	r.shareController = shareController
+	r.historyController = historyController
+	r.eventsController = eventsController
+	r.quoteController = quoteController
 }
 
 func (r *routes) Routes(registry *web.RouterRegistry) {
@@ -200,6 +494,9 @@ func (r *routes) Routes(registry *web.RouterRegistry) {
 
 	registry.HandleAny("cart.deleteItem", r.viewController.DeleteAndViewAction)
 	registry.MustRoute("/cart/delete/:id", `cart.deleteItem(id,deliveryCode?="")`)
+
+	registry.HandleAny("cart.import", r.shareController.ImportAndViewAction)
+	registry.MustRoute("/cart/import/:token", "cart.import(token)")
 	r.apiRoutes(registry)
 }
 
@@ -227,6 +524,9 @@ func (r *routes) apiRoutes(registry *web.RouterRegistry) {
 	registry.HandleDelete("cart.api.item", r.apiController.DeleteItemAction)
 	registry.HandlePut("cart.api.item", r.apiController.UpdateItemAction)
 
+	registry.MustRoute("/api/v1/cart/items/bulk", `cart.api.items.bulk(deliveryCode?="")`)
+	registry.HandlePost("cart.api.items.bulk", r.apiController.BulkAddAction)
+
 	registry.MustRoute("/api/v1/cart/voucher", `cart.api.voucher(couponCode)`)
 	registry.HandlePost("cart.api.voucher", r.apiController.ApplyVoucherAndGetAction)
 	registry.HandleDelete("cart.api.voucher", r.apiController.RemoveVoucherAndGetAction)
@@ -238,6 +538,34 @@ func (r *routes) apiRoutes(registry *web.RouterRegistry) {
 	registry.MustRoute("/api/v1/cart/voucher-gift-card", `cart.api.voucher-gift-card(couponCode)`)
 	registry.HandlePost("cart.api.voucher-gift-card", r.apiController.ApplyCombinedVoucherGift)
 
+	registry.MustRoute("/api/v1/cart/share", "cart.api.share")
+	registry.HandlePost("cart.api.share", r.shareController.ShareAction)
+
+	registry.MustRoute("/api/v1/cart/import/:token", "cart.api.import(token)")
+	registry.HandlePost("cart.api.import", r.shareController.ImportAction)
+
+	registry.MustRoute("/api/v1/cart/history", "cart.api.history")
+	registry.HandleGet("cart.api.history", r.historyController.HistoryAction)
+
+	registry.MustRoute("/api/v1/cart/events", "cart.api.events")
+	registry.HandleGet("cart.api.events", r.eventsController.EventsAction)
+
+	registry.MustRoute("/api/v1/cart/quotes", "cart.api.quotes")
+	registry.HandleGet("cart.api.quotes", r.quoteController.ListAction)
+	registry.HandlePost("cart.api.quotes", r.quoteController.CreateAction)
+
+	registry.MustRoute("/api/v1/cart/quotes/:id", "cart.api.quote(id)")
+	registry.HandleGet("cart.api.quote", r.quoteController.GetAction)
+
+	registry.MustRoute("/api/v1/cart/quotes/:id/items/:itemID/price", `cart.api.quote.price(id,itemID,price,currency?="")`)
+	registry.HandlePut("cart.api.quote.price", r.quoteController.UpdateItemPriceAction)
+
+	registry.MustRoute("/api/v1/cart/quotes/:id/send", "cart.api.quote.send(id)")
+	registry.HandlePost("cart.api.quote.send", r.quoteController.SendAction)
+
+	registry.MustRoute("/api/v1/cart/quotes/:id/accept", "cart.api.quote.accept(id)")
+	registry.HandlePost("cart.api.quote.accept", r.quoteController.AcceptAction)
+
 	// Legacy Routes:
 	registry.MustRoute("/api/cart", "cart.api.get")
 	registry.HandleDelete("cart.api.get", r.apiController.DeleteAllItemsAction)
@@ -15,3 +15,21 @@ func TestModule_Configure(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestModule_ConfigureRedis(t *testing.T) {
+	if err := config.TryModules(config.Map{
+		"core.auth.web.debugController":              false,
+		"commerce.cart.defaultCartAdapter.storage":   "redis",
+		"commerce.cart.cacheBackend":                 "shared",
+		"commerce.cart.sharedCache.storage":          "redis",
+		"commerce.cart.history.enabled":              true,
+		"commerce.cart.history.storage":              "redis",
+		"commerce.cart.notifications.enabled":        true,
+		"commerce.cart.notifications.pubSub":         "redis",
+		"commerce.cart.notifications.redis.address":  "redis:6379",
+		"commerce.cart.sharedCache.redis.useTLS":     true,
+		"commerce.cart.defaultCartAdapter.redis.ttl": "1h",
+	}, new(cart.Module)); err != nil {
+		t.Error(err)
+	}
+}
@@ -231,12 +231,12 @@ func (os *OrderService) CancelOrder(ctx context.Context, session *web.Session, o
 	return os.cartService.CancelOrder(ctx, session, order.PlacedOrders, order.Cart)
 }
 
-// CancelOrderWithoutRestore cancels an previously placed order
+// CancelOrderWithoutRestore cancels an previously placed order and releases the reservations of the completed cart
 func (os *OrderService) CancelOrderWithoutRestore(ctx context.Context, session *web.Session, order *PlaceOrderInfo) error {
 	ctx, span := trace.StartSpan(ctx, "checkout/OrderService/CancelOrderWithoutRestore")
 	defer span.End()
 
-	return os.cartService.CancelOrderWithoutRestore(ctx, session, order.PlacedOrders)
+	return os.cartService.CancelOrderAndReleaseCart(ctx, session, order.PlacedOrders, order.Cart)
 }
 
 // CurrentCartPlaceOrderWithPaymentProcessing places the current cart which is fetched from the context
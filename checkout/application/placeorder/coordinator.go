@@ -5,6 +5,7 @@ import (
 	"encoding/gob"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"time"
 
@@ -37,6 +38,12 @@ type (
 	// Unlock function to release the previously acquired lock, should be called within defer
 	Unlock func() error
 
+	// sessionStorage is the part of the web.SessionStore used to load and save the session of a process
+	sessionStorage interface {
+		LoadByID(ctx context.Context, id string) (*web.Session, error)
+		Save(ctx context.Context, session *web.Session) (http.Header, error)
+	}
+
 	// Coordinator ensures that certain parts of the place order process are only done once at a time
 	Coordinator struct {
 		locker         TryLocker
@@ -44,7 +51,7 @@ type (
 		cartService    *application.CartService
 		processFactory *process.Factory
 		contextStore   process.ContextStore
-		sessionStore   *web.SessionStore
+		sessionStore   sessionStorage
 		sessionName    string
 		area           string
 	}
@@ -352,8 +359,11 @@ func (c *Coordinator) proceedInStateMachineUntilNoStateChange(ctx context.Contex
 
 	stateBeforeRun := p.Context().CurrentStateName
 	for i := 0; i < maxRunCount; i++ {
+		// a timed out state is not run anymore, the process continues with the failed state
+		if !p.FailIfTimedOut(ctx) {
+			p.Run(ctx)
+		}
 
-		p.Run(ctx)
 		err := c.storeProcessContext(ctx, p.Context())
 		if err != nil {
 			return err
@@ -372,6 +382,60 @@ func (c *Coordinator) proceedInStateMachineUntilNoStateChange(ctx context.Contex
 	return nil
 }
 
+// FailTimedOut fails the last process if its current state timed out and reports if it failed,
+// it is a NOP if the process is locked because the running process checks the timeout itself
+func (c *Coordinator) FailTimedOut(ctx context.Context) (bool, error) {
+	ctx, span := trace.StartSpan(ctx, "checkout/Coordinator/FailTimedOut")
+	defer span.End()
+
+	var failed bool
+	var returnErr error
+	web.RunWithDetachedContext(ctx, func(ctx context.Context) {
+		p, err := c.LastProcess(ctx)
+		if err != nil {
+			returnErr = err
+			return
+		}
+
+		if !p.IsTimedOut() {
+			return
+		}
+
+		unlock, err := c.locker.TryLock(ctx, determineLockKeyForProcess(p), maxLockDuration)
+		if err == ErrLockTaken {
+			return
+		}
+		if err != nil {
+			returnErr = err
+			return
+		}
+		defer func() {
+			_ = unlock()
+		}()
+
+		// lock acquired get fresh process state
+		p, err = c.LastProcess(ctx)
+		if err != nil {
+			returnErr = err
+			return
+		}
+
+		if !p.IsTimedOut() {
+			return
+		}
+
+		err = c.proceedInStateMachineUntilNoStateChange(ctx, p)
+		if err != nil {
+			returnErr = err
+			return
+		}
+
+		failed = true
+	})
+
+	return failed, returnErr
+}
+
 // RunBlocking waits for the lock and starts the next processing
 // RunBlocking waits until the process is finished and returns its result
 func (c *Coordinator) RunBlocking(ctx context.Context) (*process.Context, error) {
@@ -0,0 +1,132 @@
+package placeorder
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opencensus.io/trace"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+
+	"flamingo.me/flamingo-commerce/v3/checkout/domain/placeorder/process"
+	"flamingo.me/flamingo-commerce/v3/internal/periodic"
+)
+
+type (
+	// TimeoutSweeper periodically fails place order processes whose current state timed out and which nobody polls anymore,
+	// the rollbacks of the failed processes (e.g. payment cancel, cart restore) run with the session of the process.
+	// The checkout module binds it as singleton, every further instance would run its own sweep.
+	TimeoutSweeper struct {
+		coordinator    *Coordinator
+		contextStore   process.ContextStore
+		processFactory *process.Factory
+		sessionStore   sessionStorage
+		logger         flamingo.Logger
+		job            *periodic.Job
+	}
+)
+
+var _ flamingo.Subscriber = &TimeoutSweeper{}
+
+// Inject dependencies
+func (s *TimeoutSweeper) Inject(
+	coordinator *Coordinator,
+	contextStore process.ContextStore,
+	processFactory *process.Factory,
+	sessionStore *web.SessionStore,
+	logger flamingo.Logger,
+	cfg *struct {
+		SweepInterval string     `inject:"config:commerce.checkout.placeorder.timeouts.sweepInterval"`
+		StateTimeouts config.Map `inject:"config:commerce.checkout.placeorder.timeouts.states,optional"`
+	},
+) *TimeoutSweeper {
+	s.coordinator = coordinator
+	s.contextStore = contextStore
+	s.processFactory = processFactory
+	s.sessionStore = sessionStore
+	s.logger = logger.WithField(flamingo.LogKeyModule, "checkout").WithField(flamingo.LogKeyCategory, "TimeoutSweeper")
+
+	var sweepInterval time.Duration
+	if cfg != nil {
+		var err error
+		sweepInterval, err = time.ParseDuration(cfg.SweepInterval)
+		if err != nil {
+			panic("can't parse commerce.checkout.placeorder.timeouts.sweepInterval")
+		}
+
+		// without state timeouts nothing can time out, the sweep is not started at all
+		if len(cfg.StateTimeouts) == 0 {
+			sweepInterval = 0
+		}
+	}
+
+	s.job = periodic.NewJob(sweepInterval, func(ctx context.Context) error {
+		_, err := s.Sweep(ctx)
+		return err
+	}, s.logger)
+
+	return s
+}
+
+// Notify starts the background sweep on server start and stops it on shutdown
+func (s *TimeoutSweeper) Notify(ctx context.Context, event flamingo.Event) {
+	s.job.Notify(ctx, event)
+}
+
+// Sweep scans the context store once, fails all processes whose current state timed out and returns their number
+func (s *TimeoutSweeper) Sweep(ctx context.Context) (int, error) {
+	ctx, span := trace.StartSpan(ctx, "checkout/TimeoutSweeper/Sweep")
+	defer span.End()
+
+	store, ok := s.contextStore.(process.IterableContextStore)
+	if !ok {
+		return 0, fmt.Errorf("TimeoutSweeper: context store %T does not support iterating contexts", s.contextStore)
+	}
+
+	var timedOut []string
+
+	err := store.ForEachContext(ctx, func(key string, pctx process.Context) error {
+		p, err := s.processFactory.NewFromProcessContext(pctx)
+		if err != nil {
+			return err
+		}
+
+		if p.IsTimedOut() {
+			timedOut = append(timedOut, key)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("TimeoutSweeper: %w", err)
+	}
+
+	// FailTimedOut stores the failed context, so the processes are failed once the iteration is done
+	failed := 0
+
+	for _, sessionID := range timedOut {
+		// the contexts are stored by session, the rollbacks need the session e.g. to restore the cart
+		session, err := s.sessionStore.LoadByID(ctx, sessionID)
+		if err != nil {
+			s.logger.WithContext(ctx).Warn(fmt.Sprintf("TimeoutSweeper: session of timed out place order process not loadable: %s", err))
+
+			continue
+		}
+
+		processFailed, err := s.coordinator.FailTimedOut(web.ContextWithSession(ctx, session))
+		if err != nil {
+			s.logger.WithContext(ctx).Error(fmt.Errorf("TimeoutSweeper: %w", err))
+
+			continue
+		}
+
+		if processFailed {
+			failed++
+		}
+	}
+
+	return failed, nil
+}
@@ -0,0 +1,292 @@
+package placeorder
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+
+	cartDomain "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/checkout/domain/placeorder/process"
+	"flamingo.me/flamingo-commerce/v3/checkout/infrastructure/contextstore"
+)
+
+type (
+	sweeperTestState struct {
+		name  string
+		final bool
+	}
+
+	sweeperTestLocker struct {
+		keys   []string
+		onLock func()
+		err    error
+	}
+
+	sweeperTestSessionStore struct {
+		session *web.Session
+		saved   int
+	}
+)
+
+func (s *sweeperTestState) Run(context.Context, *process.Process) process.RunResult {
+	return process.RunResult{}
+}
+
+func (s *sweeperTestState) Rollback(context.Context, process.RollbackData) error {
+	return nil
+}
+
+func (s *sweeperTestState) IsFinal() bool {
+	return s.final
+}
+
+func (s *sweeperTestState) Name() string {
+	return s.name
+}
+
+func (l *sweeperTestLocker) TryLock(_ context.Context, key string, _ time.Duration) (Unlock, error) {
+	l.keys = append(l.keys, key)
+	if l.err != nil {
+		return nil, l.err
+	}
+
+	if l.onLock != nil {
+		l.onLock()
+	}
+
+	return func() error { return nil }, nil
+}
+
+func (s *sweeperTestSessionStore) LoadByID(_ context.Context, id string) (*web.Session, error) {
+	if s.session == nil || s.session.ID() != id {
+		return nil, errors.New("session not found")
+	}
+
+	return s.session, nil
+}
+
+func (s *sweeperTestSessionStore) Save(context.Context, *web.Session) (http.Header, error) {
+	s.saved++
+
+	return nil, nil
+}
+
+func provideTimeoutTestFactory(t *testing.T) *process.Factory {
+	t.Helper()
+
+	waiting := &sweeperTestState{name: "Waiting"}
+	failed := &sweeperTestState{name: "Failed", final: true}
+	allStates := map[string]process.State{
+		waiting.Name(): waiting,
+		failed.Name():  failed,
+	}
+
+	factory := &process.Factory{}
+	factory.Inject(
+		func() *process.Process {
+			return new(process.Process).Inject(allStates, flamingo.NullLogger{}, &struct {
+				Area          string     `inject:"config:area"`
+				StateTimeouts config.Map `inject:"config:commerce.checkout.placeorder.timeouts.states,optional"`
+			}{
+				StateTimeouts: config.Map{"Waiting": "1m"},
+			})
+		},
+		allStates,
+		&struct {
+			StartState  process.State `inject:"startState"`
+			FailedState process.State `inject:"failedState"`
+		}{
+			StartState:  waiting,
+			FailedState: failed,
+		},
+	)
+
+	return factory
+}
+
+func waitingContext(enteredAt time.Time) process.Context {
+	return process.Context{
+		UUID:             "process-1",
+		CurrentStateName: "Waiting",
+		StateEnteredAt:   enteredAt,
+		Cart:             cartDomain.Cart{ID: "cart-1"},
+	}
+}
+
+func TestTimeoutSweeper_Sweep(t *testing.T) {
+	t.Parallel()
+
+	type fixture struct {
+		sweeper      *TimeoutSweeper
+		locker       *sweeperTestLocker
+		sessionStore *sweeperTestSessionStore
+		contextStore *contextstore.Memory
+		session      *web.Session
+	}
+
+	setup := func(t *testing.T, pctx process.Context) fixture {
+		t.Helper()
+
+		session := web.EmptySession()
+		contextStore := new(contextstore.Memory).Inject()
+		require.NoError(t, contextStore.Store(context.Background(), session.ID(), pctx))
+
+		locker := &sweeperTestLocker{}
+		sessionStore := &sweeperTestSessionStore{session: session}
+		factory := provideTimeoutTestFactory(t)
+		coordinator := &Coordinator{
+			locker:         locker,
+			logger:         flamingo.NullLogger{},
+			processFactory: factory,
+			contextStore:   contextStore,
+			sessionStore:   sessionStore,
+		}
+
+		return fixture{
+			sweeper: &TimeoutSweeper{
+				coordinator:    coordinator,
+				contextStore:   contextStore,
+				processFactory: factory,
+				sessionStore:   sessionStore,
+				logger:         flamingo.NullLogger{},
+			},
+			locker:       locker,
+			sessionStore: sessionStore,
+			contextStore: contextStore,
+			session:      session,
+		}
+	}
+
+	t.Run("timed out process is failed", func(t *testing.T) {
+		t.Parallel()
+
+		f := setup(t, waitingContext(time.Now().Add(-2*time.Minute)))
+
+		failed, err := f.sweeper.Sweep(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, failed)
+		assert.Equal(t, []string{"checkout_placeorder_lock_cart-1"}, f.locker.keys)
+
+		pctx, found := f.contextStore.Get(context.Background(), f.session.ID())
+		require.True(t, found)
+		assert.Equal(t, "Failed", pctx.CurrentStateName)
+		assert.Equal(t, process.TimeoutReason{StateName: "Waiting", Timeout: time.Minute}, pctx.FailedReason)
+		assert.Positive(t, f.sessionStore.saved, "session of the failed process not saved")
+	})
+
+	t.Run("process within its timeout is not touched", func(t *testing.T) {
+		t.Parallel()
+
+		f := setup(t, waitingContext(time.Now()))
+
+		failed, err := f.sweeper.Sweep(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, failed)
+		assert.Empty(t, f.locker.keys)
+
+		pctx, found := f.contextStore.Get(context.Background(), f.session.ID())
+		require.True(t, found)
+		assert.Equal(t, "Waiting", pctx.CurrentStateName)
+	})
+
+	t.Run("locked process is left to the running process", func(t *testing.T) {
+		t.Parallel()
+
+		f := setup(t, waitingContext(time.Now().Add(-2*time.Minute)))
+		f.locker.err = ErrLockTaken
+
+		failed, err := f.sweeper.Sweep(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, failed)
+		assert.Len(t, f.locker.keys, 1)
+
+		pctx, found := f.contextStore.Get(context.Background(), f.session.ID())
+		require.True(t, found)
+		assert.Equal(t, "Waiting", pctx.CurrentStateName)
+		assert.Nil(t, pctx.FailedReason)
+		assert.Zero(t, f.sessionStore.saved)
+	})
+
+	t.Run("process no longer timed out after the lock is not failed", func(t *testing.T) {
+		t.Parallel()
+
+		f := setup(t, waitingContext(time.Now().Add(-2*time.Minute)))
+		// the process proceeded while the sweep waited for the lock
+		f.locker.onLock = func() {
+			require.NoError(t, f.contextStore.Store(context.Background(), f.session.ID(), waitingContext(time.Now())))
+		}
+
+		failed, err := f.sweeper.Sweep(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, failed)
+		assert.Len(t, f.locker.keys, 1)
+
+		pctx, found := f.contextStore.Get(context.Background(), f.session.ID())
+		require.True(t, found)
+		assert.Equal(t, "Waiting", pctx.CurrentStateName)
+		assert.Nil(t, pctx.FailedReason)
+		assert.Zero(t, f.sessionStore.saved)
+	})
+
+	t.Run("process without loadable session is skipped", func(t *testing.T) {
+		t.Parallel()
+
+		f := setup(t, waitingContext(time.Now().Add(-2*time.Minute)))
+		f.sessionStore.session = nil
+
+		failed, err := f.sweeper.Sweep(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, failed)
+		assert.Empty(t, f.locker.keys)
+	})
+}
+
+func TestCoordinator_FailTimedOut(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no process", func(t *testing.T) {
+		t.Parallel()
+
+		coordinator := &Coordinator{
+			locker:         &sweeperTestLocker{},
+			logger:         flamingo.NullLogger{},
+			processFactory: provideTimeoutTestFactory(t),
+			contextStore:   new(contextstore.Memory).Inject(),
+			sessionStore:   &sweeperTestSessionStore{},
+		}
+
+		failed, err := coordinator.FailTimedOut(web.ContextWithSession(context.Background(), web.EmptySession()))
+		assert.ErrorIs(t, err, ErrNoPlaceOrderProcess)
+		assert.False(t, failed)
+	})
+
+	t.Run("lock error is returned", func(t *testing.T) {
+		t.Parallel()
+
+		session := web.EmptySession()
+		contextStore := new(contextstore.Memory).Inject()
+		require.NoError(t, contextStore.Store(context.Background(), session.ID(), waitingContext(time.Now().Add(-2*time.Minute))))
+
+		lockErr := errors.New("lock backend not available")
+		coordinator := &Coordinator{
+			locker:         &sweeperTestLocker{err: lockErr},
+			logger:         flamingo.NullLogger{},
+			processFactory: provideTimeoutTestFactory(t),
+			contextStore:   contextStore,
+			sessionStore:   &sweeperTestSessionStore{session: session},
+		}
+
+		failed, err := coordinator.FailTimedOut(web.ContextWithSession(context.Background(), session))
+		assert.ErrorIs(t, err, lockErr)
+		assert.False(t, failed)
+	})
+}
@@ -28,6 +28,7 @@ func provideProcessFactory(t *testing.T) *process.Factory {
 		func() *process.Process {
 			return &process.Process{}
 		},
+		nil,
 		&struct {
 			StartState  process.State `inject:"startState"`
 			FailedState process.State `inject:"failedState"`
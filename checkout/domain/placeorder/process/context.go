@@ -3,6 +3,7 @@ package process
 import (
 	"context"
 	"net/url"
+	"time"
 
 	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
 	"flamingo.me/flamingo-commerce/v3/checkout/application"
@@ -11,9 +12,11 @@ import (
 type (
 	// Context contains information (state etc) about a place order process
 	Context struct {
-		UUID               string
-		CurrentStateName   string
-		CurrentStateData   StateData
+		UUID             string
+		CurrentStateName string
+		CurrentStateData StateData
+		// StateEnteredAt is the time the process changed to the current state, used to detect state timeouts
+		StateEnteredAt     time.Time
 		PlaceOrderInfo     *application.PlaceOrderInfo
 		Cart               cart.Cart
 		ReturnURL          *url.URL
@@ -29,4 +32,11 @@ type (
 		Get(ctx context.Context, key string) (Context, bool)
 		Delete(ctx context.Context, key string) error
 	}
+
+	// IterableContextStore is a ContextStore which can iterate all stored contexts, e.g. to fail timed out processes nobody polls anymore
+	IterableContextStore interface {
+		ContextStore
+		// ForEachContext calls fn for every stored context with its key until fn returns an error
+		ForEachContext(ctx context.Context, fn func(key string, placeOrderContext Context) error) error
+	}
 )
@@ -0,0 +1,257 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type (
+	// NextStatesDeclarer is implemented by states which declare the states they can change to with Process.UpdateState,
+	// the change to the failed state on a failed run is always possible and not declared
+	NextStatesDeclarer interface {
+		NextStates() []string
+	}
+
+	// Graph of the place order states and their declared transitions
+	Graph struct {
+		StartState  string
+		FailedState string
+		// States sorted by name
+		States []GraphState
+	}
+
+	// GraphState is a node of the state graph
+	GraphState struct {
+		Name  string
+		Final bool
+		// Declared is false if the state does not implement NextStatesDeclarer, its transitions are unknown
+		Declared   bool
+		NextStates []string
+	}
+)
+
+// NewGraph creates the graph of the given states, the start and failed state may be nil if they are not bound
+func NewGraph(allStates map[string]State, startState State, failedState State) Graph {
+	graph := Graph{}
+	if startState != nil {
+		graph.StartState = startState.Name()
+	}
+
+	if failedState != nil {
+		graph.FailedState = failedState.Name()
+	}
+
+	for name, state := range allStates {
+		graphState := GraphState{Name: name, Final: state.IsFinal()}
+		if declarer, ok := state.(NextStatesDeclarer); ok {
+			graphState.Declared = true
+			graphState.NextStates = append([]string(nil), declarer.NextStates()...)
+			sort.Strings(graphState.NextStates)
+		}
+
+		graph.States = append(graph.States, graphState)
+	}
+
+	sort.Slice(graph.States, func(i, j int) bool {
+		return graph.States[i].Name < graph.States[j].Name
+	})
+
+	return graph
+}
+
+// State returns the state of the graph by name
+func (g Graph) State(name string) (GraphState, bool) {
+	for _, state := range g.States {
+		if state.Name == name {
+			return state, true
+		}
+	}
+
+	return GraphState{}, false
+}
+
+// Validate checks that the start and failed state and all declared next states are bound, that every final state is
+// reachable from the start state and that every state reachable from the start state can reach a final state without failing.
+// States which do not declare their next states are assumed to reach every state.
+func (g Graph) Validate() error {
+	var errs []error
+
+	if _, ok := g.State(g.StartState); !ok {
+		errs = append(errs, fmt.Errorf("start state %q is not bound", g.StartState))
+	}
+
+	if failed, ok := g.State(g.FailedState); !ok {
+		errs = append(errs, fmt.Errorf("failed state %q is not bound", g.FailedState))
+	} else if !failed.Final {
+		errs = append(errs, fmt.Errorf("failed state %q is not final", g.FailedState))
+	}
+
+	for _, state := range g.States {
+		for _, next := range state.NextStates {
+			if _, ok := g.State(next); !ok {
+				errs = append(errs, fmt.Errorf("state %q declares next state %q which is not bound", state.Name, next))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	reachable := g.reachable(g.StartState, true)
+	for _, state := range g.States {
+		if state.Final && !reachable[state.Name] {
+			errs = append(errs, fmt.Errorf("final state %q is not reachable from start state %q", state.Name, g.StartState))
+		}
+	}
+
+	for _, state := range g.States {
+		if !reachable[state.Name] || state.Final {
+			continue
+		}
+
+		if !g.reachesFinalState(state.Name) {
+			errs = append(errs, fmt.Errorf("state %q can't reach a final state", state.Name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// DOT renders the graph in the Graphviz DOT language, implicit changes to the failed state are dashed
+func (g Graph) DOT() string {
+	var b strings.Builder
+
+	b.WriteString("digraph placeorder {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\t\"__start\" [shape=point];\n")
+
+	for _, state := range g.States {
+		switch {
+		case state.Final:
+			fmt.Fprintf(&b, "\t%q [shape=doublecircle];\n", state.Name)
+		case !state.Declared:
+			fmt.Fprintf(&b, "\t%q [shape=box, style=dashed];\n", state.Name)
+		default:
+			fmt.Fprintf(&b, "\t%q [shape=box];\n", state.Name)
+		}
+	}
+
+	if g.StartState != "" {
+		fmt.Fprintf(&b, "\t\"__start\" -> %q;\n", g.StartState)
+	}
+
+	for _, state := range g.States {
+		for _, next := range state.NextStates {
+			fmt.Fprintf(&b, "\t%q -> %q;\n", state.Name, next)
+		}
+
+		if g.hasImplicitFailure(state) {
+			fmt.Fprintf(&b, "\t%q -> %q [style=dashed, color=gray];\n", state.Name, g.FailedState)
+		}
+	}
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// Mermaid renders the graph as Mermaid flowchart, implicit changes to the failed state are dotted
+func (g Graph) Mermaid() string {
+	var b strings.Builder
+
+	ids := make(map[string]string, len(g.States))
+	for i, state := range g.States {
+		ids[state.Name] = fmt.Sprintf("s%d", i)
+	}
+
+	b.WriteString("flowchart LR\n")
+	b.WriteString("\tstart((start))\n")
+
+	for _, state := range g.States {
+		switch {
+		case state.Final:
+			fmt.Fprintf(&b, "\t%s(((%q)))\n", ids[state.Name], state.Name)
+		case !state.Declared:
+			fmt.Fprintf(&b, "\t%s{{%q}}\n", ids[state.Name], state.Name)
+		default:
+			fmt.Fprintf(&b, "\t%s[%q]\n", ids[state.Name], state.Name)
+		}
+	}
+
+	if id, ok := ids[g.StartState]; ok {
+		fmt.Fprintf(&b, "\tstart --> %s\n", id)
+	}
+
+	for _, state := range g.States {
+		for _, next := range state.NextStates {
+			if id, ok := ids[next]; ok {
+				fmt.Fprintf(&b, "\t%s --> %s\n", ids[state.Name], id)
+			}
+		}
+
+		if g.hasImplicitFailure(state) {
+			fmt.Fprintf(&b, "\t%s -.-> %s\n", ids[state.Name], ids[g.FailedState])
+		}
+	}
+
+	return b.String()
+}
+
+func (g Graph) hasImplicitFailure(state GraphState) bool {
+	if state.Final {
+		return false
+	}
+
+	_, ok := g.State(g.FailedState)
+
+	return ok
+}
+
+// reachable returns the states reachable from the given state including itself
+func (g Graph) reachable(from string, withFailure bool) map[string]bool {
+	reachable := map[string]bool{from: true}
+	queue := []string{from}
+
+	for len(queue) > 0 {
+		state, ok := g.State(queue[0])
+		queue = queue[1:]
+
+		if !ok {
+			continue
+		}
+
+		next := state.NextStates
+		if !state.Declared && !state.Final {
+			next = nil
+			for _, candidate := range g.States {
+				next = append(next, candidate.Name)
+			}
+		}
+
+		if withFailure && g.hasImplicitFailure(state) {
+			next = append(next, g.FailedState)
+		}
+
+		for _, name := range next {
+			if !reachable[name] {
+				reachable[name] = true
+				queue = append(queue, name)
+			}
+		}
+	}
+
+	return reachable
+}
+
+func (g Graph) reachesFinalState(from string) bool {
+	for name := range g.reachable(from, false) {
+		if state, ok := g.State(name); ok && state.Final {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,55 @@
+package process
+
+import (
+	"context"
+	"fmt"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+type (
+	// GraphCheck validates the state graph on server start, an invalid graph stops the start unless allowed
+	GraphCheck struct {
+		factory      *Factory
+		logger       flamingo.Logger
+		allowInvalid bool
+	}
+)
+
+var _ flamingo.Subscriber = new(GraphCheck)
+
+// Inject dependencies
+func (g *GraphCheck) Inject(
+	factory *Factory,
+	logger flamingo.Logger,
+	cfg *struct {
+		AllowInvalid bool `inject:"config:commerce.checkout.placeorder.graph.allowInvalid,optional"`
+	},
+) *GraphCheck {
+	g.factory = factory
+	g.logger = logger.WithField(flamingo.LogKeyModule, "checkout").WithField(flamingo.LogKeyCategory, "process")
+
+	if cfg != nil {
+		g.allowInvalid = cfg.AllowInvalid
+	}
+
+	return g
+}
+
+// Notify validates the state graph on server start
+func (g *GraphCheck) Notify(ctx context.Context, event flamingo.Event) {
+	if _, ok := event.(*flamingo.ServerStartEvent); !ok {
+		return
+	}
+
+	err := g.factory.Graph().Validate()
+	if err == nil {
+		return
+	}
+
+	if !g.allowInvalid {
+		panic(fmt.Sprintf("invalid place order state graph: %s", err))
+	}
+
+	g.logger.WithContext(ctx).Error(fmt.Sprintf("invalid place order state graph: %s", err))
+}
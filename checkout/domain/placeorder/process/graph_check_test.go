@@ -0,0 +1,72 @@
+package process_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+
+	"flamingo.me/flamingo-commerce/v3/checkout/domain/placeorder/process"
+)
+
+func TestGraphCheck_Notify(t *testing.T) {
+	t.Parallel()
+
+	start := graphState{name: "Start", next: []string{"Success"}}
+	failed := graphState{name: "Failed", final: true}
+	// the custom final state is only reached by a payment validator which doesn't declare it
+	unreachable := graphState{name: "Custom", final: true}
+
+	graphCheck := func(allowInvalid bool, states ...process.State) *process.GraphCheck {
+		factory := &process.Factory{}
+		factory.Inject(nil, statesOf(states...), &struct {
+			StartState  process.State `inject:"startState"`
+			FailedState process.State `inject:"failedState"`
+		}{
+			StartState:  start,
+			FailedState: failed,
+		})
+
+		return new(process.GraphCheck).Inject(factory, flamingo.NullLogger{}, &struct {
+			AllowInvalid bool `inject:"config:commerce.checkout.placeorder.graph.allowInvalid,optional"`
+		}{AllowInvalid: allowInvalid})
+	}
+
+	t.Run("invalid graph stops the start by default", func(t *testing.T) {
+		t.Parallel()
+
+		check := graphCheck(false, start, graphState{name: "Success", final: true}, failed, unreachable)
+		assert.PanicsWithValue(t, `invalid place order state graph: final state "Custom" is not reachable from start state "Start"`, func() {
+			check.Notify(context.Background(), &flamingo.ServerStartEvent{})
+		})
+	})
+
+	t.Run("allowed invalid graph is only logged", func(t *testing.T) {
+		t.Parallel()
+
+		check := graphCheck(true, start, graphState{name: "Success", final: true}, failed, unreachable)
+		assert.NotPanics(t, func() {
+			check.Notify(context.Background(), &flamingo.ServerStartEvent{})
+		})
+	})
+
+	t.Run("valid graph doesn't stop the start", func(t *testing.T) {
+		t.Parallel()
+
+		check := graphCheck(false, start, graphState{name: "Success", final: true}, failed)
+		assert.NotPanics(t, func() {
+			check.Notify(context.Background(), &flamingo.ServerStartEvent{})
+		})
+	})
+
+	t.Run("other events are ignored", func(t *testing.T) {
+		t.Parallel()
+
+		check := graphCheck(false, start, failed, unreachable)
+		assert.NotPanics(t, func() {
+			check.Notify(context.Background(), &flamingo.ShutdownEvent{})
+		})
+	})
+}
@@ -0,0 +1,133 @@
+package process_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"flamingo.me/flamingo-commerce/v3/checkout/domain/placeorder/process"
+)
+
+type (
+	graphState struct {
+		name  string
+		final bool
+		next  []string
+	}
+
+	undeclaredState struct {
+		name string
+	}
+)
+
+func (s graphState) Run(context.Context, *process.Process) process.RunResult {
+	return process.RunResult{}
+}
+func (s graphState) Rollback(context.Context, process.RollbackData) error { return nil }
+func (s graphState) IsFinal() bool                                        { return s.final }
+func (s graphState) Name() string                                         { return s.name }
+func (s graphState) NextStates() []string                                 { return s.next }
+
+func (s undeclaredState) Run(context.Context, *process.Process) process.RunResult {
+	return process.RunResult{}
+}
+func (s undeclaredState) Rollback(context.Context, process.RollbackData) error { return nil }
+func (s undeclaredState) IsFinal() bool                                        { return false }
+func (s undeclaredState) Name() string                                         { return s.name }
+
+func statesOf(states ...process.State) map[string]process.State {
+	result := make(map[string]process.State, len(states))
+	for _, state := range states {
+		result[state.Name()] = state
+	}
+
+	return result
+}
+
+func TestGraph_Validate(t *testing.T) {
+	t.Parallel()
+
+	start := graphState{name: "Start", next: []string{"Wait"}}
+	wait := graphState{name: "Wait", next: []string{"Wait", "Success"}}
+	success := graphState{name: "Success", final: true}
+	failed := graphState{name: "Failed", final: true}
+
+	t.Run("valid graph", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, process.NewGraph(statesOf(start, wait, success, failed), start, failed).Validate())
+	})
+
+	t.Run("unbound states", func(t *testing.T) {
+		t.Parallel()
+
+		err := process.NewGraph(statesOf(start, success), start, failed).Validate()
+		assert.ErrorContains(t, err, `failed state "Failed" is not bound`)
+		assert.ErrorContains(t, err, `state "Start" declares next state "Wait" which is not bound`)
+	})
+
+	t.Run("failed state not final", func(t *testing.T) {
+		t.Parallel()
+
+		notFinal := graphState{name: "Failed"}
+		assert.ErrorContains(t, process.NewGraph(statesOf(start, wait, success, notFinal), start, notFinal).Validate(), `failed state "Failed" is not final`)
+	})
+
+	t.Run("unreachable final state", func(t *testing.T) {
+		t.Parallel()
+
+		orphan := graphState{name: "Orphan", final: true}
+		err := process.NewGraph(statesOf(start, wait, success, failed, orphan), start, failed).Validate()
+		assert.ErrorContains(t, err, `final state "Orphan" is not reachable from start state "Start"`)
+	})
+
+	t.Run("state without way to a final state", func(t *testing.T) {
+		t.Parallel()
+
+		loop := graphState{name: "Wait", next: []string{"Wait"}}
+		err := process.NewGraph(statesOf(graphState{name: "Start", next: []string{"Wait", "Success"}}, loop, success, failed), start, failed).Validate()
+		assert.EqualError(t, err, `state "Wait" can't reach a final state`)
+	})
+
+	t.Run("undeclared states may reach every state", func(t *testing.T) {
+		t.Parallel()
+
+		custom := undeclaredState{name: "Custom"}
+		assert.NoError(t, process.NewGraph(statesOf(graphState{name: "Start", next: []string{"Custom"}}, custom, success, failed), start, failed).Validate())
+	})
+}
+
+func TestGraph_Render(t *testing.T) {
+	t.Parallel()
+
+	start := graphState{name: "Start", next: []string{"Success"}}
+	failed := graphState{name: "Failed", final: true}
+	graph := process.NewGraph(statesOf(start, graphState{name: "Success", final: true}, failed, undeclaredState{name: "Custom"}), start, failed)
+
+	assert.Equal(t, `digraph placeorder {
+	rankdir=LR;
+	"__start" [shape=point];
+	"Custom" [shape=box, style=dashed];
+	"Failed" [shape=doublecircle];
+	"Start" [shape=box];
+	"Success" [shape=doublecircle];
+	"__start" -> "Start";
+	"Custom" -> "Failed" [style=dashed, color=gray];
+	"Start" -> "Success";
+	"Start" -> "Failed" [style=dashed, color=gray];
+}
+`, graph.DOT())
+
+	assert.Equal(t, `flowchart LR
+	start((start))
+	s0{{"Custom"}}
+	s1((("Failed")))
+	s2["Start"]
+	s3((("Success")))
+	start --> s2
+	s0 -.-> s1
+	s2 --> s3
+	s2 -.-> s1
+`, graph.Mermaid())
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -13,6 +14,7 @@ import (
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
 
+	"flamingo.me/flamingo/v3/framework/config"
 	"flamingo.me/flamingo/v3/framework/flamingo"
 	"flamingo.me/flamingo/v3/framework/opencensus"
 
@@ -27,16 +29,19 @@ type (
 
 	// Process representing a place order process and has a current context with infos about result and current state
 	Process struct {
-		context     Context
-		allStates   map[string]State
-		failedState State
-		logger      flamingo.Logger
-		area        string
+		context       Context
+		allStates     map[string]State
+		failedState   State
+		logger        flamingo.Logger
+		area          string
+		stateTimeouts map[string]time.Duration
+		now           func() time.Time
 	}
 
 	// Factory use to get Process instance
 	Factory struct {
 		provider    Provider
+		allStates   map[string]State
 		startState  State
 		failedState State
 	}
@@ -75,6 +80,12 @@ type (
 	CartValidationErrorReason struct {
 		ValidationResult validation.Result
 	}
+
+	// TimeoutReason is used when the process stayed longer than the configured timeout in a state
+	TimeoutReason struct {
+		StateName string
+		Timeout   time.Duration
+	}
 )
 
 var (
@@ -91,6 +102,7 @@ func init() {
 	gob.Register(PaymentCanceledByCustomerReason{})
 	gob.Register(CartValidationErrorReason{})
 	gob.Register(CanceledByCustomerReason{})
+	gob.Register(TimeoutReason{})
 
 	if err := opencensus.View("flamingo-commerce/checkout/placeorder/state_run_count", processedState, view.Count(), keyState); err != nil {
 		panic(err)
@@ -126,15 +138,22 @@ func (e CartValidationErrorReason) Reason() string {
 	return "Cart invalid"
 }
 
+// Reason for the timeout
+func (e TimeoutReason) Reason() string {
+	return fmt.Sprintf("State %q timed out after %s", e.StateName, e.Timeout)
+}
+
 // Inject dependencies
 func (f *Factory) Inject(
 	provider Provider,
+	allStates map[string]State,
 	dep *struct {
 		StartState  State `inject:"startState"`
 		FailedState State `inject:"failedState"`
 	},
 ) {
 	f.provider = provider
+	f.allStates = allStates
 
 	if dep != nil {
 		f.failedState = dep.FailedState
@@ -142,6 +161,11 @@ func (f *Factory) Inject(
 	}
 }
 
+// Graph of the bound states and their declared transitions
+func (f *Factory) Graph() Graph {
+	return NewGraph(f.allStates, f.startState, f.failedState)
+}
+
 // New process with initial state
 func (f *Factory) New(returnURL *url.URL, cart cart.Cart) (*Process, error) {
 	if f.startState == nil {
@@ -152,6 +176,7 @@ func (f *Factory) New(returnURL *url.URL, cart cart.Cart) (*Process, error) {
 	p.context = Context{
 		UUID:             uuid.New().String(),
 		CurrentStateName: f.startState.Name(),
+		StateEnteredAt:   p.currentTime(),
 		Cart:             cart,
 		ReturnURL:        returnURL,
 	}
@@ -173,16 +198,32 @@ func (p *Process) Inject(
 	allStates map[string]State,
 	logger flamingo.Logger,
 	cfg *struct {
-		Area string `inject:"config:area"`
+		Area          string     `inject:"config:area"`
+		StateTimeouts config.Map `inject:"config:commerce.checkout.placeorder.timeouts.states,optional"`
 	},
 ) *Process {
 	p.allStates = allStates
 	p.logger = logger.
 		WithField(flamingo.LogKeyModule, "checkout").
 		WithField(flamingo.LogKeyCategory, "process")
+	p.stateTimeouts = make(map[string]time.Duration)
 
 	if cfg != nil {
 		p.area = cfg.Area
+
+		for stateName, value := range cfg.StateTimeouts {
+			timeout, ok := value.(string)
+			if !ok {
+				panic(fmt.Sprintf("commerce.checkout.placeorder.timeouts.states.%s is not a duration string", stateName))
+			}
+
+			duration, err := time.ParseDuration(timeout)
+			if err != nil {
+				panic(fmt.Sprintf("can't parse commerce.checkout.placeorder.timeouts.states.%s", stateName))
+			}
+
+			p.stateTimeouts[stateName] = duration
+		}
 	}
 
 	return p
@@ -251,10 +292,51 @@ func (p *Process) Context() Context {
 
 // UpdateState updates the current state in the context and its related state data
 func (p *Process) UpdateState(s string, stateData StateData) {
+	if p.context.CurrentStateName != s {
+		p.context.StateEnteredAt = p.currentTime()
+	}
+
 	p.context.CurrentStateName = s
 	p.context.CurrentStateData = stateData
 }
 
+// StateDeadline returns the time the current state times out, false if the state has no timeout.
+// Final states never time out, a context without StateEnteredAt has no known entry time and doesn't time out either.
+func (p *Process) StateDeadline() (time.Time, bool) {
+	timeout := p.stateTimeouts[p.context.CurrentStateName]
+	if timeout <= 0 || p.context.StateEnteredAt.IsZero() {
+		return time.Time{}, false
+	}
+
+	currentState, err := p.CurrentState()
+	if err != nil || currentState.IsFinal() {
+		return time.Time{}, false
+	}
+
+	return p.context.StateEnteredAt.Add(timeout), true
+}
+
+// IsTimedOut checks if the process stayed longer than the configured timeout in the current state
+func (p *Process) IsTimedOut() bool {
+	deadline, ok := p.StateDeadline()
+
+	return ok && !p.currentTime().Before(deadline)
+}
+
+// FailIfTimedOut performs all collected rollbacks and switches to FailedState with a TimeoutReason
+// if the current state timed out, it reports if the process failed
+func (p *Process) FailIfTimedOut(ctx context.Context) bool {
+	if !p.IsTimedOut() {
+		return false
+	}
+
+	stateName := p.context.CurrentStateName
+	p.logger.WithContext(ctx).Info(fmt.Sprintf("place order process %q timed out in state %q", p.context.UUID, stateName))
+	p.Failed(ctx, TimeoutReason{StateName: stateName, Timeout: p.stateTimeouts[stateName]})
+
+	return true
+}
+
 // UpdateCart updates the cart in the current state context
 func (p *Process) UpdateCart(cartToStore cart.Cart) {
 	p.context.Cart = cartToStore
@@ -275,3 +357,12 @@ func (p *Process) Failed(ctx context.Context, reason FailedReason) {
 	p.context.FailedReason = reason
 	p.UpdateState(p.failedState.Name(), nil)
 }
+
+func (p *Process) currentTime() time.Time {
+	// processes created without injection, e.g. in tests, use the wall clock
+	if p.now == nil {
+		return time.Now()
+	}
+
+	return p.now()
+}
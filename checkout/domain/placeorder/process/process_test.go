@@ -0,0 +1,116 @@
+package process
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/config"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	testState struct {
+		name       string
+		final      bool
+		rolledBack *[]RollbackData
+	}
+)
+
+func (s testState) Run(context.Context, *Process) RunResult {
+	return RunResult{}
+}
+
+func (s testState) Rollback(_ context.Context, data RollbackData) error {
+	*s.rolledBack = append(*s.rolledBack, data)
+	return nil
+}
+
+func (s testState) IsFinal() bool {
+	return s.final
+}
+
+func (s testState) Name() string {
+	return s.name
+}
+
+func newTimeoutTestProcess(t *testing.T, now *time.Time, rolledBack *[]RollbackData) *Process {
+	t.Helper()
+
+	failed := testState{name: "Failed", final: true, rolledBack: rolledBack}
+	p := new(Process).Inject(
+		map[string]State{
+			"New":             testState{name: "New", rolledBack: rolledBack},
+			"WaitForCustomer": testState{name: "WaitForCustomer", rolledBack: rolledBack},
+			"Failed":          failed,
+		},
+		flamingo.NullLogger{},
+		&struct {
+			Area          string     `inject:"config:area"`
+			StateTimeouts config.Map `inject:"config:commerce.checkout.placeorder.timeouts.states,optional"`
+		}{StateTimeouts: config.Map{"WaitForCustomer": "30m", "Failed": "1s"}},
+	)
+	p.failedState = failed
+	p.now = func() time.Time { return *now }
+	p.context = Context{UUID: "uuid", CurrentStateName: "New", StateEnteredAt: *now}
+
+	return p
+}
+
+func TestProcess_StateTimeout(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	var rolledBack []RollbackData
+	p := newTimeoutTestProcess(t, &now, &rolledBack)
+
+	_, ok := p.StateDeadline()
+	assert.False(t, ok, "state without timeout")
+
+	now = now.Add(time.Minute)
+	p.UpdateState("WaitForCustomer", nil)
+	p.context.RollbackReferences = []RollbackReference{{StateName: "New", Data: "rollback"}}
+
+	deadline, ok := p.StateDeadline()
+	require.True(t, ok)
+	assert.Equal(t, now.Add(30*time.Minute), deadline)
+
+	now = now.Add(10 * time.Minute)
+	p.UpdateState("WaitForCustomer", "data")
+	assert.Equal(t, now.Add(-10*time.Minute), p.Context().StateEnteredAt, "staying in the state doesn't reset the timeout")
+
+	now = now.Add(19 * time.Minute)
+	assert.False(t, p.IsTimedOut())
+	assert.False(t, p.FailIfTimedOut(context.Background()))
+	assert.Equal(t, "WaitForCustomer", p.Context().CurrentStateName)
+
+	now = now.Add(time.Minute)
+	assert.True(t, p.IsTimedOut())
+	assert.True(t, p.FailIfTimedOut(context.Background()))
+	assert.Equal(t, "Failed", p.Context().CurrentStateName)
+	assert.Equal(t, TimeoutReason{StateName: "WaitForCustomer", Timeout: 30 * time.Minute}, p.Context().FailedReason)
+	assert.Equal(t, []RollbackData{"rollback"}, rolledBack)
+
+	now = now.Add(time.Hour)
+	assert.False(t, p.IsTimedOut(), "final states don't time out")
+}
+
+func TestProcess_StateTimeoutOfContextWithoutStateEnteredAt(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	var rolledBack []RollbackData
+	p := newTimeoutTestProcess(t, &now, &rolledBack)
+	p.context = Context{UUID: "uuid", CurrentStateName: "WaitForCustomer"}
+
+	now = now.Add(24 * time.Hour)
+	assert.False(t, p.IsTimedOut())
+}
+
+func TestTimeoutReason_Reason(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `State "WaitForCustomer" timed out after 30m0s`, TimeoutReason{StateName: "WaitForCustomer", Timeout: 30 * time.Minute}.Reason())
+}
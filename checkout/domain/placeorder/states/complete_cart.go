@@ -46,6 +46,11 @@ func (CompleteCart) Name() string {
 	return "CompleteCart"
 }
 
+// NextStates returns the states the state can change to
+func (CompleteCart) NextStates() []string {
+	return []string{PlaceOrder{}.Name()}
+}
+
 // Run the state operations
 func (c CompleteCart) Run(ctx context.Context, p *process.Process) process.RunResult {
 	ctx, span := trace.StartSpan(ctx, "placeorder/state/CompleteCart/Run")
@@ -31,6 +31,11 @@ func (CompletePayment) Name() string {
 	return "CompletePayment"
 }
 
+// NextStates returns the states the state can change to
+func (CompletePayment) NextStates() []string {
+	return []string{ValidatePayment{}.Name()}
+}
+
 // Run the state operations
 func (c CompletePayment) Run(ctx context.Context, p *process.Process) process.RunResult {
 	ctx, span := trace.StartSpan(ctx, "placeorder/state/CompletePayment/Run")
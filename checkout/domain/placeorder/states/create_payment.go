@@ -45,6 +45,11 @@ func (CreatePayment) Name() string {
 	return "CreatePayment"
 }
 
+// NextStates returns the states the state can change to
+func (CreatePayment) NextStates() []string {
+	return []string{CompleteCart{}.Name()}
+}
+
 // Run the state operations
 func (c CreatePayment) Run(ctx context.Context, p *process.Process) process.RunResult {
 	ctx, span := trace.StartSpan(ctx, "placeorder/state/CreatePayment/Run")
@@ -36,6 +36,11 @@ func (f Failed) Name() string {
 	return "Failed"
 }
 
+// NextStates returns no states, the state is final
+func (f Failed) NextStates() []string {
+	return nil
+}
+
 // Run the state operations
 func (f Failed) Run(ctx context.Context, p *process.Process) process.RunResult {
 	_, span := trace.StartSpan(ctx, "placeorder/state/Failed/Run")
@@ -20,6 +20,11 @@ func (New) Name() string {
 	return "New"
 }
 
+// NextStates returns the states the state can change to
+func (New) NextStates() []string {
+	return []string{PrepareCart{}.Name()}
+}
+
 // Run the state operations
 func (n New) Run(ctx context.Context, p *process.Process) process.RunResult {
 	_, span := trace.StartSpan(ctx, "placeorder/state/New/Run")
@@ -64,6 +64,11 @@ func (PlaceOrder) Name() string {
 	return "PlaceOrder"
 }
 
+// NextStates returns the states the state can change to
+func (PlaceOrder) NextStates() []string {
+	return []string{ValidatePayment{}.Name(), Success{}.Name()}
+}
+
 // Run the state operations
 func (po PlaceOrder) Run(ctx context.Context, p *process.Process) process.RunResult {
 	ctx, span := trace.StartSpan(ctx, "placeorder/state/PlaceOrder/Run")
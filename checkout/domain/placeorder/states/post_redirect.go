@@ -59,6 +59,11 @@ func (PostRedirect) Name() string {
 	return "PostRedirect"
 }
 
+// NextStates returns the states the payment validator changes to after the customer has been sent to the payment provider via a form post
+func (PostRedirect) NextStates() []string {
+	return paymentValidationNextStates()
+}
+
 // Run the state operations
 func (pr PostRedirect) Run(ctx context.Context, p *process.Process) process.RunResult {
 	ctx, span := trace.StartSpan(ctx, "placeorder/state/PostRedirect/Run")
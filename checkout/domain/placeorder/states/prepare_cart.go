@@ -34,6 +34,11 @@ func (PrepareCart) Name() string {
 	return "PrepareCart"
 }
 
+// NextStates returns the states the state can change to
+func (PrepareCart) NextStates() []string {
+	return []string{ValidateCart{}.Name()}
+}
+
 // Run the state operations
 func (v PrepareCart) Run(ctx context.Context, p *process.Process) process.RunResult {
 	ctx, span := trace.StartSpan(ctx, "placeorder/state/PrepareCart/Run")
@@ -45,6 +45,11 @@ func (Redirect) Name() string {
 	return "Redirect"
 }
 
+// NextStates returns the states the payment validator changes to after the customer has been redirected to the payment provider
+func (Redirect) NextStates() []string {
+	return paymentValidationNextStates()
+}
+
 // Run the state operations
 func (r Redirect) Run(ctx context.Context, p *process.Process) process.RunResult {
 	ctx, span := trace.StartSpan(ctx, "placeorder/state/Redirect/Run")
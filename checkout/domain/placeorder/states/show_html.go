@@ -38,6 +38,11 @@ func (ShowHTML) Name() string {
 	return "ShowHTML"
 }
 
+// NextStates returns the states the payment validator changes to while the html of the payment provider is shown to the customer
+func (ShowHTML) NextStates() []string {
+	return paymentValidationNextStates()
+}
+
 // Run the state operations
 func (sh ShowHTML) Run(ctx context.Context, p *process.Process) process.RunResult {
 	ctx, span := trace.StartSpan(ctx, "placeorder/state/ShowHTML/Run")
@@ -45,6 +45,11 @@ func (ShowIframe) Name() string {
 	return "ShowIframe"
 }
 
+// NextStates returns the states the payment validator changes to while the iframe of the payment provider is shown to the customer
+func (ShowIframe) NextStates() []string {
+	return paymentValidationNextStates()
+}
+
 // Run the state operations
 func (si ShowIframe) Run(ctx context.Context, p *process.Process) process.RunResult {
 	ctx, span := trace.StartSpan(ctx, "placeorder/state/ShowIframe/Run")
@@ -49,6 +49,11 @@ func (ShowWalletPayment) Name() string {
 	return "ShowWalletPayment"
 }
 
+// NextStates returns the states the payment validator changes to once the customer handled the wallet payment request
+func (ShowWalletPayment) NextStates() []string {
+	return paymentValidationNextStates()
+}
+
 // Run the state operations
 func (pr ShowWalletPayment) Run(ctx context.Context, p *process.Process) process.RunResult {
 	ctx, span := trace.StartSpan(ctx, "placeorder/state/ShowWalletPayment/Run")
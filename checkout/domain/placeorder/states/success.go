@@ -36,6 +36,11 @@ func (s Success) Name() string {
 	return "Success"
 }
 
+// NextStates returns no states, the state is final
+func (s Success) NextStates() []string {
+	return nil
+}
+
 // Run the state operations
 func (s Success) Run(ctx context.Context, p *process.Process) process.RunResult {
 	_, span := trace.StartSpan(ctx, "placeorder/state/Success/Run")
@@ -0,0 +1,29 @@
+package states_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"flamingo.me/flamingo-commerce/v3/checkout/domain/placeorder/process"
+	"flamingo.me/flamingo-commerce/v3/checkout/domain/placeorder/states"
+)
+
+func TestDefaultStateGraph(t *testing.T) {
+	allStates := make(map[string]process.State)
+	for _, state := range []process.State{
+		states.New{}, states.PrepareCart{}, states.ValidateCart{}, states.ValidatePaymentSelection{}, states.CreatePayment{},
+		states.CompleteCart{}, states.CompletePayment{}, states.PlaceOrder{}, states.ValidatePayment{}, states.WaitForCustomer{},
+		states.Success{}, states.Failed{}, states.ShowIframe{}, states.ShowHTML{}, states.ShowWalletPayment{}, states.Redirect{},
+		states.PostRedirect{}, states.TriggerClientSDK{},
+	} {
+		allStates[state.Name()] = state
+	}
+
+	graph := process.NewGraph(allStates, states.New{}, states.Failed{})
+	assert.NoError(t, graph.Validate())
+
+	for _, state := range graph.States {
+		assert.True(t, state.Declared, "state %q does not declare its next states", state.Name)
+	}
+}
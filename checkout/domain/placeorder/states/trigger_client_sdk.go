@@ -54,6 +54,11 @@ func (TriggerClientSDK) Name() string {
 	return "TriggerClientSDK"
 }
 
+// NextStates returns the states the payment validator changes to once the client sdk of the payment provider has been triggered
+func (TriggerClientSDK) NextStates() []string {
+	return paymentValidationNextStates()
+}
+
 // Run the state operations
 func (r TriggerClientSDK) Run(ctx context.Context, p *process.Process) process.RunResult {
 	ctx, span := trace.StartSpan(ctx, "placeorder/state/TriggerClientSDK/Run")
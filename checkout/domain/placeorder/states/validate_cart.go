@@ -33,6 +33,11 @@ func (ValidateCart) Name() string {
 	return "ValidateCart"
 }
 
+// NextStates returns the states the state can change to
+func (ValidateCart) NextStates() []string {
+	return []string{ValidatePaymentSelection{}.Name(), CompleteCart{}.Name()}
+}
+
 // Run the state operations
 func (v ValidateCart) Run(ctx context.Context, p *process.Process) process.RunResult {
 	ctx, span := trace.StartSpan(ctx, "placeorder/state/ValidateCart/Run")
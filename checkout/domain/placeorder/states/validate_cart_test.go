@@ -91,6 +91,7 @@ func TestValidateCart_Run(t *testing.T) {
 			result.Inject(
 				nil,
 				flamingo.NullLogger{},
+				nil,
 			)
 
 			return result
@@ -115,10 +116,13 @@ func TestValidateCart_Run(t *testing.T) {
 				new(flamingo.NullLogger),
 				nil,
 				&struct {
-					CartValidator     validation.Validator     `inject:",optional"`
-					ItemValidator     validation.ItemValidator `inject:",optional"`
-					CartCache         application.CartCache    `inject:",optional"`
-					PlaceOrderService placeorder.Service       `inject:",optional"`
+					CartValidator     validation.Validator      `inject:",optional"`
+					CartValidators    []validation.Validator    `inject:",optional"`
+					ItemValidator     validation.ItemValidator  `inject:",optional"`
+					CartCache         application.CartCache     `inject:",optional"`
+					PlaceOrderService placeorder.Service        `inject:",optional"`
+					HistoryStorage    cartDomain.HistoryStorage `inject:",optional"`
+					ChangeNotifier    cartDomain.ChangeNotifier `inject:",optional"`
 				}{CartValidator: &validator{Valid: tt.isValid}, ItemValidator: nil, CartCache: nil, PlaceOrderService: nil},
 			)
 			state := new(states.ValidateCart).Inject(&cartService)
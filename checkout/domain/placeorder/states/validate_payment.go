@@ -34,6 +34,11 @@ func (ValidatePayment) Name() string {
 	return "ValidatePayment"
 }
 
+// NextStates returns the states the payment validator can change to
+func (ValidatePayment) NextStates() []string {
+	return paymentValidationNextStates()
+}
+
 // Run the state operations
 func (v ValidatePayment) Run(ctx context.Context, p *process.Process) process.RunResult {
 	ctx, span := trace.StartSpan(ctx, "placeorder/state/ValidatePayment/Run")
@@ -51,3 +56,18 @@ func (v ValidatePayment) Rollback(ctx context.Context, _ process.RollbackData) e
 func (v ValidatePayment) IsFinal() bool {
 	return false
 }
+
+// paymentValidationNextStates are the states the default process.PaymentValidatorFunc changes to
+func paymentValidationNextStates() []string {
+	return []string{
+		PostRedirect{}.Name(),
+		ShowWalletPayment{}.Name(),
+		Redirect{}.Name(),
+		ShowHTML{}.Name(),
+		ShowIframe{}.Name(),
+		TriggerClientSDK{}.Name(),
+		CompletePayment{}.Name(),
+		Success{}.Name(),
+		WaitForCustomer{}.Name(),
+	}
+}
@@ -41,6 +41,11 @@ func (ValidatePaymentSelection) Name() string {
 	return "ValidatePaymentSelection"
 }
 
+// NextStates returns the states the state can change to
+func (ValidatePaymentSelection) NextStates() []string {
+	return []string{CreatePayment{}.Name()}
+}
+
 // Run the state operations
 func (v ValidatePaymentSelection) Run(ctx context.Context, p *process.Process) process.RunResult {
 	_, span := trace.StartSpan(ctx, "placeorder/state/ValidatePaymentSelection/Run")
@@ -34,6 +34,11 @@ func (WaitForCustomer) Name() string {
 	return "WaitForCustomer"
 }
 
+// NextStates returns the states the process continues with once the payment validator detects that the customer finished the pending action
+func (WaitForCustomer) NextStates() []string {
+	return paymentValidationNextStates()
+}
+
 // Run the state operations
 func (wc WaitForCustomer) Run(ctx context.Context, p *process.Process) process.RunResult {
 	ctx, span := trace.StartSpan(ctx, "placeorder/state/WaitForCustomer/Run")
@@ -15,7 +15,7 @@ type (
 	}
 )
 
-var _ process.ContextStore = new(Memory)
+var _ process.IterableContextStore = new(Memory)
 
 // Inject dependencies
 func (m *Memory) Inject() *Memory {
@@ -50,3 +50,21 @@ func (m *Memory) Delete(_ context.Context, key string) error {
 
 	return nil
 }
+
+// ForEachContext calls fn for a snapshot of all stored contexts until fn returns an error
+func (m *Memory) ForEachContext(_ context.Context, fn func(key string, placeOrderContext process.Context) error) error {
+	m.mx.RLock()
+	snapshot := make(map[string]process.Context, len(m.storage))
+	for key, value := range m.storage {
+		snapshot[key] = value
+	}
+	m.mx.RUnlock()
+
+	for key, value := range snapshot {
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package contextstore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flamingo.me/flamingo-commerce/v3/checkout/domain/placeorder/process"
+	"flamingo.me/flamingo-commerce/v3/checkout/infrastructure/contextstore"
+)
+
+func TestMemory_ForEachContext(t *testing.T) {
+	t.Parallel()
+
+	store := new(contextstore.Memory).Inject()
+	require.NoError(t, store.Store(context.Background(), "session-1", process.Context{UUID: "uuid-1"}))
+	require.NoError(t, store.Store(context.Background(), "session-2", process.Context{UUID: "uuid-2"}))
+	require.NoError(t, store.Delete(context.Background(), "session-2"))
+
+	visited := make(map[string]string)
+	err := store.ForEachContext(context.Background(), func(key string, pctx process.Context) error {
+		// the store may be modified while iterating
+		require.NoError(t, store.Store(context.Background(), "session-3", process.Context{UUID: "uuid-3"}))
+		visited[key] = pctx.UUID
+
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"session-1": "uuid-1"}, visited)
+}
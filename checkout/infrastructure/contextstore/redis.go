@@ -26,9 +26,12 @@ type (
 	}
 )
 
+// keysIndex is the redis set of all stored context keys, it makes the contexts iterable without scanning the whole database
+const keysIndex = "checkout_placeorder_contextstore_keys"
+
 var (
-	_ process.ContextStore = new(Redis)
-	_ healthcheck.Status   = &Redis{}
+	_ process.IterableContextStore = new(Redis)
+	_ healthcheck.Status           = &Redis{}
 	// ErrNoRedisConnection is returned if the underlying connection is erroneous
 	ErrNoRedisConnection = errors.New("no redis connection, see healthcheck")
 )
@@ -115,6 +118,11 @@ func (r *Redis) Store(ctx context.Context, key string, placeOrderContext process
 		int(r.ttl.Round(time.Second).Seconds()),
 		buffer,
 	)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Do("SADD", keysIndex, key)
 
 	return err
 }
@@ -159,10 +167,61 @@ func (r *Redis) Delete(ctx context.Context, key string) error {
 	}
 
 	_, err := conn.Do("DEL", key)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Do("SREM", keysIndex, key)
 
 	return err
 }
 
+// ForEachContext calls fn for all stored contexts until fn returns an error,
+// keys of contexts removed by their ttl are dropped from the index
+func (r *Redis) ForEachContext(ctx context.Context, fn func(key string, placeOrderContext process.Context) error) error {
+	ctx, span := trace.StartSpan(ctx, "checkout/Redis/ForEachContext")
+	defer span.End()
+
+	conn := r.pool.Get()
+	keys, err := redis.Strings(conn.Do("SMEMBERS", keysIndex))
+	if err != nil {
+		conn.Close()
+		r.logger.Error("placeorder/contextstore/ForEachContext:", err)
+		return ErrNoRedisConnection
+	}
+
+	var expiredKeys []interface{}
+	for _, key := range keys {
+		exists, err := redis.Bool(conn.Do("EXISTS", key))
+		if err == nil && !exists {
+			expiredKeys = append(expiredKeys, key)
+		}
+	}
+
+	if len(expiredKeys) > 0 {
+		_, err = conn.Do("SREM", append([]interface{}{keysIndex}, expiredKeys...)...)
+		if err != nil {
+			r.logger.Error("placeorder/contextstore/ForEachContext:", err)
+		}
+	}
+
+	// the connection is released before calling fn, which may use the store itself
+	conn.Close()
+
+	for _, key := range keys {
+		placeOrderContext, found := r.Get(ctx, key)
+		if !found {
+			continue
+		}
+
+		if err := fn(key, placeOrderContext); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Status handles the health check of redis
 func (r *Redis) Status() (alive bool, details string) {
 	conn := r.pool.Get()
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/gob"
+	"errors"
 	"fmt"
 	"os/exec"
 	"testing"
@@ -272,6 +273,72 @@ func TestRedis_Delete(t *testing.T) {
 	})
 }
 
+func TestRedis_ForEachContext(t *testing.T) {
+	username := "testuser"
+	password := "testPassword"
+
+	runTestCases := func(t *testing.T, store *contextstore.Redis, conn redis.Conn) {
+		ctx := context.Background()
+		require.NoError(t, store.Store(ctx, "session-1", process.Context{UUID: "process-1"}))
+		require.NoError(t, store.Store(ctx, "session-2", process.Context{UUID: "process-2"}))
+		require.NoError(t, store.Store(ctx, "session-expired", process.Context{UUID: "process-expired"}))
+
+		// simulates a context expired by its ttl, the key stays in the index
+		_, err := conn.Do("DEL", "session-expired")
+		require.NoError(t, err)
+
+		t.Run("calls fn for every stored context", func(t *testing.T) {
+			visited := make(map[string]string)
+			err := store.ForEachContext(ctx, func(key string, pctx process.Context) error {
+				visited[key] = pctx.UUID
+				return nil
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, map[string]string{"session-1": "process-1", "session-2": "process-2"}, visited)
+		})
+
+		t.Run("expired keys are removed from the index", func(t *testing.T) {
+			keys, err := redis.Strings(conn.Do("SMEMBERS", "checkout_placeorder_contextstore_keys"))
+			require.NoError(t, err)
+
+			assert.ElementsMatch(t, []string{"session-1", "session-2"}, keys)
+		})
+
+		t.Run("error of fn stops the iteration", func(t *testing.T) {
+			fnErr := errors.New("stop")
+			calls := 0
+			err := store.ForEachContext(ctx, func(string, process.Context) error {
+				calls++
+				return fnErr
+			})
+
+			assert.ErrorIs(t, err, fnErr)
+			assert.Equal(t, 1, calls)
+		})
+	}
+
+	t.Run("local-redis", func(t *testing.T) {
+		if _, err := exec.LookPath("redis-server"); err != nil {
+			t.Skip("redis-server not installed")
+		}
+		server, conn := startUpLocalRedis(t)
+		store := getRedisStore("unix", server.Socket(), "", "")
+		runTestCases(t, store, conn)
+	})
+	t.Run("docker-redis", func(t *testing.T) {
+		if _, err := exec.LookPath("docker"); err != nil {
+			t.Skip("docker not installed")
+		}
+
+		shutdown, address, conn := startUpDockerRedis(t, username, password)
+		defer shutdown()
+
+		store := getRedisStore("tcp", address, username, password)
+		runTestCases(t, store, conn)
+	})
+}
+
 func getContainerRequest(username, password string) testcontainers.ContainerRequest {
 	return testcontainers.ContainerRequest{
 		Image:        "valkey/valkey:7",
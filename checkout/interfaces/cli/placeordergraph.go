@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"flamingo.me/flamingo-commerce/v3/checkout/domain/placeorder/process"
+)
+
+// PlaceOrderGraphCmd prints the graph of the bound place order states in DOT or Mermaid format
+func PlaceOrderGraphCmd(processFactory *process.Factory) *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "placeorder-graph",
+		Short: "Print the place order state graph",
+		Long:  "Print the graph of the bound place order states and their transitions, render it e.g. with `dot -Tsvg` or Mermaid",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			graph := processFactory.Graph()
+
+			switch format {
+			case "dot":
+				fmt.Fprint(cmd.OutOrStdout(), graph.DOT())
+			case "mermaid":
+				fmt.Fprint(cmd.OutOrStdout(), graph.Mermaid())
+			default:
+				return fmt.Errorf("unknown format %q, use dot or mermaid", format)
+			}
+
+			if err := graph.Validate(); err != nil {
+				return fmt.Errorf("invalid place order state graph: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "dot", "output format: dot or mermaid")
+
+	return cmd
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/url"
 
@@ -9,6 +10,7 @@ import (
 
 	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
 	placeorderDomain "flamingo.me/flamingo-commerce/v3/cart/domain/placeorder"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/quote"
 	"flamingo.me/flamingo-commerce/v3/cart/domain/validation"
 	"flamingo.me/flamingo-commerce/v3/checkout/application"
 	"flamingo.me/flamingo-commerce/v3/checkout/domain/placeorder/process"
@@ -27,6 +29,7 @@ type (
 		responder            *web.Responder
 		placeorderHandler    *placeorder.Handler
 		cartService          *cartApplication.CartService
+		quoteService         *cartApplication.QuoteService
 		logger               flamingo.Logger
 		decoratedCartFactory *decorator.DecoratedCartFactory
 	}
@@ -67,6 +70,7 @@ func (c *APIController) Inject(
 	responder *web.Responder,
 	placeorderHandler *placeorder.Handler,
 	cartService *cartApplication.CartService,
+	quoteService *cartApplication.QuoteService,
 	decoratedCartFactory *decorator.DecoratedCartFactory,
 	logger flamingo.Logger,
 ) {
@@ -74,6 +78,7 @@ func (c *APIController) Inject(
 	c.placeorderHandler = placeorderHandler
 	c.decoratedCartFactory = decoratedCartFactory
 	c.cartService = cartService
+	c.quoteService = quoteService
 	c.logger = logger.WithField(flamingo.LogKeyModule, "checkout").WithField(flamingo.LogKeyCategory, "apicontroller")
 }
 
@@ -125,6 +130,65 @@ func (c *APIController) StartPlaceOrderAction(ctx context.Context, r *web.Reques
 	return response
 }
 
+// StartQuotePlaceOrderAction converts an accepted quote by starting the place order process for a cart with the locked prices of the quote
+// @Summary Starts the place order process for an accepted quote, the cart of the quote becomes the current cart of the customer
+// @Tags Checkout
+// @Produce json
+// @Success 201 {object} startPlaceOrderResult "201 if new process was started"
+// @Failure 500 {object} errorResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Param id path string true "the id of the accepted quote"
+// @Param returnURL query string true "the returnURL that should be used after an external payment flow"
+// @Router /api/v1/checkout/quotes/{id}/placeorder [put]
+func (c *APIController) StartQuotePlaceOrderAction(ctx context.Context, r *web.Request) web.Result {
+	ctx, span := trace.StartSpan(ctx, "checkout/APIController/StartQuotePlaceOrderAction")
+	defer span.End()
+
+	returnURLRaw, err := r.Query1("returnURL")
+	if err != nil {
+		response := c.responder.Data(errorResponse{Code: "400", Message: "returnURL missing"})
+		response.Status(http.StatusBadRequest)
+		return response
+	}
+	returnURL, err := url.Parse(returnURLRaw)
+	if err != nil {
+		response := c.responder.Data(errorResponse{Code: "400", Message: err.Error()})
+		response.Status(http.StatusBadRequest)
+		return response
+	}
+
+	cart, err := c.quoteService.PrepareConversion(ctx, web.SessionFromContext(ctx), r.Params["id"])
+	if errors.Is(err, quote.ErrQuoteNotFound) {
+		response := c.responder.Data(errorResponse{Code: "404", Message: err.Error()})
+		response.Status(http.StatusNotFound)
+		return response
+	}
+	if errors.Is(err, quote.ErrInvalidTransition) || errors.Is(err, quote.ErrQuoteExpired) {
+		response := c.responder.Data(errorResponse{Code: "409", Message: err.Error()})
+		response.Status(http.StatusConflict)
+		return response
+	}
+	if err != nil {
+		response := c.responder.Data(errorResponse{Code: "500", Message: err.Error()})
+		response.Status(http.StatusInternalServerError)
+		return response
+	}
+
+	pctx, err := c.placeorderHandler.StartPlaceOrder(ctx, placeorder.StartPlaceOrderCommand{Cart: *cart, ReturnURL: returnURL})
+	if err != nil {
+		response := c.responder.Data(errorResponse{Code: "500", Message: err.Error()})
+		response.Status(http.StatusInternalServerError)
+		return response
+	}
+	response := c.responder.Data(startPlaceOrderResult{
+		UUID: pctx.UUID,
+	})
+	response.Status(http.StatusCreated)
+	return response
+}
+
 // CancelPlaceOrderAction cancels a running place order process
 // @Summary Cancels a running place order process
 // @Tags Checkout
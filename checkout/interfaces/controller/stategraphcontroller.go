@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"flamingo.me/flamingo/v3/framework/web"
+
+	"flamingo.me/flamingo-commerce/v3/checkout/domain/placeorder/process"
+)
+
+type (
+	// StateGraphController renders the place order state graph for debugging
+	StateGraphController struct {
+		responder      *web.Responder
+		processFactory *process.Factory
+	}
+)
+
+// Inject dependencies
+func (c *StateGraphController) Inject(responder *web.Responder, processFactory *process.Factory) *StateGraphController {
+	c.responder = responder
+	c.processFactory = processFactory
+
+	return c
+}
+
+// GraphAction returns the state graph in DOT format or in Mermaid format with the query param format=mermaid
+func (c *StateGraphController) GraphAction(_ context.Context, r *web.Request) web.Result {
+	graph := c.processFactory.Graph()
+
+	var body string
+
+	switch format, _ := r.Query1("format"); format {
+	case "", "dot":
+		body = graph.DOT()
+	case "mermaid":
+		body = graph.Mermaid()
+	default:
+		return c.responder.HTTP(http.StatusBadRequest, strings.NewReader("unknown format, use dot or mermaid"))
+	}
+
+	response := c.responder.HTTP(http.StatusOK, strings.NewReader(body))
+	response.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	return response
+}
@@ -19,6 +19,7 @@ import (
 type CommerceCheckoutMutationResolver struct {
 	placeorderHandler    *placeorder.Handler
 	cartService          *cartApplication.CartService
+	quoteService         *cartApplication.QuoteService
 	stateMapper          *dto.StateMapper
 	logger               flamingo.Logger
 	decoratedCartFactory *decorator.DecoratedCartFactory
@@ -28,6 +29,7 @@ type CommerceCheckoutMutationResolver struct {
 func (r *CommerceCheckoutMutationResolver) Inject(
 	placeorderHandler *placeorder.Handler,
 	cartService *cartApplication.CartService,
+	quoteService *cartApplication.QuoteService,
 	decoratedCartFactory *decorator.DecoratedCartFactory,
 	stateMapper *dto.StateMapper,
 	logger flamingo.Logger,
@@ -35,6 +37,7 @@ func (r *CommerceCheckoutMutationResolver) Inject(
 	r.placeorderHandler = placeorderHandler
 	r.decoratedCartFactory = decoratedCartFactory
 	r.cartService = cartService
+	r.quoteService = quoteService
 	r.stateMapper = stateMapper
 	r.logger = logger.WithField(flamingo.LogKeyModule, "checkout").WithField(flamingo.LogKeyCategory, "graphql")
 }
@@ -108,6 +111,29 @@ func (r *CommerceCheckoutMutationResolver) CommerceCheckoutStartPlaceOrder(ctx c
 	}, nil
 }
 
+// CommerceCheckoutStartPlaceOrderForQuote starts a new process for an accepted quote with its locked prices
+func (r *CommerceCheckoutMutationResolver) CommerceCheckoutStartPlaceOrderForQuote(ctx context.Context, quoteID string, returnURLRaw string) (*dto.StartPlaceOrderResult, error) {
+	var returnURL *url.URL
+	if returnURLRaw != "" {
+		var err error
+		returnURL, err = url.Parse(returnURLRaw)
+		if err != nil {
+			return nil, err
+		}
+	}
+	cart, err := r.quoteService.PrepareConversion(ctx, web.SessionFromContext(ctx), quoteID)
+	if err != nil {
+		return nil, err
+	}
+	pctx, err := r.placeorderHandler.StartPlaceOrder(ctx, placeorder.StartPlaceOrderCommand{Cart: *cart, ReturnURL: returnURL})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.StartPlaceOrderResult{
+		UUID: pctx.UUID,
+	}, nil
+}
+
 // CommerceCheckoutCancelPlaceOrder cancels a running place order
 func (r *CommerceCheckoutMutationResolver) CommerceCheckoutCancelPlaceOrder(ctx context.Context) (bool, error) {
 	err := r.placeorderHandler.CancelPlaceOrder(ctx, placeorder.CancelPlaceOrderCommand{})
@@ -48,10 +48,12 @@ func (*Service) Types(types *graphql.Types) {
 	types.Map("Commerce_Checkout_PlaceOrderState_State_FailedReason_CartValidationError", process.CartValidationErrorReason{})
 	types.Map("Commerce_Checkout_PlaceOrderState_State_FailedReason_CanceledByCustomer", process.CanceledByCustomerReason{})
 	types.Map("Commerce_Checkout_PlaceOrderState_State_FailedReason_PaymentCanceledByCustomer", process.PaymentCanceledByCustomerReason{})
+	types.Map("Commerce_Checkout_PlaceOrderState_State_FailedReason_Timeout", process.TimeoutReason{})
 
 	types.Resolve("Query", "Commerce_Checkout_ActivePlaceOrder", CommerceCheckoutQueryResolver{}, "CommerceCheckoutActivePlaceOrder")
 	types.Resolve("Query", "Commerce_Checkout_CurrentContext", CommerceCheckoutQueryResolver{}, "CommerceCheckoutCurrentContext")
 	types.Resolve("Mutation", "Commerce_Checkout_StartPlaceOrder", CommerceCheckoutMutationResolver{}, "CommerceCheckoutStartPlaceOrder")
+	types.Resolve("Mutation", "Commerce_Checkout_StartPlaceOrderForQuote", CommerceCheckoutMutationResolver{}, "CommerceCheckoutStartPlaceOrderForQuote")
 	types.Resolve("Mutation", "Commerce_Checkout_CancelPlaceOrder", CommerceCheckoutMutationResolver{}, "CommerceCheckoutCancelPlaceOrder")
 	types.Resolve("Mutation", "Commerce_Checkout_ClearPlaceOrder", CommerceCheckoutMutationResolver{}, "CommerceCheckoutClearPlaceOrder")
 	types.Resolve("Mutation", "Commerce_Checkout_RefreshPlaceOrder", CommerceCheckoutMutationResolver{}, "CommerceCheckoutRefreshPlaceOrder")
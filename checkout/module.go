@@ -3,6 +3,7 @@ package checkout
 import (
 	"flamingo.me/dingo"
 	"github.com/go-playground/form/v4"
+	"github.com/spf13/cobra"
 
 	"flamingo.me/flamingo/v3/core/healthcheck/domain/healthcheck"
 	"flamingo.me/flamingo/v3/framework/flamingo"
@@ -18,6 +19,7 @@ import (
 	"flamingo.me/flamingo-commerce/v3/checkout/domain/placeorder/process"
 	"flamingo.me/flamingo-commerce/v3/checkout/domain/placeorder/states"
 	"flamingo.me/flamingo-commerce/v3/checkout/infrastructure/locker"
+	"flamingo.me/flamingo-commerce/v3/checkout/interfaces/cli"
 	"flamingo.me/flamingo-commerce/v3/checkout/interfaces/controller"
 	"flamingo.me/flamingo-commerce/v3/checkout/interfaces/graphql"
 )
@@ -27,6 +29,7 @@ type (
 	Module struct {
 		PlaceOrderLockType     string `inject:"config:commerce.checkout.placeorder.lock.type"`
 		PlaceOrderContextStore string `inject:"config:commerce.checkout.placeorder.contextstore.type"`
+		StateGraphController   bool   `inject:"config:commerce.checkout.placeorder.graph.debugController"`
 	}
 )
 
@@ -52,6 +55,14 @@ func (m *Module) Configure(injector *dingo.Injector) {
 
 	injector.Bind(new(process.PaymentValidatorFunc)).ToInstance(placeorder.PaymentValidator)
 
+	// singleton, the sweeper fails timed out processes in the background
+	injector.Bind(new(placeorder.TimeoutSweeper)).In(dingo.Singleton)
+	flamingo.BindEventSubscriber(injector).To(new(placeorder.TimeoutSweeper))
+
+	// the state graph is validated on server start
+	flamingo.BindEventSubscriber(injector).To(new(process.GraphCheck))
+	injector.BindMulti(new(cobra.Command)).ToProvider(cli.PlaceOrderGraphCmd)
+
 	injector.Bind(new(process.State)).AnnotatedWith("startState").To(states.New{})
 	injector.Bind(new(process.State)).AnnotatedWith("failedState").To(states.Failed{})
 	injector.BindMap(new(process.State), new(states.New).Name()).To(states.New{})
@@ -96,6 +107,10 @@ func (m *Module) Configure(injector *dingo.Injector) {
 	web.BindRoutes(injector, new(routes))
 	web.BindRoutes(injector, new(apiRoutes))
 
+	if m.StateGraphController {
+		web.BindRoutes(injector, new(stateGraphRoutes))
+	}
+
 	injector.BindMulti(new(flamingographql.Service)).To(graphql.Service{})
 }
 
@@ -144,6 +159,19 @@ commerce: checkout: {
 				cancelOrdersDuringRollback: bool | *false		
 			}
 		}
+		timeouts: {
+			// timeouts of the states by state name, e.g. WaitForCustomer: "30m"
+			states: {
+				[string]: string
+			}
+			sweepInterval: string | *"1m"
+		}
+		graph: {
+			// exposes the state graph on /checkout/placeorder/graph, don't enable it in production
+			debugController: bool | *false
+			// starts with an invalid state graph, which is then only logged
+			allowInvalid: bool | *false
+		}
 	}
 }`
 }
@@ -219,6 +247,9 @@ func (r *apiRoutes) Routes(registry *web.RouterRegistry) {
 	registry.HandlePut("checkout.api.placeorder", r.apiController.StartPlaceOrderAction)
 	registry.HandleDelete("checkout.api.placeorder", r.apiController.ClearPlaceOrderAction)
 
+	registry.MustRoute("/api/v1/checkout/quotes/:id/placeorder", "checkout.api.quote.placeorder(id)")
+	registry.HandlePut("checkout.api.quote.placeorder", r.apiController.StartQuotePlaceOrderAction)
+
 	registry.MustRoute("/api/v1/checkout/placeorder/cancel", "checkout.api.placeorder.cancel")
 	registry.HandlePost("checkout.api.placeorder.cancel", r.apiController.CancelPlaceOrderAction)
 
@@ -228,3 +259,16 @@ func (r *apiRoutes) Routes(registry *web.RouterRegistry) {
 	registry.MustRoute("/api/v1/checkout/placeorder/refresh-blocking", "checkout.api.placeorder.refreshblocking")
 	registry.HandlePost("checkout.api.placeorder.refreshblocking", r.apiController.RefreshPlaceOrderBlockingAction)
 }
+
+type stateGraphRoutes struct {
+	controller *controller.StateGraphController
+}
+
+func (r *stateGraphRoutes) Inject(controller *controller.StateGraphController) {
+	r.controller = controller
+}
+
+func (r *stateGraphRoutes) Routes(registry *web.RouterRegistry) {
+	registry.MustRoute("/checkout/placeorder/graph", "checkout.placeorder.graph")
+	registry.HandleGet("checkout.placeorder.graph", r.controller.GraphAction)
+}
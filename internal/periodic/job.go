@@ -0,0 +1,82 @@
+// Package periodic runs background jobs of the commerce modules while the flamingo server is running
+package periodic
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+)
+
+type (
+	// Job calls a function in a fixed interval, it is started by the flamingo.ServerStartEvent and stopped by the
+	// flamingo.ServerShutdownEvent. Errors of the function are logged, the next run happens anyway.
+	Job struct {
+		interval time.Duration
+		run      func(ctx context.Context) error
+		logger   flamingo.Logger
+		mutex    sync.Mutex
+		stop     chan struct{}
+	}
+)
+
+var _ flamingo.Subscriber = &Job{}
+
+// NewJob creates a job which calls run every interval once started, a job with an interval <= 0 never starts
+func NewJob(interval time.Duration, run func(ctx context.Context) error, logger flamingo.Logger) *Job {
+	return &Job{
+		interval: interval,
+		run:      run,
+		logger:   logger,
+	}
+}
+
+// Notify starts the job on server start and stops it on shutdown
+func (j *Job) Notify(_ context.Context, event flamingo.Event) {
+	switch event.(type) {
+	case *flamingo.ServerStartEvent:
+		j.Start()
+	case *flamingo.ServerShutdownEvent:
+		j.Stop()
+	}
+}
+
+// Start runs the job in the background, starting a running job has no effect
+func (j *Job) Start() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if j.stop != nil || j.interval <= 0 {
+		return
+	}
+
+	j.stop = make(chan struct{})
+
+	go func(stop <-chan struct{}) {
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := j.run(context.Background()); err != nil {
+					j.logger.Error(err)
+				}
+			}
+		}
+	}(j.stop)
+}
+
+// Stop stops the job, a run in progress is finished
+func (j *Job) Stop() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if j.stop != nil {
+		close(j.stop)
+		j.stop = nil
+	}
+}
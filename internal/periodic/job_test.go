@@ -0,0 +1,54 @@
+package periodic_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"github.com/stretchr/testify/assert"
+
+	"flamingo.me/flamingo-commerce/v3/internal/periodic"
+)
+
+func TestJob(t *testing.T) {
+	t.Parallel()
+
+	t.Run("runs between server start and shutdown", func(t *testing.T) {
+		t.Parallel()
+
+		var runs atomic.Int32
+		job := periodic.NewJob(time.Millisecond, func(context.Context) error {
+			runs.Add(1)
+
+			return errors.New("errors are logged")
+		}, flamingo.NullLogger{})
+
+		job.Notify(context.Background(), &flamingo.ServerStartEvent{})
+		job.Notify(context.Background(), &flamingo.ServerStartEvent{})
+		assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, time.Millisecond, "the job keeps running after an error")
+
+		job.Notify(context.Background(), &flamingo.ServerShutdownEvent{})
+		stopped := runs.Load()
+		time.Sleep(10 * time.Millisecond)
+		assert.LessOrEqual(t, runs.Load(), stopped+1, "at most the run in progress finishes after the shutdown")
+	})
+
+	t.Run("job without interval never runs", func(t *testing.T) {
+		t.Parallel()
+
+		var runs atomic.Int32
+		job := periodic.NewJob(0, func(context.Context) error {
+			runs.Add(1)
+
+			return nil
+		}, flamingo.NullLogger{})
+
+		job.Start()
+		time.Sleep(10 * time.Millisecond)
+		job.Stop()
+		assert.Equal(t, int32(0), runs.Load())
+	})
+}
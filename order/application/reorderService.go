@@ -0,0 +1,214 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"flamingo.me/flamingo/v3/core/auth"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+	"go.opencensus.io/trace"
+
+	cartApplication "flamingo.me/flamingo-commerce/v3/cart/application"
+	cartDomain "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/validation"
+	"flamingo.me/flamingo-commerce/v3/order/domain"
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+	productDomain "flamingo.me/flamingo-commerce/v3/product/domain"
+)
+
+type (
+	// ReorderService adds the items of a previous order of the authenticated customer to the current cart
+	ReorderService struct {
+		orderService       domain.CustomerIdentityOrderService
+		productService     productDomain.ProductService
+		cartService        *cartApplication.CartService
+		webIdentityService *auth.WebIdentityService
+		logger             flamingo.Logger
+		compareNetPrice    bool
+	}
+
+	// ReorderLineStatus describes what happened with an order item during the reorder
+	ReorderLineStatus string
+
+	// ReorderLine is the report of a single order item
+	ReorderLine struct {
+		OrderItem *domain.OrderItem
+		Status    ReorderLineStatus
+		// OrderedPrice and CurrentPrice are set if the product is still available
+		OrderedPrice *priceDomain.Price
+		CurrentPrice *priceDomain.Price
+		// RestrictionResult is set if the qty exceeds the allowed qty of the cart restrictions
+		RestrictionResult *validation.RestrictionResult
+		// Error contains the reason why the item has not been added
+		Error string
+	}
+
+	// ReorderResult is the report of a reorder
+	ReorderResult struct {
+		OrderID string
+		Lines   []ReorderLine
+	}
+)
+
+const (
+	// ReorderLineStatusAdded the item has been added to the cart with an unchanged price
+	ReorderLineStatusAdded ReorderLineStatus = "added"
+	// ReorderLineStatusPriceChanged the item has been added to the cart, but the current price differs from the ordered price
+	ReorderLineStatusPriceChanged ReorderLineStatus = "priceChanged"
+	// ReorderLineStatusUnavailable the product does not exist anymore or is not saleable
+	ReorderLineStatusUnavailable ReorderLineStatus = "unavailable"
+	// ReorderLineStatusNotAdded the product is available but has been rejected by the cart (e.g. qty restrictions)
+	ReorderLineStatusNotAdded ReorderLineStatus = "notAdded"
+)
+
+var (
+	// ErrReorderNotAuthenticated is returned if there is no authenticated customer
+	ErrReorderNotAuthenticated = errors.New("reorder is only possible for authenticated customers")
+)
+
+// Inject dependencies
+func (rs *ReorderService) Inject(
+	orderService domain.CustomerIdentityOrderService,
+	productService productDomain.ProductService,
+	cartService *cartApplication.CartService,
+	webIdentityService *auth.WebIdentityService,
+	logger flamingo.Logger,
+	config *struct {
+		CompareNetPrice bool `inject:"config:commerce.order.reorder.compareNetPrice,optional"`
+	},
+) *ReorderService {
+	rs.orderService = orderService
+	rs.productService = productService
+	rs.cartService = cartService
+	rs.webIdentityService = webIdentityService
+	rs.logger = logger.WithField(flamingo.LogKeyModule, "order").WithField(flamingo.LogKeyCategory, "application.reorderService")
+
+	if config != nil {
+		rs.compareNetPrice = config.CompareNetPrice
+	}
+
+	return rs
+}
+
+// Reorder adds the items of the given order of the authenticated customer to the current cart.
+// Availability and price of every item are checked again, the result reports the outcome of every order item.
+func (rs *ReorderService) Reorder(ctx context.Context, session *web.Session, orderID string, deliveryCode string) (*ReorderResult, error) {
+	ctx, span := trace.StartSpan(ctx, "order/ReorderService/Reorder")
+	defer span.End()
+
+	identity := rs.webIdentityService.Identify(ctx, web.RequestFromContext(ctx))
+	if identity == nil {
+		return nil, ErrReorderNotAuthenticated
+	}
+
+	order, err := rs.orderService.GetByID(ctx, identity, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("ReorderService: error loading order %q: %w", orderID, err)
+	}
+
+	result := &ReorderResult{
+		OrderID: order.ID,
+		Lines:   make([]ReorderLine, len(order.OrderItems)),
+	}
+
+	addRequests := make([]cartDomain.DeliveryAddRequest, 0, len(order.OrderItems))
+	addedLines := make([]int, 0, len(order.OrderItems))
+
+	for i, item := range order.OrderItems {
+		result.Lines[i] = rs.checkItem(ctx, item)
+		if result.Lines[i].Status == ReorderLineStatusUnavailable {
+			continue
+		}
+
+		addRequests = append(addRequests, cartDomain.DeliveryAddRequest{
+			DeliveryCode: deliveryCode,
+			AddRequest: cartDomain.AddRequest{
+				MarketplaceCode:        item.MarketplaceCode,
+				VariantMarketplaceCode: item.VariantMarketplaceCode,
+				Qty:                    int(math.Round(item.Qty)),
+				SourceID:               item.SourceID,
+			},
+		})
+		addedLines = append(addedLines, i)
+	}
+
+	if len(addRequests) == 0 {
+		return result, nil
+	}
+
+	bulkResults, err := rs.cartService.AddProductsBulk(ctx, session, addRequests)
+	if err != nil {
+		return nil, err
+	}
+
+	for n, bulkResult := range bulkResults {
+		line := &result.Lines[addedLines[n]]
+
+		if bulkResult.Status != cartApplication.BulkAddStatusSuccess {
+			line.Status = ReorderLineStatusNotAdded
+			line.RestrictionResult = bulkResult.RestrictionResult
+			line.Error = bulkResult.Error
+		}
+	}
+
+	return result, nil
+}
+
+// checkItem checks if the product of the order item is still saleable and compares the prices
+func (rs *ReorderService) checkItem(ctx context.Context, item *domain.OrderItem) ReorderLine {
+	line := ReorderLine{
+		OrderItem: item,
+		Status:    ReorderLineStatusAdded,
+	}
+
+	if item.Qty < 1 {
+		line.Status = ReorderLineStatusUnavailable
+		line.Error = fmt.Sprintf("invalid qty %v", item.Qty)
+
+		return line
+	}
+
+	product, err := rs.productService.Get(ctx, item.MarketplaceCode)
+	if err != nil {
+		line.Status = ReorderLineStatusUnavailable
+		line.Error = err.Error()
+
+		return line
+	}
+
+	if configurable, ok := product.(productDomain.ConfigurableProduct); ok && item.VariantMarketplaceCode != "" {
+		product, err = configurable.GetConfigurableWithActiveVariant(item.VariantMarketplaceCode)
+		if err != nil {
+			line.Status = ReorderLineStatusUnavailable
+			line.Error = err.Error()
+
+			return line
+		}
+	}
+
+	if !product.IsSaleable() || !product.SaleableData().IsSaleableNow() {
+		line.Status = ReorderLineStatusUnavailable
+		line.Error = "product is not saleable"
+
+		return line
+	}
+
+	orderedAmount := item.SinglePriceInclTax
+	if rs.compareNetPrice {
+		orderedAmount = item.SinglePrice
+	}
+
+	orderedPrice := priceDomain.NewFromFloat(orderedAmount, item.CurrencyCode).GetPayable()
+	currentPrice := product.SaleableData().ActivePrice.GetFinalPrice().GetPayable()
+	line.OrderedPrice = &orderedPrice
+	line.CurrentPrice = &currentPrice
+
+	if !orderedPrice.Equal(currentPrice) {
+		line.Status = ReorderLineStatusPriceChanged
+	}
+
+	return line
+}
@@ -0,0 +1,151 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"flamingo.me/flamingo/v3/core/auth"
+	authMock "flamingo.me/flamingo/v3/core/auth/mock"
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cartApplication "flamingo.me/flamingo-commerce/v3/cart/application"
+	cartDomain "flamingo.me/flamingo-commerce/v3/cart/domain/cart"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/decorator"
+	"flamingo.me/flamingo-commerce/v3/cart/domain/validation"
+	"flamingo.me/flamingo-commerce/v3/cart/infrastructure"
+	"flamingo.me/flamingo-commerce/v3/order/application"
+	"flamingo.me/flamingo-commerce/v3/order/domain"
+	"flamingo.me/flamingo-commerce/v3/product/infrastructure/fake"
+)
+
+type (
+	fakeOrderService struct {
+		order *domain.Order
+	}
+
+	nullEventRouter struct{}
+)
+
+func (f *fakeOrderService) Get(context.Context, auth.Identity) ([]*domain.Order, error) {
+	return []*domain.Order{f.order}, nil
+}
+
+func (f *fakeOrderService) GetByID(context.Context, auth.Identity, string) (*domain.Order, error) {
+	return f.order, nil
+}
+
+func (nullEventRouter) Dispatch(context.Context, flamingo.Event) {}
+
+func newReorderService(t *testing.T, order *domain.Order, identified bool) (*application.ReorderService, *cartApplication.CartService) {
+	t.Helper()
+
+	productService := &fake.ProductService{}
+
+	storage := (&infrastructure.InMemoryCartStorage{}).Inject()
+	behaviour := &infrastructure.DefaultCartBehaviour{}
+	behaviour.Inject(storage, productService, flamingo.NullLogger{}, nil, nil, nil, nil)
+	guestCartService := &infrastructure.DefaultGuestCartService{}
+	guestCartService.Inject(behaviour, flamingo.NullLogger{})
+	customerCartService := &infrastructure.DefaultCustomerCartService{}
+	customerCartService.Inject(behaviour, flamingo.NullLogger{})
+
+	mockIdentifier := new(authMock.Identifier).SetIdentifyMethod(
+		func(identifier *authMock.Identifier, ctx context.Context, request *web.Request) (auth.Identity, error) {
+			if !identified {
+				return nil, auth.ErrNoIdentity
+			}
+
+			return &authMock.Identity{Sub: "customer-1"}, nil
+		},
+	)
+	webIdentityService := new(auth.WebIdentityService).Inject([]auth.RequestIdentifier{mockIdentifier}, nil, nil, nil)
+
+	decoratedCartFactory := &decorator.DecoratedCartFactory{}
+	decoratedCartFactory.Inject(productService, flamingo.NullLogger{}, nil)
+
+	crs := &cartApplication.CartReceiverService{}
+	crs.Inject(guestCartService, customerCartService, decoratedCartFactory, webIdentityService, flamingo.NullLogger{}, nullEventRouter{}, nil)
+
+	deliveryInfoBuilder := &cartDomain.DefaultDeliveryInfoBuilder{}
+	deliveryInfoBuilder.Inject(flamingo.NullLogger{}, nil)
+
+	cs := &cartApplication.CartService{}
+	cs.Inject(
+		crs,
+		productService,
+		nil,
+		nullEventRouter{},
+		deliveryInfoBuilder,
+		new(validation.RestrictionService).Inject(nil),
+		webIdentityService,
+		flamingo.NullLogger{},
+		&struct {
+			DefaultDeliveryCode string `inject:"config:commerce.cart.defaultDeliveryCode,optional"`
+			DeleteEmptyDelivery bool   `inject:"config:commerce.cart.deleteEmptyDelivery,optional"`
+		}{
+			DefaultDeliveryCode: "delivery",
+		},
+		nil,
+	)
+
+	reorderService := new(application.ReorderService).Inject(&fakeOrderService{order: order}, productService, cs, webIdentityService, flamingo.NullLogger{}, nil)
+
+	return reorderService, cs
+}
+
+func TestReorderService_Reorder(t *testing.T) {
+	t.Parallel()
+
+	order := &domain.Order{
+		ID: "order-1",
+		OrderItems: []*domain.OrderItem{
+			{MarketplaceCode: "fake_fixed_simple_without_discounts", Qty: 2, CurrencyCode: "EUR", SinglePriceInclTax: 20.99, SourceID: "source-1"},
+			{MarketplaceCode: "fake_simple_with_fixed_price", Qty: 1, CurrencyCode: "EUR", SinglePriceInclTax: 12.99},
+			{MarketplaceCode: "unknown", Qty: 1, CurrencyCode: "EUR", SinglePriceInclTax: 5},
+		},
+	}
+
+	t.Run("items are added and reported", func(t *testing.T) {
+		t.Parallel()
+
+		service, cartService := newReorderService(t, order, true)
+		session := web.EmptySession()
+
+		result, err := service.Reorder(context.Background(), session, "order-1", "")
+		require.NoError(t, err)
+
+		assert.Equal(t, "order-1", result.OrderID)
+		require.Len(t, result.Lines, 3)
+
+		assert.Equal(t, application.ReorderLineStatusAdded, result.Lines[0].Status)
+		assert.Equal(t, application.ReorderLineStatusPriceChanged, result.Lines[1].Status)
+		require.NotNil(t, result.Lines[1].CurrentPrice)
+		assert.Equal(t, 10.49, result.Lines[1].CurrentPrice.FloatAmount())
+		assert.Equal(t, application.ReorderLineStatusUnavailable, result.Lines[2].Status)
+		assert.NotEmpty(t, result.Lines[2].Error)
+
+		cart, err := cartService.GetCartReceiverService().ViewCart(context.Background(), session)
+		require.NoError(t, err)
+		assert.Equal(t, 3, cart.ItemCount())
+
+		delivery, found := cart.GetDeliveryByCode("delivery")
+		require.True(t, found)
+		for _, item := range delivery.Cartitems {
+			if item.MarketplaceCode == "fake_fixed_simple_without_discounts" {
+				assert.Equal(t, "source-1", item.SourceID)
+			}
+		}
+	})
+
+	t.Run("reorder requires an authenticated customer", func(t *testing.T) {
+		t.Parallel()
+
+		service, _ := newReorderService(t, order, false)
+
+		_, err := service.Reorder(context.Background(), web.EmptySession(), "order-1", "")
+		assert.ErrorIs(t, err, application.ErrReorderNotAuthenticated)
+	})
+}
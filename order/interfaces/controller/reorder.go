@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"flamingo.me/flamingo/v3/framework/flamingo"
+	"flamingo.me/flamingo/v3/framework/web"
+	"go.opencensus.io/trace"
+
+	"flamingo.me/flamingo-commerce/v3/order/application"
+)
+
+type (
+	// ReorderAPIController adds the items of a previous order to the current cart
+	ReorderAPIController struct {
+		responder      *web.Responder
+		reorderService *application.ReorderService
+		logger         flamingo.Logger
+	}
+
+	// errorResponse format
+	errorResponse struct {
+		Code    string
+		Message string
+	} // @name orderError
+)
+
+// Inject dependencies
+func (c *ReorderAPIController) Inject(
+	responder *web.Responder,
+	reorderService *application.ReorderService,
+	logger flamingo.Logger,
+) *ReorderAPIController {
+	c.responder = responder
+	c.reorderService = reorderService
+	c.logger = logger.WithField(flamingo.LogKeyModule, "order").WithField(flamingo.LogKeyCategory, "reorderapicontroller")
+
+	return c
+}
+
+// ReorderAction adds the items of the order to the current cart
+// @Summary Add the items of a previous order of the authenticated customer to the current cart
+// @Description Availability and price of every item are checked again, the result contains the outcome of every order item
+// @Tags Order
+// @Produce json
+// @Success 200 {object} application.ReorderResult
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Param orderID path string true "the id of the order"
+// @Param deliveryCode query string false "optional delivery code the items are added to, the default delivery if empty"
+// @Router /api/v1/order/{orderID}/reorder [post]
+func (c *ReorderAPIController) ReorderAction(ctx context.Context, r *web.Request) web.Result {
+	ctx, span := trace.StartSpan(ctx, "order/ReorderAPIController/ReorderAction")
+	defer span.End()
+
+	result, err := c.reorderService.Reorder(ctx, r.Session(), r.Params["orderID"], r.Params["deliveryCode"])
+	if err != nil {
+		if errors.Is(err, application.ErrReorderNotAuthenticated) {
+			return c.responder.Data(errorResponse{Code: "401", Message: err.Error()}).Status(http.StatusUnauthorized)
+		}
+
+		c.logger.WithContext(ctx).Error("order.reorderapicontroller.reorder: %v", err.Error())
+
+		return c.responder.Data(errorResponse{Code: "500", Message: err.Error()}).Status(http.StatusInternalServerError)
+	}
+
+	return c.responder.Data(result)
+}
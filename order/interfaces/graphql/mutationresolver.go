@@ -0,0 +1,54 @@
+package graphql
+
+import (
+	"context"
+
+	"flamingo.me/flamingo/v3/framework/web"
+
+	cartGraphql "flamingo.me/flamingo-commerce/v3/cart/interfaces/graphql"
+	"flamingo.me/flamingo-commerce/v3/order/application"
+	"flamingo.me/flamingo-commerce/v3/order/interfaces/graphql/orderdto"
+)
+
+// CommerceOrderMutationResolver resolves order mutations
+type CommerceOrderMutationResolver struct {
+	reorderService    *application.ReorderService
+	cartQueryResolver *cartGraphql.CommerceCartQueryResolver
+}
+
+// Inject dependencies
+func (r *CommerceOrderMutationResolver) Inject(
+	reorderService *application.ReorderService,
+	cartQueryResolver *cartGraphql.CommerceCartQueryResolver,
+) *CommerceOrderMutationResolver {
+	r.reorderService = reorderService
+	r.cartQueryResolver = cartQueryResolver
+
+	return r
+}
+
+// CommerceOrderReorder adds the items of a previous order to the current cart
+func (r *CommerceOrderMutationResolver) CommerceOrderReorder(ctx context.Context, orderID string, deliveryCode *string) (*orderdto.ReorderResult, error) {
+	req := web.RequestFromContext(ctx)
+
+	code := ""
+	if deliveryCode != nil {
+		code = *deliveryCode
+	}
+
+	result, err := r.reorderService.Reorder(ctx, req.Session(), orderID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	decoratedCart, err := r.cartQueryResolver.CommerceCart(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &orderdto.ReorderResult{
+		OrderID:       result.OrderID,
+		Lines:         orderdto.MapReorderLines(result.Lines),
+		DecoratedCart: decoratedCart,
+	}, nil
+}
@@ -0,0 +1,52 @@
+package orderdto
+
+import (
+	"math"
+
+	"flamingo.me/flamingo-commerce/v3/cart/domain/validation"
+	"flamingo.me/flamingo-commerce/v3/cart/interfaces/graphql/dto"
+	"flamingo.me/flamingo-commerce/v3/order/application"
+	"flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+type (
+	// ReorderResult is the result of the Commerce_Order_Reorder mutation
+	ReorderResult struct {
+		OrderID       string
+		Lines         []ReorderLine
+		DecoratedCart *dto.DecoratedCart
+	}
+
+	// ReorderLine is the GraphQL representation of application.ReorderLine
+	ReorderLine struct {
+		MarketplaceCode        string
+		VariantMarketplaceCode string
+		Qty                    int
+		SourceID               string
+		Status                 string
+		OrderedPrice           *domain.Price
+		CurrentPrice           *domain.Price
+		RestrictionResult      *validation.RestrictionResult
+		Error                  string
+	}
+)
+
+// MapReorderLines maps the lines of application.ReorderResult
+func MapReorderLines(lines []application.ReorderLine) []ReorderLine {
+	result := make([]ReorderLine, 0, len(lines))
+	for _, line := range lines {
+		result = append(result, ReorderLine{
+			MarketplaceCode:        line.OrderItem.MarketplaceCode,
+			VariantMarketplaceCode: line.OrderItem.VariantMarketplaceCode,
+			Qty:                    int(math.Round(line.OrderItem.Qty)),
+			SourceID:               line.OrderItem.SourceID,
+			Status:                 string(line.Status),
+			OrderedPrice:           line.OrderedPrice,
+			CurrentPrice:           line.CurrentPrice,
+			RestrictionResult:      line.RestrictionResult,
+			Error:                  line.Error,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,30 @@
+package graphql
+
+import (
+	// embed schema.graphql
+	_ "embed"
+
+	"flamingo.me/graphql"
+
+	"flamingo.me/flamingo-commerce/v3/order/interfaces/graphql/orderdto"
+)
+
+// Service is the Graphql-Service of this module
+type Service struct{}
+
+var _ graphql.Service = new(Service)
+
+//go:embed schema.graphql
+var schema []byte
+
+// Schema returns graphql schema of this module
+func (*Service) Schema() []byte {
+	return schema
+}
+
+// Types configures the GraphQL to Go resolvers
+func (*Service) Types(types *graphql.Types) {
+	types.Map("Commerce_Order_ReorderResult", orderdto.ReorderResult{})
+	types.Map("Commerce_Order_ReorderLine", orderdto.ReorderLine{})
+	types.Resolve("Mutation", "Commerce_Order_Reorder", CommerceOrderMutationResolver{}, "CommerceOrderReorder")
+}
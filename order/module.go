@@ -2,10 +2,13 @@ package order
 
 import (
 	"flamingo.me/dingo"
+	"flamingo.me/flamingo-commerce/v3/cart"
 	"flamingo.me/flamingo-commerce/v3/order/domain"
 	"flamingo.me/flamingo-commerce/v3/order/infrastructure/fake"
 	"flamingo.me/flamingo-commerce/v3/order/interfaces/controller"
+	"flamingo.me/flamingo-commerce/v3/order/interfaces/graphql"
 	"flamingo.me/flamingo/v3/framework/web"
+	flamingographql "flamingo.me/graphql"
 )
 
 type (
@@ -39,19 +42,32 @@ func (m *Module) Configure(injector *dingo.Injector) {
 	}
 
 	injector.Bind((*domain.OrderDecoratorInterface)(nil)).To(domain.OrderDecorator{})
+	injector.BindMulti(new(flamingographql.Service)).To(graphql.Service{})
 	web.BindRoutes(injector, new(routes))
 }
 
+// Depends on other modules
+func (m *Module) Depends() []dingo.Module {
+	return []dingo.Module{
+		new(cart.Module),
+	}
+}
+
 type routes struct {
-	controller *controller.DataControllerCustomerOrders
+	controller        *controller.DataControllerCustomerOrders
+	reorderController *controller.ReorderAPIController
 }
 
-func (r *routes) Inject(controller *controller.DataControllerCustomerOrders) {
+func (r *routes) Inject(controller *controller.DataControllerCustomerOrders, reorderController *controller.ReorderAPIController) {
 	r.controller = controller
+	r.reorderController = reorderController
 }
 
 func (r *routes) Routes(registry *web.RouterRegistry) {
 	registry.HandleData("customerorders", r.controller.Data)
+
+	registry.MustRoute("/api/v1/order/:orderID/reorder", `order.api.reorder(orderID, deliveryCode?="")`)
+	registry.HandlePost("order.api.reorder", r.reorderController.ReorderAction)
 }
 
 // FlamingoLegacyConfigAlias maps legacy config entries to new ones
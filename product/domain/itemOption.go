@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"unicode/utf8"
+
+	priceDomain "flamingo.me/flamingo-commerce/v3/price/domain"
+)
+
+type (
+	// ItemOption is a personalisation option of a product, e.g. engraving, gift wrap or monogram
+	ItemOption struct {
+		// Code identifies the option
+		Code string
+		// Label is the speaking name of the option
+		Label string
+		// Required options must be chosen when the product is added to the cart
+		Required bool
+		// MaxLength limits the length of free text values, 0 means no limit
+		MaxLength int
+		// Values is the list of allowed values, an empty list allows free text
+		Values []string
+		// Surcharge is added to the single price of the item if the option is chosen
+		Surcharge priceDomain.Price
+	}
+)
+
+// ItemOption returns the item option with the given code
+func (bpd BasicProductData) ItemOption(code string) (ItemOption, bool) {
+	for _, option := range bpd.ItemOptions {
+		if option.Code == code {
+			return option, true
+		}
+	}
+
+	return ItemOption{}, false
+}
+
+// IsValidValue checks if the value is allowed for the option
+func (o ItemOption) IsValidValue(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	if o.MaxLength > 0 && utf8.RuneCountInString(value) > o.MaxLength {
+		return false
+	}
+
+	if len(o.Values) == 0 {
+		return true
+	}
+
+	for _, allowed := range o.Values {
+		if allowed == value {
+			return true
+		}
+	}
+
+	return false
+}
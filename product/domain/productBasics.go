@@ -76,6 +76,9 @@ type (
 
 		Keywords []string
 		IsNew    bool
+
+		// ItemOptions are the personalisation options that can be chosen when the product is added to the cart
+		ItemOptions []ItemOption
 	}
 
 	// CategoryTeaser represents some Teaser infos for Category
@@ -7,7 +7,9 @@ package graphql
 
 import (
 	"context"
+	"time"
 
+	"flamingo.me/flamingo-commerce/v3/cart/application"
 	"flamingo.me/flamingo-commerce/v3/cart/domain/cart"
 	"flamingo.me/flamingo-commerce/v3/cart/domain/validation"
 	"flamingo.me/flamingo-commerce/v3/cart/interfaces/controller/forms"
@@ -20,6 +22,8 @@ import (
 	dto1 "flamingo.me/flamingo-commerce/v3/checkout/interfaces/graphql/dto"
 	graphql6 "flamingo.me/flamingo-commerce/v3/customer/interfaces/graphql"
 	"flamingo.me/flamingo-commerce/v3/customer/interfaces/graphql/dtocustomer"
+	graphql8 "flamingo.me/flamingo-commerce/v3/order/interfaces/graphql"
+	"flamingo.me/flamingo-commerce/v3/order/interfaces/graphql/orderdto"
 	domain1 "flamingo.me/flamingo-commerce/v3/price/domain"
 	"flamingo.me/flamingo-commerce/v3/product/domain"
 	graphql2 "flamingo.me/flamingo-commerce/v3/product/interfaces/graphql"
@@ -220,32 +224,44 @@ func (r *rootResolverCommerce_Search_Meta) SortOptions(ctx context.Context, obj
 
 type rootResolverMutation struct {
 	resolveFlamingo                                   func(ctx context.Context) (*string, error)
-	resolveCommerceCartAddToCart                      func(ctx context.Context, addToCartInput dto.AddToCart) (*dto.DecoratedCart, error)
-	resolveCommerceCartDeleteCartDelivery             func(ctx context.Context, deliveryCode string) (*dto.DecoratedCart, error)
-	resolveCommerceCartDeleteItem                     func(ctx context.Context, itemID string, deliveryCode string) (*dto.DecoratedCart, error)
-	resolveCommerceCartUpdateItemQty                  func(ctx context.Context, itemID string, deliveryCode string, qty int) (*dto.DecoratedCart, error)
-	resolveCommerceCartUpdateItemBundleConfig         func(ctx context.Context, itemID string, bundleConfig []*dto.ChoiceConfiguration) (*dto.DecoratedCart, error)
+	resolveCommerceCartAddToCart                      func(ctx context.Context, addToCartInput dto.AddToCart, revision *int) (*dto.DecoratedCart, error)
+	resolveCommerceCartAddToCartBulk                  func(ctx context.Context, addToCartInputs []dto.AddToCart, revision *int) (*dto.AddToCartBulkResult, error)
+	resolveCommerceCartDeleteCartDelivery             func(ctx context.Context, deliveryCode string, revision *int) (*dto.DecoratedCart, error)
+	resolveCommerceCartDeleteItem                     func(ctx context.Context, itemID string, deliveryCode string, revision *int) (*dto.DecoratedCart, error)
+	resolveCommerceCartUpdateItemQty                  func(ctx context.Context, itemID string, deliveryCode string, qty int, revision *int) (*dto.DecoratedCart, error)
+	resolveCommerceCartUpdateItemBundleConfig         func(ctx context.Context, itemID string, bundleConfig []*dto.ChoiceConfiguration, revision *int) (*dto.DecoratedCart, error)
 	resolveCommerceCartUpdateBillingAddress           func(ctx context.Context, addressForm *forms.AddressForm) (*dto.BillingAddressForm, error)
 	resolveCommerceCartUpdatePersonalData             func(ctx context.Context, personalData *forms.DefaultPersonalDataForm) (*dto.PersonalDataForm, error)
 	resolveCommerceCartUpdateSelectedPayment          func(ctx context.Context, gateway string, method string) (*dto.SelectedPaymentResult, error)
-	resolveCommerceCartApplyCouponCodeOrGiftCard      func(ctx context.Context, code string) (*dto.DecoratedCart, error)
-	resolveCommerceCartRemoveGiftCard                 func(ctx context.Context, giftCardCode string) (*dto.DecoratedCart, error)
-	resolveCommerceCartRemoveCouponCode               func(ctx context.Context, couponCode string) (*dto.DecoratedCart, error)
+	resolveCommerceCartApplyCouponCodeOrGiftCard      func(ctx context.Context, code string, revision *int) (*dto.DecoratedCart, error)
+	resolveCommerceCartRemoveGiftCard                 func(ctx context.Context, giftCardCode string, revision *int) (*dto.DecoratedCart, error)
+	resolveCommerceCartRemoveCouponCode               func(ctx context.Context, couponCode string, revision *int) (*dto.DecoratedCart, error)
 	resolveCommerceCartUpdateDeliveryAddresses        func(ctx context.Context, deliveryAdresses []*forms.DeliveryForm) ([]*dto.DeliveryAddressForm, error)
 	resolveCommerceCartUpdateDeliveryShippingOptions  func(ctx context.Context, shippingOptions []*dto.DeliveryShippingOption) (*dto.UpdateShippingOptionsResult, error)
 	resolveCommerceCartClean                          func(ctx context.Context) (bool, error)
-	resolveCommerceCartUpdateAdditionalData           func(ctx context.Context, additionalData []*dto.KeyValue) (*dto.DecoratedCart, error)
-	resolveCommerceCartUpdateDeliveriesAdditionalData func(ctx context.Context, data []*dto.DeliveryAdditionalData) (*dto.DecoratedCart, error)
+	resolveCommerceCartUpdateAdditionalData           func(ctx context.Context, additionalData []*dto.KeyValue, revision *int) (*dto.DecoratedCart, error)
+	resolveCommerceCartUpdateDeliveriesAdditionalData func(ctx context.Context, data []*dto.DeliveryAdditionalData, revision *int) (*dto.DecoratedCart, error)
+	resolveCommerceCartCreateCustomerCart             func(ctx context.Context, name string) (*cart.Cart, error)
+	resolveCommerceCartRenameCustomerCart             func(ctx context.Context, cartID string, name string) (*cart.Cart, error)
+	resolveCommerceCartSwitchCustomerCart             func(ctx context.Context, cartID string) (*dto.DecoratedCart, error)
+	resolveCommerceCartMoveItem                       func(ctx context.Context, itemID string, deliveryCode string, targetCartID string, revision *int, targetDeliveryCode *string) (*dto.DecoratedCart, error)
+	resolveCommerceCartCreateQuote                    func(ctx context.Context, customerID string, expiresAt *time.Time) (*dto.Quote, error)
+	resolveCommerceCartUpdateQuoteItemPrice           func(ctx context.Context, quoteID string, itemID string, price float64, currency *string) (*dto.Quote, error)
+	resolveCommerceCartSendQuote                      func(ctx context.Context, quoteID string) (*dto.Quote, error)
+	resolveCommerceCartAcceptQuote                    func(ctx context.Context, quoteID string) (*dto.Quote, error)
 	resolveCommerceCheckoutStartPlaceOrder            func(ctx context.Context, returnURL string) (*dto1.StartPlaceOrderResult, error)
+	resolveCommerceCheckoutStartPlaceOrderForQuote    func(ctx context.Context, quoteID string, returnURL string) (*dto1.StartPlaceOrderResult, error)
 	resolveCommerceCheckoutCancelPlaceOrder           func(ctx context.Context) (bool, error)
 	resolveCommerceCheckoutClearPlaceOrder            func(ctx context.Context) (bool, error)
 	resolveCommerceCheckoutRefreshPlaceOrder          func(ctx context.Context) (*dto1.PlaceOrderContext, error)
 	resolveCommerceCheckoutRefreshPlaceOrderBlocking  func(ctx context.Context) (*dto1.PlaceOrderContext, error)
+	resolveCommerceOrderReorder                       func(ctx context.Context, orderID string, deliveryCode *string) (*orderdto.ReorderResult, error)
 }
 
 func (r *rootResolverMutation) Inject(
 	mutationFlamingo *graphql4.FlamingoQueryResolver,
 	mutationCommerceCartAddToCart *graphql1.CommerceCartMutationResolver,
+	mutationCommerceCartAddToCartBulk *graphql1.CommerceCartMutationResolver,
 	mutationCommerceCartDeleteCartDelivery *graphql1.CommerceCartMutationResolver,
 	mutationCommerceCartDeleteItem *graphql1.CommerceCartMutationResolver,
 	mutationCommerceCartUpdateItemQty *graphql1.CommerceCartMutationResolver,
@@ -261,14 +277,25 @@ func (r *rootResolverMutation) Inject(
 	mutationCommerceCartClean *graphql1.CommerceCartMutationResolver,
 	mutationCommerceCartUpdateAdditionalData *graphql1.CommerceCartMutationResolver,
 	mutationCommerceCartUpdateDeliveriesAdditionalData *graphql1.CommerceCartMutationResolver,
+	mutationCommerceCartCreateCustomerCart *graphql1.CommerceCartMutationResolver,
+	mutationCommerceCartRenameCustomerCart *graphql1.CommerceCartMutationResolver,
+	mutationCommerceCartSwitchCustomerCart *graphql1.CommerceCartMutationResolver,
+	mutationCommerceCartMoveItem *graphql1.CommerceCartMutationResolver,
+	mutationCommerceCartCreateQuote *graphql1.CommerceCartQuoteResolver,
+	mutationCommerceCartUpdateQuoteItemPrice *graphql1.CommerceCartQuoteResolver,
+	mutationCommerceCartSendQuote *graphql1.CommerceCartQuoteResolver,
+	mutationCommerceCartAcceptQuote *graphql1.CommerceCartQuoteResolver,
 	mutationCommerceCheckoutStartPlaceOrder *graphql5.CommerceCheckoutMutationResolver,
+	mutationCommerceCheckoutStartPlaceOrderForQuote *graphql5.CommerceCheckoutMutationResolver,
 	mutationCommerceCheckoutCancelPlaceOrder *graphql5.CommerceCheckoutMutationResolver,
 	mutationCommerceCheckoutClearPlaceOrder *graphql5.CommerceCheckoutMutationResolver,
 	mutationCommerceCheckoutRefreshPlaceOrder *graphql5.CommerceCheckoutMutationResolver,
 	mutationCommerceCheckoutRefreshPlaceOrderBlocking *graphql5.CommerceCheckoutMutationResolver,
+	mutationCommerceOrderReorder *graphql8.CommerceOrderMutationResolver,
 ) {
 	r.resolveFlamingo = mutationFlamingo.Flamingo
 	r.resolveCommerceCartAddToCart = mutationCommerceCartAddToCart.CommerceAddToCart
+	r.resolveCommerceCartAddToCartBulk = mutationCommerceCartAddToCartBulk.CommerceAddToCartBulk
 	r.resolveCommerceCartDeleteCartDelivery = mutationCommerceCartDeleteCartDelivery.CommerceDeleteCartDelivery
 	r.resolveCommerceCartDeleteItem = mutationCommerceCartDeleteItem.CommerceDeleteItem
 	r.resolveCommerceCartUpdateItemQty = mutationCommerceCartUpdateItemQty.CommerceUpdateItemQty
@@ -284,30 +311,43 @@ func (r *rootResolverMutation) Inject(
 	r.resolveCommerceCartClean = mutationCommerceCartClean.CartClean
 	r.resolveCommerceCartUpdateAdditionalData = mutationCommerceCartUpdateAdditionalData.UpdateAdditionalData
 	r.resolveCommerceCartUpdateDeliveriesAdditionalData = mutationCommerceCartUpdateDeliveriesAdditionalData.UpdateDeliveriesAdditionalData
+	r.resolveCommerceCartCreateCustomerCart = mutationCommerceCartCreateCustomerCart.CreateCustomerCart
+	r.resolveCommerceCartRenameCustomerCart = mutationCommerceCartRenameCustomerCart.RenameCustomerCart
+	r.resolveCommerceCartSwitchCustomerCart = mutationCommerceCartSwitchCustomerCart.SwitchCustomerCart
+	r.resolveCommerceCartMoveItem = mutationCommerceCartMoveItem.MoveItem
+	r.resolveCommerceCartCreateQuote = mutationCommerceCartCreateQuote.CommerceCartCreateQuote
+	r.resolveCommerceCartUpdateQuoteItemPrice = mutationCommerceCartUpdateQuoteItemPrice.CommerceCartUpdateQuoteItemPrice
+	r.resolveCommerceCartSendQuote = mutationCommerceCartSendQuote.CommerceCartSendQuote
+	r.resolveCommerceCartAcceptQuote = mutationCommerceCartAcceptQuote.CommerceCartAcceptQuote
 	r.resolveCommerceCheckoutStartPlaceOrder = mutationCommerceCheckoutStartPlaceOrder.CommerceCheckoutStartPlaceOrder
+	r.resolveCommerceCheckoutStartPlaceOrderForQuote = mutationCommerceCheckoutStartPlaceOrderForQuote.CommerceCheckoutStartPlaceOrderForQuote
 	r.resolveCommerceCheckoutCancelPlaceOrder = mutationCommerceCheckoutCancelPlaceOrder.CommerceCheckoutCancelPlaceOrder
 	r.resolveCommerceCheckoutClearPlaceOrder = mutationCommerceCheckoutClearPlaceOrder.CommerceCheckoutClearPlaceOrder
 	r.resolveCommerceCheckoutRefreshPlaceOrder = mutationCommerceCheckoutRefreshPlaceOrder.CommerceCheckoutRefreshPlaceOrder
 	r.resolveCommerceCheckoutRefreshPlaceOrderBlocking = mutationCommerceCheckoutRefreshPlaceOrderBlocking.CommerceCheckoutRefreshPlaceOrderBlocking
+	r.resolveCommerceOrderReorder = mutationCommerceOrderReorder.CommerceOrderReorder
 }
 
 func (r *rootResolverMutation) Flamingo(ctx context.Context) (*string, error) {
 	return r.resolveFlamingo(ctx)
 }
-func (r *rootResolverMutation) CommerceCartAddToCart(ctx context.Context, addToCartInput dto.AddToCart) (*dto.DecoratedCart, error) {
-	return r.resolveCommerceCartAddToCart(ctx, addToCartInput)
+func (r *rootResolverMutation) CommerceCartAddToCart(ctx context.Context, addToCartInput dto.AddToCart, revision *int) (*dto.DecoratedCart, error) {
+	return r.resolveCommerceCartAddToCart(ctx, addToCartInput, revision)
 }
-func (r *rootResolverMutation) CommerceCartDeleteCartDelivery(ctx context.Context, deliveryCode string) (*dto.DecoratedCart, error) {
-	return r.resolveCommerceCartDeleteCartDelivery(ctx, deliveryCode)
+func (r *rootResolverMutation) CommerceCartAddToCartBulk(ctx context.Context, addToCartInputs []dto.AddToCart, revision *int) (*dto.AddToCartBulkResult, error) {
+	return r.resolveCommerceCartAddToCartBulk(ctx, addToCartInputs, revision)
 }
-func (r *rootResolverMutation) CommerceCartDeleteItem(ctx context.Context, itemID string, deliveryCode string) (*dto.DecoratedCart, error) {
-	return r.resolveCommerceCartDeleteItem(ctx, itemID, deliveryCode)
+func (r *rootResolverMutation) CommerceCartDeleteCartDelivery(ctx context.Context, deliveryCode string, revision *int) (*dto.DecoratedCart, error) {
+	return r.resolveCommerceCartDeleteCartDelivery(ctx, deliveryCode, revision)
 }
-func (r *rootResolverMutation) CommerceCartUpdateItemQty(ctx context.Context, itemID string, deliveryCode string, qty int) (*dto.DecoratedCart, error) {
-	return r.resolveCommerceCartUpdateItemQty(ctx, itemID, deliveryCode, qty)
+func (r *rootResolverMutation) CommerceCartDeleteItem(ctx context.Context, itemID string, deliveryCode string, revision *int) (*dto.DecoratedCart, error) {
+	return r.resolveCommerceCartDeleteItem(ctx, itemID, deliveryCode, revision)
 }
-func (r *rootResolverMutation) CommerceCartUpdateItemBundleConfig(ctx context.Context, itemID string, bundleConfig []*dto.ChoiceConfiguration) (*dto.DecoratedCart, error) {
-	return r.resolveCommerceCartUpdateItemBundleConfig(ctx, itemID, bundleConfig)
+func (r *rootResolverMutation) CommerceCartUpdateItemQty(ctx context.Context, itemID string, deliveryCode string, qty int, revision *int) (*dto.DecoratedCart, error) {
+	return r.resolveCommerceCartUpdateItemQty(ctx, itemID, deliveryCode, qty, revision)
+}
+func (r *rootResolverMutation) CommerceCartUpdateItemBundleConfig(ctx context.Context, itemID string, bundleConfig []*dto.ChoiceConfiguration, revision *int) (*dto.DecoratedCart, error) {
+	return r.resolveCommerceCartUpdateItemBundleConfig(ctx, itemID, bundleConfig, revision)
 }
 func (r *rootResolverMutation) CommerceCartUpdateBillingAddress(ctx context.Context, addressForm *forms.AddressForm) (*dto.BillingAddressForm, error) {
 	return r.resolveCommerceCartUpdateBillingAddress(ctx, addressForm)
@@ -318,14 +358,14 @@ func (r *rootResolverMutation) CommerceCartUpdatePersonalData(ctx context.Contex
 func (r *rootResolverMutation) CommerceCartUpdateSelectedPayment(ctx context.Context, gateway string, method string) (*dto.SelectedPaymentResult, error) {
 	return r.resolveCommerceCartUpdateSelectedPayment(ctx, gateway, method)
 }
-func (r *rootResolverMutation) CommerceCartApplyCouponCodeOrGiftCard(ctx context.Context, code string) (*dto.DecoratedCart, error) {
-	return r.resolveCommerceCartApplyCouponCodeOrGiftCard(ctx, code)
+func (r *rootResolverMutation) CommerceCartApplyCouponCodeOrGiftCard(ctx context.Context, code string, revision *int) (*dto.DecoratedCart, error) {
+	return r.resolveCommerceCartApplyCouponCodeOrGiftCard(ctx, code, revision)
 }
-func (r *rootResolverMutation) CommerceCartRemoveGiftCard(ctx context.Context, giftCardCode string) (*dto.DecoratedCart, error) {
-	return r.resolveCommerceCartRemoveGiftCard(ctx, giftCardCode)
+func (r *rootResolverMutation) CommerceCartRemoveGiftCard(ctx context.Context, giftCardCode string, revision *int) (*dto.DecoratedCart, error) {
+	return r.resolveCommerceCartRemoveGiftCard(ctx, giftCardCode, revision)
 }
-func (r *rootResolverMutation) CommerceCartRemoveCouponCode(ctx context.Context, couponCode string) (*dto.DecoratedCart, error) {
-	return r.resolveCommerceCartRemoveCouponCode(ctx, couponCode)
+func (r *rootResolverMutation) CommerceCartRemoveCouponCode(ctx context.Context, couponCode string, revision *int) (*dto.DecoratedCart, error) {
+	return r.resolveCommerceCartRemoveCouponCode(ctx, couponCode, revision)
 }
 func (r *rootResolverMutation) CommerceCartUpdateDeliveryAddresses(ctx context.Context, deliveryAdresses []*forms.DeliveryForm) ([]*dto.DeliveryAddressForm, error) {
 	return r.resolveCommerceCartUpdateDeliveryAddresses(ctx, deliveryAdresses)
@@ -336,15 +376,42 @@ func (r *rootResolverMutation) CommerceCartUpdateDeliveryShippingOptions(ctx con
 func (r *rootResolverMutation) CommerceCartClean(ctx context.Context) (bool, error) {
 	return r.resolveCommerceCartClean(ctx)
 }
-func (r *rootResolverMutation) CommerceCartUpdateAdditionalData(ctx context.Context, additionalData []*dto.KeyValue) (*dto.DecoratedCart, error) {
-	return r.resolveCommerceCartUpdateAdditionalData(ctx, additionalData)
+func (r *rootResolverMutation) CommerceCartUpdateAdditionalData(ctx context.Context, additionalData []*dto.KeyValue, revision *int) (*dto.DecoratedCart, error) {
+	return r.resolveCommerceCartUpdateAdditionalData(ctx, additionalData, revision)
+}
+func (r *rootResolverMutation) CommerceCartUpdateDeliveriesAdditionalData(ctx context.Context, data []*dto.DeliveryAdditionalData, revision *int) (*dto.DecoratedCart, error) {
+	return r.resolveCommerceCartUpdateDeliveriesAdditionalData(ctx, data, revision)
+}
+func (r *rootResolverMutation) CommerceCartCreateCustomerCart(ctx context.Context, name string) (*cart.Cart, error) {
+	return r.resolveCommerceCartCreateCustomerCart(ctx, name)
+}
+func (r *rootResolverMutation) CommerceCartRenameCustomerCart(ctx context.Context, cartID string, name string) (*cart.Cart, error) {
+	return r.resolveCommerceCartRenameCustomerCart(ctx, cartID, name)
+}
+func (r *rootResolverMutation) CommerceCartSwitchCustomerCart(ctx context.Context, cartID string) (*dto.DecoratedCart, error) {
+	return r.resolveCommerceCartSwitchCustomerCart(ctx, cartID)
 }
-func (r *rootResolverMutation) CommerceCartUpdateDeliveriesAdditionalData(ctx context.Context, data []*dto.DeliveryAdditionalData) (*dto.DecoratedCart, error) {
-	return r.resolveCommerceCartUpdateDeliveriesAdditionalData(ctx, data)
+func (r *rootResolverMutation) CommerceCartMoveItem(ctx context.Context, itemID string, deliveryCode string, targetCartID string, revision *int, targetDeliveryCode *string) (*dto.DecoratedCart, error) {
+	return r.resolveCommerceCartMoveItem(ctx, itemID, deliveryCode, targetCartID, revision, targetDeliveryCode)
+}
+func (r *rootResolverMutation) CommerceCartCreateQuote(ctx context.Context, customerID string, expiresAt *time.Time) (*dto.Quote, error) {
+	return r.resolveCommerceCartCreateQuote(ctx, customerID, expiresAt)
+}
+func (r *rootResolverMutation) CommerceCartUpdateQuoteItemPrice(ctx context.Context, quoteID string, itemID string, price float64, currency *string) (*dto.Quote, error) {
+	return r.resolveCommerceCartUpdateQuoteItemPrice(ctx, quoteID, itemID, price, currency)
+}
+func (r *rootResolverMutation) CommerceCartSendQuote(ctx context.Context, quoteID string) (*dto.Quote, error) {
+	return r.resolveCommerceCartSendQuote(ctx, quoteID)
+}
+func (r *rootResolverMutation) CommerceCartAcceptQuote(ctx context.Context, quoteID string) (*dto.Quote, error) {
+	return r.resolveCommerceCartAcceptQuote(ctx, quoteID)
 }
 func (r *rootResolverMutation) CommerceCheckoutStartPlaceOrder(ctx context.Context, returnURL string) (*dto1.StartPlaceOrderResult, error) {
 	return r.resolveCommerceCheckoutStartPlaceOrder(ctx, returnURL)
 }
+func (r *rootResolverMutation) CommerceCheckoutStartPlaceOrderForQuote(ctx context.Context, quoteID string, returnURL string) (*dto1.StartPlaceOrderResult, error) {
+	return r.resolveCommerceCheckoutStartPlaceOrderForQuote(ctx, quoteID, returnURL)
+}
 func (r *rootResolverMutation) CommerceCheckoutCancelPlaceOrder(ctx context.Context) (bool, error) {
 	return r.resolveCommerceCheckoutCancelPlaceOrder(ctx)
 }
@@ -357,20 +424,31 @@ func (r *rootResolverMutation) CommerceCheckoutRefreshPlaceOrder(ctx context.Con
 func (r *rootResolverMutation) CommerceCheckoutRefreshPlaceOrderBlocking(ctx context.Context) (*dto1.PlaceOrderContext, error) {
 	return r.resolveCommerceCheckoutRefreshPlaceOrderBlocking(ctx)
 }
+func (r *rootResolverMutation) CommerceOrderReorder(ctx context.Context, orderID string, deliveryCode *string) (*orderdto.ReorderResult, error) {
+	return r.resolveCommerceOrderReorder(ctx, orderID, deliveryCode)
+}
 
 type rootResolverQuery struct {
-	resolveFlamingo                         func(ctx context.Context) (*string, error)
-	resolveCommerceProduct                  func(ctx context.Context, marketPlaceCode string, variantMarketPlaceCode *string, bundleConfiguration []*graphqlproductdto.ChoiceConfiguration) (graphqlproductdto.Product, error)
-	resolveCommerceProductSearch            func(ctx context.Context, searchRequest searchdto.CommerceSearchRequest) (*graphql2.SearchResultDTO, error)
-	resolveCommerceCustomerStatus           func(ctx context.Context) (*dtocustomer.CustomerStatusResult, error)
-	resolveCommerceCustomer                 func(ctx context.Context) (*dtocustomer.CustomerResult, error)
-	resolveCommerceCartDecoratedCart        func(ctx context.Context) (*dto.DecoratedCart, error)
-	resolveCommerceCartValidator            func(ctx context.Context) (*validation.Result, error)
-	resolveCommerceCartQtyRestriction       func(ctx context.Context, marketplaceCode string, variantCode *string, deliveryCode string) (*validation.RestrictionResult, error)
-	resolveCommerceCheckoutActivePlaceOrder func(ctx context.Context) (bool, error)
-	resolveCommerceCheckoutCurrentContext   func(ctx context.Context) (*dto1.PlaceOrderContext, error)
-	resolveCommerceCategoryTree             func(ctx context.Context, activeCategoryCode string) (domain3.Tree, error)
-	resolveCommerceCategory                 func(ctx context.Context, categoryCode string, categorySearchRequest *searchdto.CommerceSearchRequest) (*categorydto.CategorySearchResult, error)
+	resolveFlamingo                             func(ctx context.Context) (*string, error)
+	resolveCommerceProduct                      func(ctx context.Context, marketPlaceCode string, variantMarketPlaceCode *string, bundleConfiguration []*graphqlproductdto.ChoiceConfiguration) (graphqlproductdto.Product, error)
+	resolveCommerceProductSearch                func(ctx context.Context, searchRequest searchdto.CommerceSearchRequest) (*graphql2.SearchResultDTO, error)
+	resolveCommerceCustomerStatus               func(ctx context.Context) (*dtocustomer.CustomerStatusResult, error)
+	resolveCommerceCustomer                     func(ctx context.Context) (*dtocustomer.CustomerResult, error)
+	resolveCommerceCartDecoratedCart            func(ctx context.Context) (*dto.DecoratedCart, error)
+	resolveCommerceCartValidator                func(ctx context.Context) (*validation.Result, error)
+	resolveCommerceCartQtyRestriction           func(ctx context.Context, marketplaceCode string, variantCode *string, deliveryCode string) (*validation.RestrictionResult, error)
+	resolveCommerceCartCustomerCarts            func(ctx context.Context) ([]*cart.Cart, error)
+	resolveCommerceCartGiftCardBalance          func(ctx context.Context, code string) (*dto.GiftCardBalance, error)
+	resolveCommerceCartAvailableShippingMethods func(ctx context.Context, deliveryCode string) ([]cart.ShippingMethod, error)
+	resolveCommerceCartHistory                  func(ctx context.Context) ([]dto.HistoryEntry, error)
+	resolveCommerceCartLastMergeReport          func(ctx context.Context) (*application.CartMergeReport, error)
+	resolveCommerceCartPreviewBundleConfig      func(ctx context.Context, itemID string, bundleConfig []*dto.ChoiceConfiguration) (*application.BundleConfigPreview, error)
+	resolveCommerceCartQuotes                   func(ctx context.Context, customerID *string) ([]*dto.Quote, error)
+	resolveCommerceCartQuote                    func(ctx context.Context, id string) (*dto.Quote, error)
+	resolveCommerceCheckoutActivePlaceOrder     func(ctx context.Context) (bool, error)
+	resolveCommerceCheckoutCurrentContext       func(ctx context.Context) (*dto1.PlaceOrderContext, error)
+	resolveCommerceCategoryTree                 func(ctx context.Context, activeCategoryCode string) (domain3.Tree, error)
+	resolveCommerceCategory                     func(ctx context.Context, categoryCode string, categorySearchRequest *searchdto.CommerceSearchRequest) (*categorydto.CategorySearchResult, error)
 }
 
 func (r *rootResolverQuery) Inject(
@@ -382,6 +460,14 @@ func (r *rootResolverQuery) Inject(
 	queryCommerceCartDecoratedCart *graphql1.CommerceCartQueryResolver,
 	queryCommerceCartValidator *graphql1.CommerceCartQueryResolver,
 	queryCommerceCartQtyRestriction *graphql1.CommerceCartQueryResolver,
+	queryCommerceCartCustomerCarts *graphql1.CommerceCartQueryResolver,
+	queryCommerceCartGiftCardBalance *graphql1.CommerceCartGiftCardResolver,
+	queryCommerceCartAvailableShippingMethods *graphql1.CommerceCartShippingResolver,
+	queryCommerceCartHistory *graphql1.CommerceCartHistoryResolver,
+	queryCommerceCartLastMergeReport *graphql1.CommerceCartQueryResolver,
+	queryCommerceCartPreviewBundleConfig *graphql1.CommerceCartQueryResolver,
+	queryCommerceCartQuotes *graphql1.CommerceCartQuoteResolver,
+	queryCommerceCartQuote *graphql1.CommerceCartQuoteResolver,
 	queryCommerceCheckoutActivePlaceOrder *graphql5.CommerceCheckoutQueryResolver,
 	queryCommerceCheckoutCurrentContext *graphql5.CommerceCheckoutQueryResolver,
 	queryCommerceCategoryTree *graphql7.CommerceCategoryQueryResolver,
@@ -395,6 +481,14 @@ func (r *rootResolverQuery) Inject(
 	r.resolveCommerceCartDecoratedCart = queryCommerceCartDecoratedCart.CommerceCart
 	r.resolveCommerceCartValidator = queryCommerceCartValidator.CommerceCartValidator
 	r.resolveCommerceCartQtyRestriction = queryCommerceCartQtyRestriction.CommerceCartQtyRestriction
+	r.resolveCommerceCartCustomerCarts = queryCommerceCartCustomerCarts.CommerceCartCustomerCarts
+	r.resolveCommerceCartGiftCardBalance = queryCommerceCartGiftCardBalance.CommerceCartGiftCardBalance
+	r.resolveCommerceCartAvailableShippingMethods = queryCommerceCartAvailableShippingMethods.CommerceCartAvailableShippingMethods
+	r.resolveCommerceCartHistory = queryCommerceCartHistory.CommerceCartHistory
+	r.resolveCommerceCartLastMergeReport = queryCommerceCartLastMergeReport.CommerceCartLastMergeReport
+	r.resolveCommerceCartPreviewBundleConfig = queryCommerceCartPreviewBundleConfig.CommercePreviewBundleConfig
+	r.resolveCommerceCartQuotes = queryCommerceCartQuotes.CommerceCartQuotes
+	r.resolveCommerceCartQuote = queryCommerceCartQuote.CommerceCartQuote
 	r.resolveCommerceCheckoutActivePlaceOrder = queryCommerceCheckoutActivePlaceOrder.CommerceCheckoutActivePlaceOrder
 	r.resolveCommerceCheckoutCurrentContext = queryCommerceCheckoutCurrentContext.CommerceCheckoutCurrentContext
 	r.resolveCommerceCategoryTree = queryCommerceCategoryTree.CommerceCategoryTree
@@ -425,6 +519,30 @@ func (r *rootResolverQuery) CommerceCartValidator(ctx context.Context) (*validat
 func (r *rootResolverQuery) CommerceCartQtyRestriction(ctx context.Context, marketplaceCode string, variantCode *string, deliveryCode string) (*validation.RestrictionResult, error) {
 	return r.resolveCommerceCartQtyRestriction(ctx, marketplaceCode, variantCode, deliveryCode)
 }
+func (r *rootResolverQuery) CommerceCartCustomerCarts(ctx context.Context) ([]*cart.Cart, error) {
+	return r.resolveCommerceCartCustomerCarts(ctx)
+}
+func (r *rootResolverQuery) CommerceCartGiftCardBalance(ctx context.Context, code string) (*dto.GiftCardBalance, error) {
+	return r.resolveCommerceCartGiftCardBalance(ctx, code)
+}
+func (r *rootResolverQuery) CommerceCartAvailableShippingMethods(ctx context.Context, deliveryCode string) ([]cart.ShippingMethod, error) {
+	return r.resolveCommerceCartAvailableShippingMethods(ctx, deliveryCode)
+}
+func (r *rootResolverQuery) CommerceCartHistory(ctx context.Context) ([]dto.HistoryEntry, error) {
+	return r.resolveCommerceCartHistory(ctx)
+}
+func (r *rootResolverQuery) CommerceCartLastMergeReport(ctx context.Context) (*application.CartMergeReport, error) {
+	return r.resolveCommerceCartLastMergeReport(ctx)
+}
+func (r *rootResolverQuery) CommerceCartPreviewBundleConfig(ctx context.Context, itemID string, bundleConfig []*dto.ChoiceConfiguration) (*application.BundleConfigPreview, error) {
+	return r.resolveCommerceCartPreviewBundleConfig(ctx, itemID, bundleConfig)
+}
+func (r *rootResolverQuery) CommerceCartQuotes(ctx context.Context, customerID *string) ([]*dto.Quote, error) {
+	return r.resolveCommerceCartQuotes(ctx, customerID)
+}
+func (r *rootResolverQuery) CommerceCartQuote(ctx context.Context, id string) (*dto.Quote, error) {
+	return r.resolveCommerceCartQuote(ctx, id)
+}
 func (r *rootResolverQuery) CommerceCheckoutActivePlaceOrder(ctx context.Context) (bool, error) {
 	return r.resolveCommerceCheckoutActivePlaceOrder(ctx)
 }
@@ -450,6 +568,7 @@ func direct(root *rootResolver) map[string]interface{} {
 		"Commerce_Search_Meta.SortOptions":                    root.Commerce_Search_Meta().SortOptions,
 		"Mutation.Flamingo":                                   root.Mutation().Flamingo,
 		"Mutation.CommerceCartAddToCart":                      root.Mutation().CommerceCartAddToCart,
+		"Mutation.CommerceCartAddToCartBulk":                  root.Mutation().CommerceCartAddToCartBulk,
 		"Mutation.CommerceCartDeleteCartDelivery":             root.Mutation().CommerceCartDeleteCartDelivery,
 		"Mutation.CommerceCartDeleteItem":                     root.Mutation().CommerceCartDeleteItem,
 		"Mutation.CommerceCartUpdateItemQty":                  root.Mutation().CommerceCartUpdateItemQty,
@@ -465,22 +584,40 @@ func direct(root *rootResolver) map[string]interface{} {
 		"Mutation.CommerceCartClean":                          root.Mutation().CommerceCartClean,
 		"Mutation.CommerceCartUpdateAdditionalData":           root.Mutation().CommerceCartUpdateAdditionalData,
 		"Mutation.CommerceCartUpdateDeliveriesAdditionalData": root.Mutation().CommerceCartUpdateDeliveriesAdditionalData,
+		"Mutation.CommerceCartCreateCustomerCart":             root.Mutation().CommerceCartCreateCustomerCart,
+		"Mutation.CommerceCartRenameCustomerCart":             root.Mutation().CommerceCartRenameCustomerCart,
+		"Mutation.CommerceCartSwitchCustomerCart":             root.Mutation().CommerceCartSwitchCustomerCart,
+		"Mutation.CommerceCartMoveItem":                       root.Mutation().CommerceCartMoveItem,
+		"Mutation.CommerceCartCreateQuote":                    root.Mutation().CommerceCartCreateQuote,
+		"Mutation.CommerceCartUpdateQuoteItemPrice":           root.Mutation().CommerceCartUpdateQuoteItemPrice,
+		"Mutation.CommerceCartSendQuote":                      root.Mutation().CommerceCartSendQuote,
+		"Mutation.CommerceCartAcceptQuote":                    root.Mutation().CommerceCartAcceptQuote,
 		"Mutation.CommerceCheckoutStartPlaceOrder":            root.Mutation().CommerceCheckoutStartPlaceOrder,
+		"Mutation.CommerceCheckoutStartPlaceOrderForQuote":    root.Mutation().CommerceCheckoutStartPlaceOrderForQuote,
 		"Mutation.CommerceCheckoutCancelPlaceOrder":           root.Mutation().CommerceCheckoutCancelPlaceOrder,
 		"Mutation.CommerceCheckoutClearPlaceOrder":            root.Mutation().CommerceCheckoutClearPlaceOrder,
 		"Mutation.CommerceCheckoutRefreshPlaceOrder":          root.Mutation().CommerceCheckoutRefreshPlaceOrder,
 		"Mutation.CommerceCheckoutRefreshPlaceOrderBlocking":  root.Mutation().CommerceCheckoutRefreshPlaceOrderBlocking,
-		"Query.Flamingo":                         root.Query().Flamingo,
-		"Query.CommerceProduct":                  root.Query().CommerceProduct,
-		"Query.CommerceProductSearch":            root.Query().CommerceProductSearch,
-		"Query.CommerceCustomerStatus":           root.Query().CommerceCustomerStatus,
-		"Query.CommerceCustomer":                 root.Query().CommerceCustomer,
-		"Query.CommerceCartDecoratedCart":        root.Query().CommerceCartDecoratedCart,
-		"Query.CommerceCartValidator":            root.Query().CommerceCartValidator,
-		"Query.CommerceCartQtyRestriction":       root.Query().CommerceCartQtyRestriction,
-		"Query.CommerceCheckoutActivePlaceOrder": root.Query().CommerceCheckoutActivePlaceOrder,
-		"Query.CommerceCheckoutCurrentContext":   root.Query().CommerceCheckoutCurrentContext,
-		"Query.CommerceCategoryTree":             root.Query().CommerceCategoryTree,
-		"Query.CommerceCategory":                 root.Query().CommerceCategory,
+		"Mutation.CommerceOrderReorder":                       root.Mutation().CommerceOrderReorder,
+		"Query.Flamingo":                                      root.Query().Flamingo,
+		"Query.CommerceProduct":                               root.Query().CommerceProduct,
+		"Query.CommerceProductSearch":                         root.Query().CommerceProductSearch,
+		"Query.CommerceCustomerStatus":                        root.Query().CommerceCustomerStatus,
+		"Query.CommerceCustomer":                              root.Query().CommerceCustomer,
+		"Query.CommerceCartDecoratedCart":                     root.Query().CommerceCartDecoratedCart,
+		"Query.CommerceCartValidator":                         root.Query().CommerceCartValidator,
+		"Query.CommerceCartQtyRestriction":                    root.Query().CommerceCartQtyRestriction,
+		"Query.CommerceCartCustomerCarts":                     root.Query().CommerceCartCustomerCarts,
+		"Query.CommerceCartGiftCardBalance":                   root.Query().CommerceCartGiftCardBalance,
+		"Query.CommerceCartAvailableShippingMethods":          root.Query().CommerceCartAvailableShippingMethods,
+		"Query.CommerceCartHistory":                           root.Query().CommerceCartHistory,
+		"Query.CommerceCartLastMergeReport":                   root.Query().CommerceCartLastMergeReport,
+		"Query.CommerceCartPreviewBundleConfig":               root.Query().CommerceCartPreviewBundleConfig,
+		"Query.CommerceCartQuotes":                            root.Query().CommerceCartQuotes,
+		"Query.CommerceCartQuote":                             root.Query().CommerceCartQuote,
+		"Query.CommerceCheckoutActivePlaceOrder":              root.Query().CommerceCheckoutActivePlaceOrder,
+		"Query.CommerceCheckoutCurrentContext":                root.Query().CommerceCheckoutCurrentContext,
+		"Query.CommerceCategoryTree":                          root.Query().CommerceCategoryTree,
+		"Query.CommerceCategory":                              root.Query().CommerceCategory,
 	}
 }